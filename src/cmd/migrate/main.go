@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/migrations"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+const usage = `usage: migrate <command> [arg]
+
+commands:
+  up            apply all pending migrations
+  down [N]      roll back the last N applied migrations (default 1)
+  status        list migrations and whether they are applied
+  to VERSION    migrate up or down to VERSION (0 rolls back everything)`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	cfg := config.GetConfig()
+	err := logging.Init(&cfg.Logger)
+	if err != nil {
+		logging.GetLogger().Fatal(constants.General, constants.Startup, err.Error(), nil)
+	}
+	err = db.InitDb(cfg)
+	if err != nil {
+		logging.GetLogger().Fatal(constants.Postgres, constants.Startup, err.Error(), nil)
+	}
+	defer db.CloseDb()
+
+	if err := run(context.Background(), migrations.NewMigrator(db.GetDb()), os.Args[1:]); err != nil {
+		logging.GetLogger().Error(constants.Postgres, constants.Migration, err.Error(), nil)
+		db.CloseDb()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, migrator *migrations.Migrator, args []string) error {
+	switch args[0] {
+	case "up":
+		return migrator.Up(ctx)
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			n, err = strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations %q", args[1])
+			}
+		}
+		return migrator.Down(ctx, n)
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("missing target version\n%s", usage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.To(ctx, version)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+}
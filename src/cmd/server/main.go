@@ -1,18 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/dependency"
 	"github.com/alielmi98/go-hexa-workout/docs"
 	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
+	notification_router "github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/router"
 	user_router "github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/router"
+	user_usecase "github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
 	workout_router "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/router"
+	workout_usecase "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
 	"github.com/alielmi98/go-hexa-workout/migrations"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/jobs"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -23,27 +34,106 @@ import (
 // @name Authorization
 func main() {
 	cfg := config.GetConfig()
+	err := logging.Init(&cfg.Logger)
+	if err != nil {
+		logging.GetLogger().Fatal(constants.General, constants.Startup, err.Error(), nil)
+	}
+	logger := logging.GetLogger()
 
-	err := db.InitDb(cfg)
+	err = db.InitDb(cfg)
 	defer db.CloseDb()
 	if err != nil {
-		log.Fatalf("caller:%s  Level:%s  Msg:%s", constants.Postgres, constants.Startup, err.Error())
+		logger.Fatal(constants.Postgres, constants.Startup, err.Error(), nil)
+	}
+
+	if cfg.RateLimit.Store == constants.RateLimitRedisStore {
+		err = db.InitRedis(cfg)
+		defer db.CloseRedis()
+		if err != nil {
+			logger.Fatal(constants.Redis, constants.Startup, err.Error(), nil)
+		}
 	}
 
-	migrations.Up_1()
-	InitServer(cfg)
+	err = migrations.NewMigrator(db.GetDb()).Up(context.Background())
+	if err != nil {
+		logger.Fatal(constants.Postgres, constants.Migration, err.Error(), nil)
+	}
+	userRepo, tokenProvider := dependency.GetUserRepository(cfg)
+	err = user_usecase.NewUserUsecase(cfg, userRepo, tokenProvider, dependency.GetRefreshTokenRepository(), dependency.GetRateLimitStore(cfg)).EnsureAdminUser(context.Background())
+	if err != nil {
+		logger.Fatal(constants.General, constants.Startup, err.Error(), nil)
+	}
 
+	// The server and the jobs stop gracefully on SIGINT and SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	scheduler, err := StartJobs(ctx, cfg)
+	if err != nil {
+		logger.Fatal(constants.General, constants.Startup, err.Error(), nil)
+	}
+	InitServer(ctx, cfg)
+	if scheduler != nil {
+		scheduler.Wait()
+	}
 }
 
-func InitServer(cfg *config.Config) {
+// StartJobs starts the background jobs, nothing is started when they are disabled
+func StartJobs(ctx context.Context, cfg *config.Config) (*jobs.Scheduler, error) {
+	if !cfg.Jobs.Enabled {
+		return nil, nil
+	}
+	sqlDb, err := db.GetDb().DB()
+	if err != nil {
+		return nil, err
+	}
+	scheduler := jobs.NewScheduler(jobs.NewPostgresElector(sqlDb, cfg.Jobs.LeaderLockKey))
+	scheduleJobs := workout_usecase.NewScheduleJobsUsecase(cfg, dependency.GetScheduleJobsRepository(), dependency.GetNotifier(cfg))
+	for _, job := range []struct {
+		name     string
+		schedule string
+		run      jobs.Func
+	}{
+		{"mark-missed-workouts", cfg.Jobs.MissedSchedule, scheduleJobs.MarkMissed},
+		{"enqueue-reminders", cfg.Jobs.ReminderSchedule, scheduleJobs.EnqueueReminders},
+		{"send-reminders", cfg.Jobs.SendReminderSchedule, scheduleJobs.SendReminders},
+	} {
+		if job.schedule == "" {
+			continue
+		}
+		if err := scheduler.Add(job.name, job.schedule, job.run); err != nil {
+			return nil, err
+		}
+	}
+	scheduler.Start(ctx)
+	return scheduler, nil
+}
+
+func InitServer(ctx context.Context, cfg *config.Config) {
 	r := gin.New()
 
-	r.Use(middlewares.Cors(cfg), middlewares.LimitByRequest())
+	r.Use(middlewares.RequestLogger(), middlewares.AccessLogger(&cfg.Logger), middlewares.Recovery(), middlewares.Cors(cfg),
+		middlewares.LimitByRequest(&cfg.RateLimit, dependency.GetRateLimitStore(cfg), dependency.GetTokenProvider(cfg)))
 	RegisterRoutes(r, cfg)
 	RegisterSwagger(r, cfg)
-	log.Printf("Caller:%s Level:%s Msg:%s", constants.General, constants.Startup, "Started")
-	r.Run(fmt.Sprintf(":%s", cfg.Server.InternalPort))
+	logging.GetLogger().Info(constants.General, constants.Startup, "Started", nil)
 
+	server := &http.Server{Addr: fmt.Sprintf(":%s", cfg.Server.InternalPort), Handler: r}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		// The requests in flight are given some time to finish
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.GetLogger().Error(constants.General, constants.Startup, err.Error(), nil)
+		}
+	}()
+	err := server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logging.GetLogger().Fatal(constants.General, constants.Startup, err.Error(), nil)
+	}
+	<-shutdown
 }
 
 func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
@@ -51,15 +141,24 @@ func RegisterRoutes(r *gin.Engine, cfg *config.Config) {
 
 	v1 := api.Group("/v1")
 	{
+		tokenProvider := dependency.GetTokenProvider(cfg)
+
 		//Account
 		account := v1.Group("/account")
-		user_router.Account(account, cfg)
+		user_router.Account(account, cfg, tokenProvider)
+
+		//User management
+		users := v1.Group("/users")
+		user_router.User(users, cfg, tokenProvider)
 
 		//Workout
-		tokenProvider := dependency.GetTokenProvider(cfg)
 		workout := v1.Group("/workouts")
 		workout_router.WorkoutRouters(workout, cfg, tokenProvider)
 
+		//Notification
+		notifications := v1.Group("/notifications")
+		notification_router.Notification(notifications, cfg, tokenProvider)
+
 	}
 
 }
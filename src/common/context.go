@@ -0,0 +1,23 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+// UserIdFromContext reads the id of the authenticated user, set by the authentication middleware
+func UserIdFromContext(ctx context.Context) (int, error) {
+	value := ctx.Value(constants.UserIdKey)
+	if value == nil {
+		return 0, service_errors.Wrap(service_errors.CodeUserIdNotFound, errors.New("user ID not found in context"))
+	}
+	userId, ok := value.(float64)
+	if !ok {
+		return 0, service_errors.Wrap(service_errors.CodeUserIdNotFound, fmt.Errorf("invalid user ID type %T in context", value))
+	}
+	return int(userId), nil
+}
@@ -6,7 +6,7 @@ import (
 )
 
 var (
-	lowerCharSet   = "abcdedfghijklmnopqrst"
+	lowerCharSet   = "abcdefghijklmnopqrstuvwxyz"
 	upperCharSet   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	specialCharSet = "!@#$%&*"
 	numberSet      = "0123456789"
@@ -22,3 +22,23 @@ func ToSnakeCase(str string) string {
 	snake = matchAllCap.ReplaceAllString(snake, "${1}_${2}")
 	return strings.ToLower(snake)
 }
+
+// HasLetter reports whether s contains an ascii letter
+func HasLetter(s string) bool {
+	return strings.ContainsAny(s, lowerCharSet+upperCharSet)
+}
+
+// HasDigit reports whether s contains a digit
+func HasDigit(s string) bool {
+	return strings.ContainsAny(s, numberSet)
+}
+
+// HasUpper reports whether s contains an uppercase ascii letter
+func HasUpper(s string) bool {
+	return strings.ContainsAny(s, upperCharSet)
+}
+
+// HasLower reports whether s contains a lowercase ascii letter
+func HasLower(s string) bool {
+	return strings.ContainsAny(s, lowerCharSet)
+}
@@ -16,7 +16,20 @@ const (
 	EmailKey               string = "Email"
 	MobileNumberKey        string = "MobileNumber"
 	RolesKey               string = "Roles"
+	SessionIdKey           string = "SessionId"
+	TokenIdKey             string = "TokenId"
 	ExpireTimeKey          string = "Exp"
+	// StandardExpireTimeKey is the registered claim the jwt library validates
+	StandardExpireTimeKey string = "exp"
 
 	RefreshTokenCookieName string = "refresh_token"
+	RequestIdHeaderKey     string = "X-Request-Id"
+
+	// Rate limit
+	RateLimitMemoryStore        string = "memory"
+	RateLimitRedisStore         string = "redis"
+	RateLimitLimitHeaderKey     string = "X-RateLimit-Limit"
+	RateLimitRemainingHeaderKey string = "X-RateLimit-Remaining"
+	RateLimitResetHeaderKey     string = "X-RateLimit-Reset"
+	RetryAfterHeaderKey         string = "Retry-After"
 )
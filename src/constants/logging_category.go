@@ -31,6 +31,9 @@ const (
 	Api          SubCategory = "Api"
 	HashPassword SubCategory = "HashPassword"
 	UseCase      SubCategory = "UseCase"
+	Recovery     SubCategory = "Recovery"
+	Job          SubCategory = "Job"
+	Notification SubCategory = "Notification"
 
 	// Validation
 	PasswordValidation SubCategory = "PasswordValidation"
@@ -52,4 +55,13 @@ const (
 	RequestBody  ExtraKey = "RequestBody"
 	ResponseBody ExtraKey = "ResponseBody"
 	ErrorMessage ExtraKey = "ErrorMessage"
+	Stack        ExtraKey = "Stack"
+	RequestId    ExtraKey = "RequestId"
+	UserId       ExtraKey = "UserId"
+	SessionId    ExtraKey = "SessionId"
+	JobName      ExtraKey = "JobName"
+	Channel      ExtraKey = "Channel"
+
+	MigrationVersion ExtraKey = "MigrationVersion"
+	MigrationName    ExtraKey = "MigrationName"
 )
@@ -1,14 +1,24 @@
 package dependency
 
 import (
+	"sync"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	notificationChannel "github.com/alielmi98/go-hexa-workout/internal/notification/adapter/channel"
+	notificationInfraRepository "github.com/alielmi98/go-hexa-workout/internal/notification/adapter/repo"
+	notificationUsecase "github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase"
+	notificationPort "github.com/alielmi98/go-hexa-workout/internal/notification/port"
 	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/auth"
 	userInfraRepository "github.com/alielmi98/go-hexa-workout/internal/user/adapter/repo"
 	userPort "github.com/alielmi98/go-hexa-workout/internal/user/port"
+	workoutNotifier "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/notifier"
 	workoutInfraRepository "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/repo"
 	workoutModels "github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	workoutPort "github.com/alielmi98/go-hexa-workout/internal/workout/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
 )
 
 // midedlewares
@@ -16,31 +26,143 @@ func GetTokenProvider(cfg *config.Config) userPort.TokenProvider {
 	return auth.NewJwtProvider(cfg)
 }
 
+func GetRateLimitStore(cfg *config.Config) limiter.Store {
+	if cfg.RateLimit.Store == constants.RateLimitRedisStore {
+		return limiter.NewRedisStore(db.GetRedis())
+	}
+	return limiter.NewMemoryStore()
+}
+
 // user
 func GetUserRepository(cfg *config.Config) (userPort.UserRepository, userPort.TokenProvider) {
-	return userInfraRepository.NewUserPgRepo(), auth.NewJwtProvider(cfg)
+	// The other modules delete the data they keep for a user together with the account
+	cleanups := []userInfraRepository.AccountCleanup{workoutInfraRepository.DeleteAccountData, notificationInfraRepository.DeleteAccountData}
+	return userInfraRepository.NewUserPgRepo(cleanups...), auth.NewJwtProvider(cfg)
+}
+
+func GetRefreshTokenRepository() userPort.RefreshTokenRepository {
+	return userInfraRepository.NewRefreshTokenPgRepo()
 }
 
 // Workout
+func GetTransactor() workoutPort.Transactor {
+	return db.NewTransactor()
+}
+
 func GetWorkoutRepository() workoutPort.WorkoutRepository {
 	var preloads []db.PreloadEntity = []db.PreloadEntity{}
-	return workoutInfraRepository.NewBaseRepository[workoutModels.Workout](preloads)
+	return db.NewBaseRepository[workoutModels.Workout](preloads)
 }
 
 func GetWorkoutExerciseRepository() workoutPort.WorkoutExerciseRepository {
 	var preloads []db.PreloadEntity = []db.PreloadEntity{}
-	workoutExerciseRepo := workoutInfraRepository.NewBaseRepository[workoutModels.WorkoutExercise](preloads)
+	workoutExerciseRepo := db.NewBaseRepository[workoutModels.WorkoutExercise](preloads)
 	return workoutExerciseRepo
 }
 
+func GetExerciseSetRepository() workoutPort.ExerciseSetRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	exerciseSetRepo := db.NewBaseRepository[workoutModels.ExerciseSet](preloads)
+	return exerciseSetRepo
+}
+
 func GetScheduledWorkoutsRepository() workoutPort.ScheduledWorkoutsRepository {
 	var preloads []db.PreloadEntity = []db.PreloadEntity{}
-	scheduledWorkoutsRepo := workoutInfraRepository.NewBaseRepository[workoutModels.ScheduledWorkouts](preloads)
+	scheduledWorkoutsRepo := db.NewBaseRepository[workoutModels.ScheduledWorkouts](preloads)
 	return scheduledWorkoutsRepo
 }
 
+func GetScheduleRuleRepository() workoutPort.ScheduleRuleRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	scheduleRuleRepo := db.NewBaseRepository[workoutModels.ScheduleRule](preloads)
+	return scheduleRuleRepo
+}
+
+func GetCalendarFeedRepository() workoutPort.CalendarFeedRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	calendarFeedRepo := db.NewBaseRepository[workoutModels.CalendarFeed](preloads)
+	return calendarFeedRepo
+}
+
 func GetWorkoutReportRepository() workoutPort.WorkoutReportRepository {
 	var preloads []db.PreloadEntity = []db.PreloadEntity{}
-	workoutReportRepo := workoutInfraRepository.NewBaseRepository[workoutModels.WorkoutReport](preloads)
+	workoutReportRepo := db.NewBaseRepository[workoutModels.WorkoutReport](preloads)
 	return workoutReportRepo
 }
+
+func GetExerciseRepository() workoutPort.ExerciseRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	exerciseRepo := db.NewBaseRepository[workoutModels.Exercise](preloads)
+	return exerciseRepo
+}
+
+func GetWorkoutSessionRepository() workoutPort.WorkoutSessionRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	return workoutInfraRepository.NewWorkoutSessionRepository(preloads)
+}
+
+func GetWorkoutSessionSetRepository() workoutPort.WorkoutSessionSetRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	workoutSessionSetRepo := db.NewBaseRepository[workoutModels.WorkoutSessionSet](preloads)
+	return workoutSessionSetRepo
+}
+
+func GetPersonalRecordRepository() workoutPort.PersonalRecordRepository {
+	var preloads []db.PreloadEntity = []db.PreloadEntity{}
+	personalRecordRepo := db.NewBaseRepository[workoutModels.PersonalRecord](preloads)
+	return personalRecordRepo
+}
+
+func GetAnalyticsRepository() workoutPort.AnalyticsRepository {
+	return workoutInfraRepository.NewAnalyticsRepository()
+}
+
+func GetScheduleJobsRepository() workoutPort.ScheduleJobsRepository {
+	return workoutInfraRepository.NewScheduleJobsRepository()
+}
+
+func GetNotifier(cfg *config.Config) workoutPort.Notifier {
+	return workoutNotifier.NewNotifier(notificationUsecase.NewNotificationUsecase(cfg, GetNotificationRepository(),
+		GetNotificationPreferenceRepository(), GetRecipientRepository(), GetNotificationChannels(cfg)...))
+}
+
+// Notification
+func GetNotificationRepository() notificationPort.NotificationRepository {
+	return notificationInfraRepository.NewNotificationRepository()
+}
+
+func GetNotificationPreferenceRepository() notificationPort.PreferenceRepository {
+	return notificationInfraRepository.NewPreferenceRepository()
+}
+
+func GetRecipientRepository() notificationPort.RecipientRepository {
+	return notificationInfraRepository.NewRecipientRepository()
+}
+
+var (
+	deliveryWorker     *notificationChannel.DeliveryWorker
+	deliveryWorkerOnce sync.Once
+)
+
+// GetDeliveryWorker returns the worker shared by the background notification channels
+func GetDeliveryWorker(cfg *config.Config) *notificationChannel.DeliveryWorker {
+	deliveryWorkerOnce.Do(func() {
+		deliveryWorker = notificationChannel.NewDeliveryWorker(cfg.Notification.DeliveryWorkers, cfg.Notification.DeliveryQueueSize)
+	})
+	return deliveryWorker
+}
+
+// GetNotificationChannels returns the channels the notifications are delivered on,
+// the email channel is only available when an SMTP server is configured.
+// The emails and webhooks are sent in the background by the delivery worker.
+func GetNotificationChannels(cfg *config.Config) []notificationPort.Channel {
+	worker := GetDeliveryWorker(cfg)
+	channels := []notificationPort.Channel{
+		notificationChannel.NewInAppChannel(GetNotificationRepository()),
+		worker.Background(notificationChannel.NewWebhookChannel(cfg.Notification.WebhookTimeout*time.Second, cfg.Notification.AllowPrivateWebhooks)),
+	}
+	if cfg.Notification.Smtp.Host != "" {
+		channels = append(channels, worker.Background(notificationChannel.NewEmailChannel(&cfg.Notification.Smtp)))
+	}
+	return channels
+}
@@ -1292,15 +1292,15 @@ const docTemplate = `{
         "github_com_alielmi98_go-hexa-workout_internal_workout_adapter_http_dto.UpdateWorkoutReportRequest": {
             "type": "object",
             "required": [
-                "details",
-                "workout_id"
+                "details"
             ],
             "properties": {
                 "details": {
                     "type": "string"
                 },
                 "workout_id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 1
                 }
             }
         },
@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const redactedValue = "*****"
+
+type bodyLogWriter struct {
+	gin.ResponseWriter
+	body    *bytes.Buffer
+	maxSize int
+}
+
+func (w bodyLogWriter) Write(b []byte) (int, error) {
+	if remaining := w.maxSize - w.body.Len(); remaining > 0 {
+		if len(b) > remaining {
+			w.body.Write(b[:remaining])
+		} else {
+			w.body.Write(b)
+		}
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// prefixedBody is a request body whose beginning was already read, closing it closes the original body
+type prefixedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// AccessLogger writes one entry per request with the status, latency and response size, the bodies
+// are added with the secret fields of the dtos redacted when cfg.LogBodies is set. At most
+// cfg.MaxBodySize bytes of a body are kept, larger bodies can not be redacted and are omitted.
+func AccessLogger(cfg *config.LoggerConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		var requestBody []byte
+		var writer *bodyLogWriter
+		if cfg.LogBodies {
+			if c.Request.Body != nil {
+				// Only the logged part is buffered, the handler reads it again and then the rest of the body
+				requestBody, _ = io.ReadAll(io.LimitReader(c.Request.Body, int64(cfg.MaxBodySize)+1))
+				c.Request.Body = prefixedBody{Reader: io.MultiReader(bytes.NewReader(requestBody), c.Request.Body), Closer: c.Request.Body}
+			}
+			// Read one more byte than logged so that truncation is detected
+			writer = &bodyLogWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}, maxSize: cfg.MaxBodySize + 1}
+			c.Writer = writer
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		extra := map[constants.ExtraKey]interface{}{
+			constants.StatusCode: status,
+			constants.Latency:    time.Since(start).String(),
+			constants.BodySize:   c.Writer.Size(),
+		}
+		if userId, ok := c.Get(constants.UserIdKey); ok && userId != nil {
+			extra[constants.UserId] = userId
+		}
+		if len(c.Errors) > 0 {
+			extra[constants.ErrorMessage] = c.Errors.String()
+		}
+		if cfg.LogBodies {
+			extra[constants.RequestBody] = redactBody(requestBody, cfg.MaxBodySize)
+			extra[constants.ResponseBody] = redactBody(writer.body.Bytes(), cfg.MaxBodySize)
+		}
+
+		logger := logging.FromContext(c)
+		msg := http.StatusText(status)
+		switch {
+		case status >= http.StatusInternalServerError:
+			logger.Error(constants.RequestResponse, constants.Api, msg, extra)
+		case status >= http.StatusBadRequest:
+			logger.Warn(constants.RequestResponse, constants.Api, msg, extra)
+		default:
+			logger.Info(constants.RequestResponse, constants.Api, msg, extra)
+		}
+	}
+}
+
+// redactBody masks the secret fields of a json body and cuts the result to maxSize,
+// bodies that are not valid json, like a truncated response, are never logged as they are
+func redactBody(body []byte, maxSize int) string {
+	if len(body) == 0 || maxSize <= 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "[omitted, not json or larger than the limit]"
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return ""
+	}
+	if len(redacted) > maxSize {
+		return string(redacted[:maxSize]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if logging.IsSecretField(key) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
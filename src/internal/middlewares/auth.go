@@ -1,10 +1,12 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/auth"
 	"github.com/alielmi98/go-hexa-workout/internal/user/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
@@ -20,15 +22,16 @@ func Authentication(cfg *config.Config, tokenProvider port.TokenProvider) gin.Ha
 		auth := c.GetHeader(constants.AuthorizationHeaderKey)
 		token := strings.Split(auth, " ")
 		if auth == "" || len(token) < 2 {
-			err = &service_errors.ServiceError{EndUserMessage: service_errors.TokenRequired}
+			err = service_errors.New(service_errors.CodeTokenRequired)
 		} else {
 			claimMap, err = tokenProvider.GetClaims(token[1])
 			if err != nil {
-				switch err.(*jwt.ValidationError).Errors {
-				case jwt.ValidationErrorExpired:
-					err = &service_errors.ServiceError{EndUserMessage: service_errors.TokenExpired}
-				default:
-					err = &service_errors.ServiceError{EndUserMessage: service_errors.TokenInvalid}
+				// Any other failure, like a token without claims, is an invalid token
+				var validationErr *jwt.ValidationError
+				if errors.As(err, &validationErr) && validationErr.Errors&jwt.ValidationErrorExpired != 0 {
+					err = service_errors.New(service_errors.CodeTokenExpired)
+				} else {
+					err = service_errors.New(service_errors.CodeTokenInvalid)
 				}
 			}
 		}
@@ -46,8 +49,44 @@ func Authentication(cfg *config.Config, tokenProvider port.TokenProvider) gin.Ha
 		c.Set(constants.EmailKey, claimMap[constants.EmailKey])
 		c.Set(constants.MobileNumberKey, claimMap[constants.MobileNumberKey])
 		c.Set(constants.RolesKey, claimMap[constants.RolesKey])
+		c.Set(constants.SessionIdKey, claimMap[constants.SessionIdKey])
 		c.Set(constants.ExpireTimeKey, claimMap[constants.ExpireTimeKey])
 
 		c.Next()
 	}
 }
+
+// Authorization allows the request when the authenticated user has at least one of the roles,
+// it must be registered after Authentication
+func Authorization(validRoles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claim, exists := c.Get(constants.RolesKey)
+		if !exists {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.GenerateBaseResponseWithError(
+				nil, false, helper.ForbiddenError, service_errors.New(service_errors.CodePermissionDenied),
+			))
+			return
+		}
+		roles, err := auth.RolesFromClaim(claim)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, helper.GenerateBaseResponseWithError(
+				nil, false, helper.ForbiddenError, err,
+			))
+			return
+		}
+
+		userRoles := map[string]struct{}{}
+		for _, role := range roles {
+			userRoles[role] = struct{}{}
+		}
+		for _, role := range validRoles {
+			if _, ok := userRoles[role]; ok {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, helper.GenerateBaseResponseWithError(
+			nil, false, helper.ForbiddenError, service_errors.New(service_errors.CodePermissionDenied),
+		))
+	}
+}
@@ -2,22 +2,83 @@ package middlewares
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/user/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
-	"github.com/didip/tollbooth"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 	"github.com/gin-gonic/gin"
 )
 
-func LimitByRequest() gin.HandlerFunc {
-	lmt := tollbooth.NewLimiter(1, nil)
+// LimitByRequest counts the requests of the authenticated user, or of the client ip when the request
+// has no valid access token, against the limit of the matching route group in cfg
+func LimitByRequest(cfg *config.RateLimitConfig, store limiter.Store, tokenProvider port.TokenProvider) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		err := tollbooth.LimitByRequest(lmt, c.Writer, c.Request)
+		rule := matchRateLimit(cfg, c.Request.URL.Path)
+		if rule.Requests <= 0 {
+			c.Next()
+			return
+		}
+
+		key := rule.Path + ":ip:" + c.ClientIP()
+		if userId := rateLimitUserId(c, tokenProvider); userId != "" {
+			key = rule.Path + ":user:" + userId
+		}
+		result, err := limiter.Allow(c, store, key, rule.Requests, rule.Window*time.Second)
 		if err != nil {
+			// A store outage should not take the api down with it
+			logging.FromContext(c).Error(constants.Redis, constants.ExternalService, err.Error(), nil)
+			c.Next()
+			return
+		}
+
+		resetSeconds := strconv.Itoa(int((result.ResetIn + time.Second - 1) / time.Second))
+		c.Header(constants.RateLimitLimitHeaderKey, strconv.Itoa(result.Limit))
+		c.Header(constants.RateLimitRemainingHeaderKey, strconv.Itoa(result.Remaining))
+		c.Header(constants.RateLimitResetHeaderKey, resetSeconds)
+		if !result.Allowed {
+			c.Header(constants.RetryAfterHeaderKey, resetSeconds)
 			c.AbortWithStatusJSON(http.StatusTooManyRequests,
-				helper.GenerateBaseResponseWithError(nil, false, helper.LimiterError, err))
+				helper.GenerateBaseResponseWithError(nil, false, helper.LimiterError,
+					service_errors.New(service_errors.CodeTooManyRequests)))
 			return
-		} else {
-			c.Next()
 		}
+		c.Next()
+	}
+}
+
+// matchRateLimit returns the route limit with the longest path that prefixes the request path,
+// or the default limit with an empty path
+func matchRateLimit(cfg *config.RateLimitConfig, path string) config.RouteRateLimitConfig {
+	rule := config.RouteRateLimitConfig{Requests: cfg.Requests, Window: cfg.Window}
+	for _, route := range cfg.Routes {
+		if strings.HasPrefix(path, route.Path) && len(route.Path) > len(rule.Path) {
+			rule = route
+		}
+	}
+	return rule
+}
+
+// rateLimitUserId reads the user id from the access token without rejecting the request, the
+// authentication middleware of the route still decides whether the token is required
+func rateLimitUserId(c *gin.Context, tokenProvider port.TokenProvider) string {
+	auth := c.GetHeader(constants.AuthorizationHeaderKey)
+	token := strings.Split(auth, " ")
+	if len(token) < 2 {
+		return ""
+	}
+	claims, err := tokenProvider.GetClaims(token[1])
+	if err != nil {
+		return ""
+	}
+	if userId, ok := claims[constants.UserIdKey].(float64); ok {
+		return strconv.Itoa(int(userId))
 	}
+	return ""
 }
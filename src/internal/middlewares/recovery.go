@@ -0,0 +1,30 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic in a handler into a 500 response with the CustomRecovery result code,
+// the panic and its stack are logged with the request logger so that the entry carries the request id
+// returned in the X-Request-Id header
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logging.FromContext(c).Error(constants.General, constants.Recovery, fmt.Sprintf("panic recovered: %v", err),
+					map[constants.ExtraKey]interface{}{constants.Stack: string(debug.Stack())})
+				c.AbortWithStatusJSON(http.StatusInternalServerError, helper.GenerateBaseResponseWithError(nil, false, helper.CustomRecovery,
+					service_errors.New(service_errors.CodeUnknownError)))
+			}
+		}()
+		c.Next()
+	}
+}
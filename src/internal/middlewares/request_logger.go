@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestIdLength limits the length of request ids sent by clients
+const maxRequestIdLength = 64
+
+// RequestLogger gives every request an id and a logger that adds the request id, method, route
+// and client ip to the entries, usecases and repositories get it with logging.FromContext.
+// The route is logged instead of the path so secrets in path parameters are not written to the logs.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(constants.RequestIdHeaderKey)
+		if requestId == "" || len(requestId) > maxRequestIdLength {
+			requestId = newRequestId()
+		}
+		c.Header(constants.RequestIdHeaderKey, requestId)
+
+		logger := logging.GetLogger().With(map[constants.ExtraKey]interface{}{
+			constants.RequestId: requestId,
+			constants.Method:    c.Request.Method,
+			constants.Path:      routeOf(c),
+			constants.ClientIp:  c.ClientIP(),
+		})
+		c.Set(string(constants.RequestId), requestId)
+		c.Set(logging.ContextKey, logger)
+		c.Next()
+	}
+}
+
+// routeOf returns the route pattern the request matched, like /v1/calendar/feed/:token,
+// or the path when no route matched
+func routeOf(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return c.Request.URL.Path
+}
+
+func newRequestId() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
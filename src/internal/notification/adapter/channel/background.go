@@ -0,0 +1,79 @@
+package channel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+const (
+	defaultDeliveryWorkers   = 4
+	defaultDeliveryQueueSize = 100
+)
+
+// errQueueFull is returned when a delivery can not be queued, the notification is not sent on the channel
+var errQueueFull = errors.New("the delivery queue is full")
+
+// delivery is a notification waiting to be sent on a channel
+type delivery struct {
+	ctx          context.Context
+	channel      port.Channel
+	to           models.Recipient
+	notification models.Notification
+}
+
+// DeliveryWorker sends the notifications of the background channels, so a slow SMTP server or webhook
+// does not hold up the caller. The failed deliveries are logged and not retried.
+type DeliveryWorker struct {
+	queue chan delivery
+}
+
+// NewDeliveryWorker starts the workers that send the queued deliveries, they run for the life of the process
+func NewDeliveryWorker(workers int, queueSize int) *DeliveryWorker {
+	if workers <= 0 {
+		workers = defaultDeliveryWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultDeliveryQueueSize
+	}
+	w := &DeliveryWorker{queue: make(chan delivery, queueSize)}
+	for i := 0; i < workers; i++ {
+		go w.run()
+	}
+	return w
+}
+
+// Background returns the channel with its sends queued on the worker
+func (w *DeliveryWorker) Background(channel port.Channel) port.Channel {
+	return &backgroundChannel{Channel: channel, worker: w}
+}
+
+func (w *DeliveryWorker) run() {
+	for d := range w.queue {
+		if err := d.channel.Send(d.ctx, d.to, d.notification); err != nil {
+			logging.FromContext(d.ctx).Warn(constants.Internal, constants.Notification, err.Error(),
+				map[constants.ExtraKey]interface{}{constants.Channel: d.channel.Name(), constants.UserId: d.to.UserId})
+		}
+	}
+}
+
+// backgroundChannel queues the notifications instead of sending them, Send only fails when the queue is full
+type backgroundChannel struct {
+	port.Channel
+	worker *DeliveryWorker
+}
+
+func (c *backgroundChannel) Send(ctx context.Context, to models.Recipient, notification models.Notification) error {
+	// The delivery outlives the request or job that queued it, only its logger is kept
+	detached := logging.NewContext(context.Background(), logging.FromContext(ctx))
+	select {
+	case c.worker.queue <- delivery{ctx: detached, channel: c.Channel, to: to, notification: notification}:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
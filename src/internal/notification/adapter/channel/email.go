@@ -0,0 +1,138 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+)
+
+// defaultSmtpTimeout bounds a delivery when the config has no timeout
+const defaultSmtpTimeout = 10 * time.Second
+
+// EmailChannel sends the notifications as plain text emails through an SMTP server,
+// the users without an email address are skipped
+type EmailChannel struct {
+	cfg     config.SmtpConfig
+	timeout time.Duration
+}
+
+func NewEmailChannel(cfg *config.SmtpConfig) *EmailChannel {
+	timeout := cfg.Timeout * time.Second
+	if timeout <= 0 {
+		timeout = defaultSmtpTimeout
+	}
+	return &EmailChannel{cfg: *cfg, timeout: timeout}
+}
+
+func (c *EmailChannel) Name() string {
+	return models.ChannelEmail
+}
+
+func (c *EmailChannel) Send(ctx context.Context, to models.Recipient, notification models.Notification) error {
+	if to.Email == "" {
+		return nil
+	}
+	from, err := mail.ParseAddress(c.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	message, err := c.message(from, to, notification)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.cfg.Host, c.cfg.Port))
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	client, err := smtp.NewClient(conn, c.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: c.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if c.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Email); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message builds the headers and the quoted-printable body of the email, the user provided
+// values are encoded so they can not add headers
+func (c *EmailChannel) message(from *mail.Address, to models.Recipient, notification models.Notification) ([]byte, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+
+	var message bytes.Buffer
+	headers := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", (&mail.Address{Name: to.Name, Address: to.Email}).String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", notification.Title)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-Id", fmt.Sprintf("<%s@%s>", hex.EncodeToString(id), domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&message, "%s: %s\r\n", header.key, header.value)
+	}
+	message.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&message)
+	if _, err := fmt.Fprintf(body, "Hi %s,\r\n\r\n%s\r\n", to.Name, notification.Body); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return message.Bytes(), nil
+}
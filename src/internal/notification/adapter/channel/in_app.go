@@ -0,0 +1,27 @@
+package channel
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/port"
+)
+
+// InAppChannel stores the notifications so the users can read them through the /notifications endpoints
+type InAppChannel struct {
+	repository port.NotificationRepository
+}
+
+func NewInAppChannel(notificationRepository port.NotificationRepository) *InAppChannel {
+	return &InAppChannel{repository: notificationRepository}
+}
+
+func (c *InAppChannel) Name() string {
+	return models.ChannelInApp
+}
+
+func (c *InAppChannel) Send(ctx context.Context, to models.Recipient, notification models.Notification) error {
+	notification.UserId = to.UserId
+	_, err := c.repository.Create(ctx, notification)
+	return err
+}
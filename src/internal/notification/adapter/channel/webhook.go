@@ -0,0 +1,136 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+)
+
+// SignatureHeader holds the hex HMAC-SHA256 of the request body with the webhook secret of the user,
+// prefixed with "sha256="
+const SignatureHeader = "X-Webhook-Signature"
+
+// defaultWebhookTimeout bounds a delivery when the config has no timeout
+const defaultWebhookTimeout = 5 * time.Second
+
+// errPrivateAddress is returned when a webhook url resolves to an address that is not public
+var errPrivateAddress = errors.New("the webhook address is not public")
+
+// WebhookChannel posts the notifications as JSON to the webhook url of the users,
+// a response outside of 2xx is a failed delivery. Redirects are not followed.
+type WebhookChannel struct {
+	client *http.Client
+}
+
+// webhookPayload is the body of the webhook requests
+type webhookPayload struct {
+	Kind   string          `json:"kind"`
+	Title  string          `json:"title"`
+	Body   string          `json:"body"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	UserId int             `json:"userId"`
+	SentAt time.Time       `json:"sentAt"`
+}
+
+// NewWebhookChannel connects only to public addresses unless allowPrivate is set. The address a url
+// resolves to is checked when the connection is made, so a public name pointing at the local network is
+// refused as well.
+func NewWebhookChannel(timeout time.Duration, allowPrivate bool) *WebhookChannel {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = publicAddressOnly
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	return &WebhookChannel{client: &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func (c *WebhookChannel) Name() string {
+	return models.ChannelWebhook
+}
+
+func (c *WebhookChannel) Send(ctx context.Context, to models.Recipient, notification models.Notification) error {
+	if to.WebhookUrl == "" {
+		return nil
+	}
+	payload := webhookPayload{
+		Kind:   notification.Kind,
+		Title:  notification.Title,
+		Body:   notification.Body,
+		UserId: to.UserId,
+		SentAt: time.Now().UTC(),
+	}
+	if notification.Data != nil {
+		payload.Data = json.RawMessage(*notification.Data)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, to.WebhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if to.WebhookSecret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(to.WebhookSecret, body))
+	}
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// The body is drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 4096))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return nil
+}
+
+// publicAddressOnly refuses the connections to the addresses that are not public
+func publicAddressOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !common.IsPublicIP(ip) {
+		return errPrivateAddress
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of the body with the secret, receivers compare it to the SignatureHeader
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+// The secret fields are redacted from the logged bodies, a dto with a secret field is registered here
+func init() {
+	logging.RegisterSecrets(PreferenceResponse{}, UpdatePreferenceRequest{})
+}
+
+type NotificationResponse struct {
+	Id        int             `json:"id"`
+	Kind      string          `json:"kind"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Read      bool            `json:"read"`
+	ReadAt    *time.Time      `json:"read_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type UnreadCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+// MarkAllReadResponse holds how many notifications were marked as read
+type MarkAllReadResponse struct {
+	Count int64 `json:"count"`
+}
+
+type PreferenceResponse struct {
+	ScheduleReminders bool   `json:"schedule_reminders"`
+	PersonalRecords   bool   `json:"personal_records"`
+	Email             bool   `json:"email"`
+	InApp             bool   `json:"in_app"`
+	Webhook           bool   `json:"webhook"`
+	WebhookUrl        string `json:"webhook_url" log:"secret"`
+	WebhookSecretSet  bool   `json:"webhook_secret_set"`
+}
+
+// UpdatePreferenceRequest changes the fields that are sent and keeps the others,
+// an empty webhook_secret removes the secret. Webhook urls often carry a token of their own, so they are not logged.
+type UpdatePreferenceRequest struct {
+	ScheduleReminders *bool   `json:"schedule_reminders"`
+	PersonalRecords   *bool   `json:"personal_records"`
+	Email             *bool   `json:"email"`
+	InApp             *bool   `json:"in_app"`
+	Webhook           *bool   `json:"webhook"`
+	WebhookUrl        *string `json:"webhook_url" binding:"omitempty,max=2048" log:"secret"`
+	WebhookSecret     *string `json:"webhook_secret" binding:"omitempty,max=128" log:"secret"`
+}
+
+func ToNotificationResponse(from dto.NotificationResponse) NotificationResponse {
+	response := NotificationResponse{
+		Id:        from.Id,
+		Kind:      from.Kind,
+		Title:     from.Title,
+		Body:      from.Body,
+		Read:      from.Read,
+		ReadAt:    from.ReadAt,
+		CreatedAt: from.CreatedAt,
+	}
+	if from.Data != nil {
+		response.Data = json.RawMessage(*from.Data)
+	}
+	return response
+}
+
+func ToNotificationPagedList(from *filter.PagedList[dto.NotificationResponse]) *filter.PagedList[NotificationResponse] {
+	items := make([]NotificationResponse, 0, len(*from.Items))
+	for _, item := range *from.Items {
+		items = append(items, ToNotificationResponse(item))
+	}
+	return &filter.PagedList[NotificationResponse]{
+		PageNumber:      from.PageNumber,
+		PageSize:        from.PageSize,
+		TotalRows:       from.TotalRows,
+		TotalPages:      from.TotalPages,
+		HasPreviousPage: from.HasPreviousPage,
+		HasNextPage:     from.HasNextPage,
+		Items:           &items,
+	}
+}
+
+func ToPreferenceResponse(from dto.PreferenceResponse) PreferenceResponse {
+	return PreferenceResponse{
+		ScheduleReminders: from.ScheduleReminders,
+		PersonalRecords:   from.PersonalRecords,
+		Email:             from.Email,
+		InApp:             from.InApp,
+		Webhook:           from.Webhook,
+		WebhookUrl:        from.WebhookUrl,
+		WebhookSecretSet:  from.WebhookSecretSet,
+	}
+}
+
+func ToUpdatePreferenceRequest(from UpdatePreferenceRequest) dto.UpdatePreferenceRequest {
+	return dto.UpdatePreferenceRequest{
+		ScheduleReminders: from.ScheduleReminders,
+		PersonalRecords:   from.PersonalRecords,
+		Email:             from.Email,
+		InApp:             from.InApp,
+		Webhook:           from.Webhook,
+		WebhookUrl:        from.WebhookUrl,
+		WebhookSecret:     from.WebhookSecret,
+	}
+}
@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler serves the in-app notifications and the notification preferences of the user
+type NotificationHandler struct {
+	Usecase *usecase.NotificationUsecase
+}
+
+// NewNotificationHandler ...
+func NewNotificationHandler(cfg *config.Config) *NotificationHandler {
+	return &NotificationHandler{
+		Usecase: usecase.NewNotificationUsecase(cfg, dependency.GetNotificationRepository(), dependency.GetNotificationPreferenceRepository(),
+			dependency.GetRecipientRepository(), dependency.GetNotificationChannels(cfg)...),
+	}
+}
+
+// GetNotificationsByFilter godoc
+// @Summary Get Notifications by Filter
+// @Description Get the in-app Notifications of the user by Filter
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.NotificationResponse]} "Notifications response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/notifications/get-by-filter [post]
+// @Security AuthBearer
+func (h *NotificationHandler) GetByFilter(c *gin.Context) {
+	req := filter.PaginationInputWithFilter{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+	res, err := h.Usecase.GetByFilter(c, req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToNotificationPagedList(res), true, helper.Success))
+}
+
+// GetUnreadCount godoc
+// @Summary Get the unread count
+// @Description Get the number of unread Notifications of the user
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UnreadCountResponse} "Unread count response"
+// @Router /v1/notifications/unread-count [get]
+// @Security AuthBearer
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	count, err := h.Usecase.UnreadCount(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.UnreadCountResponse{Count: count}, true, helper.Success))
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a Notification as read
+// @Description Mark a Notification of the user as read
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.NotificationResponse} "Notification response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/notifications/{id}/read [post]
+// @Security AuthBearer
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	h.setRead(c, true)
+}
+
+// MarkNotificationUnread godoc
+// @Summary Mark a Notification as unread
+// @Description Mark a Notification of the user as unread
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.NotificationResponse} "Notification response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/notifications/{id}/unread [post]
+// @Security AuthBearer
+func (h *NotificationHandler) MarkUnread(c *gin.Context) {
+	h.setRead(c, false)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all Notifications as read
+// @Description Mark every unread Notification of the user as read
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.MarkAllReadResponse} "Marked count response"
+// @Router /v1/notifications/read-all [post]
+// @Security AuthBearer
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	count, err := h.Usecase.MarkAllRead(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.MarkAllReadResponse{Count: count}, true, helper.Success))
+}
+
+// DeleteNotification godoc
+// @Summary Delete a Notification
+// @Description Delete a Notification of the user
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} helper.BaseHttpResponse "Deleted"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/notifications/{id} [delete]
+// @Security AuthBearer
+func (h *NotificationHandler) Delete(c *gin.Context) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	if err := h.Usecase.Delete(c, id); err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get the notification preferences
+// @Description Get the kinds of notifications the user receives and the channels they are delivered on
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.PreferenceResponse} "Preferences response"
+// @Router /v1/notifications/preferences [get]
+// @Security AuthBearer
+func (h *NotificationHandler) GetPreference(c *gin.Context) {
+	res, err := h.Usecase.GetPreference(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToPreferenceResponse(res), true, helper.Success))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update the notification preferences
+// @Description Update the notification preferences of the user, the fields that are not sent are kept
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Param Request body dto.UpdatePreferenceRequest true "Update the notification preferences"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.PreferenceResponse} "Preferences response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/notifications/preferences [put]
+// @Security AuthBearer
+func (h *NotificationHandler) UpdatePreference(c *gin.Context) {
+	req := dto.UpdatePreferenceRequest{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+	res, err := h.Usecase.UpdatePreference(c, dto.ToUpdatePreferenceRequest(req))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToPreferenceResponse(res), true, helper.Success))
+}
+
+func (h *NotificationHandler) setRead(c *gin.Context, read bool) {
+	id, ok := idParam(c)
+	if !ok {
+		return
+	}
+	res, err := h.Usecase.SetRead(c, id, read)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToNotificationResponse(res), true, helper.Success))
+}
+
+// idParam reads the id of the path and aborts with a bad request when it is not a valid id
+func idParam(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Params.ByName("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err))
+		return 0, false
+	}
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, errors.New("invalid id")))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/user/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+func Notification(router *gin.RouterGroup, cfg *config.Config, tokenProvider port.TokenProvider) {
+	handler := handler.NewNotificationHandler(cfg)
+	router.Use(middlewares.Authentication(cfg, tokenProvider))
+	router.POST("/get-by-filter", handler.GetByFilter)
+	router.GET("/unread-count", handler.UnreadCount)
+	router.POST("/read-all", handler.MarkAllRead)
+	router.GET("/preferences", handler.GetPreference)
+	router.PUT("/preferences", handler.UpdatePreference)
+	router.POST("/:id/read", handler.MarkRead)
+	router.POST("/:id/unread", handler.MarkUnread)
+	router.DELETE("/:id", handler.Delete)
+}
@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"gorm.io/gorm"
+)
+
+// DeleteAccountData soft deletes the notifications and the preferences of a user.
+// The user repository runs it in the transaction that deletes the account.
+func DeleteAccountData(tx *gorm.DB, userId int, deleteMap map[string]interface{}) error {
+	for _, model := range []interface{}{&models.Notification{}, &models.NotificationPreference{}} {
+		if err := tx.Model(model).Where("deleted_by is null and user_id = ?", userId).Updates(deleteMap).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository is the BaseRepository of the notifications with the read state updates,
+// which are written by column since Updates skips the false and nil fields
+type NotificationRepository struct {
+	*db.BaseRepository[models.Notification]
+	database *gorm.DB
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	return &NotificationRepository{
+		BaseRepository: db.NewBaseRepository[models.Notification]([]db.PreloadEntity{}),
+		database:       db.GetDb(),
+	}
+}
+
+func (r NotificationRepository) SetRead(ctx context.Context, userId int, id int, read bool) (models.Notification, error) {
+	var readAt *time.Time
+	if read {
+		now := time.Now().UTC()
+		readAt = &now
+	}
+	result := r.database.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("id = ? and user_id = ? and deleted_by is null", id, userId).
+		Updates(map[string]interface{}{"read": read, "read_at": readAt})
+	if result.Error != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, result.Error.Error(), nil)
+		return models.Notification{}, db.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return models.Notification{}, service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return r.GetById(ctx, id)
+}
+
+func (r NotificationRepository) MarkAllRead(ctx context.Context, userId int) (int64, error) {
+	result := r.database.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? and read = false and deleted_by is null", userId).
+		Updates(map[string]interface{}{"read": true, "read_at": time.Now().UTC()})
+	if result.Error != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, result.Error.Error(), nil)
+		return 0, db.TranslateError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r NotificationRepository) CountUnread(ctx context.Context, userId int) (int64, error) {
+	var count int64
+	err := r.database.WithContext(ctx).
+		Model(&models.Notification{}).
+		Where("user_id = ? and read = false and deleted_by is null", userId).
+		Count(&count).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return 0, db.TranslateError(err)
+	}
+	return count, nil
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PreferenceRepository struct {
+	database *gorm.DB
+}
+
+func NewPreferenceRepository() *PreferenceRepository {
+	return &PreferenceRepository{database: db.GetDb()}
+}
+
+func (r PreferenceRepository) GetByUserId(ctx context.Context, userId int) (models.NotificationPreference, error) {
+	var preference models.NotificationPreference
+	err := r.database.WithContext(ctx).
+		Where("user_id = ? and deleted_by is null", userId).
+		First(&preference).
+		Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		}
+		return models.NotificationPreference{}, db.TranslateError(err)
+	}
+	return preference, nil
+}
+
+func (r PreferenceRepository) Save(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error) {
+	modifiedBy := sql.NullInt64{}
+	if value := ctx.Value(constants.UserIdKey); value != nil {
+		modifiedBy = sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	// A user has a single row, the unique index on user_id turns a second save into an update
+	updates := clause.AssignmentColumns([]string{"schedule_reminders", "personal_records", "email", "in_app",
+		"webhook", "webhook_url", "webhook_secret"})
+	updates = append(updates, clause.Assignments(map[string]interface{}{"modified_at": time.Now().UTC(), "modified_by": modifiedBy})...)
+	err := r.database.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoUpdates: updates}).
+		Create(&preference).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Insert, err.Error(), nil)
+		return models.NotificationPreference{}, db.TranslateError(err)
+	}
+	return r.GetByUserId(ctx, preference.UserId)
+}
@@ -0,0 +1,47 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// RecipientRepository reads the contact details of the users, the disabled and deleted users are not found
+type RecipientRepository struct {
+	database *gorm.DB
+}
+
+func NewRecipientRepository() *RecipientRepository {
+	return &RecipientRepository{database: db.GetDb()}
+}
+
+func (r RecipientRepository) GetByUserId(ctx context.Context, userId int) (models.Recipient, error) {
+	var row struct {
+		Username  string
+		FirstName *string
+		Email     *string
+	}
+	err := r.database.WithContext(ctx).
+		Raw(`SELECT username, first_name, email FROM users WHERE id = ? AND enabled AND deleted_by is null`, userId).
+		Take(&row).
+		Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		}
+		return models.Recipient{}, db.TranslateError(err)
+	}
+	recipient := models.Recipient{UserId: userId, Name: row.Username}
+	if row.FirstName != nil && *row.FirstName != "" {
+		recipient.Name = *row.FirstName
+	}
+	if row.Email != nil {
+		recipient.Email = *row.Email
+	}
+	return recipient, nil
+}
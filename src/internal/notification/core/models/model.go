@@ -0,0 +1,178 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"gorm.io/gorm"
+)
+
+// Kinds of the notifications, a user chooses which ones they receive
+const (
+	KindScheduleReminder = "schedule_reminder"
+	KindPersonalRecord   = "personal_record"
+)
+
+// Channels the notifications are delivered on
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInApp   = "in_app"
+)
+
+// Notification is a message to a user. The in-app channel stores it so the user can read it later,
+// Data is a JSON object with the ids the message is about.
+type Notification struct {
+	Id     int        `gorm:"primarykey"`
+	UserId int        `gorm:"not null"`
+	Kind   string     `gorm:"type:string;size:32;not null"`
+	Title  string     `gorm:"type:string;size:150;not null"`
+	Body   string     `gorm:"type:text;not null"`
+	Data   *string    `gorm:"type:jsonb;null" filter:"-"`
+	Read   bool       `gorm:"not null;default:false"`
+	ReadAt *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// NotificationPreference holds the kinds of notifications a user receives and the channels they are
+// delivered on. A user without preferences gets the DefaultPreference.
+type NotificationPreference struct {
+	Id                int    `gorm:"primarykey"`
+	UserId            int    `gorm:"not null"`
+	ScheduleReminders bool   `gorm:"not null"`
+	PersonalRecords   bool   `gorm:"not null"`
+	Email             bool   `gorm:"not null"`
+	InApp             bool   `gorm:"not null"`
+	Webhook           bool   `gorm:"not null"`
+	WebhookUrl        string `gorm:"type:string;size:2048;not null;default:''"`
+	// WebhookSecret signs the webhook requests when it is set
+	WebhookSecret string `gorm:"type:string;size:128;not null;default:''" filter:"-"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// DefaultPreference sends every kind of notification by email and in the app
+func DefaultPreference(userId int) NotificationPreference {
+	return NotificationPreference{UserId: userId, ScheduleReminders: true, PersonalRecords: true, Email: true, InApp: true}
+}
+
+// Wants reports whether the user receives the notifications of a kind
+func (p NotificationPreference) Wants(kind string) bool {
+	switch kind {
+	case KindScheduleReminder:
+		return p.ScheduleReminders
+	case KindPersonalRecord:
+		return p.PersonalRecords
+	}
+	return false
+}
+
+// Uses reports whether the notifications are delivered on a channel
+func (p NotificationPreference) Uses(channel string) bool {
+	switch channel {
+	case ChannelEmail:
+		return p.Email
+	case ChannelInApp:
+		return p.InApp
+	case ChannelWebhook:
+		return p.Webhook && p.WebhookUrl != ""
+	}
+	return false
+}
+
+// Recipient is where the notifications of a user are delivered
+type Recipient struct {
+	UserId        int
+	Name          string
+	Email         string
+	WebhookUrl    string
+	WebhookSecret string
+}
+
+func (Notification) OwnerScope() string {
+	return "user_id = ?"
+}
+
+func (NotificationPreference) OwnerScope() string {
+	return "user_id = ?"
+}
+
+// GORM hooks for Notification
+func (m *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *Notification) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *Notification) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for NotificationPreference
+func (m *NotificationPreference) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *NotificationPreference) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *NotificationPreference) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
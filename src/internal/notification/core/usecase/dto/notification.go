@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// NotifyRequest is a notification to a user, Data holds the ids it is about
+type NotifyRequest struct {
+	UserId int
+	Kind   string
+	Title  string
+	Body   string
+	Data   map[string]interface{}
+}
+
+type NotificationResponse struct {
+	Id        int
+	UserId    int
+	Kind      string
+	Title     string
+	Body      string
+	Data      *string
+	Read      bool
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+// PreferenceResponse hides the webhook secret, WebhookSecretSet tells whether there is one
+type PreferenceResponse struct {
+	ScheduleReminders bool
+	PersonalRecords   bool
+	Email             bool
+	InApp             bool
+	Webhook           bool
+	WebhookUrl        string
+	WebhookSecretSet  bool
+}
+
+// UpdatePreferenceRequest changes the preferences that are set and keeps the others,
+// an empty WebhookSecret removes the secret
+type UpdatePreferenceRequest struct {
+	ScheduleReminders *bool
+	PersonalRecords   *bool
+	Email             *bool
+	InApp             *bool
+	Webhook           *bool
+	WebhookUrl        *string
+	WebhookSecret     *string
+}
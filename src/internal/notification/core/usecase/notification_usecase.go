@@ -0,0 +1,255 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+type NotificationUsecase struct {
+	repository  port.NotificationRepository
+	preferences port.PreferenceRepository
+	recipients  port.RecipientRepository
+	channels    []port.Channel
+	// allowPrivateWebhooks accepts webhook urls on the local network
+	allowPrivateWebhooks bool
+}
+
+func NewNotificationUsecase(cfg *config.Config, notificationRepository port.NotificationRepository, preferenceRepository port.PreferenceRepository,
+	recipientRepository port.RecipientRepository, channels ...port.Channel) *NotificationUsecase {
+	return &NotificationUsecase{
+		repository:           notificationRepository,
+		preferences:          preferenceRepository,
+		recipients:           recipientRepository,
+		channels:             channels,
+		allowPrivateWebhooks: cfg.Notification.AllowPrivateWebhooks,
+	}
+}
+
+// Notify delivers a notification on the channels the user enabled, nothing is sent when the user turned
+// the kind off or can not be found. Every channel is tried and their failures are returned together,
+// the channels sending in the background only fail when they can not queue the delivery.
+func (u *NotificationUsecase) Notify(ctx context.Context, req dto.NotifyRequest) error {
+	preference, err := u.preferenceOf(ctx, req.UserId)
+	if err != nil {
+		return err
+	}
+	if !preference.Wants(req.Kind) {
+		return nil
+	}
+	recipient, err := u.recipients.GetByUserId(ctx, req.UserId)
+	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	recipient.WebhookUrl = preference.WebhookUrl
+	recipient.WebhookSecret = preference.WebhookSecret
+
+	notification := models.Notification{UserId: req.UserId, Kind: req.Kind, Title: req.Title, Body: req.Body}
+	if len(req.Data) > 0 {
+		data, err := json.Marshal(req.Data)
+		if err != nil {
+			return err
+		}
+		payload := string(data)
+		notification.Data = &payload
+	}
+
+	var errs []error
+	for _, channel := range u.channels {
+		if !preference.Uses(channel.Name()) {
+			continue
+		}
+		if err := channel.Send(ctx, recipient, notification); err != nil {
+			logging.FromContext(ctx).Warn(constants.Internal, constants.Notification, err.Error(),
+				map[constants.ExtraKey]interface{}{constants.Channel: channel.Name(), constants.UserId: req.UserId})
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// GetByFilter lists the notifications of the user in the context
+func (u *NotificationUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.NotificationResponse], error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.OwnerId = userId
+	count, notifications, err := u.repository.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return filter.Paginate[models.Notification, dto.NotificationResponse](count, notifications, req.GetPageNumber(), int64(req.GetPageSize()))
+}
+
+func (u *NotificationUsecase) UnreadCount(ctx context.Context) (int64, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return u.repository.CountUnread(ctx, userId)
+}
+
+// SetRead marks a notification of the user in the context as read or unread
+func (u *NotificationUsecase) SetRead(ctx context.Context, id int, read bool) (dto.NotificationResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.NotificationResponse{}, err
+	}
+	notification, err := u.repository.SetRead(ctx, userId, id, read)
+	if err != nil {
+		return dto.NotificationResponse{}, err
+	}
+	return toNotificationResponse(notification), nil
+}
+
+// MarkAllRead marks every notification of the user in the context as read and returns how many were unread
+func (u *NotificationUsecase) MarkAllRead(ctx context.Context) (int64, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return u.repository.MarkAllRead(ctx, userId)
+}
+
+func (u *NotificationUsecase) Delete(ctx context.Context, id int) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	notification, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	// The notifications of the other users are reported as missing so their ids are not disclosed
+	if notification.UserId != userId {
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return u.repository.Delete(ctx, id)
+}
+
+// GetPreference returns the preferences of the user in the context, or the defaults when they saved none
+func (u *NotificationUsecase) GetPreference(ctx context.Context) (dto.PreferenceResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.PreferenceResponse{}, err
+	}
+	preference, err := u.preferenceOf(ctx, userId)
+	if err != nil {
+		return dto.PreferenceResponse{}, err
+	}
+	return toPreferenceResponse(preference), nil
+}
+
+func (u *NotificationUsecase) UpdatePreference(ctx context.Context, req dto.UpdatePreferenceRequest) (dto.PreferenceResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.PreferenceResponse{}, err
+	}
+	preference, err := u.preferenceOf(ctx, userId)
+	if err != nil {
+		return dto.PreferenceResponse{}, err
+	}
+	setIfPresent(&preference.ScheduleReminders, req.ScheduleReminders)
+	setIfPresent(&preference.PersonalRecords, req.PersonalRecords)
+	setIfPresent(&preference.Email, req.Email)
+	setIfPresent(&preference.InApp, req.InApp)
+	setIfPresent(&preference.Webhook, req.Webhook)
+	setIfPresent(&preference.WebhookUrl, req.WebhookUrl)
+	setIfPresent(&preference.WebhookSecret, req.WebhookSecret)
+	// The webhook can only be enabled with a url, and a url is kept only when it is valid
+	if (preference.Webhook || preference.WebhookUrl != "") && !isWebhookUrl(preference.WebhookUrl, u.allowPrivateWebhooks) {
+		return dto.PreferenceResponse{}, service_errors.New(service_errors.CodeInvalidWebhookUrl)
+	}
+
+	saved, err := u.preferences.Save(ctx, preference)
+	if err != nil {
+		return dto.PreferenceResponse{}, err
+	}
+	return toPreferenceResponse(saved), nil
+}
+
+// preferenceOf returns the saved preferences of the user or the defaults
+func (u *NotificationUsecase) preferenceOf(ctx context.Context, userId int) (models.NotificationPreference, error) {
+	preference, err := u.preferences.GetByUserId(ctx, userId)
+	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+			return models.DefaultPreference(userId), nil
+		}
+		return models.NotificationPreference{}, err
+	}
+	return preference, nil
+}
+
+// isWebhookUrl reports whether the value is an absolute http or https url. Unless private webhooks are
+// allowed the host can not be a local name or a non-public address, the webhook channel checks the
+// addresses the other names resolve to when it connects.
+func isWebhookUrl(value string, allowPrivate bool) bool {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return false
+	}
+	if allowPrivate {
+		return true
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return common.IsPublicIP(ip)
+	}
+	return true
+}
+
+func setIfPresent[T any](field *T, value *T) {
+	if value != nil {
+		*field = *value
+	}
+}
+
+func toNotificationResponse(notification models.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		Id:        notification.Id,
+		UserId:    notification.UserId,
+		Kind:      notification.Kind,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		Data:      notification.Data,
+		Read:      notification.Read,
+		ReadAt:    notification.ReadAt,
+		CreatedAt: notification.CreatedAt,
+	}
+}
+
+func toPreferenceResponse(preference models.NotificationPreference) dto.PreferenceResponse {
+	return dto.PreferenceResponse{
+		ScheduleReminders: preference.ScheduleReminders,
+		PersonalRecords:   preference.PersonalRecords,
+		Email:             preference.Email,
+		InApp:             preference.InApp,
+		Webhook:           preference.Webhook,
+		WebhookUrl:        preference.WebhookUrl,
+		WebhookSecretSet:  preference.WebhookSecret != "",
+	}
+}
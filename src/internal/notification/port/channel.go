@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+)
+
+// Channel delivers the notifications, Name is one of the models.Channel constants
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, to models.Recipient, notification models.Notification) error
+}
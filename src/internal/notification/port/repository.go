@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification models.Notification) (models.Notification, error)
+	Delete(ctx context.Context, id int) error
+	GetById(ctx context.Context, id int) (models.Notification, error)
+	GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Notification, error)
+	// SetRead marks a notification of the user as read or unread, it fails with RecordNotFound
+	// when the user has no such notification
+	SetRead(ctx context.Context, userId int, id int, read bool) (models.Notification, error)
+	// MarkAllRead marks the unread notifications of the user as read and returns their count
+	MarkAllRead(ctx context.Context, userId int) (int64, error)
+	CountUnread(ctx context.Context, userId int) (int64, error)
+}
+
+type PreferenceRepository interface {
+	// GetByUserId fails with RecordNotFound when the user has not saved preferences
+	GetByUserId(ctx context.Context, userId int) (models.NotificationPreference, error)
+	// Save creates or replaces the preferences of the user
+	Save(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error)
+}
+
+type RecipientRepository interface {
+	// GetByUserId returns the name and email of the user
+	GetByUserId(ctx context.Context, userId int) (models.Recipient, error)
+}
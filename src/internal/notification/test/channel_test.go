@@ -0,0 +1,334 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/adapter/channel"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+)
+
+// ==================== EMAIL CHANNEL TESTS ====================
+
+// smtpDelivery is what the fake SMTP server received
+type smtpDelivery struct {
+	from string
+	to   []string
+	data string
+}
+
+// startFakeSmtpServer serves a single SMTP session on a local port without STARTTLS and AUTH,
+// rejectRcpt makes it refuse the recipients
+func startFakeSmtpServer(t *testing.T, rejectRcpt bool) (string, <-chan smtpDelivery) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	deliveries := make(chan smtpDelivery, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		reader := bufio.NewReader(conn)
+		reply := func(lines ...string) {
+			for _, line := range lines {
+				_, _ = io.WriteString(conn, line+"\r\n")
+			}
+		}
+		var delivery smtpDelivery
+		reply("220 localhost ESMTP fake")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimRight(line, "\r\n")
+			verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0])
+			switch verb {
+			case "EHLO", "HELO":
+				reply("250-localhost", "250 8BITMIME")
+			case "MAIL":
+				delivery.from = strings.Fields(strings.TrimPrefix(command, "MAIL FROM:"))[0]
+				reply("250 OK")
+			case "RCPT":
+				if rejectRcpt {
+					reply("550 no such user")
+					continue
+				}
+				delivery.to = append(delivery.to, strings.TrimPrefix(command, "RCPT TO:"))
+				reply("250 OK")
+			case "DATA":
+				reply("354 end with a single dot")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				delivery.data = data.String()
+				reply("250 OK queued")
+			case "QUIT":
+				reply("221 bye")
+				deliveries <- delivery
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	return port, deliveries
+}
+
+func emailChannel(port string) *channel.EmailChannel {
+	return channel.NewEmailChannel(&config.SmtpConfig{Host: "127.0.0.1", Port: port, From: "Workout <no-reply@workout.local>", Timeout: 5})
+}
+
+func TestEmailChannel_SendsThroughSmtp(t *testing.T) {
+	port, deliveries := startFakeSmtpServer(t, false)
+	notification := models.Notification{
+		Kind:  models.KindPersonalRecord,
+		Title: "New personal record on Bench Press — 100 kg",
+		Body:  "You set a new personal record on Bench Press.\nMax weight: 100 (previous 95)",
+	}
+
+	err := emailChannel(port).Send(context.Background(), models.Recipient{UserId: 1, Name: "Ali", Email: "ali@example.com"}, notification)
+
+	assert.NoError(t, err)
+	delivery := <-deliveries
+	assert.Equal(t, "<no-reply@workout.local>", delivery.from)
+	assert.Equal(t, []string{"<ali@example.com>"}, delivery.to)
+
+	message, err := mail.ReadMessage(strings.NewReader(delivery.data))
+	assert.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, notification.Title, subject)
+	to, err := message.Header.AddressList("To")
+	assert.NoError(t, err)
+	assert.Equal(t, "ali@example.com", to[0].Address)
+	assert.Equal(t, "quoted-printable", message.Header.Get("Content-Transfer-Encoding"))
+	body, err := io.ReadAll(quotedprintable.NewReader(message.Body))
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), "Hi Ali,")
+	assert.Contains(t, string(body), "Max weight: 100 (previous 95)")
+}
+
+func TestEmailChannel_HeadersCanNotBeInjected(t *testing.T) {
+	port, deliveries := startFakeSmtpServer(t, false)
+	notification := models.Notification{Title: "Hello\r\nBcc: someone@example.com", Body: "body"}
+
+	err := emailChannel(port).Send(context.Background(), models.Recipient{Name: "Ali", Email: "ali@example.com"}, notification)
+
+	assert.NoError(t, err)
+	message, err := mail.ReadMessage(strings.NewReader((<-deliveries).data))
+	assert.NoError(t, err)
+	assert.Equal(t, "", message.Header.Get("Bcc"))
+}
+
+func TestEmailChannel_RejectedRecipient(t *testing.T) {
+	port, _ := startFakeSmtpServer(t, true)
+
+	err := emailChannel(port).Send(context.Background(), models.Recipient{Name: "Ali", Email: "ali@example.com"}, models.Notification{Title: "Hi"})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "550")
+}
+
+func TestEmailChannel_WithoutAddressIsSkipped(t *testing.T) {
+	// Nothing listens on the port, the channel must not connect
+	err := emailChannel("1").Send(context.Background(), models.Recipient{Name: "Ali"}, models.Notification{Title: "Hi"})
+
+	assert.NoError(t, err)
+}
+
+// ==================== WEBHOOK CHANNEL TESTS ====================
+
+func TestWebhookChannel_PostsSignedJson(t *testing.T) {
+	type received struct {
+		signature string
+		body      []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{signature: r.Header.Get(channel.SignatureHeader), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	data := `{"scheduled_workout_id":7}`
+	notification := models.Notification{Kind: models.KindScheduleReminder, Title: "Upcoming workout: Push day", Body: "Soon", Data: &data}
+
+	err := channel.NewWebhookChannel(time.Second, true).Send(context.Background(),
+		models.Recipient{UserId: 3, WebhookUrl: server.URL, WebhookSecret: "s3cret"}, notification)
+
+	assert.NoError(t, err)
+	request := <-requests
+	assert.Equal(t, "sha256="+channel.Sign("s3cret", request.body), request.signature)
+	var payload struct {
+		Kind   string          `json:"kind"`
+		Title  string          `json:"title"`
+		UserId int             `json:"userId"`
+		Data   json.RawMessage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(request.body, &payload))
+	assert.Equal(t, models.KindScheduleReminder, payload.Kind)
+	assert.Equal(t, "Upcoming workout: Push day", payload.Title)
+	assert.Equal(t, 3, payload.UserId)
+	assert.Equal(t, data, string(payload.Data))
+}
+
+func TestWebhookChannel_WithoutSecretIsNotSigned(t *testing.T) {
+	signatures := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signatures <- r.Header.Get(channel.SignatureHeader)
+	}))
+	defer server.Close()
+
+	err := channel.NewWebhookChannel(time.Second, true).Send(context.Background(), models.Recipient{WebhookUrl: server.URL}, models.Notification{Title: "Hi"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", <-signatures)
+}
+
+func TestWebhookChannel_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := channel.NewWebhookChannel(time.Second, true).Send(context.Background(), models.Recipient{WebhookUrl: server.URL}, models.Notification{Title: "Hi"})
+
+	assert.EqualError(t, err, "webhook responded with 500 Internal Server Error")
+}
+
+func TestWebhookChannel_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	err := channel.NewWebhookChannel(50*time.Millisecond, true).Send(context.Background(), models.Recipient{WebhookUrl: server.URL}, models.Notification{Title: "Hi"})
+
+	assert.Error(t, err)
+}
+
+func TestWebhookChannel_RefusesPrivateAddresses(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+	}))
+	defer server.Close()
+	webhook := channel.NewWebhookChannel(time.Second, false)
+
+	for _, url := range []string{server.URL, "http://169.254.169.254/latest/meta-data", "http://10.0.0.1:8080"} {
+		err := webhook.Send(context.Background(), models.Recipient{WebhookUrl: url}, models.Notification{Title: "Hi"})
+
+		assert.Error(t, err, url)
+	}
+	assert.Equal(t, 0, len(requests))
+}
+
+func TestWebhookChannel_DoesNotFollowRedirects(t *testing.T) {
+	requests := make(chan struct{}, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- struct{}{}
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	err := channel.NewWebhookChannel(time.Second, true).Send(context.Background(), models.Recipient{WebhookUrl: server.URL}, models.Notification{Title: "Hi"})
+
+	assert.EqualError(t, err, "webhook responded with 307 Temporary Redirect")
+	assert.Equal(t, 0, len(requests))
+}
+
+// ==================== IN-APP CHANNEL TESTS ====================
+
+func TestInAppChannel_StoresTheNotification(t *testing.T) {
+	var stored models.Notification
+	repo := &MockNotificationRepository{
+		CreateFn: func(ctx context.Context, notification models.Notification) (models.Notification, error) {
+			stored = notification
+			return notification, nil
+		},
+	}
+
+	err := channel.NewInAppChannel(repo).Send(context.Background(), models.Recipient{UserId: 6}, models.Notification{Kind: models.KindPersonalRecord, Title: "Hi"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, stored.UserId)
+	assert.False(t, stored.Read)
+}
+
+// ==================== BACKGROUND CHANNEL TESTS ====================
+
+func TestBackgroundChannel_DoesNotWaitForTheDelivery(t *testing.T) {
+	release := make(chan struct{})
+	delivered := make(chan error, 1)
+	slow := &MockChannel{ChannelName: models.ChannelEmail, SendFn: func(ctx context.Context, to models.Recipient, notification models.Notification) error {
+		<-release
+		delivered <- ctx.Err()
+		return nil
+	}}
+	background := channel.NewDeliveryWorker(1, 1).Background(slow)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := background.Send(ctx, models.Recipient{UserId: 6}, models.Notification{Title: "Hi"})
+	cancel()
+	close(release)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChannelEmail, background.Name())
+	select {
+	case ctxErr := <-delivered:
+		// The delivery is not canceled with the context of the caller
+		assert.NoError(t, ctxErr)
+	case <-time.After(time.Second):
+		t.Fatal("the notification was not delivered")
+	}
+}
+
+func TestBackgroundChannel_FullQueueFails(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+	slow := &MockChannel{ChannelName: models.ChannelWebhook, SendFn: func(ctx context.Context, to models.Recipient, notification models.Notification) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}}
+	background := channel.NewDeliveryWorker(1, 1).Background(slow)
+
+	assert.NoError(t, background.Send(context.Background(), models.Recipient{}, models.Notification{}))
+	<-started
+	assert.NoError(t, background.Send(context.Background(), models.Recipient{}, models.Notification{}))
+	err := background.Send(context.Background(), models.Recipient{}, models.Notification{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,144 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// MockNotificationRepository implements NotificationRepository interface for testing
+type MockNotificationRepository struct {
+	CreateFn      func(ctx context.Context, notification models.Notification) (models.Notification, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.Notification, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Notification, error)
+	SetReadFn     func(ctx context.Context, userId int, id int, read bool) (models.Notification, error)
+	MarkAllReadFn func(ctx context.Context, userId int) (int64, error)
+	CountUnreadFn func(ctx context.Context, userId int) (int64, error)
+}
+
+func (m *MockNotificationRepository) Create(ctx context.Context, notification models.Notification) (models.Notification, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, notification)
+	}
+	notification.Id = 1
+	return notification, nil
+}
+
+func (m *MockNotificationRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockNotificationRepository) GetById(ctx context.Context, id int) (models.Notification, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.Notification{Id: id, UserId: 1}, nil
+}
+
+func (m *MockNotificationRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Notification, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	return 0, &[]models.Notification{}, nil
+}
+
+func (m *MockNotificationRepository) SetRead(ctx context.Context, userId int, id int, read bool) (models.Notification, error) {
+	if m.SetReadFn != nil {
+		return m.SetReadFn(ctx, userId, id, read)
+	}
+	return models.Notification{Id: id, UserId: userId, Read: read}, nil
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userId int) (int64, error) {
+	if m.MarkAllReadFn != nil {
+		return m.MarkAllReadFn(ctx, userId)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) CountUnread(ctx context.Context, userId int) (int64, error) {
+	if m.CountUnreadFn != nil {
+		return m.CountUnreadFn(ctx, userId)
+	}
+	return 0, nil
+}
+
+// MockPreferenceRepository implements PreferenceRepository interface for testing,
+// a user has no preferences unless GetByUserIdFn is set
+type MockPreferenceRepository struct {
+	GetByUserIdFn func(ctx context.Context, userId int) (models.NotificationPreference, error)
+	SaveFn        func(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error)
+}
+
+func (m *MockPreferenceRepository) GetByUserId(ctx context.Context, userId int) (models.NotificationPreference, error) {
+	if m.GetByUserIdFn != nil {
+		return m.GetByUserIdFn(ctx, userId)
+	}
+	return models.NotificationPreference{}, service_errors.New(service_errors.CodeRecordNotFound)
+}
+
+func (m *MockPreferenceRepository) Save(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error) {
+	if m.SaveFn != nil {
+		return m.SaveFn(ctx, preference)
+	}
+	return preference, nil
+}
+
+// MockRecipientRepository implements RecipientRepository interface for testing
+type MockRecipientRepository struct {
+	GetByUserIdFn func(ctx context.Context, userId int) (models.Recipient, error)
+}
+
+func (m *MockRecipientRepository) GetByUserId(ctx context.Context, userId int) (models.Recipient, error) {
+	if m.GetByUserIdFn != nil {
+		return m.GetByUserIdFn(ctx, userId)
+	}
+	return models.Recipient{UserId: userId, Name: "Ali", Email: "ali@example.com"}, nil
+}
+
+// MockChannel implements Channel interface for testing and records the notifications it sends
+type MockChannel struct {
+	ChannelName string
+	SendFn      func(ctx context.Context, to models.Recipient, notification models.Notification) error
+	Sent        []models.Notification
+}
+
+func (m *MockChannel) Name() string {
+	return m.ChannelName
+}
+
+func (m *MockChannel) Send(ctx context.Context, to models.Recipient, notification models.Notification) error {
+	m.Sent = append(m.Sent, notification)
+	if m.SendFn != nil {
+		return m.SendFn(ctx, to, notification)
+	}
+	return nil
+}
+
+func createContextWithUserId(userId float64) context.Context {
+	return context.WithValue(context.Background(), constants.UserIdKey, userId)
+}
+
+// createAuthenticatedGinContext creates a gin context of user 1 for the handler tests
+func createAuthenticatedGinContext(method, url string, body []byte, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequest(method, url, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	c.Request = req
+	c.Params = params
+	c.Set(constants.UserIdKey, float64(1))
+	return c, w
+}
@@ -0,0 +1,132 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+func setupNotificationHandler(repo *MockNotificationRepository, preferences *MockPreferenceRepository) *handler.NotificationHandler {
+	return &handler.NotificationHandler{Usecase: setupNotificationUsecase(repo, preferences)}
+}
+
+func TestGetNotifications_Handler_Success(t *testing.T) {
+	data := `{"scheduled_workout_id":7}`
+	repo := &MockNotificationRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Notification, error) {
+			return 1, &[]models.Notification{{Id: 2, UserId: 1, Kind: models.KindScheduleReminder, Title: "Upcoming workout", Data: &data, CreatedAt: time.Now()}}, nil
+		},
+	}
+	c, w := createAuthenticatedGinContext("POST", "/v1/notifications/get-by-filter", []byte(`{"pageNumber":1,"pageSize":10}`), nil)
+
+	setupNotificationHandler(repo, &MockPreferenceRepository{}).GetByFilter(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result filter.PagedList[dto.NotificationResponse] `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1, len(*response.Result.Items))
+	item := (*response.Result.Items)[0]
+	assert.Equal(t, "Upcoming workout", item.Title)
+	assert.False(t, item.Read)
+	assert.Equal(t, data, string(item.Data))
+}
+
+func TestUnreadCount_Handler_Success(t *testing.T) {
+	repo := &MockNotificationRepository{
+		CountUnreadFn: func(ctx context.Context, userId int) (int64, error) {
+			return 3, nil
+		},
+	}
+	c, w := createAuthenticatedGinContext("GET", "/v1/notifications/unread-count", nil, nil)
+
+	setupNotificationHandler(repo, &MockPreferenceRepository{}).UnreadCount(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result dto.UnreadCountResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(3), response.Result.Count)
+}
+
+func TestMarkRead_Handler_Success(t *testing.T) {
+	c, w := createAuthenticatedGinContext("POST", "/v1/notifications/5/read", nil, gin.Params{{Key: "id", Value: "5"}})
+
+	setupNotificationHandler(&MockNotificationRepository{}, &MockPreferenceRepository{}).MarkRead(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result dto.NotificationResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 5, response.Result.Id)
+	assert.True(t, response.Result.Read)
+}
+
+func TestMarkUnread_Handler_NotFound(t *testing.T) {
+	repo := &MockNotificationRepository{
+		SetReadFn: func(ctx context.Context, userId int, id int, read bool) (models.Notification, error) {
+			return models.Notification{}, service_errors.New(service_errors.CodeRecordNotFound)
+		},
+	}
+	c, w := createAuthenticatedGinContext("POST", "/v1/notifications/5/unread", nil, gin.Params{{Key: "id", Value: "5"}})
+
+	setupNotificationHandler(repo, &MockPreferenceRepository{}).MarkUnread(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMarkRead_Handler_InvalidId(t *testing.T) {
+	for _, id := range []string{"abc", "0"} {
+		c, w := createAuthenticatedGinContext("POST", "/v1/notifications/"+id+"/read", nil, gin.Params{{Key: "id", Value: id}})
+
+		setupNotificationHandler(&MockNotificationRepository{}, &MockPreferenceRepository{}).MarkRead(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, id)
+	}
+}
+
+func TestDeleteNotification_Handler_Success(t *testing.T) {
+	c, w := createAuthenticatedGinContext("DELETE", "/v1/notifications/4", nil, gin.Params{{Key: "id", Value: "4"}})
+
+	setupNotificationHandler(&MockNotificationRepository{}, &MockPreferenceRepository{}).Delete(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestUpdatePreference_Handler_HidesTheSecret(t *testing.T) {
+	body := []byte(`{"webhook":true,"webhook_url":"https://hooks.example.com/workouts","webhook_secret":"s3cret"}`)
+	c, w := createAuthenticatedGinContext("PUT", "/v1/notifications/preferences", body, nil)
+
+	setupNotificationHandler(&MockNotificationRepository{}, &MockPreferenceRepository{}).UpdatePreference(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+	var response struct {
+		Result dto.PreferenceResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, response.Result.Webhook)
+	assert.True(t, response.Result.WebhookSecretSet)
+	assert.True(t, response.Result.Email)
+}
+
+func TestUpdatePreference_Handler_InvalidWebhookUrl(t *testing.T) {
+	c, w := createAuthenticatedGinContext("PUT", "/v1/notifications/preferences", []byte(`{"webhook":true,"webhook_url":"javascript:alert(1)"}`), nil)
+
+	setupNotificationHandler(&MockNotificationRepository{}, &MockPreferenceRepository{}).UpdatePreference(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
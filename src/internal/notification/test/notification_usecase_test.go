@@ -0,0 +1,308 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+func setupNotificationUsecase(repo *MockNotificationRepository, preferences *MockPreferenceRepository, channels ...*MockChannel) *usecase.NotificationUsecase {
+	var ports []port.Channel
+	for _, channel := range channels {
+		ports = append(ports, channel)
+	}
+	return usecase.NewNotificationUsecase(&config.Config{}, repo, preferences, &MockRecipientRepository{}, ports...)
+}
+
+func allChannels() (*MockChannel, *MockChannel, *MockChannel) {
+	return &MockChannel{ChannelName: models.ChannelEmail}, &MockChannel{ChannelName: models.ChannelInApp}, &MockChannel{ChannelName: models.ChannelWebhook}
+}
+
+func preferencesWith(preference models.NotificationPreference) *MockPreferenceRepository {
+	return &MockPreferenceRepository{
+		GetByUserIdFn: func(ctx context.Context, userId int) (models.NotificationPreference, error) {
+			preference.UserId = userId
+			return preference, nil
+		},
+	}
+}
+
+func reminderRequest() dto.NotifyRequest {
+	return dto.NotifyRequest{
+		UserId: 1,
+		Kind:   models.KindScheduleReminder,
+		Title:  "Upcoming workout: Push day",
+		Body:   "Your workout Push day is scheduled for Mon, 04 Mar 2024 18:00 UTC.",
+		Data:   map[string]interface{}{"scheduled_workout_id": 7},
+	}
+}
+
+// ==================== NOTIFY TESTS ====================
+
+func TestNotify_DefaultPreferences(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, &MockPreferenceRepository{}, email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(email.Sent))
+	assert.Equal(t, 1, len(inApp.Sent))
+	assert.Equal(t, 0, len(webhook.Sent))
+	assert.Equal(t, `{"scheduled_workout_id":7}`, *inApp.Sent[0].Data)
+	assert.Equal(t, models.KindScheduleReminder, inApp.Sent[0].Kind)
+}
+
+func TestNotify_KindTurnedOff(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	preference := models.DefaultPreference(1)
+	preference.ScheduleReminders = false
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferencesWith(preference), email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(email.Sent)+len(inApp.Sent)+len(webhook.Sent))
+}
+
+func TestNotify_WebhookOfThePreferences(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	var recipient models.Recipient
+	webhook.SendFn = func(ctx context.Context, to models.Recipient, notification models.Notification) error {
+		recipient = to
+		return nil
+	}
+	preference := models.NotificationPreference{PersonalRecords: true, Webhook: true, WebhookUrl: "https://hooks.example.com/pr", WebhookSecret: "s3cret"}
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferencesWith(preference), email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), dto.NotifyRequest{UserId: 1, Kind: models.KindPersonalRecord, Title: "New personal record"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(email.Sent))
+	assert.Equal(t, 0, len(inApp.Sent))
+	assert.Equal(t, 1, len(webhook.Sent))
+	assert.Equal(t, "https://hooks.example.com/pr", recipient.WebhookUrl)
+	assert.Equal(t, "s3cret", recipient.WebhookSecret)
+	assert.Equal(t, "ali@example.com", recipient.Email)
+}
+
+func TestNotify_WebhookWithoutUrlIsSkipped(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	preference := models.DefaultPreference(1)
+	preference.Webhook = true
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferencesWith(preference), email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(webhook.Sent))
+}
+
+func TestNotify_ChannelFailureDoesNotStopTheOthers(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	email.SendFn = func(ctx context.Context, to models.Recipient, notification models.Notification) error {
+		return errors.New("connection refused")
+	}
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, &MockPreferenceRepository{}, email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.EqualError(t, err, "email: connection refused")
+	assert.Equal(t, 1, len(inApp.Sent))
+}
+
+func TestNotify_UnknownUserIsSkipped(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	useCase := usecase.NewNotificationUsecase(&config.Config{}, &MockNotificationRepository{}, &MockPreferenceRepository{},
+		&MockRecipientRepository{
+			GetByUserIdFn: func(ctx context.Context, userId int) (models.Recipient, error) {
+				return models.Recipient{}, service_errors.New(service_errors.CodeRecordNotFound)
+			},
+		}, email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(email.Sent)+len(inApp.Sent))
+}
+
+func TestNotify_PreferenceError(t *testing.T) {
+	email, inApp, webhook := allChannels()
+	preferences := &MockPreferenceRepository{
+		GetByUserIdFn: func(ctx context.Context, userId int) (models.NotificationPreference, error) {
+			return models.NotificationPreference{}, errors.New("database error")
+		},
+	}
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferences, email, inApp, webhook)
+
+	err := useCase.Notify(context.Background(), reminderRequest())
+
+	assert.EqualError(t, err, "database error")
+	assert.Equal(t, 0, len(email.Sent)+len(inApp.Sent))
+}
+
+// ==================== IN-APP NOTIFICATION TESTS ====================
+
+func TestGetNotifications_OnlyOfTheUser(t *testing.T) {
+	var ownerId int
+	repo := &MockNotificationRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Notification, error) {
+			ownerId = req.OwnerId
+			return 1, &[]models.Notification{{Id: 4, UserId: 5, Title: "Upcoming workout"}}, nil
+		},
+	}
+	useCase := setupNotificationUsecase(repo, &MockPreferenceRepository{})
+
+	result, err := useCase.GetByFilter(createContextWithUserId(5), filter.PaginationInputWithFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, ownerId)
+	assert.Equal(t, int64(1), result.TotalRows)
+	assert.Equal(t, "Upcoming workout", (*result.Items)[0].Title)
+}
+
+func TestGetNotifications_WithoutUser(t *testing.T) {
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, &MockPreferenceRepository{})
+
+	_, err := useCase.GetByFilter(context.Background(), filter.PaginationInputWithFilter{})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserIdNotFound))
+}
+
+func TestMarkNotificationRead_OfTheUser(t *testing.T) {
+	var userId, id int
+	var read bool
+	repo := &MockNotificationRepository{
+		SetReadFn: func(ctx context.Context, u int, i int, r bool) (models.Notification, error) {
+			userId, id, read = u, i, r
+			return models.Notification{Id: i, UserId: u, Read: r}, nil
+		},
+	}
+	useCase := setupNotificationUsecase(repo, &MockPreferenceRepository{})
+
+	response, err := useCase.SetRead(createContextWithUserId(3), 8, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, userId)
+	assert.Equal(t, 8, id)
+	assert.True(t, read)
+	assert.True(t, response.Read)
+}
+
+func TestDeleteNotification_OfAnotherUser(t *testing.T) {
+	deleted := false
+	repo := &MockNotificationRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Notification, error) {
+			return models.Notification{Id: id, UserId: 2}, nil
+		},
+		DeleteFn: func(ctx context.Context, id int) error {
+			deleted = true
+			return nil
+		},
+	}
+	useCase := setupNotificationUsecase(repo, &MockPreferenceRepository{})
+
+	err := useCase.Delete(createContextWithUserId(1), 4)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+	assert.False(t, deleted)
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	repo := &MockNotificationRepository{
+		MarkAllReadFn: func(ctx context.Context, userId int) (int64, error) {
+			return int64(userId) * 2, nil
+		},
+	}
+	useCase := setupNotificationUsecase(repo, &MockPreferenceRepository{})
+
+	count, err := useCase.MarkAllRead(createContextWithUserId(3))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), count)
+}
+
+// ==================== PREFERENCE TESTS ====================
+
+func TestGetPreference_Defaults(t *testing.T) {
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, &MockPreferenceRepository{})
+
+	preference, err := useCase.GetPreference(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.PreferenceResponse{ScheduleReminders: true, PersonalRecords: true, Email: true, InApp: true}, preference)
+}
+
+func TestUpdatePreference_KeepsTheFieldsNotSent(t *testing.T) {
+	var saved models.NotificationPreference
+	preferences := preferencesWith(models.NotificationPreference{Id: 2, ScheduleReminders: true, PersonalRecords: true, InApp: true, WebhookSecret: "old"})
+	preferences.SaveFn = func(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error) {
+		saved = preference
+		return preference, nil
+	}
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferences)
+	off, on, url := false, true, "https://hooks.example.com/workouts"
+
+	response, err := useCase.UpdatePreference(createContextWithUserId(1), dto.UpdatePreferenceRequest{ScheduleReminders: &off, Webhook: &on, WebhookUrl: &url})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, saved.UserId)
+	assert.False(t, saved.ScheduleReminders)
+	assert.True(t, saved.PersonalRecords)
+	assert.True(t, saved.InApp)
+	assert.Equal(t, "old", saved.WebhookSecret)
+	assert.True(t, response.WebhookSecretSet)
+	assert.Equal(t, url, response.WebhookUrl)
+}
+
+func TestUpdatePreference_InvalidWebhookUrl(t *testing.T) {
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, &MockPreferenceRepository{})
+	on := true
+	urls := []string{"", "hooks.example.com", "ftp://hooks.example.com", "https://",
+		"http://localhost:8080/hook", "http://127.0.0.1/hook", "http://[::1]/hook", "http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook", "http://192.168.1.10/hook", "http://172.16.0.1/hook", "http://[fd00::1]/hook", "http://0.0.0.0/hook"}
+	for _, url := range urls {
+		webhookUrl := url
+
+		_, err := useCase.UpdatePreference(createContextWithUserId(1), dto.UpdatePreferenceRequest{Webhook: &on, WebhookUrl: &webhookUrl})
+
+		assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidWebhookUrl), url)
+	}
+}
+
+func TestUpdatePreference_AllowsPrivateWebhooksWhenConfigured(t *testing.T) {
+	cfg := &config.Config{Notification: config.NotificationConfig{AllowPrivateWebhooks: true}}
+	useCase := usecase.NewNotificationUsecase(cfg, &MockNotificationRepository{}, &MockPreferenceRepository{}, &MockRecipientRepository{})
+	on, url := true, "http://10.0.0.5:8080/hook"
+
+	response, err := useCase.UpdatePreference(createContextWithUserId(1), dto.UpdatePreferenceRequest{Webhook: &on, WebhookUrl: &url})
+
+	assert.NoError(t, err)
+	assert.Equal(t, url, response.WebhookUrl)
+}
+
+func TestUpdatePreference_RemovesTheSecret(t *testing.T) {
+	var saved models.NotificationPreference
+	preferences := preferencesWith(models.NotificationPreference{WebhookSecret: "old"})
+	preferences.SaveFn = func(ctx context.Context, preference models.NotificationPreference) (models.NotificationPreference, error) {
+		saved = preference
+		return preference, nil
+	}
+	useCase := setupNotificationUsecase(&MockNotificationRepository{}, preferences)
+	empty := ""
+
+	response, err := useCase.UpdatePreference(createContextWithUserId(1), dto.UpdatePreferenceRequest{WebhookSecret: &empty})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", saved.WebhookSecret)
+	assert.False(t, response.WebhookSecretSet)
+}
@@ -34,7 +34,10 @@ func (s *JwtProvider) GenerateToken(token *entity.TokenPayload) (*dto.TokenDetai
 	atc[constants.UsernameKey] = token.Username
 	atc[constants.EmailKey] = token.Email
 	atc[constants.MobileNumberKey] = token.MobileNumber
+	atc[constants.RolesKey] = token.Roles
+	atc[constants.SessionIdKey] = token.SessionId
 	atc[constants.ExpireTimeKey] = td.AccessTokenExpireTime
+	atc[constants.StandardExpireTimeKey] = td.AccessTokenExpireTime
 
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atc)
 
@@ -52,7 +55,11 @@ func (s *JwtProvider) GenerateToken(token *entity.TokenPayload) (*dto.TokenDetai
 	rtc[constants.UsernameKey] = token.Username
 	rtc[constants.EmailKey] = token.Email
 	rtc[constants.MobileNumberKey] = token.MobileNumber
+	rtc[constants.RolesKey] = token.Roles
+	rtc[constants.SessionIdKey] = token.SessionId
+	rtc[constants.TokenIdKey] = token.TokenId
 	rtc[constants.ExpireTimeKey] = td.RefreshTokenExpireTime
+	rtc[constants.StandardExpireTimeKey] = td.RefreshTokenExpireTime
 
 	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, rtc)
 
@@ -66,12 +73,25 @@ func (s *JwtProvider) GenerateToken(token *entity.TokenPayload) (*dto.TokenDetai
 }
 
 func (s *JwtProvider) VerifyToken(token string) (*jwt.Token, error) {
+	return verify(token, s.cfg.JWT.Secret)
+}
+
+func (s *JwtProvider) GetClaims(token string) (claimMap map[string]interface{}, err error) {
+	return claims(token, s.cfg.JWT.Secret)
+}
+
+// GetRefreshClaims returns the claims of a refresh token, it is verified with the refresh secret
+func (s *JwtProvider) GetRefreshClaims(refreshToken string) (map[string]interface{}, error) {
+	return claims(refreshToken, s.cfg.JWT.RefreshSecret)
+}
+
+func verify(token string, secret string) (*jwt.Token, error) {
 	at, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
 		_, ok := token.Method.(*jwt.SigningMethodHMAC)
 		if !ok {
-			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.UnExpectedError}
+			return nil, service_errors.New(service_errors.CodeUnExpectedError)
 		}
-		return []byte(s.cfg.JWT.Secret), nil
+		return []byte(secret), nil
 	})
 	if err != nil {
 		return nil, err
@@ -79,10 +99,10 @@ func (s *JwtProvider) VerifyToken(token string) (*jwt.Token, error) {
 	return at, nil
 }
 
-func (s *JwtProvider) GetClaims(token string) (claimMap map[string]interface{}, err error) {
+func claims(token string, secret string) (claimMap map[string]interface{}, err error) {
 	claimMap = map[string]interface{}{}
 
-	verifyToken, err := s.VerifyToken(token)
+	verifyToken, err := verify(token, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -93,26 +113,27 @@ func (s *JwtProvider) GetClaims(token string) (claimMap map[string]interface{},
 		}
 		return claimMap, nil
 	}
-	return nil, &service_errors.ServiceError{EndUserMessage: service_errors.ClaimsNotFound}
+	return nil, service_errors.New(service_errors.CodeClaimsNotFound)
 }
-func (s *JwtProvider) RefreshToken(refreshToken string) (*dto.TokenDetail, error) {
-	claims, err := s.GetClaims(refreshToken)
-	if err != nil {
-		return nil, err
-	}
 
-	tokenDto := entity.TokenPayload{
-		UserId:       int(claims[constants.UserIdKey].(float64)),
-		FirstName:    claims[constants.FirstNameKey].(string),
-		LastName:     claims[constants.LastNameKey].(string),
-		Username:     claims[constants.UsernameKey].(string),
-		MobileNumber: claims[constants.MobileNumberKey].(string),
-		Email:        claims[constants.EmailKey].(string),
+// RolesFromClaim converts the decoded roles claim of a token to role names
+func RolesFromClaim(claim interface{}) ([]string, error) {
+	roles := []string{}
+	if claim == nil {
+		return roles, nil
 	}
-	newTokenDetail, err := s.GenerateToken(&tokenDto)
-	if err != nil {
-		return nil, err
+	switch values := claim.(type) {
+	case []string:
+		return values, nil
+	case []interface{}:
+		for _, value := range values {
+			role, ok := value.(string)
+			if !ok {
+				return nil, service_errors.New(service_errors.CodeInvalidRolesFormat)
+			}
+			roles = append(roles, role)
+		}
+		return roles, nil
 	}
-
-	return newTokenDetail, nil
+	return nil, service_errors.New(service_errors.CodeInvalidRolesFormat)
 }
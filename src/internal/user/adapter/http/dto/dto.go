@@ -1,8 +1,20 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+// The secret fields are redacted from the logged bodies, a dto with a secret field is registered here
+func init() {
+	logging.RegisterSecrets(TokenDetail{}, RegisterUserByUsernameRequest{}, LoginByUsernameRequest{},
+		ChangePasswordRequest{}, RefreshTokenRequest{})
+}
+
 type TokenDetail struct {
-	AccessToken            string `json:"accessToken"`
-	RefreshToken           string `json:"refreshToken"`
+	AccessToken            string `json:"accessToken" log:"secret"`
+	RefreshToken           string `json:"refreshToken" log:"secret"`
 	AccessTokenExpireTime  int64  `json:"accessTokenExpireTime"`
 	RefreshTokenExpireTime int64  `json:"refreshTokenExpireTime"`
 }
@@ -12,10 +24,40 @@ type RegisterUserByUsernameRequest struct {
 	LastName  string `json:"lastName" binding:"required,min=3"`
 	Username  string `json:"username" binding:"required,min=5"`
 	Email     string `json:"email" binding:"min=6,email"`
-	Password  string `json:"password" binding:"required,min=6"`
+	Password  string `json:"password" binding:"required,min=6" log:"secret"`
 }
 
 type LoginByUsernameRequest struct {
 	Username string `json:"username" binding:"required,min=5"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,min=6" log:"secret"`
+	// ClientIp is set by the handler for counting the failed attempts per ip
+	ClientIp string `json:"-"`
+}
+
+type UpdateProfileRequest struct {
+	FirstName    string `json:"firstName" binding:"omitempty,min=3,max=15"`
+	LastName     string `json:"lastName" binding:"omitempty,min=3,max=25"`
+	MobileNumber string `json:"mobileNumber" binding:"omitempty,numeric,len=11"`
+	Email        string `json:"email" binding:"omitempty,email,max=64"`
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required" log:"secret"`
+	NewPassword string `json:"newPassword" binding:"required" log:"secret"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" log:"secret"`
+}
+
+type UserResponse struct {
+	Id           int        `json:"id"`
+	Username     string     `json:"username"`
+	FirstName    string     `json:"firstName"`
+	LastName     string     `json:"lastName"`
+	MobileNumber string     `json:"mobileNumber"`
+	Email        string     `json:"email"`
+	Enabled      bool       `json:"enabled"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"`
+	Roles        []string   `json:"roles"`
 }
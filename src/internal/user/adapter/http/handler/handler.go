@@ -23,7 +23,7 @@ type AccountHandler struct {
 func NewAccountHandler(cfg *config.Config) *AccountHandler {
 	repo, token := dependency.GetUserRepository(cfg)
 	return &AccountHandler{
-		Usecase: usecase.NewUserUsecase(cfg, repo, token),
+		Usecase: usecase.NewUserUsecase(cfg, repo, token, dependency.GetRefreshTokenRepository(), dependency.GetRateLimitStore(cfg)),
 		Cfg:     cfg,
 	}
 }
@@ -50,7 +50,7 @@ func (h *AccountHandler) RegisterByUsername(c *gin.Context) {
 	err := h.Usecase.RegisterByUsername(c, &req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 	c.JSON(http.StatusCreated, helper.GenerateBaseResponse("User created", true, helper.Success))
@@ -66,6 +66,7 @@ func (h *AccountHandler) RegisterByUsername(c *gin.Context) {
 // @Success 200 {object} helper.BaseHttpResponse "Success"
 // @Failure 400 {object} helper.BaseHttpResponse "Failed"
 // @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Failure 429 {object} helper.BaseHttpResponse "Too many failed attempts"
 // @Router /v1/account/login [post]
 func (h *AccountHandler) LoginByUsername(c *gin.Context) {
 	var req dto.LoginByUsernameRequest
@@ -74,64 +75,215 @@ func (h *AccountHandler) LoginByUsername(c *gin.Context) {
 			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
 		return
 	}
+	req.ClientIp = c.ClientIP()
 	td, err := h.Usecase.LoginByUsername(c, &req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 
-	// Set the refresh token in a cookie
-	// Set the new refresh token in a cookie
-	http.SetCookie(c.Writer, &http.Cookie{
-		Name:     constants.RefreshTokenCookieName,
-		Value:    td.RefreshToken,
-		MaxAge:   int(h.Cfg.JWT.RefreshTokenExpireDuration * 60),
-		Path:     "/",
-		Domain:   h.Cfg.Server.Domain,
-		Secure:   true,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
-	})
+	h.setRefreshTokenCookie(c, td.RefreshToken, int(h.Cfg.JWT.RefreshTokenExpireDuration*60))
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(td, true, helper.Success))
 }
 
 // RefreshToken godoc
 // @Summary RefreshToken
-// @Description RefreshToken
+// @Description Exchanges a refresh token from the body or the refresh token cookie for a new token pair, every refresh token can be used once
 // @Tags Account
 // @Accept  json
 // @Produce  json
+// @Param Request body dto.RefreshTokenRequest false "RefreshTokenRequest"
 // @Success 200 {object} helper.BaseHttpResponse "Success"
 // @Failure 400 {object} helper.BaseHttpResponse "Failed"
 // @Failure 401 {object} helper.BaseHttpResponse "Failed"
 // @Router /v1/account/refresh-token [post]
 func (h *AccountHandler) RefreshToken(c *gin.Context) {
-	// Get the refresh token from the cookie
-	refreshToken, err := c.Cookie(constants.RefreshTokenCookieName)
+	var req dto.RefreshTokenRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+			return
+		}
+	}
+	refreshToken := req.RefreshToken
+	if refreshToken == "" {
+		// Fall back to the refresh token cookie
+		var err error
+		refreshToken, err = c.Cookie(constants.RefreshTokenCookieName)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest,
+				helper.GenerateBaseResponseWithError(nil, false, helper.AuthError, err))
+			return
+		}
+	}
+	// Call the usecase to rotate the token
+	td, err := h.Usecase.RefreshToken(c, refreshToken)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	h.setRefreshTokenCookie(c, td.RefreshToken, int(h.Cfg.JWT.RefreshTokenExpireDuration*60))
+	// Return the token details
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(td, true, helper.Success))
+}
+
+// Logout godoc
+// @Summary Logout
+// @Description Revokes the refresh tokens of the current session
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/logout [post]
+// @Security AuthBearer
+func (h *AccountHandler) Logout(c *gin.Context) {
+	err := h.Usecase.Logout(c, c.GetString(constants.SessionIdKey))
 	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	h.setRefreshTokenCookie(c, "", -1)
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse("Logged out", true, helper.Success))
+}
+
+// LogoutAll godoc
+// @Summary LogoutAll
+// @Description Revokes the refresh tokens of every session of the current user
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/logout-all [post]
+// @Security AuthBearer
+func (h *AccountHandler) LogoutAll(c *gin.Context) {
+	err := h.Usecase.LogoutAll(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	h.setRefreshTokenCookie(c, "", -1)
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse("Logged out of all sessions", true, helper.Success))
+}
+
+// GetProfile godoc
+// @Summary GetProfile
+// @Description Returns the profile of the current user
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UserResponse} "Success"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/me [get]
+// @Security AuthBearer
+func (h *AccountHandler) GetProfile(c *gin.Context) {
+	res, err := h.Usecase.GetProfile(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// UpdateProfile godoc
+// @Summary UpdateProfile
+// @Description Updates the first name, last name, email and mobile number of the current user, empty fields are left unchanged
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Param Request body dto.UpdateProfileRequest true "UpdateProfileRequest"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UserResponse} "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Failed"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Failure 409 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/me [put]
+// @Security AuthBearer
+func (h *AccountHandler) UpdateProfile(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest,
-			helper.GenerateBaseResponseWithError(nil, false, helper.AuthError, err))
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
 		return
 	}
-	// Call the usecase to refresh the token
-	td, err := h.Usecase.RefreshToken(refreshToken)
+	res, err := h.Usecase.UpdateProfile(c, &req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// DeleteAccount godoc
+// @Summary DeleteAccount
+// @Description Deletes the account of the current user together with its workouts
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/me [delete]
+// @Security AuthBearer
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	err := h.Usecase.DeleteAccount(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	h.setRefreshTokenCookie(c, "", -1)
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse("Account deleted", true, helper.Success))
+}
+
+// ChangePassword godoc
+// @Summary ChangePassword
+// @Description Changes the password of the current user, the new password must meet the password policy. Every session is logged out.
+// @Tags Account
+// @Accept  json
+// @Produce  json
+// @Param Request body dto.ChangePasswordRequest true "ChangePasswordRequest"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Failed"
+// @Failure 401 {object} helper.BaseHttpResponse "Failed"
+// @Router /v1/account/change-password [post]
+// @Security AuthBearer
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+	err := h.Usecase.ChangePassword(c, &req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	h.setRefreshTokenCookie(c, "", -1)
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse("Password changed", true, helper.Success))
+}
+
+// setRefreshTokenCookie writes the refresh token cookie when it is enabled, a negative max age removes it
+func (h *AccountHandler) setRefreshTokenCookie(c *gin.Context, refreshToken string, maxAge int) {
+	if !h.Cfg.JWT.RefreshTokenCookie {
 		return
 	}
-	// Set the new refresh token in a cookie
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     constants.RefreshTokenCookieName,
-		Value:    td.RefreshToken,
-		MaxAge:   int(h.Cfg.JWT.RefreshTokenExpireDuration * 60),
+		Value:    refreshToken,
+		MaxAge:   maxAge,
 		Path:     "/",
 		Domain:   h.Cfg.Server.Domain,
 		Secure:   true,
 		HttpOnly: true,
 		SameSite: http.SameSiteStrictMode,
 	})
-	// Return the token details
-	c.JSON(http.StatusOK, helper.GenerateBaseResponse(td, true, helper.Success))
 }
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	_ "github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler serves the user management endpoints of admins
+type UserHandler struct {
+	Usecase *usecase.UserUsecase
+}
+
+// NewUserHandler ...
+func NewUserHandler(cfg *config.Config) *UserHandler {
+	repo, token := dependency.GetUserRepository(cfg)
+	return &UserHandler{
+		Usecase: usecase.NewUserUsecase(cfg, repo, token, dependency.GetRefreshTokenRepository(), dependency.GetRateLimitStore(cfg)),
+	}
+}
+
+// GetUsersByFilter godoc
+// @Summary Get Users by Filter
+// @Description Get Users by Filter, admin only
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.UserResponse]} "Users response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Router /v1/users/get-by-filter [post]
+// @Security AuthBearer
+func (h *UserHandler) GetByFilter(c *gin.Context) {
+	req := filter.PaginationInputWithFilter{}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+	res, err := h.Usecase.GetUsers(c, req)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+// DisableUser godoc
+// @Summary Disable a User
+// @Description Disable a User so that they can no longer login, admin only
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UserResponse} "User response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/users/{id}/disable [put]
+// @Security AuthBearer
+func (h *UserHandler) Disable(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+// EnableUser godoc
+// @Summary Enable a User
+// @Description Enable a disabled User, admin only
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UserResponse} "User response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/users/{id}/enable [put]
+// @Security AuthBearer
+func (h *UserHandler) Enable(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+// UnlockUser godoc
+// @Summary Unlock a User
+// @Description Clear the failed login attempts and the lock of a User, admin only
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.UserResponse} "User response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/users/{id}/unlock [put]
+// @Security AuthBearer
+func (h *UserHandler) Unlock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Params.ByName("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err))
+		return
+	}
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, errors.New("invalid id")))
+		return
+	}
+	res, err := h.Usecase.UnlockUser(c, id)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
+
+func (h *UserHandler) setEnabled(c *gin.Context, enabled bool) {
+	id, err := strconv.Atoi(c.Params.ByName("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err))
+		return
+	}
+	if id == 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, errors.New("invalid id")))
+		return
+	}
+	res, err := h.Usecase.SetUserEnabled(c, id, enabled)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(res, true, helper.Success))
+}
@@ -1,15 +1,33 @@
 package router
 
 import (
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
 	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/user/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/gin-gonic/gin"
 )
 
-func Account(router *gin.RouterGroup, cfg *config.Config) {
+func Account(router *gin.RouterGroup, cfg *config.Config, tokenProvider port.TokenProvider) {
 	handler := handler.NewAccountHandler(cfg)
 	router.POST("/register", handler.RegisterByUsername)
 	router.POST("/login", handler.LoginByUsername)
 	router.POST("/refresh-token", handler.RefreshToken)
+	router.POST("/logout", middlewares.Authentication(cfg, tokenProvider), handler.Logout)
+	router.POST("/logout-all", middlewares.Authentication(cfg, tokenProvider), handler.LogoutAll)
+	router.POST("/change-password", middlewares.Authentication(cfg, tokenProvider), handler.ChangePassword)
+	router.GET("/me", middlewares.Authentication(cfg, tokenProvider), handler.GetProfile)
+	router.PUT("/me", middlewares.Authentication(cfg, tokenProvider), handler.UpdateProfile)
+	router.DELETE("/me", middlewares.Authentication(cfg, tokenProvider), handler.DeleteAccount)
 
 }
+
+func User(router *gin.RouterGroup, cfg *config.Config, tokenProvider port.TokenProvider) {
+	handler := handler.NewUserHandler(cfg)
+	router.Use(middlewares.Authentication(cfg, tokenProvider), middlewares.Authorization(constants.AdminRoleName))
+	router.POST("/get-by-filter", handler.GetByFilter)
+	router.PUT("/:id/disable", handler.Disable)
+	router.PUT("/:id/enable", handler.Enable)
+	router.PUT("/:id/unlock", handler.Unlock)
+}
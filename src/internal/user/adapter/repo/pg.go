@@ -2,44 +2,87 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"log"
+	"time"
 
 	"github.com/alielmi98/go-hexa-workout/constants"
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
 	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 
 	"gorm.io/gorm"
 )
 
+// AccountCleanup soft deletes the rows another module keeps for a user with the values of deleteMap.
+// The modules register their cleanup with the repository, which runs it in the transaction that deletes the account.
+type AccountCleanup func(tx *gorm.DB, userId int, deleteMap map[string]interface{}) error
+
 type PgRepo struct {
-	db *gorm.DB
+	db       *gorm.DB
+	cleanups []AccountCleanup
+}
+
+func NewUserPgRepo(cleanups ...AccountCleanup) *PgRepo {
+	return NewUserPgRepoWithDb(db.GetDb(), cleanups...)
 }
 
-func NewUserPgRepo() *PgRepo {
-	return &PgRepo{db: db.GetDb()}
+// NewUserPgRepoWithDb uses the given connection instead of the shared one
+func NewUserPgRepoWithDb(database *gorm.DB, cleanups ...AccountCleanup) *PgRepo {
+	return &PgRepo{db: database, cleanups: cleanups}
 }
 
+// Create inserts the user and grants it the default role
 func (r *PgRepo) Create(ctx context.Context, user *model.User) error {
 	tx := r.db.WithContext(ctx).Begin()
 	err := tx.Create(&user).Error
 	if err != nil {
 		tx.Rollback()
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Rollback, err.Error())
-		return err
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
+	}
+	err = addRole(tx, user.Id, constants.DefaultRoleName)
+	if err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
 	}
 	tx.Commit()
 	return nil
 }
+
+func (r *PgRepo) AddRole(ctx context.Context, userId int, roleName string) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if err := addRole(tx, userId, roleName); err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
+	}
+	tx.Commit()
+	return nil
+}
+
+func addRole(tx *gorm.DB, userId int, roleName string) error {
+	var role model.Role
+	err := tx.Model(&model.Role{}).
+		Where("name = ?", roleName).
+		First(&role).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&model.UserRole{UserId: userId, RoleId: role.Id}).Error
+}
+
 func (r *PgRepo) GetByID(ctx context.Context, id int) (*model.User, error) {
 	var user model.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("UserRoles.Role").Where("deleted_by is null").First(&user, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return nil, service_errors.New(service_errors.CodeRecordNotFound)
 		}
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Select, err.Error())
-		return nil, err
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return nil, db.TranslateError(err)
 	}
 	return &user, nil
 }
@@ -48,34 +91,159 @@ func (r *PgRepo) Update(ctx context.Context, id int, user *model.User) error {
 	tx := r.db.WithContext(ctx).Begin()
 	if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(user).Error; err != nil {
 		tx.Rollback()
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Rollback, err.Error())
-		return err
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
 	}
 	tx.Commit()
 	return nil
 }
+
+// Delete soft deletes the user and runs the cleanups of the other modules in the same transaction
 func (r *PgRepo) Delete(ctx context.Context, id int) error {
+	if ctx.Value(constants.UserIdKey) == nil {
+		return service_errors.New(service_errors.CodePermissionDenied)
+	}
+	deleteMap := map[string]interface{}{
+		"deleted_by": &sql.NullInt64{Int64: int64(ctx.Value(constants.UserIdKey).(float64)), Valid: true},
+		"deleted_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+	}
+
 	tx := r.db.WithContext(ctx).Begin()
-	if err := tx.Where("id = ?", id).Delete(&model.User{}).Error; err != nil {
+	result := tx.Model(&model.User{}).
+		Where("id = ? and deleted_by is null", id).
+		Updates(deleteMap)
+	if result.Error != nil {
 		tx.Rollback()
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Rollback, err.Error())
-		return err
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, result.Error.Error(), nil)
+		return db.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+
+	for _, cleanup := range r.cleanups {
+		if err := cleanup(tx, id, deleteMap); err != nil {
+			tx.Rollback()
+			logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+			return db.TranslateError(err)
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (r *PgRepo) SetEnabled(ctx context.Context, id int, enabled bool) error {
+	tx := r.db.WithContext(ctx).Begin()
+	// Updates with a map so that false is not skipped as a zero value
+	result := tx.Model(&model.User{}).
+		Where("id = ? and deleted_by is null", id).
+		Updates(map[string]interface{}{"enabled": enabled})
+	if result.Error != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, result.Error.Error(), nil)
+		return db.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	tx.Commit()
+	return nil
+}
+
+func (r *PgRepo) AddFailedLogin(ctx context.Context, id int, maxAttempts int, lockedUntil time.Time) error {
+	tx := r.db.WithContext(ctx).Begin()
+	err := tx.Model(&model.User{}).
+		Where("id = ?", id).
+		UpdateColumn("failed_login_attempts", gorm.Expr("failed_login_attempts + 1")).
+		Error
+	if err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
+	}
+	// The increment and the lock are in one transaction so concurrent attempts are not lost
+	err = tx.Model(&model.User{}).
+		Where("id = ? and failed_login_attempts >= ?", id, maxAttempts).
+		UpdateColumns(map[string]interface{}{
+			"failed_login_attempts": 0,
+			"locked_until":          sql.NullTime{Valid: true, Time: lockedUntil.UTC()},
+		}).Error
+	if err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return db.TranslateError(err)
 	}
 	tx.Commit()
 	return nil
 }
 
+func (r *PgRepo) Unlock(ctx context.Context, id int) error {
+	tx := r.db.WithContext(ctx).Begin()
+	result := tx.Model(&model.User{}).
+		Where("id = ? and deleted_by is null", id).
+		UpdateColumns(map[string]interface{}{"failed_login_attempts": 0, "locked_until": sql.NullTime{}})
+	if result.Error != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, result.Error.Error(), nil)
+		return db.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	tx.Commit()
+	return nil
+}
+
+func (r *PgRepo) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]model.User, error) {
+	var users *[]model.User
+	query, args, err := db.GenerateDynamicQuery[model.User](&req.DynamicFilter)
+	if err != nil {
+		return 0, &[]model.User{}, err
+	}
+	sort := db.GenerateDynamicSort[model.User](&req.DynamicFilter)
+	var totalRows int64 = 0
+
+	database := r.db.WithContext(ctx)
+	err = database.
+		Model(&model.User{}).
+		Where(query, args...).
+		Count(&totalRows).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return 0, &[]model.User{}, db.TranslateError(err)
+	}
+
+	err = database.
+		Preload("UserRoles.Role").
+		Where(query, args...).
+		Offset(req.GetOffset()).
+		Limit(req.GetPageSize()).
+		Order(sort).
+		Find(&users).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return 0, &[]model.User{}, db.TranslateError(err)
+	}
+	return totalRows, users, nil
+}
+
 func (r *PgRepo) FindByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
 	err := r.db.WithContext(ctx).
 		Model(&model.User{}).
-		Where("username = ?", username).
+		Preload("UserRoles.Role").
+		Where("username = ? and deleted_by is null", username).
 		First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, &service_errors.ServiceError{EndUserMessage: service_errors.UsernameOrPasswordInvalid}
+			return nil, service_errors.New(service_errors.CodeUsernameOrPasswordInvalid)
 		}
-		return nil, err
+		return nil, db.TranslateError(err)
 	}
 	return &user, nil
 }
@@ -87,7 +255,20 @@ func (r *PgRepo) ExistsByEmail(email string) (bool, error) {
 		Where("email = ?", email).
 		Find(&exists).
 		Error; err != nil {
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Select, err.Error())
+		logging.GetLogger().Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *PgRepo) ExistsByMobileNumber(mobileNumber string) (bool, error) {
+	var exists bool
+	if err := r.db.Model(&model.User{}).
+		Select("count(*) > 0").
+		Where("mobile_number = ?", mobileNumber).
+		Find(&exists).
+		Error; err != nil {
+		logging.GetLogger().Error(constants.Postgres, constants.Select, err.Error(), nil)
 		return false, err
 	}
 	return exists, nil
@@ -100,7 +281,7 @@ func (r *PgRepo) ExistsByUsername(username string) (bool, error) {
 		Where("username = ?", username).
 		Find(&exists).
 		Error; err != nil {
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Select, err.Error())
+		logging.GetLogger().Error(constants.Postgres, constants.Select, err.Error(), nil)
 		return false, err
 	}
 	return exists, nil
@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+
+	"gorm.io/gorm"
+)
+
+type RefreshTokenPgRepo struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenPgRepo() *RefreshTokenPgRepo {
+	return &RefreshTokenPgRepo{db: db.GetDb()}
+}
+
+func (r *RefreshTokenPgRepo) Create(ctx context.Context, token *model.RefreshToken) error {
+	if err := r.db.WithContext(ctx).Create(token).Error; err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Insert, err.Error(), nil)
+		return err
+	}
+	return nil
+}
+
+func (r *RefreshTokenPgRepo) GetByTokenId(ctx context.Context, tokenId string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("token_id = ?", tokenId).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, service_errors.New(service_errors.CodeRecordNotFound)
+		}
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *RefreshTokenPgRepo) Rotate(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+	tx := r.db.WithContext(ctx).Begin()
+	// Only a token that is still active can be revoked, so two concurrent refreshes can not both succeed
+	result := tx.Model(&model.RefreshToken{}).
+		Where("token_id = ? and revoked_at is null", tokenId).
+		Updates(map[string]interface{}{"revoked_at": time.Now().UTC()})
+	if result.Error != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, result.Error.Error(), nil)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return service_errors.New(service_errors.CodeRefreshTokenReused)
+	}
+	if err := tx.Create(next).Error; err != nil {
+		tx.Rollback()
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Rollback, err.Error(), nil)
+		return err
+	}
+	tx.Commit()
+	return nil
+}
+
+func (r *RefreshTokenPgRepo) RevokeSession(ctx context.Context, sessionId string) error {
+	return r.revoke(ctx, "session_id = ? and revoked_at is null", sessionId)
+}
+
+func (r *RefreshTokenPgRepo) RevokeAllForUser(ctx context.Context, userId int) error {
+	return r.revoke(ctx, "user_id = ? and revoked_at is null", userId)
+}
+
+func (r *RefreshTokenPgRepo) revoke(ctx context.Context, query string, args ...interface{}) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.RefreshToken{}).
+		Where(query, args...).
+		Updates(map[string]interface{}{"revoked_at": time.Now().UTC()}).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, err.Error(), nil)
+		return err
+	}
+	return nil
+}
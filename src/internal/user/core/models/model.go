@@ -15,8 +15,62 @@ type User struct {
 	LastName     string `gorm:"type:string;size:25;null"`
 	MobileNumber string `gorm:"type:string;size:11;null;unique;default:null"`
 	Email        string `gorm:"type:string;size:64;null;unique;default:null"`
-	Password     string `gorm:"type:string;size:64;not null"`
+	Password     string `gorm:"type:string;size:64;not null" filter:"-"`
 	Enabled      bool   `gorm:"default:true"`
+	UserRoles    *[]UserRole
+
+	FailedLoginAttempts int          `gorm:"type:int;not null;default:0" filter:"-"`
+	LockedUntil         sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+type Role struct {
+	Id        int    `gorm:"primarykey"`
+	Name      string `gorm:"type:string;size:10;not null;unique"`
+	UserRoles *[]UserRole
+
+	CreatedAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+type UserRole struct {
+	Id     int  `gorm:"primarykey"`
+	User   User `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	Role   Role `gorm:"foreignKey:RoleId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId int  `gorm:"not null;uniqueIndex:idx_user_role"`
+	RoleId int  `gorm:"not null;uniqueIndex:idx_user_role"`
+
+	CreatedAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// RefreshToken is an issued refresh token. Tokens rotated from the same login share the session id
+// and a token is revoked once it has been exchanged for a new one.
+type RefreshToken struct {
+	Id        int          `gorm:"primarykey"`
+	User      User         `gorm:"foreignKey:UserId;constraint:OnUpdate:NO ACTION;OnDelete:NO ACTION"`
+	UserId    int          `gorm:"not null;index"`
+	TokenId   string       `gorm:"type:string;size:32;not null;unique"`
+	SessionId string       `gorm:"type:string;size:32;not null;index"`
+	ExpiresAt time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
+	RevokedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
 
 	CreatedAt  time.Time    `gorm:"type:TIMESTAMP with time zone;not null"`
 	ModifiedAt sql.NullTime `gorm:"type:TIMESTAMP with time zone;null"`
@@ -27,6 +81,18 @@ type User struct {
 	DeletedBy  *sql.NullInt64 `gorm:"null"`
 }
 
+// RoleNames returns the names of the loaded roles of the user
+func (m *User) RoleNames() []string {
+	roles := []string{}
+	if m.UserRoles == nil {
+		return roles
+	}
+	for _, ur := range *m.UserRoles {
+		roles = append(roles, ur.Role.Name)
+	}
+	return roles
+}
+
 func (m *User) BeforeCreate(tx *gorm.DB) (err error) {
 	value := tx.Statement.Context.Value(constants.UserIdKey)
 	var userId = -1
@@ -59,3 +125,105 @@ func (m *User) BeforeDelete(tx *gorm.DB) (err error) {
 	m.DeletedBy = userId
 	return
 }
+
+// GORM hooks for Role
+func (m *Role) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *Role) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *Role) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for UserRole
+func (m *UserRole) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *UserRole) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *UserRole) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for RefreshToken
+func (m *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *RefreshToken) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *RefreshToken) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
@@ -2,29 +2,40 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
 
+	"github.com/alielmi98/go-hexa-workout/common"
 	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
 	"github.com/alielmi98/go-hexa-workout/internal/user/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserUsecase struct {
-	cfg   *config.Config
-	repo  port.UserRepository
-	token port.TokenProvider
+	cfg      *config.Config
+	repo     port.UserRepository
+	token    port.TokenProvider
+	sessions port.RefreshTokenRepository
+	attempts limiter.Store
 }
 
-func NewUserUsecase(cfg *config.Config, repository port.UserRepository, token port.TokenProvider) *UserUsecase {
+func NewUserUsecase(cfg *config.Config, repository port.UserRepository, token port.TokenProvider, sessions port.RefreshTokenRepository, attempts limiter.Store) *UserUsecase {
 	return &UserUsecase{
-		cfg:   cfg,
-		repo:  repository,
-		token: token,
+		cfg:      cfg,
+		repo:     repository,
+		token:    token,
+		sessions: sessions,
+		attempts: attempts,
 	}
 }
 
@@ -36,19 +47,22 @@ func (s *UserUsecase) RegisterByUsername(ctx context.Context, req *dto.RegisterU
 		LastName:  req.LastName,
 		Email:     req.Email,
 	}
+	if err := s.checkPasswordPolicy("password", req.Password); err != nil {
+		return err
+	}
 	// Check if username already exists
 	if existing, _ := s.repo.ExistsByUsername(req.Username); existing {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.UsernameExists}
+		return service_errors.New(service_errors.CodeUsernameExists)
 	}
 	// Check if email already exists
 	if existing, _ := s.repo.ExistsByEmail(req.Email); existing {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.EmailExists}
+		return service_errors.New(service_errors.CodeEmailExists)
 	}
 	// Hash password
 	bp := []byte(req.Password)
 	hp, err := bcrypt.GenerateFromPassword(bp, bcrypt.DefaultCost)
 	if err != nil {
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.General, constants.HashPassword, err.Error())
+		logging.FromContext(ctx).Error(constants.General, constants.HashPassword, err.Error(), nil)
 		return err
 	}
 	req.Password = string(hp)
@@ -62,31 +76,394 @@ func (s *UserUsecase) RegisterByUsername(ctx context.Context, req *dto.RegisterU
 
 }
 
+// unknownUserHash is compared with the password of an unknown username
+const unknownUserHash = "$2a$10$rRAVLw6gp4Rg6czQmLnKMuJAe5I0BbpU9CsyVxd.UzTfsaNjt8aCa"
+
 func (s *UserUsecase) LoginByUsername(ctx context.Context, req *dto.LoginByUsernameRequest) (*dto.TokenDetail, error) {
+	if err := s.checkIpAttempts(ctx, req.ClientIp); err != nil {
+		return nil, err
+	}
 	user, err := s.repo.FindByUsername(ctx, req.Username)
 	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeUsernameOrPasswordInvalid) {
+			// Compared anyway so an unknown username takes as long as a wrong password
+			_ = bcrypt.CompareHashAndPassword([]byte(unknownUserHash), []byte(req.Password))
+			s.addFailedIpAttempt(ctx, req.ClientIp)
+		}
 		return nil, err
 	}
+	// A locked account answers like a wrong password, even to the right one, so the response
+	// does not tell which usernames exist or are locked
+	locked := user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time)
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	if err != nil || locked {
+		s.addFailedIpAttempt(ctx, req.ClientIp)
+		if !locked && s.cfg.Lockout.MaxFailedAttempts > 0 {
+			lockedUntil := time.Now().Add(s.cfg.Lockout.Duration * time.Minute)
+			if err := s.repo.AddFailedLogin(ctx, user.Id, s.cfg.Lockout.MaxFailedAttempts, lockedUntil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, service_errors.New(service_errors.CodeUsernameOrPasswordInvalid)
+	}
+	if user.FailedLoginAttempts > 0 || user.LockedUntil.Valid {
+		if err := s.repo.Unlock(ctx, user.Id); err != nil {
+			return nil, err
+		}
+	}
+	if !user.Enabled {
+		return nil, service_errors.New(service_errors.CodeUserDisabled)
+	}
+
+	sessionId, err := newTokenId()
+	if err != nil {
+		return nil, err
+	}
+	token, refreshToken, err := s.issueTokens(user, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.Create(ctx, refreshToken)
 	if err != nil {
-		return nil, &service_errors.ServiceError{EndUserMessage: service_errors.UsernameOrPasswordInvalid}
+		return nil, err
 	}
+	return token, nil
+}
 
-	tdto := entity.TokenPayload{UserId: user.Id, FirstName: user.FirstName, LastName: user.LastName,
-		Username: user.Username, Email: user.Email, MobileNumber: user.MobileNumber}
+// RefreshToken exchanges a refresh token for a new token pair. Every refresh token can be used once,
+// presenting a used or revoked token again revokes the whole session.
+func (s *UserUsecase) RefreshToken(ctx context.Context, refreshToken string) (*dto.TokenDetail, error) {
+	claims, err := s.token.GetRefreshClaims(refreshToken)
+	if err != nil {
+		return nil, service_errors.Wrap(service_errors.CodeInvalidRefreshToken, err)
+	}
+	tokenId, _ := claims[constants.TokenIdKey].(string)
+	if tokenId == "" {
+		return nil, service_errors.New(service_errors.CodeInvalidRefreshToken)
+	}
+	stored, err := s.sessions.GetByTokenId(ctx, tokenId)
+	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+			return nil, service_errors.New(service_errors.CodeInvalidRefreshToken)
+		}
+		return nil, err
+	}
+	if stored.RevokedAt.Valid {
+		return nil, s.revokeReusedSession(ctx, stored)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		if err := s.sessions.RevokeSession(ctx, stored.SessionId); err != nil {
+			return nil, err
+		}
+		return nil, service_errors.New(service_errors.CodeInvalidRefreshToken)
+	}
 
-	token, err := s.token.GenerateToken(&tdto)
+	// Load the user again so disabled users are logged out and role changes are picked up
+	user, err := s.repo.GetByID(ctx, stored.UserId)
 	if err != nil {
 		return nil, err
 	}
+	if !user.Enabled {
+		if err := s.sessions.RevokeSession(ctx, stored.SessionId); err != nil {
+			return nil, err
+		}
+		return nil, service_errors.New(service_errors.CodeUserDisabled)
+	}
+
+	token, next, err := s.issueTokens(user, stored.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.Rotate(ctx, tokenId, next)
+	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeRefreshTokenReused) {
+			return nil, s.revokeReusedSession(ctx, stored)
+		}
+		return nil, err
+	}
 	return token, nil
 }
 
-func (s *UserUsecase) RefreshToken(refreshToken string) (*dto.TokenDetail, error) {
-	tokenDetail, err := s.token.RefreshToken(refreshToken)
+// Logout revokes the refresh tokens of the session the access token belongs to
+func (s *UserUsecase) Logout(ctx context.Context, sessionId string) error {
+	if sessionId == "" {
+		return service_errors.New(service_errors.CodeTokenInvalid)
+	}
+	return s.sessions.RevokeSession(ctx, sessionId)
+}
+
+// LogoutAll revokes the refresh tokens of every session of the current user
+func (s *UserUsecase) LogoutAll(ctx context.Context) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	return s.sessions.RevokeAllForUser(ctx, userId)
+}
+
+// revokeReusedSession ends a session whose refresh token was presented twice, the token may have been stolen
+func (s *UserUsecase) revokeReusedSession(ctx context.Context, token *model.RefreshToken) error {
+	logging.FromContext(ctx).Warn(constants.Internal, constants.UseCase, "refresh token was reused, revoking the session",
+		map[constants.ExtraKey]interface{}{constants.UserId: token.UserId, constants.SessionId: token.SessionId})
+	if err := s.sessions.RevokeSession(ctx, token.SessionId); err != nil {
+		return err
+	}
+	return service_errors.New(service_errors.CodeRefreshTokenReused)
+}
+
+// issueTokens generates a token pair for the session and the record of its refresh token
+func (s *UserUsecase) issueTokens(user *model.User, sessionId string) (*dto.TokenDetail, *model.RefreshToken, error) {
+	tokenId, err := newTokenId()
+	if err != nil {
+		return nil, nil, err
+	}
+	tdto := entity.TokenPayload{UserId: user.Id, FirstName: user.FirstName, LastName: user.LastName,
+		Username: user.Username, Email: user.Email, MobileNumber: user.MobileNumber, Roles: user.RoleNames(),
+		SessionId: sessionId, TokenId: tokenId}
+
+	token, err := s.token.GenerateToken(&tdto)
+	if err != nil {
+		return nil, nil, err
+	}
+	refreshToken := &model.RefreshToken{
+		UserId:    user.Id,
+		TokenId:   tokenId,
+		SessionId: sessionId,
+		ExpiresAt: time.Unix(token.RefreshTokenExpireTime, 0).UTC(),
+	}
+	return token, refreshToken, nil
+}
+
+func newTokenId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GetProfile returns the current user
+func (s *UserUsecase) GetProfile(ctx context.Context) (dto.UserResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	user, err := s.repo.GetByID(ctx, userId)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return toUserResponse(user), nil
+}
+
+// UpdateProfile changes the personal details of the current user, empty fields are left unchanged
+func (s *UserUsecase) UpdateProfile(ctx context.Context, req *dto.UpdateProfileRequest) (dto.UserResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	user, err := s.repo.GetByID(ctx, userId)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	// Check if email already belongs to another user
+	if req.Email != "" && req.Email != user.Email {
+		if existing, _ := s.repo.ExistsByEmail(req.Email); existing {
+			return dto.UserResponse{}, service_errors.New(service_errors.CodeEmailExists)
+		}
+	}
+	// Check if mobile number already belongs to another user
+	if req.MobileNumber != "" && req.MobileNumber != user.MobileNumber {
+		if existing, _ := s.repo.ExistsByMobileNumber(req.MobileNumber); existing {
+			return dto.UserResponse{}, service_errors.New(service_errors.CodeMobileNumberExists)
+		}
+	}
+
+	err = s.repo.Update(ctx, userId, &model.User{
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		MobileNumber: req.MobileNumber,
+		Email:        req.Email,
+	})
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return s.GetProfile(ctx)
+}
+
+// DeleteAccount soft deletes the current user with all of its workouts and logs out every session
+func (s *UserUsecase) DeleteAccount(ctx context.Context) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.repo.Delete(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return s.sessions.RevokeAllForUser(ctx, userId)
+}
+
+// ChangePassword replaces the password of the current user after verifying the old one.
+// All refresh tokens of the user are revoked so every session has to log in again.
+// There is no reset by email, a user locked out by failed logins waits for the lock to expire
+// or is unlocked by an admin, a password change only lifts a lock of a user still logged in.
+func (s *UserUsecase) ChangePassword(ctx context.Context, req *dto.ChangePasswordRequest) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.repo.GetByID(ctx, userId)
+	if err != nil {
+		return err
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword))
+	if err != nil {
+		return service_errors.New(service_errors.CodeOldPasswordInvalid)
+	}
+	if err := s.checkPasswordPolicy("newPassword", req.NewPassword); err != nil {
+		return err
+	}
+
+	hp, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.General, constants.HashPassword, err.Error(), nil)
+		return err
+	}
+	err = s.repo.Update(ctx, user.Id, &model.User{Password: string(hp)})
+	if err != nil {
+		return err
+	}
+	// A new password also lifts a lock caused by someone guessing the old one
+	if user.FailedLoginAttempts > 0 || user.LockedUntil.Valid {
+		err = s.repo.Unlock(ctx, user.Id)
+		if err != nil {
+			return err
+		}
+	}
+	return s.sessions.RevokeAllForUser(ctx, user.Id)
+}
+
+// checkIpAttempts rejects the login when the client ip has too many failed attempts in the current window
+func (s *UserUsecase) checkIpAttempts(ctx context.Context, clientIp string) error {
+	if s.cfg.Lockout.MaxFailedAttemptsPerIp <= 0 || clientIp == "" {
+		return nil
+	}
+	count, _, err := s.attempts.Get(ctx, loginAttemptKey(clientIp))
+	if err != nil {
+		// The attempts store being down should not block every login
+		logging.FromContext(ctx).Error(constants.Redis, constants.ExternalService, err.Error(), nil)
+		return nil
+	}
+	if count >= s.cfg.Lockout.MaxFailedAttemptsPerIp {
+		return service_errors.New(service_errors.CodeTooManyLoginAttempts)
+	}
+	return nil
+}
+
+func (s *UserUsecase) addFailedIpAttempt(ctx context.Context, clientIp string) {
+	if s.cfg.Lockout.MaxFailedAttemptsPerIp <= 0 || clientIp == "" {
+		return
+	}
+	_, _, err := s.attempts.Take(ctx, loginAttemptKey(clientIp), s.cfg.Lockout.IpWindow*time.Minute)
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Redis, constants.ExternalService, err.Error(), nil)
+	}
+}
+
+func loginAttemptKey(clientIp string) string {
+	return "login-failed:ip:" + clientIp
+}
+
+// checkPasswordPolicy validates the password against the configured password policy
+func (s *UserUsecase) checkPasswordPolicy(property string, password string) error {
+	violations := validation.CheckPassword(&s.cfg.Password, property, password)
+	if violations != nil {
+		return service_errors.Wrap(service_errors.CodePasswordPolicyViolation, violations)
+	}
+	return nil
+}
+
+// GetUsers lists the users for admins
+func (s *UserUsecase) GetUsers(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.UserResponse], error) {
+	count, users, err := s.repo.GetByFilter(ctx, req)
 	if err != nil {
 		return nil, err
 	}
+	items := make([]dto.UserResponse, 0, len(*users))
+	for _, user := range *users {
+		items = append(items, toUserResponse(&user))
+	}
+	return filter.NewPagedList(&items, count, req.GetPageNumber(), int64(req.GetPageSize())), nil
+}
+
+// SetUserEnabled enables or disables the login of a user
+func (s *UserUsecase) SetUserEnabled(ctx context.Context, id int, enabled bool) (dto.UserResponse, error) {
+	err := s.repo.SetEnabled(ctx, id, enabled)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return toUserResponse(user), nil
+}
+
+// UnlockUser clears the failed login attempts and the lock of a user
+func (s *UserUsecase) UnlockUser(ctx context.Context, id int) (dto.UserResponse, error) {
+	err := s.repo.Unlock(ctx, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return toUserResponse(user), nil
+}
+
+// EnsureAdminUser creates the configured admin account when it does not exist yet.
+// An existing user with the same username is left untouched.
+func (s *UserUsecase) EnsureAdminUser(ctx context.Context) error {
+	if s.cfg.Admin.Password == "" {
+		return nil
+	}
+	username := s.cfg.Admin.Username
+	if username == "" {
+		username = constants.DefaultUserName
+	}
+	exists, err := s.repo.ExistsByUsername(username)
+	if err != nil || exists {
+		return err
+	}
 
-	return tokenDetail, nil
+	hp, err := bcrypt.GenerateFromPassword([]byte(s.cfg.Admin.Password), bcrypt.DefaultCost)
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.General, constants.HashPassword, err.Error(), nil)
+		return err
+	}
+	u := &model.User{Username: username, FirstName: username, Password: string(hp), Enabled: true}
+	err = s.repo.Create(ctx, u)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddRole(ctx, u.Id, constants.AdminRoleName)
+}
+
+func toUserResponse(user *model.User) dto.UserResponse {
+	var lockedUntil *time.Time
+	if user.LockedUntil.Valid && time.Now().Before(user.LockedUntil.Time) {
+		lockedUntil = &user.LockedUntil.Time
+	}
+	return dto.UserResponse{
+		Id:           user.Id,
+		Username:     user.Username,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		MobileNumber: user.MobileNumber,
+		Email:        user.Email,
+		Enabled:      user.Enabled,
+		LockedUntil:  lockedUntil,
+		Roles:        user.RoleNames(),
+	}
 }
@@ -7,4 +7,7 @@ type TokenPayload struct {
 	Username     string
 	MobileNumber string
 	Email        string
+	Roles        []string
+	SessionId    string
+	TokenId      string
 }
@@ -10,5 +10,5 @@ type TokenProvider interface {
 	GenerateToken(token *entity.TokenPayload) (*dto.TokenDetail, error)
 	VerifyToken(token string) (*jwt.Token, error)
 	GetClaims(token string) (map[string]interface{}, error)
-	RefreshToken(refreshToken string) (*dto.TokenDetail, error)
+	GetRefreshClaims(refreshToken string) (map[string]interface{}, error)
 }
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+)
+
+// RefreshTokenRepository keeps track of the issued refresh tokens so they can be used once and revoked
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *model.RefreshToken) error
+	GetByTokenId(ctx context.Context, tokenId string) (*model.RefreshToken, error)
+	// Rotate revokes the token and stores its replacement, it fails with RefreshTokenReused
+	// when the token has already been revoked
+	Rotate(ctx context.Context, tokenId string, next *model.RefreshToken) error
+	RevokeSession(ctx context.Context, sessionId string) error
+	RevokeAllForUser(ctx context.Context, userId int) error
+}
@@ -2,8 +2,10 @@ package port
 
 import (
 	"context"
+	"time"
 
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 )
 
 type UserRepository interface {
@@ -14,4 +16,13 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
 	ExistsByEmail(email string) (bool, error)
 	ExistsByUsername(username string) (bool, error)
+	ExistsByMobileNumber(mobileNumber string) (bool, error)
+	GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]model.User, error)
+	SetEnabled(ctx context.Context, id int, enabled bool) error
+	// AddFailedLogin counts a wrong password and locks the user until lockedUntil when the count
+	// reaches maxAttempts, the count starts again from zero after the lock
+	AddFailedLogin(ctx context.Context, id int, maxAttempts int, lockedUntil time.Time) error
+	// Unlock clears the failed login count and the lock of the user
+	Unlock(ctx context.Context, id int) error
+	AddRole(ctx context.Context, userId int, roleName string) error
 }
@@ -0,0 +1,58 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+func TestTranslateError_WrappedServiceError(t *testing.T) {
+	err := fmt.Errorf("update profile: %w", service_errors.New(service_errors.CodeEmailExists))
+
+	code, mapping := helper.TranslateError(err)
+
+	assert.Equal(t, service_errors.CodeEmailExists, code)
+	assert.Equal(t, http.StatusConflict, mapping.StatusCode)
+	assert.Equal(t, helper.ConflictError, mapping.ResultCode)
+}
+
+func TestTranslateError_UnmappedCodeUsesCause(t *testing.T) {
+	err := service_errors.Wrap(service_errors.CodeFailedToFetchWorkout, service_errors.New(service_errors.CodeRecordNotFound))
+
+	assert.Equal(t, http.StatusNotFound, helper.TranslateErrorToStatusCode(err))
+	assert.Equal(t, helper.NotFoundError, helper.TranslateErrorToResultCode(err))
+
+	response := helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err)
+	assert.Equal(t, service_errors.CodeRecordNotFound, response.ErrorCode)
+	assert.Equal(t, service_errors.FailedToFetchWorkout, response.Error)
+}
+
+func TestTranslateError_PlainError(t *testing.T) {
+	err := errors.New("connection refused")
+
+	code, mapping := helper.TranslateError(err)
+
+	assert.Equal(t, service_errors.ErrorCode(""), code)
+	assert.Equal(t, http.StatusInternalServerError, mapping.StatusCode)
+	assert.Equal(t, helper.InternalError, mapping.ResultCode)
+}
+
+func TestHasCode(t *testing.T) {
+	err := fmt.Errorf("login: %w", service_errors.Wrap(service_errors.CodeInvalidRefreshToken, errors.New("expired")))
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidRefreshToken))
+	assert.False(t, service_errors.HasCode(err, service_errors.CodeTokenExpired))
+	assert.False(t, service_errors.HasCode(errors.New("expired"), service_errors.CodeTokenExpired))
+}
+
+func TestGenerateBaseResponseWithValidationError_KeepsOtherErrors(t *testing.T) {
+	response := helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, errors.New("unexpected EOF"))
+
+	assert.Equal(t, "unexpected EOF", response.Error)
+	assert.True(t, response.ValidationErrors == nil)
+}
@@ -15,8 +15,11 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -30,6 +33,7 @@ func TestLoginByUsername_Success(t *testing.T) {
 				Id:       1,
 				Username: "testuser",
 				Password: string(hashedPassword),
+				Enabled:  true,
 			}, nil
 		},
 	}
@@ -38,12 +42,13 @@ func TestLoginByUsername_Success(t *testing.T) {
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
 			RefreshTokenExpireDuration: 60,
+			RefreshTokenCookie:         true,
 		},
 		Server: config.ServerConfig{
 			Domain: "localhost",
 		},
 	}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -89,7 +94,7 @@ func TestLoginByUsername_InvalidCredentials(t *testing.T) {
 	mockToken := &MockTokenProvider{}
 
 	cfg := &config.Config{}
-	useCase := usecase.NewUserUsecase(cfg, repo, mockToken)
+	useCase := usecase.NewUserUsecase(cfg, repo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 
 	accountHandler := &handler.AccountHandler{
 		Usecase: useCase,
@@ -125,7 +130,7 @@ func TestRegisterByUsername_Success(t *testing.T) {
 	mockToken := &MockTokenProvider{}
 
 	cfg := &config.Config{}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -164,7 +169,7 @@ func TestRegisterByUsername_ValidationError(t *testing.T) {
 	mockToken := &MockTokenProvider{}
 
 	cfg := &config.Config{}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -207,7 +212,7 @@ func TestRegisterByUsername_UsernameExists(t *testing.T) {
 	mockToken := &MockTokenProvider{}
 
 	cfg := &config.Config{}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -251,7 +256,7 @@ func TestRegisterByUsername_EmailExists(t *testing.T) {
 	mockToken := &MockTokenProvider{}
 
 	cfg := &config.Config{}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -289,7 +294,7 @@ func TestRefreshTokenHandler_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockRepo := &MockUserRepository{}
 	mockToken := &MockTokenProvider{
-		RefreshTokenFn: func(token string) (*dto.TokenDetail, error) {
+		GenerateTokenFn: func(token *entity.TokenPayload) (*dto.TokenDetail, error) {
 			return &dto.TokenDetail{
 				AccessToken:  "newAccessToken",
 				RefreshToken: "newRefreshToken",
@@ -300,12 +305,13 @@ func TestRefreshTokenHandler_Success(t *testing.T) {
 	cfg := &config.Config{
 		JWT: config.JWTConfig{
 			RefreshTokenExpireDuration: 60,
+			RefreshTokenCookie:         true,
 		},
 		Server: config.ServerConfig{
 			Domain: "localhost",
 		},
 	}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -350,13 +356,13 @@ func TestRefreshTokenHandler_Error(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	mockRepo := &MockUserRepository{}
 	mockToken := &MockTokenProvider{
-		RefreshTokenFn: func(token string) (*dto.TokenDetail, error) {
-			return nil, errors.New("refresh token error")
+		GetRefreshClaimsFn: func(token string) (map[string]interface{}, error) {
+			return nil, errors.New("signature is invalid")
 		},
 	}
 
 	cfg := &config.Config{}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
@@ -374,13 +380,13 @@ func TestRefreshTokenHandler_Error(t *testing.T) {
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
 
 	var response helper.BaseHttpResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, false, response.Success)
-	assert.Equal(t, "refresh token error", response.Error)
+	assert.Equal(t, service_errors.InvalidRefreshToken, response.Error)
 }
 
 func TestRefreshTokenHandler_BadRequest(t *testing.T) {
@@ -398,7 +404,7 @@ func TestRefreshTokenHandler_BadRequest(t *testing.T) {
 			Domain: "localhost",
 		},
 	}
-	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken)
+	usecase := usecase.NewUserUsecase(cfg, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	accountHandler := &handler.AccountHandler{
 		Usecase: usecase,
 		Cfg:     cfg,
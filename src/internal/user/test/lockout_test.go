@@ -0,0 +1,208 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var lockoutConfig = config.LockoutConfig{
+	MaxFailedAttempts:      3,
+	Duration:               15,
+	MaxFailedAttemptsPerIp: 2,
+	IpWindow:               15,
+}
+
+func lockoutRepo(user *model.User) *MockUserRepository {
+	return &MockUserRepository{
+		FindByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			if username != user.Username {
+				return nil, service_errors.New(service_errors.CodeUsernameOrPasswordInvalid)
+			}
+			return user, nil
+		},
+	}
+}
+
+func lockoutUser() *model.User {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	return &model.User{Id: 7, Username: "testuser", Password: string(hashedPassword), Enabled: true}
+}
+
+func TestLogin_WrongPasswordCountsFailedAttempt(t *testing.T) {
+	repo := lockoutRepo(lockoutUser())
+	var maxAttempts int
+	var lockedUntil time.Time
+	repo.AddFailedLoginFn = func(ctx context.Context, id int, max int, until time.Time) error {
+		assert.Equal(t, 7, id)
+		maxAttempts, lockedUntil = max, until
+		return nil
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{Lockout: lockoutConfig}, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	_, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "wrong-password"})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.UsernameOrPasswordInvalid, err.Error())
+	assert.Equal(t, 3, maxAttempts)
+	assert.True(t, lockedUntil.After(time.Now().Add(14*time.Minute)))
+}
+
+func TestLogin_LockedAccountAnswersLikeWrongPassword(t *testing.T) {
+	user := lockoutUser()
+	user.LockedUntil = sql.NullTime{Valid: true, Time: time.Now().Add(time.Minute)}
+	repo := lockoutRepo(user)
+	repo.AddFailedLoginFn = func(ctx context.Context, id int, max int, until time.Time) error {
+		t.Fatal("a locked account should not count attempts")
+		return nil
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{Lockout: lockoutConfig}, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	// Even the right password is rejected while the lock lasts
+	tokenDetail, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+
+	assert.Error(t, err)
+	assert.True(t, tokenDetail == nil)
+	assert.Equal(t, service_errors.UsernameOrPasswordInvalid, err.Error())
+}
+
+func TestLogin_SuccessClearsExpiredLock(t *testing.T) {
+	user := lockoutUser()
+	user.FailedLoginAttempts = 2
+	user.LockedUntil = sql.NullTime{Valid: true, Time: time.Now().Add(-time.Minute)}
+	repo := lockoutRepo(user)
+	unlocked := 0
+	repo.UnlockFn = func(ctx context.Context, id int) error {
+		unlocked = id
+		return nil
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{Lockout: lockoutConfig}, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	tokenDetail, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+
+	assert.NoError(t, err)
+	assert.True(t, tokenDetail != nil)
+	assert.Equal(t, 7, unlocked)
+}
+
+func TestLogin_TooManyFailedAttemptsPerIp(t *testing.T) {
+	useCase := usecase.NewUserUsecase(&config.Config{Lockout: lockoutConfig}, lockoutRepo(lockoutUser()), &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+	ctx := context.Background()
+
+	// Unknown usernames count against the ip as well
+	_, err := useCase.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "unknown", Password: "password", ClientIp: "10.0.0.1"})
+	assert.Equal(t, service_errors.UsernameOrPasswordInvalid, err.Error())
+	_, err = useCase.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "testuser", Password: "wrong-password", ClientIp: "10.0.0.1"})
+	assert.Equal(t, service_errors.UsernameOrPasswordInvalid, err.Error())
+
+	_, err = useCase.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "testuser", Password: "password", ClientIp: "10.0.0.1"})
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.TooManyLoginAttempts, err.Error())
+
+	tokenDetail, err := useCase.LoginByUsername(ctx, &dto.LoginByUsernameRequest{Username: "testuser", Password: "password", ClientIp: "10.0.0.2"})
+	assert.NoError(t, err)
+	assert.True(t, tokenDetail != nil)
+}
+
+func TestLogin_LockoutDisabled(t *testing.T) {
+	repo := lockoutRepo(lockoutUser())
+	repo.AddFailedLoginFn = func(ctx context.Context, id int, max int, until time.Time) error {
+		t.Fatal("attempts should not be counted without a maximum")
+		return nil
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	for i := 0; i < 5; i++ {
+		_, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "wrong-password", ClientIp: "10.0.0.1"})
+		assert.Equal(t, service_errors.UsernameOrPasswordInvalid, err.Error())
+	}
+}
+
+func TestLoginByUsername_LockedReturns401(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := lockoutUser()
+	user.LockedUntil = sql.NullTime{Valid: true, Time: time.Now().Add(time.Minute)}
+	cfg := &config.Config{Lockout: lockoutConfig}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, lockoutRepo(user), &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+	router := gin.New()
+	router.POST("/login", accountHandler.LoginByUsername)
+
+	body, _ := json.Marshal(dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), service_errors.UsernameOrPasswordInvalid)
+}
+
+func TestUnlockUser_Success(t *testing.T) {
+	unlocked := 0
+	repo := &MockUserRepository{
+		UnlockFn: func(ctx context.Context, id int) error {
+			unlocked = id
+			return nil
+		},
+	}
+	useCase, _ := setup(repo)
+
+	res, err := useCase.UnlockUser(context.Background(), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, unlocked)
+	assert.Equal(t, 7, res.Id)
+	assert.True(t, res.LockedUntil == nil)
+}
+
+func TestUnlockUser_NotFound(t *testing.T) {
+	repo := &MockUserRepository{
+		UnlockFn: func(ctx context.Context, id int) error {
+			return service_errors.New(service_errors.CodeRecordNotFound)
+		},
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.UnlockUser(context.Background(), 7)
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.RecordNotFound, err.Error())
+}
+
+func TestChangePassword_UnlocksAccount(t *testing.T) {
+	user := lockoutUser()
+	user.LockedUntil = sql.NullTime{Valid: true, Time: time.Now().Add(time.Minute)}
+	unlocked := 0
+	repo := &MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*model.User, error) { return user, nil },
+		UnlockFn: func(ctx context.Context, id int) error {
+			unlocked = id
+			return nil
+		},
+	}
+	useCase, _ := setup(repo)
+
+	err := useCase.ChangePassword(createContextWithUserId(7), &dto.ChangePasswordRequest{OldPassword: "password", NewPassword: "NewPassw0rd"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, unlocked)
+}
@@ -0,0 +1,314 @@
+package test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
+	notificationDto "github.com/alielmi98/go-hexa-workout/internal/notification/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	workoutDto "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+func readLogEntries(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	entries := []map[string]interface{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_WritesJsonAboveLevel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := logging.NewLogger(&config.LoggerConfig{Level: "warn", Encoding: "json", Output: path})
+	assert.NoError(t, err)
+
+	logger.Info(constants.Postgres, constants.Select, "skipped", nil)
+	logger.Error(constants.Postgres, constants.Insert, "insert failed", map[constants.ExtraKey]interface{}{constants.UserId: 3})
+	logger.Warnf("disk at %d%%", 90)
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "ERROR", fmt.Sprint(entries[0]["level"]))
+	assert.Equal(t, "insert failed", fmt.Sprint(entries[0]["msg"]))
+	assert.Equal(t, "Postgres", fmt.Sprint(entries[0]["Category"]))
+	assert.Equal(t, "Insert", fmt.Sprint(entries[0]["SubCategory"]))
+	assert.Equal(t, "3", fmt.Sprint(entries[0]["UserId"]))
+	assert.Equal(t, "WARN", fmt.Sprint(entries[1]["level"]))
+	assert.Equal(t, "disk at 90%", fmt.Sprint(entries[1]["msg"]))
+}
+
+func TestLogger_InvalidConfig(t *testing.T) {
+	_, err := logging.NewLogger(&config.LoggerConfig{Level: "verbose"})
+	assert.Error(t, err)
+
+	_, err = logging.NewLogger(&config.LoggerConfig{Encoding: "xml"})
+	assert.Error(t, err)
+}
+
+func TestRequestLogger_AddsRequestScopedFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, logging.Init(&config.LoggerConfig{Level: "debug", Output: path}))
+	defer func() {
+		// Restore a logger that writes to stderr for the other tests
+		_ = logging.Init(&config.LoggerConfig{})
+	}()
+
+	router := gin.New()
+	router.Use(middlewares.RequestLogger())
+	router.GET("/v1/ping", func(c *gin.Context) {
+		// Usecases and repositories receive the gin context
+		logging.FromContext(c).Info(constants.Internal, constants.UseCase, "handled", nil)
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/ping", nil)
+	req.Header.Set(constants.RequestIdHeaderKey, "req-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", w.Header().Get(constants.RequestIdHeaderKey))
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "req-123", fmt.Sprint(entries[0]["RequestId"]))
+	assert.Equal(t, "GET", fmt.Sprint(entries[0]["Method"]))
+	assert.Equal(t, "/v1/ping", fmt.Sprint(entries[0]["Path"]))
+}
+
+func TestRequestLogger_LogsTheRouteInsteadOfThePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, logging.Init(&config.LoggerConfig{Level: "debug", Output: path}))
+	defer func() {
+		_ = logging.Init(&config.LoggerConfig{})
+	}()
+
+	router := gin.New()
+	router.Use(middlewares.RequestLogger())
+	router.GET("/v1/calendar/feed/:token", func(c *gin.Context) {
+		logging.FromContext(c).Info(constants.Internal, constants.UseCase, "handled", nil)
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/calendar/feed/secret-feed-token", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "/v1/calendar/feed/:token", fmt.Sprint(entries[0]["Path"]))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "secret-feed-token")
+}
+
+func TestRequestLogger_GeneratesRequestId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middlewares.RequestLogger())
+	router.GET("/v1/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/ping", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 32, len(w.Header().Get(constants.RequestIdHeaderKey)))
+}
+
+func setupAccessLogRouter(t *testing.T, cfg *config.LoggerConfig, handler gin.HandlerFunc) (*gin.Engine, string) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, logging.Init(&config.LoggerConfig{Level: "debug", Output: path}))
+	t.Cleanup(func() { _ = logging.Init(&config.LoggerConfig{}) })
+
+	router := gin.New()
+	router.Use(middlewares.RequestLogger(), middlewares.AccessLogger(cfg))
+	router.POST("/v1/auth/login", handler)
+	return router, path
+}
+
+func TestAccessLogger_RedactsPasswordsAndTokens(t *testing.T) {
+	router, path := setupAccessLogRouter(t, &config.LoggerConfig{LogBodies: true, MaxBodySize: 4096}, func(c *gin.Context) {
+		req := dto.LoginByUsernameRequest{}
+		assert.NoError(t, c.ShouldBindJSON(&req))
+		// The handler still receives the original body
+		assert.Equal(t, "secret-password", req.Password)
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(&dto.TokenDetail{AccessToken: "access-jwt", RefreshToken: "refresh-jwt"}, true, helper.Success))
+	})
+
+	body := `{"username":"testuser","password":"secret-password"}`
+	req, _ := http.NewRequest("POST", "/v1/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "refresh-jwt")
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "200", fmt.Sprint(entries[0]["StatusCode"]))
+	assert.Equal(t, "/v1/auth/login", fmt.Sprint(entries[0]["Path"]))
+	assert.Contains(t, fmt.Sprint(entries[0]["RequestBody"]), "testuser")
+	for _, key := range []string{"RequestBody", "ResponseBody"} {
+		logged := fmt.Sprint(entries[0][key])
+		assert.NotContains(t, logged, "secret-password")
+		assert.NotContains(t, logged, "access-jwt")
+		assert.NotContains(t, logged, "refresh-jwt")
+	}
+}
+
+func TestAccessLogger_RedactsTheSecretsOfTheOtherModules(t *testing.T) {
+	router, path := setupAccessLogRouter(t, &config.LoggerConfig{LogBodies: true, MaxBodySize: 4096}, func(c *gin.Context) {
+		req := notificationDto.UpdatePreferenceRequest{}
+		assert.NoError(t, c.ShouldBindJSON(&req))
+		feed := workoutDto.CalendarFeedResponse{Token: "feed-token", Url: "https://example.com/v1/calendar/feed-token.ics"}
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(&feed, true, helper.Success))
+	})
+
+	body := `{"webhook":true,"webhook_url":"https://hooks.example.com/hook-token","webhook_secret":"signing-secret"}`
+	req, _ := http.NewRequest("POST", "/v1/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	assert.Contains(t, fmt.Sprint(entries[0]["RequestBody"]), "webhook")
+	for _, key := range []string{"RequestBody", "ResponseBody"} {
+		logged := fmt.Sprint(entries[0][key])
+		assert.NotContains(t, logged, "signing-secret")
+		assert.NotContains(t, logged, "hook-token")
+		assert.NotContains(t, logged, "feed-token")
+	}
+}
+
+func TestRegisterSecrets_ReadsTheNestedDtos(t *testing.T) {
+	type credentials struct {
+		ApiKey string `json:"api_key,omitempty" log:"secret"`
+		Label  string `json:"label"`
+	}
+	type request struct {
+		Items []*credentials `json:"items"`
+	}
+
+	logging.RegisterSecrets(request{})
+
+	assert.True(t, logging.IsSecretField("api_key"))
+	assert.True(t, logging.IsSecretField("API_KEY"))
+	assert.False(t, logging.IsSecretField("label"))
+}
+
+func TestAccessLogger_OmitsBodiesByDefault(t *testing.T) {
+	router, path := setupAccessLogRouter(t, &config.LoggerConfig{}, func(c *gin.Context) {
+		c.JSON(http.StatusBadRequest, helper.GenerateBaseResponse(nil, false, helper.ValidationError))
+	})
+
+	req, _ := http.NewRequest("POST", "/v1/auth/login", strings.NewReader(`{"password":"secret-password"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "WARN", fmt.Sprint(entries[0]["level"]))
+	assert.Equal(t, "400", fmt.Sprint(entries[0]["StatusCode"]))
+	_, ok := entries[0]["RequestBody"]
+	assert.False(t, ok)
+}
+
+func TestAccessLogger_TruncatesLargeBodies(t *testing.T) {
+	// The body fits the limit, the redacted password is longer than the original one
+	body := `{"password":"pw","note":"` + strings.Repeat("b", 10) + `"}`
+	router, path := setupAccessLogRouter(t, &config.LoggerConfig{LogBodies: true, MaxBodySize: len(body)}, func(c *gin.Context) {
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+	})
+
+	req, _ := http.NewRequest("POST", "/v1/auth/login", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	requestBody := fmt.Sprint(entries[0]["RequestBody"])
+	assert.NotContains(t, requestBody, `"pw"`)
+	assert.Contains(t, requestBody, "(truncated)")
+}
+
+func TestAccessLogger_OmitsBodiesLargerThanTheLimit(t *testing.T) {
+	body := `{"password":"secret-password","note":"` + strings.Repeat("b", 100) + `"}`
+	router, path := setupAccessLogRouter(t, &config.LoggerConfig{LogBodies: true, MaxBodySize: 32}, func(c *gin.Context) {
+		// The handler still receives the whole body, only the logged part is buffered
+		received, err := io.ReadAll(c.Request.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, body, string(received))
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(strings.Repeat("a", 100), true, helper.Success))
+	})
+
+	req, _ := http.NewRequest("POST", "/v1/auth/login", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 1, len(entries))
+	requestBody := fmt.Sprint(entries[0]["RequestBody"])
+	assert.NotContains(t, requestBody, "secret-password")
+	assert.Contains(t, requestBody, "omitted")
+	assert.NotContains(t, fmt.Sprint(entries[0]["ResponseBody"]), strings.Repeat("a", 100))
+}
+
+func TestRecovery_ReturnsCustomRecoveryResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, logging.Init(&config.LoggerConfig{Level: "debug", Output: path}))
+	t.Cleanup(func() { _ = logging.Init(&config.LoggerConfig{}) })
+
+	router := gin.New()
+	router.Use(middlewares.RequestLogger(), middlewares.AccessLogger(&config.LoggerConfig{}), middlewares.Recovery())
+	router.GET("/v1/panic", func(c *gin.Context) {
+		// A missing claim panics like this in the usecases
+		_ = c.Value(constants.UserIdKey).(float64)
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/panic", nil)
+	req.Header.Set(constants.RequestIdHeaderKey, "req-panic")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var response helper.BaseHttpResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	assert.Equal(t, helper.CustomRecovery, response.ResultCode)
+	assert.Equal(t, "req-panic", w.Header().Get(constants.RequestIdHeaderKey))
+
+	entries := readLogEntries(t, path)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, "Recovery", fmt.Sprint(entries[0]["SubCategory"]))
+	assert.Equal(t, "req-panic", fmt.Sprint(entries[0]["RequestId"]))
+	assert.Contains(t, fmt.Sprint(entries[0]["Stack"]), "goroutine")
+	// The access log still records the request
+	assert.Equal(t, "500", fmt.Sprint(entries[1]["StatusCode"]))
+}
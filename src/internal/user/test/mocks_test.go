@@ -2,13 +2,23 @@ package test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"time"
 
+	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
 	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
 	"github.com/golang-jwt/jwt"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 type MockUserRepository struct {
@@ -18,6 +28,15 @@ type MockUserRepository struct {
 	CreateFn           func(ctx context.Context, user *model.User) error
 	ExistsByUsernameFn func(username string) (bool, error)
 	ExistsByEmailFn    func(email string) (bool, error)
+	GetByIDFn          func(ctx context.Context, id int) (*model.User, error)
+	GetByFilterFn      func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]model.User, error)
+	SetEnabledFn       func(ctx context.Context, id int, enabled bool) error
+	AddRoleFn          func(ctx context.Context, userId int, roleName string) error
+	UpdateFn           func(ctx context.Context, id int, user *model.User) error
+	DeleteFn           func(ctx context.Context, id int) error
+	ExistsByMobileFn   func(mobileNumber string) (bool, error)
+	AddFailedLoginFn   func(ctx context.Context, id int, maxAttempts int, lockedUntil time.Time) error
+	UnlockFn           func(ctx context.Context, id int) error
 }
 
 func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error {
@@ -35,12 +54,21 @@ func (m *MockUserRepository) Create(ctx context.Context, user *model.User) error
 	return nil
 }
 func (m *MockUserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
-	return &model.User{Id: id, Username: "testuser", Password: "password"}, nil
+	if m.GetByIDFn != nil {
+		return m.GetByIDFn(ctx, id)
+	}
+	return &model.User{Id: id, Username: "testuser", Password: "password", Enabled: true}, nil
 }
 func (m *MockUserRepository) Update(ctx context.Context, id int, user *model.User) error {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, user)
+	}
 	return nil
 }
 func (m *MockUserRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
 	return nil
 }
 func (m *MockUserRepository) FindByUsername(ctx context.Context, username string) (*model.User, error) {
@@ -60,34 +88,163 @@ func (m *MockUserRepository) ExistsByEmail(email string) (bool, error) {
 	}
 	return false, nil
 }
+func (m *MockUserRepository) ExistsByMobileNumber(mobileNumber string) (bool, error) {
+	if m.ExistsByMobileFn != nil {
+		return m.ExistsByMobileFn(mobileNumber)
+	}
+	return false, nil
+}
 func (m *MockUserRepository) ExistsByUsername(username string) (bool, error) {
 	if m.ExistsByUsernameFn != nil {
 		return m.ExistsByUsernameFn(username)
 	}
 	return false, nil
 }
+func (m *MockUserRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]model.User, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	return 0, &[]model.User{}, nil
+}
+func (m *MockUserRepository) SetEnabled(ctx context.Context, id int, enabled bool) error {
+	if m.SetEnabledFn != nil {
+		return m.SetEnabledFn(ctx, id, enabled)
+	}
+	return nil
+}
+func (m *MockUserRepository) AddFailedLogin(ctx context.Context, id int, maxAttempts int, lockedUntil time.Time) error {
+	if m.AddFailedLoginFn != nil {
+		return m.AddFailedLoginFn(ctx, id, maxAttempts, lockedUntil)
+	}
+	return nil
+}
+func (m *MockUserRepository) Unlock(ctx context.Context, id int) error {
+	if m.UnlockFn != nil {
+		return m.UnlockFn(ctx, id)
+	}
+	return nil
+}
+func (m *MockUserRepository) AddRole(ctx context.Context, userId int, roleName string) error {
+	if m.AddRoleFn != nil {
+		return m.AddRoleFn(ctx, userId, roleName)
+	}
+	return nil
+}
 
 type MockTokenProvider struct {
-	RefreshTokenFn func(refreshToken string) (*dto.TokenDetail, error)
+	GenerateTokenFn    func(token *entity.TokenPayload) (*dto.TokenDetail, error)
+	GetRefreshClaimsFn func(refreshToken string) (map[string]interface{}, error)
+	GetClaimsFn        func(token string) (map[string]interface{}, error)
 }
 
 func (m *MockTokenProvider) GenerateToken(token *entity.TokenPayload) (*dto.TokenDetail, error) {
+	if m.GenerateTokenFn != nil {
+		return m.GenerateTokenFn(token)
+	}
 	return &dto.TokenDetail{AccessToken: "token", RefreshToken: "refresh", AccessTokenExpireTime: 0, RefreshTokenExpireTime: 0}, nil
 }
 func (m *MockTokenProvider) VerifyToken(token string) (*jwt.Token, error) { return &jwt.Token{}, nil }
 func (m *MockTokenProvider) GetClaims(token string) (map[string]interface{}, error) {
+	if m.GetClaimsFn != nil {
+		return m.GetClaimsFn(token)
+	}
 	return map[string]interface{}{}, nil
 }
-func (m *MockTokenProvider) RefreshToken(refreshToken string) (*dto.TokenDetail, error) {
-	if m.RefreshTokenFn != nil {
-		return m.RefreshTokenFn(refreshToken)
+func (m *MockTokenProvider) GetRefreshClaims(refreshToken string) (map[string]interface{}, error) {
+	if m.GetRefreshClaimsFn != nil {
+		return m.GetRefreshClaimsFn(refreshToken)
 	}
-	return &dto.TokenDetail{AccessToken: "new-token", RefreshToken: "new-refresh", AccessTokenExpireTime: 0, RefreshTokenExpireTime: 0}, nil
+	return map[string]interface{}{constants.TokenIdKey: "token-id", constants.SessionIdKey: "session-id"}, nil
+}
+
+type MockRefreshTokenRepository struct {
+	Created         []*model.RefreshToken
+	RevokedSessions []string
+	RevokedUsers    []int
+	GetByTokenIdFn  func(ctx context.Context, tokenId string) (*model.RefreshToken, error)
+	RotateFn        func(ctx context.Context, tokenId string, next *model.RefreshToken) error
+	RevokeSessionFn func(ctx context.Context, sessionId string) error
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *model.RefreshToken) error {
+	m.Created = append(m.Created, token)
+	return nil
+}
+func (m *MockRefreshTokenRepository) GetByTokenId(ctx context.Context, tokenId string) (*model.RefreshToken, error) {
+	if m.GetByTokenIdFn != nil {
+		return m.GetByTokenIdFn(ctx, tokenId)
+	}
+	return &model.RefreshToken{Id: 1, UserId: 1, TokenId: tokenId, SessionId: "session-id", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+func (m *MockRefreshTokenRepository) Rotate(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+	if m.RotateFn != nil {
+		return m.RotateFn(ctx, tokenId, next)
+	}
+	m.Created = append(m.Created, next)
+	return nil
+}
+func (m *MockRefreshTokenRepository) RevokeSession(ctx context.Context, sessionId string) error {
+	if m.RevokeSessionFn != nil {
+		return m.RevokeSessionFn(ctx, sessionId)
+	}
+	m.RevokedSessions = append(m.RevokedSessions, sessionId)
+	return nil
+}
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userId int) error {
+	m.RevokedUsers = append(m.RevokedUsers, userId)
+	return nil
+}
+
+// Helper function to create context with user ID
+func createContextWithUserId(userId float64) context.Context {
+	ctx := context.Background()
+	return context.WithValue(ctx, constants.UserIdKey, userId)
 }
 
 func setup(repo *MockUserRepository) (*usecase.UserUsecase, *MockUserRepository) {
 	mockToken := &MockTokenProvider{}
 	mockConfig := &config.Config{}
-	useCase := usecase.NewUserUsecase(mockConfig, repo, mockToken)
+	useCase := usecase.NewUserUsecase(mockConfig, repo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 	return useCase, repo
 }
+
+// RecordingConnPool is a postgres connection that records the statements it executes instead of running them,
+// every statement affects one row
+type RecordingConnPool struct {
+	Statements []string
+	Committed  bool
+	RolledBack bool
+}
+
+func (m *RecordingConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (m *RecordingConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	m.Statements = append(m.Statements, query)
+	return driver.RowsAffected(1), nil
+}
+func (m *RecordingConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("queries are not supported")
+}
+func (m *RecordingConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+func (m *RecordingConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return m, nil
+}
+func (m *RecordingConnPool) Commit() error {
+	m.Committed = true
+	return nil
+}
+func (m *RecordingConnPool) Rollback() error {
+	m.RolledBack = true
+	return nil
+}
+
+func openRecordingDb(pool *RecordingConnPool) *gorm.DB {
+	database, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		panic(err)
+	}
+	return database
+}
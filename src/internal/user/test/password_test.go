@@ -0,0 +1,217 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var passwordPolicy = config.PasswordConfig{
+	IncludeChars:     true,
+	IncludeDigits:    true,
+	MinLength:        8,
+	MaxLength:        64,
+	IncludeUppercase: true,
+	IncludeLowercase: true,
+}
+
+func tags(violations []validation.ValidationError) []string {
+	result := []string{}
+	for _, v := range violations {
+		result = append(result, v.Tag)
+	}
+	return result
+}
+
+// ==================== PASSWORD POLICY TESTS ====================
+
+func TestCheckPassword_Valid(t *testing.T) {
+	violations := validation.CheckPassword(&passwordPolicy, "password", "Str0ngPassword")
+
+	assert.True(t, violations == nil)
+}
+
+func TestCheckPassword_ListsEveryFailedRule(t *testing.T) {
+	violations := validation.CheckPassword(&passwordPolicy, "password", "abc")
+
+	assert.Equal(t, []string{validation.PasswordMinLength, validation.PasswordDigit, validation.PasswordUppercase}, tags(violations))
+	assert.Equal(t, "8", violations[0].Param)
+	for _, v := range violations {
+		assert.Equal(t, "password", v.Field)
+		assert.NotEqual(t, "abc", v.Param)
+	}
+}
+
+func TestCheckPassword_MaxLengthAndLetters(t *testing.T) {
+	cfg := config.PasswordConfig{IncludeChars: true, MaxLength: 4}
+
+	violations := validation.CheckPassword(&cfg, "password", "123456")
+
+	assert.Equal(t, []string{validation.PasswordMaxLength, validation.PasswordLetter}, tags(violations))
+}
+
+func TestRegisterUser_WeakPassword(t *testing.T) {
+	repo := &MockUserRepository{}
+	cfg := &config.Config{Password: passwordPolicy}
+	useCase := usecase.NewUserUsecase(cfg, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.RegisterByUsername(context.Background(), &dto.RegisterUserByUsernameRequest{
+		Username: "testuser", Password: "password", Email: "test@example.com",
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.PasswordPolicyViolation, err.Error())
+	violations := validation.GetValidationErrors(err)
+	assert.Equal(t, []string{validation.PasswordDigit, validation.PasswordUppercase}, tags(*violations))
+	assert.False(t, repo.SaveCalled)
+}
+
+func TestRegisterByUsername_Handler_WeakPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{Password: passwordPolicy}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, &MockUserRepository{}, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+
+	router := gin.New()
+	router.POST("/v1/account/register", accountHandler.RegisterByUsername)
+
+	jsonData, _ := json.Marshal(dto.RegisterUserByUsernameRequest{
+		FirstName: "Test", LastName: "User", Username: "testuser", Email: "test@example.com", Password: "weakpassword",
+	})
+	req, _ := http.NewRequest("POST", "/v1/account/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, service_errors.PasswordPolicyViolation, response.Error)
+	assert.True(t, response.ValidationErrors != nil)
+	assert.Equal(t, []string{validation.PasswordDigit, validation.PasswordUppercase}, tags(*response.ValidationErrors))
+}
+
+func TestRegisterByUsername_Handler_BindingErrorsAreListed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, &MockUserRepository{}, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+
+	router := gin.New()
+	router.POST("/v1/account/register", accountHandler.RegisterByUsername)
+
+	jsonData, _ := json.Marshal(map[string]string{"firstName": "Test", "lastName": "User", "username": "abc", "email": "test@example.com", "password": "Str0ngPassword"})
+	req, _ := http.NewRequest("POST", "/v1/account/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []validation.ValidationError{{Field: "username", Tag: "min", Param: "5", Message: "must be at least 5 characters long"}}, *response.ValidationErrors)
+	assert.Equal(t, service_errors.CodeValidationError, response.ErrorCode)
+}
+
+// ==================== CHANGE PASSWORD TESTS ====================
+
+func changePasswordRepo(t *testing.T, updated *string) *MockUserRepository {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("Old-Passw0rd"), bcrypt.DefaultCost)
+	return &MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*model.User, error) {
+			return &model.User{Id: id, Username: "testuser", Password: string(hashedPassword), Enabled: true}, nil
+		},
+		UpdateFn: func(ctx context.Context, id int, user *model.User) error {
+			assert.Equal(t, 1, id)
+			*updated = user.Password
+			return nil
+		},
+	}
+}
+
+func TestChangePassword_Success(t *testing.T) {
+	var updated string
+	sessions := &MockRefreshTokenRepository{}
+	cfg := &config.Config{Password: passwordPolicy}
+	useCase := usecase.NewUserUsecase(cfg, changePasswordRepo(t, &updated), &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	err := useCase.ChangePassword(createContextWithUserId(1), &dto.ChangePasswordRequest{OldPassword: "Old-Passw0rd", NewPassword: "New-Passw0rd"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(updated), []byte("New-Passw0rd")))
+	assert.Equal(t, []int{1}, sessions.RevokedUsers)
+}
+
+func TestChangePassword_WrongOldPassword(t *testing.T) {
+	var updated string
+	cfg := &config.Config{Password: passwordPolicy}
+	useCase := usecase.NewUserUsecase(cfg, changePasswordRepo(t, &updated), &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.ChangePassword(createContextWithUserId(1), &dto.ChangePasswordRequest{OldPassword: "wrong", NewPassword: "New-Passw0rd"})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.OldPasswordInvalid, err.Error())
+	assert.Equal(t, "", updated)
+}
+
+func TestChangePassword_WeakNewPassword(t *testing.T) {
+	var updated string
+	cfg := &config.Config{Password: passwordPolicy}
+	useCase := usecase.NewUserUsecase(cfg, changePasswordRepo(t, &updated), &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.ChangePassword(createContextWithUserId(1), &dto.ChangePasswordRequest{OldPassword: "Old-Passw0rd", NewPassword: "new"})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.PasswordPolicyViolation, err.Error())
+	violations := validation.GetValidationErrors(err)
+	assert.Equal(t, "newPassword", (*violations)[0].Field)
+	assert.Equal(t, "", updated)
+}
+
+func TestChangePassword_Handler_Success(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var updated string
+	cfg := &config.Config{Password: passwordPolicy}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, changePasswordRepo(t, &updated), &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+
+	router := gin.New()
+	router.POST("/v1/account/change-password", func(c *gin.Context) {
+		c.Set(constants.UserIdKey, float64(1))
+		c.Next()
+	}, accountHandler.ChangePassword)
+
+	jsonData, _ := json.Marshal(dto.ChangePasswordRequest{OldPassword: "Old-Passw0rd", NewPassword: "New-Passw0rd"})
+	req, _ := http.NewRequest("POST", "/v1/account/change-password", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, updated != "")
+}
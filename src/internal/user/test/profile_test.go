@@ -0,0 +1,355 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	notificationRepo "github.com/alielmi98/go-hexa-workout/internal/notification/adapter/repo"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/repo"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	workoutRepo "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/repo"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func profileRepo() *MockUserRepository {
+	return &MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*model.User, error) {
+			return &model.User{
+				Id:           id,
+				Username:     "testuser",
+				FirstName:    "Test",
+				LastName:     "User",
+				Email:        "test@example.com",
+				MobileNumber: "09120000000",
+				Enabled:      true,
+			}, nil
+		},
+	}
+}
+
+// ==================== PROFILE USECASE TESTS ====================
+
+func TestGetProfile_Success(t *testing.T) {
+	useCase, _ := setup(profileRepo())
+
+	res, err := useCase.GetProfile(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.Id)
+	assert.Equal(t, "test@example.com", res.Email)
+}
+
+func TestGetProfile_NoUserInContext(t *testing.T) {
+	useCase, _ := setup(profileRepo())
+
+	_, err := useCase.GetProfile(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.UserIdNotFound, err.Error())
+}
+
+func TestUpdateProfile_Success(t *testing.T) {
+	repo := profileRepo()
+	var updated *model.User
+	repo.UpdateFn = func(ctx context.Context, id int, user *model.User) error {
+		assert.Equal(t, 1, id)
+		updated = user
+		return nil
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.UpdateProfile(createContextWithUserId(1), &dto.UpdateProfileRequest{FirstName: "Changed", Email: "new@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", updated.FirstName)
+	assert.Equal(t, "new@example.com", updated.Email)
+	// Empty fields are not updated and the password can not be changed here
+	assert.Equal(t, "", updated.LastName)
+	assert.Equal(t, "", updated.Password)
+}
+
+func TestUpdateProfile_EmailExists(t *testing.T) {
+	repo := profileRepo()
+	repo.ExistsByEmailFn = func(email string) (bool, error) {
+		return email == "taken@example.com", nil
+	}
+	repo.UpdateFn = func(ctx context.Context, id int, user *model.User) error {
+		t.Fatal("profile must not be updated")
+		return nil
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.UpdateProfile(createContextWithUserId(1), &dto.UpdateProfileRequest{Email: "taken@example.com"})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.EmailExists, err.Error())
+}
+
+func TestUpdateProfile_OwnEmailIsNotADuplicate(t *testing.T) {
+	repo := profileRepo()
+	repo.ExistsByEmailFn = func(email string) (bool, error) {
+		return true, nil
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.UpdateProfile(createContextWithUserId(1), &dto.UpdateProfileRequest{Email: "test@example.com"})
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateProfile_MobileNumberExists(t *testing.T) {
+	repo := profileRepo()
+	repo.ExistsByMobileFn = func(mobileNumber string) (bool, error) {
+		return true, nil
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.UpdateProfile(createContextWithUserId(1), &dto.UpdateProfileRequest{MobileNumber: "09121111111"})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.MobileNumberExists, err.Error())
+	assert.Equal(t, http.StatusConflict, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestDeleteAccount_Success(t *testing.T) {
+	repo := profileRepo()
+	var deletedId int
+	repo.DeleteFn = func(ctx context.Context, id int) error {
+		deletedId = id
+		return nil
+	}
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	err := useCase.DeleteAccount(createContextWithUserId(4))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, deletedId)
+	assert.Equal(t, []int{4}, sessions.RevokedUsers)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// deleteAccountStatements deletes the account of user 4 and returns the statements it ran, by the table they update
+func deleteAccountStatements(t *testing.T) ([]string, map[string]string) {
+	t.Helper()
+	pool := &RecordingConnPool{}
+
+	userRepo := repo.NewUserPgRepoWithDb(openRecordingDb(pool), workoutRepo.DeleteAccountData, notificationRepo.DeleteAccountData)
+	err := userRepo.Delete(createContextWithUserId(4), 4)
+
+	assert.NoError(t, err)
+	assert.True(t, pool.Committed)
+	tables := []string{}
+	byTable := map[string]string{}
+	for _, statement := range pool.Statements {
+		table := strings.Trim(strings.Fields(statement)[1], `"`)
+		tables = append(tables, table)
+		byTable[table] = statement
+	}
+	return tables, byTable
+}
+
+func TestPgRepoDelete_CascadesToTheWorkouts(t *testing.T) {
+	tables, statements := deleteAccountStatements(t)
+
+	assert.Equal(t, "users", tables[0])
+	// The workouts go after their rows, which are selected through the workouts that are still active
+	assert.True(t, indexOf(tables, "workouts") > indexOf(tables, "workout_exercises"))
+	assert.True(t, indexOf(tables, "workouts") > indexOf(tables, "scheduled_workouts"))
+	for _, table := range []string{"workout_exercises", "scheduled_workouts"} {
+		assert.Contains(t, statements[table], "workout_id in (select id from workouts where user_id = $")
+	}
+	assert.Contains(t, statements["workouts"], "user_id = $")
+}
+
+func TestPgRepoDelete_CascadesToTheCustomExercises(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	// Only the custom exercises of the user, the catalog has no user
+	assert.Contains(t, statements["exercises"], "deleted_by is null and user_id = $")
+}
+
+func TestPgRepoDelete_CascadesToTheExerciseSets(t *testing.T) {
+	tables, statements := deleteAccountStatements(t)
+
+	assert.Contains(t, statements["exercise_sets"], "where workouts.user_id = $")
+	// The sets are selected through the workout exercises that are still active
+	assert.True(t, indexOf(tables, "exercise_sets") < indexOf(tables, "workout_exercises"))
+}
+
+func TestPgRepoDelete_CascadesToTheWorkoutSessions(t *testing.T) {
+	tables, statements := deleteAccountStatements(t)
+
+	assert.Contains(t, statements["workout_sessions"], "deleted_by is null and user_id = $")
+	assert.Contains(t, statements["workout_session_sets"], "workout_session_id in (select id from workout_sessions where user_id = $")
+	// The sets are selected through the sessions that are still active
+	assert.True(t, indexOf(tables, "workout_session_sets") < indexOf(tables, "workout_sessions"))
+}
+
+func TestPgRepoDelete_CascadesToThePersonalRecords(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	assert.Contains(t, statements["personal_records"], "deleted_by is null and user_id = $")
+}
+
+func TestPgRepoDelete_CascadesToTheGeneratedReports(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	// Reports generated for a period have no workout
+	assert.Contains(t, statements["workout_reports"], "deleted_by is null and user_id = $")
+	assert.NotContains(t, statements["workout_reports"], "workout_id")
+}
+
+func TestPgRepoDelete_CascadesToTheScheduleRules(t *testing.T) {
+	tables, statements := deleteAccountStatements(t)
+
+	assert.Contains(t, statements["schedule_rules"], "workout_id in (select id from workouts where user_id = $")
+	assert.True(t, indexOf(tables, "schedule_rules") < indexOf(tables, "workouts"))
+}
+
+func TestPgRepoDelete_CascadesToTheCalendarFeeds(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	// The subscription URL of a deleted account stops working
+	assert.Contains(t, statements["calendar_feeds"], "deleted_by is null and user_id = $")
+}
+
+func TestPgRepoDelete_CascadesToTheScheduleReminders(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	// The reminders that are not sent yet are skipped by the job once they are deleted
+	assert.Contains(t, statements["schedule_reminders"], "deleted_by is null and user_id = $")
+}
+
+func TestPgRepoDelete_CascadesToTheNotifications(t *testing.T) {
+	_, statements := deleteAccountStatements(t)
+
+	assert.Contains(t, statements["notifications"], "deleted_by is null and user_id = $")
+	assert.Contains(t, statements["notification_preferences"], "deleted_by is null and user_id = $")
+}
+
+func TestPgRepoDelete_RunsTheCleanupsInTheTransaction(t *testing.T) {
+	pool := &RecordingConnPool{}
+	var cleanedUp []int
+	cleanup := func(tx *gorm.DB, userId int, deleteMap map[string]interface{}) error {
+		cleanedUp = append(cleanedUp, userId)
+		return tx.Exec("UPDATE workouts SET deleted_by = ? WHERE user_id = ?", deleteMap["deleted_by"], userId).Error
+	}
+
+	err := repo.NewUserPgRepoWithDb(openRecordingDb(pool), cleanup).Delete(createContextWithUserId(4), 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{4}, cleanedUp)
+	assert.Equal(t, 2, len(pool.Statements))
+	assert.True(t, pool.Committed)
+	assert.False(t, pool.RolledBack)
+}
+
+func TestPgRepoDelete_CleanupErrorKeepsTheAccount(t *testing.T) {
+	pool := &RecordingConnPool{}
+	failing := func(tx *gorm.DB, userId int, deleteMap map[string]interface{}) error {
+		return gorm.ErrForeignKeyViolated
+	}
+
+	err := repo.NewUserPgRepoWithDb(openRecordingDb(pool), failing).Delete(createContextWithUserId(4), 4)
+
+	assert.Error(t, err)
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidReference))
+	assert.True(t, pool.RolledBack)
+}
+
+func TestDeleteAccount_RepositoryError(t *testing.T) {
+	repo := profileRepo()
+	repo.DeleteFn = func(ctx context.Context, id int) error {
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	err := useCase.DeleteAccount(createContextWithUserId(4))
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(sessions.RevokedUsers))
+}
+
+// ==================== PROFILE HANDLER TESTS ====================
+
+func profileRouter(repo *MockUserRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+	router := gin.New()
+	me := router.Group("/v1/account/me", func(c *gin.Context) {
+		c.Set(constants.UserIdKey, float64(1))
+		c.Next()
+	})
+	me.GET("", accountHandler.GetProfile)
+	me.PUT("", accountHandler.UpdateProfile)
+	me.DELETE("", accountHandler.DeleteAccount)
+	return router
+}
+
+func TestGetProfile_Handler_Success(t *testing.T) {
+	router := profileRouter(profileRepo())
+
+	req, _ := http.NewRequest("GET", "/v1/account/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	result := response.Result.(map[string]interface{})
+	assert.Equal(t, "testuser", result["username"])
+	_, hasPassword := result["password"]
+	assert.False(t, hasPassword)
+}
+
+func TestUpdateProfile_Handler_InvalidEmail(t *testing.T) {
+	router := profileRouter(profileRepo())
+
+	jsonData, _ := json.Marshal(dto.UpdateProfileRequest{Email: "not-an-email"})
+	req, _ := http.NewRequest("PUT", "/v1/account/me", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteAccount_Handler_Success(t *testing.T) {
+	router := profileRouter(profileRepo())
+
+	req, _ := http.NewRequest("DELETE", "/v1/account/me", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,171 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRedisStore(t *testing.T) (*limiter.RedisStore, *miniredis.Miniredis) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return limiter.NewRedisStore(client), server
+}
+
+func TestMemoryStore_CountsPerWindow(t *testing.T) {
+	store := limiter.NewMemoryStore()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, store, "ip:1", 2, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i <= 2, result.Allowed)
+	}
+	// Other keys have their own window
+	result, err := limiter.Allow(ctx, store, "ip:2", 2, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+
+	result, err = limiter.Allow(ctx, store, "ip:3", 1, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	time.Sleep(20 * time.Millisecond)
+	result, err = limiter.Allow(ctx, store, "ip:3", 1, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+}
+
+func TestRedisStore_CountsPerWindow(t *testing.T) {
+	store, server := newRedisStore(t)
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		result, err := limiter.Allow(ctx, store, "user:1", 2, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, i <= 2, result.Allowed)
+		assert.True(t, result.ResetIn > 0 && result.ResetIn <= time.Minute)
+	}
+
+	server.FastForward(time.Minute)
+	result, err := limiter.Allow(ctx, store, "user:1", 2, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 1, result.Remaining)
+}
+
+func setupRateLimitRouter(store limiter.Store, tokenProvider *MockTokenProvider) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.RateLimitConfig{
+		Requests: 3,
+		Window:   60,
+		Routes: []config.RouteRateLimitConfig{
+			{Path: "/api/v1/account/login", Requests: 1, Window: 60},
+		},
+	}
+	router := gin.New()
+	router.Use(middlewares.LimitByRequest(cfg, store, tokenProvider))
+	router.POST("/api/v1/account/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/v1/workouts/workout/1", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func sendRateLimited(router *gin.Engine, method, path, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	if token != "" {
+		req.Header.Set(constants.AuthorizationHeaderKey, "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLimitByRequest_StricterRouteLimit(t *testing.T) {
+	store, _ := newRedisStore(t)
+	router := setupRateLimitRouter(store, &MockTokenProvider{
+		GetClaimsFn: func(token string) (map[string]interface{}, error) { return nil, errors.New("invalid") },
+	})
+
+	w := sendRateLimited(router, "POST", "/api/v1/account/login", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(constants.RateLimitLimitHeaderKey))
+	assert.Equal(t, "0", w.Header().Get(constants.RateLimitRemainingHeaderKey))
+	assert.Equal(t, "60", w.Header().Get(constants.RateLimitResetHeaderKey))
+
+	w = sendRateLimited(router, "POST", "/api/v1/account/login", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get(constants.RetryAfterHeaderKey))
+	assert.Contains(t, w.Body.String(), "too many requests")
+
+	// The other routes use the default limit
+	w = sendRateLimited(router, "GET", "/api/v1/workouts/workout/1", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get(constants.RateLimitLimitHeaderKey))
+	assert.Equal(t, "", w.Header().Get(constants.RetryAfterHeaderKey))
+}
+
+func TestLimitByRequest_KeyedByUserId(t *testing.T) {
+	router := setupRateLimitRouter(limiter.NewMemoryStore(), &MockTokenProvider{
+		GetClaimsFn: func(token string) (map[string]interface{}, error) {
+			if token == "user-1" {
+				return map[string]interface{}{constants.UserIdKey: float64(1)}, nil
+			}
+			return map[string]interface{}{constants.UserIdKey: float64(2)}, nil
+		},
+	})
+
+	for i := 0; i < 3; i++ {
+		w := sendRateLimited(router, "GET", "/api/v1/workouts/workout/1", "user-1")
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	w := sendRateLimited(router, "GET", "/api/v1/workouts/workout/1", "user-1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// Same ip, another user
+	w = sendRateLimited(router, "GET", "/api/v1/workouts/workout/1", "user-2")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get(constants.RateLimitRemainingHeaderKey))
+}
+
+func TestLimitByRequest_StoreErrorAllowsRequest(t *testing.T) {
+	store, server := newRedisStore(t)
+	server.Close()
+	router := setupRateLimitRouter(store, &MockTokenProvider{})
+
+	w := sendRateLimited(router, "POST", "/api/v1/account/login", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "", w.Header().Get(constants.RateLimitLimitHeaderKey))
+}
+
+func TestStores_GetDoesNotCount(t *testing.T) {
+	redisStore, _ := newRedisStore(t)
+	ctx := context.Background()
+	for _, store := range []limiter.Store{limiter.NewMemoryStore(), redisStore} {
+		count, _, err := store.Get(ctx, "ip:1")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		_, _, err = store.Take(ctx, "ip:1", time.Minute)
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			count, resetIn, err := store.Get(ctx, "ip:1")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+			assert.True(t, resetIn > 0)
+		}
+	}
+}
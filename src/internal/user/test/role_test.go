@@ -0,0 +1,290 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func withRoles(names ...string) *[]model.UserRole {
+	userRoles := []model.UserRole{}
+	for _, name := range names {
+		userRoles = append(userRoles, model.UserRole{Role: model.Role{Name: name}})
+	}
+	return &userRoles
+}
+
+func TestLoginUser_TokenContainsRoles(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	repo := &MockUserRepository{
+		FindByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			return &model.User{
+				Id:        1,
+				Username:  "testuser",
+				Password:  string(hashedPassword),
+				Enabled:   true,
+				UserRoles: withRoles(constants.DefaultRoleName, constants.AdminRoleName),
+			}, nil
+		},
+	}
+	var payload *entity.TokenPayload
+	mockToken := &MockTokenProvider{
+		GenerateTokenFn: func(token *entity.TokenPayload) (*dto.TokenDetail, error) {
+			payload = token
+			return &dto.TokenDetail{AccessToken: "token", RefreshToken: "refresh"}, nil
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	_, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constants.DefaultRoleName, constants.AdminRoleName}, payload.Roles)
+}
+
+func TestLoginUser_Disabled(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	repo := &MockUserRepository{
+		FindByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			return &model.User{Username: "testuser", Password: string(hashedPassword), Enabled: false}, nil
+		},
+	}
+	useCase, _ := setup(repo)
+
+	tokenDetail, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+
+	assert.Error(t, err)
+	assert.True(t, tokenDetail == nil)
+	assert.Equal(t, service_errors.UserDisabled, err.Error())
+	assert.Equal(t, http.StatusForbidden, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestGetUsers_Success(t *testing.T) {
+	repo := &MockUserRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]model.User, error) {
+			users := []model.User{
+				{Id: 1, Username: "admin", Enabled: true, UserRoles: withRoles(constants.AdminRoleName)},
+				{Id: 2, Username: "athlete", Enabled: false},
+			}
+			return 12, &users, nil
+		},
+	}
+	useCase, _ := setup(repo)
+
+	res, err := useCase.GetUsers(context.Background(), filter.PaginationInputWithFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), res.TotalRows)
+	assert.Equal(t, 2, res.TotalPages)
+	assert.Equal(t, 2, len(*res.Items))
+	assert.Equal(t, []string{constants.AdminRoleName}, (*res.Items)[0].Roles)
+	assert.Equal(t, false, (*res.Items)[1].Enabled)
+}
+
+func TestSetUserEnabled_Disable(t *testing.T) {
+	var disabledId int
+	repo := &MockUserRepository{
+		SetEnabledFn: func(ctx context.Context, id int, enabled bool) error {
+			assert.Equal(t, false, enabled)
+			disabledId = id
+			return nil
+		},
+		GetByIDFn: func(ctx context.Context, id int) (*model.User, error) {
+			return &model.User{Id: id, Username: "athlete", Enabled: false}, nil
+		},
+	}
+	useCase, _ := setup(repo)
+
+	res, err := useCase.SetUserEnabled(context.Background(), 5, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, disabledId)
+	assert.Equal(t, false, res.Enabled)
+}
+
+func TestSetUserEnabled_NotFound(t *testing.T) {
+	repo := &MockUserRepository{
+		SetEnabledFn: func(ctx context.Context, id int, enabled bool) error {
+			return service_errors.New(service_errors.CodeRecordNotFound)
+		},
+	}
+	useCase, _ := setup(repo)
+
+	_, err := useCase.SetUserEnabled(context.Background(), 5, true)
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.RecordNotFound, err.Error())
+}
+
+func TestEnsureAdminUser_CreatesAdmin(t *testing.T) {
+	var grantedRole string
+	repo := &MockUserRepository{
+		CreateFn: func(ctx context.Context, user *model.User) error {
+			assert.Equal(t, "root", user.Username)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("Secret@123")))
+			user.Id = 3
+			return nil
+		},
+		AddRoleFn: func(ctx context.Context, userId int, roleName string) error {
+			assert.Equal(t, 3, userId)
+			grantedRole = roleName
+			return nil
+		},
+	}
+	cfg := &config.Config{Admin: config.AdminConfig{Username: "root", Password: "Secret@123"}}
+	useCase := usecase.NewUserUsecase(cfg, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.EnsureAdminUser(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, constants.AdminRoleName, grantedRole)
+}
+
+func TestEnsureAdminUser_ExistingUserUntouched(t *testing.T) {
+	repo := &MockUserRepository{
+		ExistsByUsernameFn: func(username string) (bool, error) {
+			assert.Equal(t, constants.DefaultUserName, username)
+			return true, nil
+		},
+		AddRoleFn: func(ctx context.Context, userId int, roleName string) error {
+			t.Fatal("existing users must not be granted the admin role")
+			return nil
+		},
+	}
+	cfg := &config.Config{Admin: config.AdminConfig{Password: "Secret@123"}}
+	useCase := usecase.NewUserUsecase(cfg, repo, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.EnsureAdminUser(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, repo.SaveCalled)
+}
+
+func TestEnsureAdminUser_NoPasswordConfigured(t *testing.T) {
+	repo := &MockUserRepository{}
+	useCase, _ := setup(repo)
+
+	err := useCase.EnsureAdminUser(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, repo.SaveCalled)
+}
+
+func performWithRoles(roles interface{}, validRoles ...string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", func(c *gin.Context) {
+		if roles != nil {
+			c.Set(constants.RolesKey, roles)
+		}
+		c.Next()
+	}, middlewares.Authorization(validRoles...), func(c *gin.Context) {
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+	})
+
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthorization_AllowsMatchingRole(t *testing.T) {
+	// Roles are decoded from the token claims as []interface{}
+	w := performWithRoles([]interface{}{constants.DefaultRoleName, constants.AdminRoleName}, constants.AdminRoleName)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorization_RejectsMissingRole(t *testing.T) {
+	w := performWithRoles([]interface{}{constants.DefaultRoleName}, constants.AdminRoleName)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.ForbiddenError, response.ResultCode)
+	assert.Equal(t, service_errors.PermissionDenied, response.Error)
+}
+
+func TestAuthorization_RejectsNoRolesClaim(t *testing.T) {
+	w := performWithRoles(nil, constants.AdminRoleName)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthorization_RejectsInvalidRolesFormat(t *testing.T) {
+	w := performWithRoles([]interface{}{1, 2}, constants.AdminRoleName)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, service_errors.InvalidRolesFormat, response.Error)
+}
+
+func TestDisableUser_Handler_InvalidId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useCase, _ := setup(&MockUserRepository{})
+	userHandler := &handler.UserHandler{Usecase: useCase}
+
+	router := gin.New()
+	router.PUT("/v1/users/:id/disable", userHandler.Disable)
+
+	req, _ := http.NewRequest("PUT", "/v1/users/abc/disable", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func performWithToken(getClaims func(token string) (map[string]interface{}, error)) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", middlewares.Authentication(&config.Config{}, &MockTokenProvider{GetClaimsFn: getClaims}), func(c *gin.Context) {
+		c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, helper.Success))
+	})
+
+	req, _ := http.NewRequest("GET", "/me", nil)
+	req.Header.Set(constants.AuthorizationHeaderKey, "Bearer token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthentication_ExpiredToken(t *testing.T) {
+	w := performWithToken(func(token string) (map[string]interface{}, error) {
+		return nil, &jwt.ValidationError{Errors: jwt.ValidationErrorExpired}
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), service_errors.TokenExpired)
+}
+
+func TestAuthentication_TokenWithoutClaimsIsInvalid(t *testing.T) {
+	// Not a jwt validation error, it must not panic
+	w := performWithToken(func(token string) (map[string]interface{}, error) {
+		return nil, service_errors.New(service_errors.CodeClaimsNotFound)
+	})
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), service_errors.TokenInvalid)
+}
@@ -0,0 +1,359 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/auth"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/user/adapter/http/handler"
+	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ==================== REFRESH TOKEN ROTATION TESTS ====================
+
+func TestLoginUser_StoresRefreshToken(t *testing.T) {
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.DefaultCost)
+	repo := &MockUserRepository{
+		FindByUsernameFn: func(ctx context.Context, username string) (*model.User, error) {
+			return &model.User{Id: 7, Username: "testuser", Password: string(hashedPassword), Enabled: true}, nil
+		},
+	}
+	var payload *entity.TokenPayload
+	mockToken := &MockTokenProvider{
+		GenerateTokenFn: func(token *entity.TokenPayload) (*dto.TokenDetail, error) {
+			payload = token
+			return &dto.TokenDetail{AccessToken: "token", RefreshToken: "refresh", RefreshTokenExpireTime: 1700000000}, nil
+		},
+	}
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, mockToken, sessions, limiter.NewMemoryStore())
+
+	_, err := useCase.LoginByUsername(context.Background(), &dto.LoginByUsernameRequest{Username: "testuser", Password: "password"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(sessions.Created))
+	stored := sessions.Created[0]
+	assert.Equal(t, 7, stored.UserId)
+	assert.Equal(t, payload.TokenId, stored.TokenId)
+	assert.Equal(t, payload.SessionId, stored.SessionId)
+	assert.NotEqual(t, stored.TokenId, stored.SessionId)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), stored.ExpiresAt)
+}
+
+func TestRefreshToken_RotatesWithinSession(t *testing.T) {
+	var payload *entity.TokenPayload
+	mockToken := &MockTokenProvider{
+		GenerateTokenFn: func(token *entity.TokenPayload) (*dto.TokenDetail, error) {
+			payload = token
+			return &dto.TokenDetail{AccessToken: "new-token", RefreshToken: "new-refresh"}, nil
+		},
+	}
+	var rotatedId string
+	sessions := &MockRefreshTokenRepository{
+		RotateFn: func(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+			rotatedId = tokenId
+			assert.Equal(t, "session-id", next.SessionId)
+			assert.Equal(t, payload.TokenId, next.TokenId)
+			return nil
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, mockToken, sessions, limiter.NewMemoryStore())
+
+	tokenDetail, err := useCase.RefreshToken(context.Background(), "refresh")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-refresh", tokenDetail.RefreshToken)
+	assert.Equal(t, "token-id", rotatedId)
+	assert.NotEqual(t, "token-id", payload.TokenId)
+	assert.Equal(t, "session-id", payload.SessionId)
+}
+
+func TestRefreshToken_ReusedTokenRevokesSession(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{
+		GetByTokenIdFn: func(ctx context.Context, tokenId string) (*model.RefreshToken, error) {
+			return &model.RefreshToken{
+				UserId:    1,
+				TokenId:   tokenId,
+				SessionId: "session-id",
+				RevokedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			}, nil
+		},
+		RotateFn: func(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+			t.Fatal("a revoked token must not be rotated")
+			return nil
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	tokenDetail, err := useCase.RefreshToken(context.Background(), "stolen-refresh")
+
+	assert.Error(t, err)
+	assert.True(t, tokenDetail == nil)
+	assert.Equal(t, service_errors.RefreshTokenReused, err.Error())
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+	assert.Equal(t, http.StatusUnauthorized, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestRefreshToken_ExpiredTokenRevokesSession(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{
+		GetByTokenIdFn: func(ctx context.Context, tokenId string) (*model.RefreshToken, error) {
+			return &model.RefreshToken{
+				UserId:    1,
+				TokenId:   tokenId,
+				SessionId: "session-id",
+				ExpiresAt: time.Now().Add(-time.Minute),
+			}, nil
+		},
+		RotateFn: func(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+			t.Fatal("an expired token must not be rotated")
+			return nil
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	tokenDetail, err := useCase.RefreshToken(context.Background(), "expired-refresh")
+
+	assert.Error(t, err)
+	assert.True(t, tokenDetail == nil)
+	assert.Equal(t, service_errors.InvalidRefreshToken, err.Error())
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+}
+
+func TestRefreshToken_ConcurrentRotationRevokesSession(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{
+		RotateFn: func(ctx context.Context, tokenId string, next *model.RefreshToken) error {
+			return service_errors.New(service_errors.CodeRefreshTokenReused)
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	_, err := useCase.RefreshToken(context.Background(), "refresh")
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.RefreshTokenReused, err.Error())
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+}
+
+func TestRefreshToken_UnknownToken(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{
+		GetByTokenIdFn: func(ctx context.Context, tokenId string) (*model.RefreshToken, error) {
+			return nil, service_errors.New(service_errors.CodeRecordNotFound)
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	_, err := useCase.RefreshToken(context.Background(), "refresh")
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.InvalidRefreshToken, err.Error())
+}
+
+func TestRefreshToken_MissingTokenIdClaim(t *testing.T) {
+	// Refresh tokens issued before rotation was introduced have no token id
+	mockToken := &MockTokenProvider{
+		GetRefreshClaimsFn: func(refreshToken string) (map[string]interface{}, error) {
+			return map[string]interface{}{constants.UserIdKey: float64(1)}, nil
+		},
+	}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	_, err := useCase.RefreshToken(context.Background(), "legacy-refresh")
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.InvalidRefreshToken, err.Error())
+}
+
+func TestRefreshToken_DisabledUser(t *testing.T) {
+	repo := &MockUserRepository{
+		GetByIDFn: func(ctx context.Context, id int) (*model.User, error) {
+			return &model.User{Id: id, Username: "testuser", Enabled: false}, nil
+		},
+	}
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, repo, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	_, err := useCase.RefreshToken(context.Background(), "refresh")
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.UserDisabled, err.Error())
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+}
+
+// ==================== LOGOUT TESTS ====================
+
+func TestLogout_RevokesSession(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	err := useCase.Logout(context.Background(), "session-id")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+}
+
+func TestLogout_NoSession(t *testing.T) {
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
+
+	err := useCase.Logout(context.Background(), "")
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.TokenInvalid, err.Error())
+}
+
+func TestLogoutAll_RevokesEverySession(t *testing.T) {
+	sessions := &MockRefreshTokenRepository{}
+	useCase := usecase.NewUserUsecase(&config.Config{}, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore())
+
+	err := useCase.LogoutAll(createContextWithUserId(3))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3}, sessions.RevokedUsers)
+}
+
+// ==================== HANDLER TESTS ====================
+
+func newAccountHandler(cfg *config.Config, sessions *MockRefreshTokenRepository) *handler.AccountHandler {
+	return &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, &MockUserRepository{}, &MockTokenProvider{}, sessions, limiter.NewMemoryStore()),
+		Cfg:     cfg,
+	}
+}
+
+func TestRefreshTokenHandler_FromBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var claimedToken string
+	cfg := &config.Config{}
+	accountHandler := &handler.AccountHandler{
+		Usecase: usecase.NewUserUsecase(cfg, &MockUserRepository{}, &MockTokenProvider{
+			GetRefreshClaimsFn: func(refreshToken string) (map[string]interface{}, error) {
+				claimedToken = refreshToken
+				return map[string]interface{}{constants.TokenIdKey: "token-id"}, nil
+			},
+		}, &MockRefreshTokenRepository{}, limiter.NewMemoryStore()),
+		Cfg: cfg,
+	}
+
+	router := gin.New()
+	router.POST("/v1/account/refresh-token", accountHandler.RefreshToken)
+
+	jsonData, _ := json.Marshal(dto.RefreshTokenRequest{RefreshToken: "bodyRefreshToken"})
+	req, _ := http.NewRequest("POST", "/v1/account/refresh-token", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bodyRefreshToken", claimedToken)
+	// The cookie is only written when it is enabled in the config
+	assert.Equal(t, 0, len(w.Result().Cookies()))
+}
+
+func TestLogoutHandler_ClearsCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sessions := &MockRefreshTokenRepository{}
+	cfg := &config.Config{JWT: config.JWTConfig{RefreshTokenCookie: true}}
+	accountHandler := newAccountHandler(cfg, sessions)
+
+	router := gin.New()
+	router.POST("/v1/account/logout", func(c *gin.Context) {
+		c.Set(constants.SessionIdKey, "session-id")
+		c.Next()
+	}, accountHandler.Logout)
+
+	req, _ := http.NewRequest("POST", "/v1/account/logout", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"session-id"}, sessions.RevokedSessions)
+	cookies := w.Result().Cookies()
+	assert.Equal(t, 1, len(cookies))
+	assert.Equal(t, constants.RefreshTokenCookieName, cookies[0].Name)
+	assert.Equal(t, "", cookies[0].Value)
+	assert.True(t, cookies[0].MaxAge < 0)
+}
+
+func TestLogoutAllHandler_Unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	accountHandler := newAccountHandler(&config.Config{}, &MockRefreshTokenRepository{})
+
+	router := gin.New()
+	router.POST("/v1/account/logout-all", accountHandler.LogoutAll)
+
+	req, _ := http.NewRequest("POST", "/v1/account/logout-all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, service_errors.UserIdNotFound, response.Error)
+	assert.Equal(t, service_errors.CodeUserIdNotFound, response.ErrorCode)
+}
+
+// ==================== JWT TESTS ====================
+
+func TestJwtProvider_RefreshClaims(t *testing.T) {
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:                     "access-secret",
+		RefreshSecret:              "refresh-secret",
+		AccessTokenExpireDuration:  5,
+		RefreshTokenExpireDuration: 60,
+	}}
+	provider := auth.NewJwtProvider(cfg)
+
+	td, err := provider.GenerateToken(&entity.TokenPayload{UserId: 1, SessionId: "session-id", TokenId: "token-id"})
+	assert.NoError(t, err)
+
+	claims, err := provider.GetRefreshClaims(td.RefreshToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "token-id", claims[constants.TokenIdKey])
+	assert.Equal(t, "session-id", claims[constants.SessionIdKey])
+	assert.Equal(t, interface{}(float64(td.RefreshTokenExpireTime)), claims[constants.StandardExpireTimeKey])
+
+	// Access tokens are signed with a different secret and can not be used to refresh
+	_, err = provider.GetRefreshClaims(td.AccessToken)
+	assert.Error(t, err)
+
+	accessClaims, err := provider.GetClaims(td.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "session-id", accessClaims[constants.SessionIdKey])
+	_, ok := accessClaims[constants.TokenIdKey]
+	assert.False(t, ok)
+}
+
+func TestJwtProvider_ExpiredRefreshToken(t *testing.T) {
+	cfg := &config.Config{JWT: config.JWTConfig{
+		Secret:                     "access-secret",
+		RefreshSecret:              "refresh-secret",
+		AccessTokenExpireDuration:  -1,
+		RefreshTokenExpireDuration: -1,
+	}}
+	provider := auth.NewJwtProvider(cfg)
+
+	td, err := provider.GenerateToken(&entity.TokenPayload{UserId: 1, SessionId: "session-id", TokenId: "token-id"})
+	assert.NoError(t, err)
+
+	_, err = provider.GetRefreshClaims(td.RefreshToken)
+	assert.Error(t, err)
+	_, err = provider.VerifyToken(td.AccessToken)
+	assert.Error(t, err)
+}
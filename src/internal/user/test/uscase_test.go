@@ -10,6 +10,7 @@ import (
 	model "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/user/core/usecase"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/limiter"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -105,6 +106,7 @@ func TestLoginUser_Success(t *testing.T) {
 			return &model.User{
 				Username:  "testuser",
 				Password:  string(hashedPassword),
+				Enabled:   true,
 				Email:     "testuser@example.com",
 				FirstName: "Test",
 				LastName:  "User",
@@ -150,6 +152,7 @@ func TestLoginUser_InvalidPassword(t *testing.T) {
 			return &model.User{
 				Username:  "testuser",
 				Password:  string(hashedPassword),
+				Enabled:   true,
 				Email:     "testuser@example.com",
 				FirstName: "Test",
 				LastName:  "User",
@@ -174,6 +177,7 @@ func TestLoginUser_GenerateTokenError(t *testing.T) {
 			return &model.User{
 				Username:  "testuser",
 				Password:  string(hashedPassword),
+				Enabled:   true,
 				Email:     "testuser@example.com",
 				FirstName: "Test",
 				LastName:  "User",
@@ -196,7 +200,7 @@ func TestRefreshToken_Success(t *testing.T) {
 	repo := &MockUserRepository{}
 	useCase, _ := setup(repo)
 
-	tokenDetail, err := useCase.RefreshToken("valid-refresh-token")
+	tokenDetail, err := useCase.RefreshToken(context.Background(), "valid-refresh-token")
 	assert.NoError(t, err)
 	assert.True(t, tokenDetail != nil)
 	assert.True(t, tokenDetail.AccessToken != "")
@@ -209,13 +213,13 @@ func TestRefreshToken_Error(t *testing.T) {
 	mockConfig := &config.Config{}
 	mockRepo := &MockUserRepository{}
 	mockToken := &MockTokenProvider{
-		RefreshTokenFn: func(refreshToken string) (*dto.TokenDetail, error) {
+		GetRefreshClaimsFn: func(refreshToken string) (map[string]interface{}, error) {
 			return nil, errors.New("refresh token error")
 		},
 	}
-	useCase := usecase.NewUserUsecase(mockConfig, mockRepo, mockToken)
+	useCase := usecase.NewUserUsecase(mockConfig, mockRepo, mockToken, &MockRefreshTokenRepository{}, limiter.NewMemoryStore())
 
-	tokenDetail, err := useCase.RefreshToken("invalid-refresh-token")
+	tokenDetail, err := useCase.RefreshToken(context.Background(), "invalid-refresh-token")
 	assert.Error(t, err)
 	assert.True(t, tokenDetail == nil)
 }
@@ -1,11 +1,19 @@
 package dto
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
 )
 
+// The secret fields are redacted from the logged bodies, a dto with a secret field is registered here
+func init() {
+	logging.RegisterSecrets(CalendarFeedResponse{})
+}
+
 type CreateWorkoutRequest struct {
 	Name        string `json:"name" binding:"required,min=3"`
 	Description string `json:"description"`
@@ -51,7 +59,8 @@ func ToCreateWorkoutRequest(from CreateWorkoutRequest) dto.CreateWorkoutRequest
 // WorkoutExercise
 type CreateWorkoutExerciseRequest struct {
 	WorkoutId   int     `json:"workout_id" binding:"required"`
-	Name        string  `json:"name" binding:"required,min=3"`
+	ExerciseId  *int    `json:"exercise_id" binding:"required_without=Name"`
+	Name        string  `json:"name" binding:"omitempty,min=3"`
 	Description string  `json:"description"`
 	Reps        int     `json:"reps" binding:"required"`
 	Sets        int     `json:"sets" binding:"required"`
@@ -60,7 +69,8 @@ type CreateWorkoutExerciseRequest struct {
 
 type UpdateWorkoutExerciseRequest struct {
 	WorkoutId   int     `json:"workout_id" binding:"required"`
-	Name        string  `json:"name" binding:"required,min=3"`
+	ExerciseId  *int    `json:"exercise_id" binding:"required_without=Name"`
+	Name        string  `json:"name" binding:"omitempty,min=3"`
 	Description string  `json:"description"`
 	Reps        int     `json:"reps" binding:"required"`
 	Sets        int     `json:"sets" binding:"required"`
@@ -69,28 +79,37 @@ type UpdateWorkoutExerciseRequest struct {
 type WorkoutExerciseResponse struct {
 	Id          int     `json:"id"`
 	WorkoutId   int     `json:"workout_id"`
+	ExerciseId  *int    `json:"exercise_id"`
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Reps        int     `json:"reps"`
 	Sets        int     `json:"sets"`
 	Weight      float64 `json:"weight"`
+	// PersonalRecords are the records broken by a created or updated workout exercise
+	PersonalRecords []PersonalRecordResponse `json:"personal_records,omitempty"`
 }
 
 func ToWorkoutExerciseResponse(from dto.WorkoutExerciseResponse) WorkoutExerciseResponse {
-	return WorkoutExerciseResponse{
+	response := WorkoutExerciseResponse{
 		Id:          from.Id,
 		WorkoutId:   from.WorkoutId,
+		ExerciseId:  from.ExerciseId,
 		Name:        from.Name,
 		Description: from.Description,
 		Reps:        from.Repetitions,
 		Sets:        from.Sets,
 		Weight:      from.Weight,
 	}
+	for _, record := range from.PersonalRecords {
+		response.PersonalRecords = append(response.PersonalRecords, ToPersonalRecordResponse(record))
+	}
+	return response
 }
 func ToCreateWorkoutExerciseRequest(from CreateWorkoutExerciseRequest) dto.CreateWorkoutExerciseRequest {
 	return dto.CreateWorkoutExerciseRequest{
 		Name:        from.Name,
 		WorkoutId:   from.WorkoutId,
+		ExerciseId:  from.ExerciseId,
 		Description: from.Description,
 		Repetitions: from.Reps,
 		Sets:        from.Sets,
@@ -102,6 +121,7 @@ func ToUpdateWorkoutExerciseRequest(from UpdateWorkoutExerciseRequest) dto.Updat
 	return dto.UpdateWorkoutExerciseRequest{
 		Name:        from.Name,
 		WorkoutId:   from.WorkoutId,
+		ExerciseId:  from.ExerciseId,
 		Description: from.Description,
 		Repetitions: from.Reps,
 		Sets:        from.Sets,
@@ -109,55 +129,193 @@ func ToUpdateWorkoutExerciseRequest(from UpdateWorkoutExerciseRequest) dto.Updat
 	}
 }
 
+// ExerciseSet
+type CreateExerciseSetRequest struct {
+	SetIndex    int      `json:"set_index" binding:"omitempty,min=1,max=100"`
+	Reps        int      `json:"reps" binding:"min=0"`
+	Weight      float64  `json:"weight" binding:"min=0"`
+	Rpe         *float64 `json:"rpe" binding:"omitempty,min=1,max=10"`
+	RestSeconds int      `json:"rest_seconds" binding:"min=0"`
+	SetType     string   `json:"set_type" binding:"omitempty,oneof=warmup working drop failure"`
+	Completed   *bool    `json:"completed"`
+}
+
+type UpdateExerciseSetRequest struct {
+	SetIndex    int      `json:"set_index" binding:"omitempty,min=1,max=100"`
+	Reps        int      `json:"reps" binding:"min=0"`
+	Weight      float64  `json:"weight" binding:"min=0"`
+	Rpe         *float64 `json:"rpe" binding:"omitempty,min=1,max=10"`
+	RestSeconds int      `json:"rest_seconds" binding:"min=0"`
+	SetType     string   `json:"set_type" binding:"omitempty,oneof=warmup working drop failure"`
+	Completed   *bool    `json:"completed"`
+}
+
+type ExerciseSetResponse struct {
+	Id                int      `json:"id"`
+	WorkoutExerciseId int      `json:"workout_exercise_id"`
+	SetIndex          int      `json:"set_index"`
+	Reps              int      `json:"reps"`
+	Weight            float64  `json:"weight"`
+	Rpe               *float64 `json:"rpe"`
+	RestSeconds       int      `json:"rest_seconds"`
+	SetType           string   `json:"set_type"`
+	Completed         bool     `json:"completed"`
+	// PersonalRecords are the records broken by a created or updated set
+	PersonalRecords []PersonalRecordResponse `json:"personal_records,omitempty"`
+}
+
+func ToExerciseSetResponse(from dto.ExerciseSetResponse) ExerciseSetResponse {
+	response := ExerciseSetResponse{
+		Id:                from.Id,
+		WorkoutExerciseId: from.WorkoutExerciseId,
+		SetIndex:          from.SetIndex,
+		Reps:              from.Repetitions,
+		Weight:            from.Weight,
+		Rpe:               from.Rpe,
+		RestSeconds:       from.RestSeconds,
+		SetType:           from.SetType,
+		Completed:         from.Completed != nil && *from.Completed,
+	}
+	for _, record := range from.PersonalRecords {
+		response.PersonalRecords = append(response.PersonalRecords, ToPersonalRecordResponse(record))
+	}
+	return response
+}
+
+func ToCreateExerciseSetRequest(from CreateExerciseSetRequest) dto.CreateExerciseSetRequest {
+	return dto.CreateExerciseSetRequest{
+		SetIndex:    from.SetIndex,
+		Repetitions: from.Reps,
+		Weight:      from.Weight,
+		Rpe:         from.Rpe,
+		RestSeconds: from.RestSeconds,
+		SetType:     from.SetType,
+		Completed:   from.Completed,
+	}
+}
+
+func ToUpdateExerciseSetRequest(from UpdateExerciseSetRequest) dto.UpdateExerciseSetRequest {
+	return dto.UpdateExerciseSetRequest{
+		SetIndex:    from.SetIndex,
+		Repetitions: from.Reps,
+		Weight:      from.Weight,
+		Rpe:         from.Rpe,
+		RestSeconds: from.RestSeconds,
+		SetType:     from.SetType,
+		Completed:   from.Completed,
+	}
+}
+
 // ScheduledWorkoutss
 type ScheduledWorkoutsResponse struct {
-	Id            int    `json:"id"`
-	WorkoutId     int    `json:"workout_id"`
-	ScheduledTime string `json:"scheduled_time"` //ScheduledTime
-	Status        string `json:"status"`
+	Id             int        `json:"id"`
+	WorkoutId      int        `json:"workout_id"`
+	ScheduledTime  string     `json:"scheduled_time"` //ScheduledTime
+	Status         string     `json:"status"`
+	ScheduleRuleId *int       `json:"schedule_rule_id,omitempty"`
+	OccurrenceTime *time.Time `json:"occurrence_time,omitempty"`
 }
 
 type CreateScheduledWorkoutsRequest struct {
-	WorkoutId     int       `json:"workout_id" binding:"required"`
-	ScheduledTime time.Time `json:"scheduled_time" binding:"required"`
-	Status        string    `json:"status" binding:"required"`
+	WorkoutId     int                `json:"workout_id" binding:"required"`
+	ScheduledTime time.Time          `json:"scheduled_time" binding:"required"`
+	Recurrence    *RecurrenceRequest `json:"recurrence"`
 }
 
+// UpdateScheduledWorkoutsRequest updates a scheduled workout, scope moves the following or all
+// the occurrences of a recurring one with it. The status keeps its value when it is empty.
 type UpdateScheduledWorkoutsRequest struct {
 	ScheduledTime time.Time `json:"scheduled_time" binding:"required"`
-	Status        string    `json:"status" binding:"required"`
+	Status        string    `json:"status"`
+	Scope         string    `json:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+// RescheduleScheduledWorkoutsRequest plans a scheduled workout again at another time
+type RescheduleScheduledWorkoutsRequest struct {
+	ScheduledTime time.Time `json:"scheduled_time" binding:"required"`
+	Scope         string    `json:"scope" binding:"omitempty,oneof=this following all"`
+}
+
+// RecurrenceRequest repeats a scheduled workout from its scheduled time like an iCalendar RRULE
+type RecurrenceRequest struct {
+	Frequency  string      `json:"frequency" binding:"required,oneof=daily weekly monthly"`
+	Interval   int         `json:"interval" binding:"omitempty,min=1,max=365"`
+	ByDay      []string    `json:"by_day" binding:"omitempty,dive,oneof=MO TU WE TH FR SA SU"`
+	Count      *int        `json:"count" binding:"omitempty,min=1"`
+	Until      *time.Time  `json:"until"`
+	TimeZone   string      `json:"time_zone" binding:"max=64"`
+	Exceptions []time.Time `json:"exceptions"`
+}
+
+type ExpandScheduledWorkoutsRequest struct {
+	From           time.Time `json:"from" binding:"required"`
+	To             time.Time `json:"to" binding:"required"`
+	ScheduleRuleId *int      `json:"schedule_rule_id" binding:"omitempty,min=1"`
 }
 
 func ToScheduledWorkoutsResponse(from dto.ScheduledWorkoutsResponse) ScheduledWorkoutsResponse {
 	return ScheduledWorkoutsResponse{
-		Id:            from.Id,
-		WorkoutId:     from.WorkoutId,
-		Status:        from.Status,
-		ScheduledTime: from.ScheduledTime,
+		Id:             from.Id,
+		WorkoutId:      from.WorkoutId,
+		Status:         from.Status,
+		ScheduledTime:  from.ScheduledTime,
+		ScheduleRuleId: from.ScheduleRuleId,
+		OccurrenceTime: from.OccurrenceTime,
+	}
+}
+
+func ToScheduledWorkoutsResponses(from []dto.ScheduledWorkoutsResponse) []ScheduledWorkoutsResponse {
+	response := []ScheduledWorkoutsResponse{}
+	for _, scheduled := range from {
+		response = append(response, ToScheduledWorkoutsResponse(scheduled))
 	}
+	return response
 }
 
 func ToCreateScheduledWorkoutsRequest(from CreateScheduledWorkoutsRequest) dto.CreateScheduledWorkoutsRequest {
-	return dto.CreateScheduledWorkoutsRequest{
+	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     from.WorkoutId,
 		ScheduledTime: from.ScheduledTime,
-		Status:        from.Status,
 	}
+	if from.Recurrence != nil {
+		req.Recurrence = &dto.RecurrenceRequest{
+			Frequency:  from.Recurrence.Frequency,
+			Interval:   from.Recurrence.Interval,
+			ByDay:      from.Recurrence.ByDay,
+			Count:      from.Recurrence.Count,
+			Until:      from.Recurrence.Until,
+			TimeZone:   from.Recurrence.TimeZone,
+			Exceptions: from.Recurrence.Exceptions,
+		}
+	}
+	return req
 }
 
 func ToUpdateScheduledWorkoutsRequest(from UpdateScheduledWorkoutsRequest) dto.UpdateScheduledWorkoutsRequest {
 	return dto.UpdateScheduledWorkoutsRequest{
 		ScheduledTime: from.ScheduledTime,
 		Status:        from.Status,
+		Scope:         from.Scope,
 	}
 }
 
+func ToRescheduleScheduledWorkoutsRequest(from RescheduleScheduledWorkoutsRequest) dto.RescheduleScheduledWorkoutsRequest {
+	return dto.RescheduleScheduledWorkoutsRequest(from)
+}
+
+func ToExpandScheduledWorkoutsRequest(from ExpandScheduledWorkoutsRequest) dto.ExpandScheduledWorkoutsRequest {
+	return dto.ExpandScheduledWorkoutsRequest(from)
+}
+
 // WorkoutReport
 type WorkoutReportResponse struct {
-	Id        int    `json:"id"`
-	WorkoutId int    `json:"workout_id"`
-	UserId    int    `json:"user_id"`
-	Details   string `json:"details"`
+	Id          int             `json:"id"`
+	WorkoutId   *int            `json:"workout_id"`
+	UserId      int             `json:"user_id"`
+	Details     string          `json:"details"`
+	PeriodStart *time.Time      `json:"period_start,omitempty"`
+	PeriodEnd   *time.Time      `json:"period_end,omitempty"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
 }
 
 type CreateWorkoutReportRequest struct {
@@ -165,18 +323,33 @@ type CreateWorkoutReportRequest struct {
 	Details   string `json:"details" binding:"required"`
 }
 
+// UpdateWorkoutReportRequest moves the report to another workout only when workout_id is given
 type UpdateWorkoutReportRequest struct {
 	Details   string `json:"details" binding:"required"`
-	WorkoutId int    `json:"workout_id" binding:"required"`
+	WorkoutId *int   `json:"workout_id" binding:"omitempty,min=1"`
+}
+
+// GenerateWorkoutReportRequest selects the days of a generated report, from defaults to 7 days before to
+// and to to today. The report covers every workout of the user unless workout_id is given.
+type GenerateWorkoutReportRequest struct {
+	WorkoutId *int   `json:"workout_id" binding:"omitempty,min=1"`
+	From      string `json:"from" binding:"omitempty,datetime=2006-01-02"`
+	To        string `json:"to" binding:"omitempty,datetime=2006-01-02"`
 }
 
 func ToWorkoutReportResponse(from dto.WorkoutReportResponse) WorkoutReportResponse {
-	return WorkoutReportResponse{
-		Id:        from.Id,
-		WorkoutId: from.WorkoutId,
-		UserId:    from.UserId,
-		Details:   from.Details,
+	response := WorkoutReportResponse{
+		Id:          from.Id,
+		WorkoutId:   from.WorkoutId,
+		UserId:      from.UserId,
+		Details:     from.Details,
+		PeriodStart: from.PeriodStart,
+		PeriodEnd:   from.PeriodEnd,
 	}
+	if from.Payload != nil {
+		response.Payload = json.RawMessage(*from.Payload)
+	}
+	return response
 }
 
 func ToCreateWorkoutReportRequest(from CreateWorkoutReportRequest) dto.CreateWorkoutReportRequest {
@@ -192,3 +365,366 @@ func ToUpdateWorkoutReportRequest(from UpdateWorkoutReportRequest) dto.UpdateWor
 		WorkoutId: from.WorkoutId,
 	}
 }
+
+// ToGenerateWorkoutReportRequest parses the days of the request, they are validated by the binding
+func ToGenerateWorkoutReportRequest(from GenerateWorkoutReportRequest) dto.GenerateWorkoutReportRequest {
+	req := dto.GenerateWorkoutReportRequest{WorkoutId: from.WorkoutId}
+	req.From, _ = time.Parse(time.DateOnly, from.From)
+	req.To, _ = time.Parse(time.DateOnly, from.To)
+	return req
+}
+
+// Exercise
+type CreateExerciseRequest struct {
+	Name             string   `json:"name" binding:"required,min=3,max=100"`
+	Description      string   `json:"description" binding:"max=255"`
+	Category         string   `json:"category" binding:"required,oneof=strength cardio flexibility plyometrics olympic"`
+	PrimaryMuscles   []string `json:"primary_muscles" binding:"required,min=1,dive,oneof=chest back lats traps shoulders biceps triceps forearms abs obliques lower_back glutes quadriceps hamstrings calves adductors abductors neck full_body"`
+	SecondaryMuscles []string `json:"secondary_muscles" binding:"dive,oneof=chest back lats traps shoulders biceps triceps forearms abs obliques lower_back glutes quadriceps hamstrings calves adductors abductors neck full_body"`
+	Equipment        string   `json:"equipment" binding:"required,oneof=barbell dumbbell kettlebell machine cable bodyweight band other"`
+	UnitType         string   `json:"unit_type" binding:"required,oneof=weight_reps reps duration distance"`
+}
+
+type UpdateExerciseRequest struct {
+	Name             string   `json:"name" binding:"required,min=3,max=100"`
+	Description      string   `json:"description" binding:"max=255"`
+	Category         string   `json:"category" binding:"required,oneof=strength cardio flexibility plyometrics olympic"`
+	PrimaryMuscles   []string `json:"primary_muscles" binding:"required,min=1,dive,oneof=chest back lats traps shoulders biceps triceps forearms abs obliques lower_back glutes quadriceps hamstrings calves adductors abductors neck full_body"`
+	SecondaryMuscles []string `json:"secondary_muscles" binding:"dive,oneof=chest back lats traps shoulders biceps triceps forearms abs obliques lower_back glutes quadriceps hamstrings calves adductors abductors neck full_body"`
+	Equipment        string   `json:"equipment" binding:"required,oneof=barbell dumbbell kettlebell machine cable bodyweight band other"`
+	UnitType         string   `json:"unit_type" binding:"required,oneof=weight_reps reps duration distance"`
+}
+
+type ExerciseResponse struct {
+	Id               int      `json:"id"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Category         string   `json:"category"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	Equipment        string   `json:"equipment"`
+	UnitType         string   `json:"unit_type"`
+	Custom           bool     `json:"custom"`
+}
+
+func ToExerciseResponse(from dto.ExerciseResponse) ExerciseResponse {
+	return ExerciseResponse{
+		Id:               from.Id,
+		Name:             from.Name,
+		Description:      from.Description,
+		Category:         from.Category,
+		PrimaryMuscles:   splitMuscles(from.PrimaryMuscles),
+		SecondaryMuscles: splitMuscles(from.SecondaryMuscles),
+		Equipment:        from.Equipment,
+		UnitType:         from.UnitType,
+		Custom:           from.UserId != nil,
+	}
+}
+
+func ToCreateExerciseRequest(from CreateExerciseRequest) dto.CreateExerciseRequest {
+	return dto.CreateExerciseRequest{
+		Name:             from.Name,
+		Description:      from.Description,
+		Category:         from.Category,
+		PrimaryMuscles:   strings.Join(from.PrimaryMuscles, ","),
+		SecondaryMuscles: strings.Join(from.SecondaryMuscles, ","),
+		Equipment:        from.Equipment,
+		UnitType:         from.UnitType,
+	}
+}
+
+func ToUpdateExerciseRequest(from UpdateExerciseRequest) dto.UpdateExerciseRequest {
+	return dto.UpdateExerciseRequest{
+		Name:             from.Name,
+		Description:      from.Description,
+		Category:         from.Category,
+		PrimaryMuscles:   strings.Join(from.PrimaryMuscles, ","),
+		SecondaryMuscles: strings.Join(from.SecondaryMuscles, ","),
+		Equipment:        from.Equipment,
+		UnitType:         from.UnitType,
+	}
+}
+
+// splitMuscles turns the stored comma separated muscle groups into a list
+func splitMuscles(muscles string) []string {
+	if muscles == "" {
+		return []string{}
+	}
+	return strings.Split(muscles, ",")
+}
+
+// WorkoutSession
+type StartWorkoutSessionRequest struct {
+	WorkoutId          int  `json:"workout_id" binding:"required"`
+	ScheduledWorkoutId *int `json:"scheduled_workout_id"`
+}
+
+type WorkoutSessionResponse struct {
+	Id                 int                         `json:"id"`
+	WorkoutId          int                         `json:"workout_id"`
+	ScheduledWorkoutId *int                        `json:"scheduled_workout_id"`
+	Status             string                      `json:"status"`
+	StartedAt          time.Time                   `json:"started_at"`
+	PausedAt           *time.Time                  `json:"paused_at"`
+	FinishedAt         *time.Time                  `json:"finished_at"`
+	PausedSeconds      int                         `json:"paused_seconds"`
+	DurationSeconds    int                         `json:"duration_seconds"`
+	Sets               []WorkoutSessionSetResponse `json:"sets,omitempty"`
+}
+
+type CreateWorkoutSessionSetRequest struct {
+	WorkoutExerciseId int       `json:"workout_exercise_id" binding:"required"`
+	SetIndex          int       `json:"set_index" binding:"omitempty,min=1"`
+	Reps              int       `json:"reps" binding:"min=0"`
+	Weight            float64   `json:"weight" binding:"min=0"`
+	Rpe               *float64  `json:"rpe" binding:"omitempty,min=1,max=10"`
+	SetType           string    `json:"set_type" binding:"omitempty,oneof=warmup working drop failure"`
+	PerformedAt       time.Time `json:"performed_at"`
+}
+
+type WorkoutSessionSetResponse struct {
+	Id                int       `json:"id"`
+	WorkoutSessionId  int       `json:"workout_session_id"`
+	WorkoutExerciseId int       `json:"workout_exercise_id"`
+	ExerciseId        *int      `json:"exercise_id"`
+	SetIndex          int       `json:"set_index"`
+	Reps              int       `json:"reps"`
+	Weight            float64   `json:"weight"`
+	Rpe               *float64  `json:"rpe"`
+	SetType           string    `json:"set_type"`
+	PerformedAt       time.Time `json:"performed_at"`
+	// PersonalRecords are the records broken by a logged set
+	PersonalRecords []PersonalRecordResponse `json:"personal_records,omitempty"`
+}
+
+func ToWorkoutSessionResponse(from dto.WorkoutSessionResponse) WorkoutSessionResponse {
+	response := WorkoutSessionResponse{
+		Id:                 from.Id,
+		WorkoutId:          from.WorkoutId,
+		ScheduledWorkoutId: from.ScheduledWorkoutId,
+		Status:             from.Status,
+		StartedAt:          from.StartedAt,
+		PausedAt:           from.PausedAt,
+		FinishedAt:         from.FinishedAt,
+		PausedSeconds:      from.PausedSeconds,
+		DurationSeconds:    from.DurationSeconds,
+	}
+	for _, set := range from.Sets {
+		response.Sets = append(response.Sets, ToWorkoutSessionSetResponse(set))
+	}
+	return response
+}
+
+func ToStartWorkoutSessionRequest(from StartWorkoutSessionRequest) dto.StartWorkoutSessionRequest {
+	return dto.StartWorkoutSessionRequest{
+		WorkoutId:          from.WorkoutId,
+		ScheduledWorkoutId: from.ScheduledWorkoutId,
+	}
+}
+
+func ToWorkoutSessionSetResponse(from dto.WorkoutSessionSetResponse) WorkoutSessionSetResponse {
+	response := WorkoutSessionSetResponse{
+		Id:                from.Id,
+		WorkoutSessionId:  from.WorkoutSessionId,
+		WorkoutExerciseId: from.WorkoutExerciseId,
+		ExerciseId:        from.ExerciseId,
+		SetIndex:          from.SetIndex,
+		Reps:              from.Repetitions,
+		Weight:            from.Weight,
+		Rpe:               from.Rpe,
+		SetType:           from.SetType,
+		PerformedAt:       from.PerformedAt,
+	}
+	for _, record := range from.PersonalRecords {
+		response.PersonalRecords = append(response.PersonalRecords, ToPersonalRecordResponse(record))
+	}
+	return response
+}
+
+func ToCreateWorkoutSessionSetRequest(from CreateWorkoutSessionSetRequest) dto.CreateWorkoutSessionSetRequest {
+	return dto.CreateWorkoutSessionSetRequest{
+		WorkoutExerciseId: from.WorkoutExerciseId,
+		SetIndex:          from.SetIndex,
+		Repetitions:       from.Reps,
+		Weight:            from.Weight,
+		Rpe:               from.Rpe,
+		SetType:           from.SetType,
+		PerformedAt:       from.PerformedAt,
+	}
+}
+
+// PersonalRecord
+type PersonalRecordResponse struct {
+	Id                int       `json:"id"`
+	ExerciseId        int       `json:"exercise_id"`
+	WorkoutExerciseId int       `json:"workout_exercise_id"`
+	ExerciseSetId     *int      `json:"exercise_set_id"`
+	WorkoutSessionId  *int      `json:"workout_session_id"`
+	RecordType        string    `json:"record_type"`
+	Value             float64   `json:"value"`
+	Weight            float64   `json:"weight"`
+	Reps              int       `json:"reps"`
+	PreviousValue     *float64  `json:"previous_value"`
+	AchievedAt        time.Time `json:"achieved_at"`
+}
+
+type ExerciseRecordsResponse struct {
+	ExerciseId   int                      `json:"exercise_id"`
+	ExerciseName string                   `json:"exercise_name"`
+	Records      []PersonalRecordResponse `json:"records"`
+	History      []PersonalRecordResponse `json:"history"`
+}
+
+func ToPersonalRecordResponse(from dto.PersonalRecordResponse) PersonalRecordResponse {
+	return PersonalRecordResponse{
+		Id:                from.Id,
+		ExerciseId:        from.ExerciseId,
+		WorkoutExerciseId: from.WorkoutExerciseId,
+		ExerciseSetId:     from.ExerciseSetId,
+		WorkoutSessionId:  from.WorkoutSessionId,
+		RecordType:        from.RecordType,
+		Value:             from.Value,
+		Weight:            from.Weight,
+		Reps:              from.Repetitions,
+		PreviousValue:     from.PreviousValue,
+		AchievedAt:        from.AchievedAt,
+	}
+}
+
+func ToExerciseRecordsResponse(from dto.ExerciseRecordsResponse) ExerciseRecordsResponse {
+	response := ExerciseRecordsResponse{
+		ExerciseId:   from.ExerciseId,
+		ExerciseName: from.ExerciseName,
+		Records:      []PersonalRecordResponse{},
+		History:      []PersonalRecordResponse{},
+	}
+	for _, record := range from.Records {
+		response.Records = append(response.Records, ToPersonalRecordResponse(record))
+	}
+	for _, record := range from.History {
+		response.History = append(response.History, ToPersonalRecordResponse(record))
+	}
+	return response
+}
+
+// Analytics
+type AnalyticsQuery struct {
+	From       time.Time `form:"from" time_format:"2006-01-02"`
+	To         time.Time `form:"to" time_format:"2006-01-02"`
+	Period     string    `form:"period" binding:"omitempty,oneof=day week month"`
+	ExerciseId *int      `form:"exercise_id" binding:"omitempty,min=1"`
+}
+
+type VolumeResponse struct {
+	Period   time.Time `json:"period"`
+	Volume   float64   `json:"volume"`
+	Workouts int       `json:"workouts"`
+}
+
+type ExerciseTrendResponse struct {
+	ExerciseId *int                         `json:"exercise_id"`
+	Name       string                       `json:"name"`
+	Points     []ExerciseTrendPointResponse `json:"points"`
+}
+
+type ExerciseTrendPointResponse struct {
+	Period    time.Time `json:"period"`
+	MaxWeight float64   `json:"max_weight"`
+	Volume    float64   `json:"volume"`
+	Reps      int       `json:"reps"`
+}
+
+type FrequencyResponse struct {
+	Period         time.Time `json:"period"`
+	Scheduled      int       `json:"scheduled"`
+	Completed      int       `json:"completed"`
+	CompletionRate float64   `json:"completion_rate"`
+}
+
+type StreakResponse struct {
+	StartDay time.Time `json:"start_day"`
+	EndDay   time.Time `json:"end_day"`
+	Days     int       `json:"days"`
+}
+
+type StreaksResponse struct {
+	Current StreakResponse   `json:"current"`
+	Longest StreakResponse   `json:"longest"`
+	Streaks []StreakResponse `json:"streaks"`
+}
+
+type MuscleShareResponse struct {
+	Muscle  string  `json:"muscle"`
+	Sets    int     `json:"sets"`
+	Volume  float64 `json:"volume"`
+	Percent float64 `json:"percent"`
+}
+
+func ToAnalyticsRequest(from AnalyticsQuery) dto.AnalyticsRequest {
+	return dto.AnalyticsRequest{
+		From:       from.From,
+		To:         from.To,
+		Period:     from.Period,
+		ExerciseId: from.ExerciseId,
+	}
+}
+
+func ToVolumeResponses(from []dto.VolumeResponse) []VolumeResponse {
+	response := []VolumeResponse{}
+	for _, point := range from {
+		response = append(response, VolumeResponse(point))
+	}
+	return response
+}
+
+func ToExerciseTrendResponses(from []dto.ExerciseTrendResponse) []ExerciseTrendResponse {
+	response := []ExerciseTrendResponse{}
+	for _, trend := range from {
+		points := []ExerciseTrendPointResponse{}
+		for _, point := range trend.Points {
+			points = append(points, ExerciseTrendPointResponse(point))
+		}
+		response = append(response, ExerciseTrendResponse{ExerciseId: trend.ExerciseId, Name: trend.Name, Points: points})
+	}
+	return response
+}
+
+func ToFrequencyResponses(from []dto.FrequencyResponse) []FrequencyResponse {
+	response := []FrequencyResponse{}
+	for _, point := range from {
+		response = append(response, FrequencyResponse(point))
+	}
+	return response
+}
+
+func ToStreaksResponse(from dto.StreaksResponse) StreaksResponse {
+	response := StreaksResponse{
+		Current: StreakResponse(from.Current),
+		Longest: StreakResponse(from.Longest),
+		Streaks: []StreakResponse{},
+	}
+	for _, streak := range from.Streaks {
+		response.Streaks = append(response.Streaks, StreakResponse(streak))
+	}
+	return response
+}
+
+func ToMuscleShareResponses(from []dto.MuscleShareResponse) []MuscleShareResponse {
+	response := []MuscleShareResponse{}
+	for _, muscle := range from {
+		response = append(response, MuscleShareResponse(muscle))
+	}
+	return response
+}
+
+// Calendar
+// CalendarFeedResponse is a calendar subscription, Url can be added to a calendar app without a Bearer token
+// so both carry the token
+type CalendarFeedResponse struct {
+	Token string `json:"token" log:"secret"`
+	Url   string `json:"url" log:"secret"`
+}
+
+func ToCalendarFeedResponse(from dto.CalendarFeedResponse, url string) CalendarFeedResponse {
+	return CalendarFeedResponse{Token: from.Token, Url: url}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	Usecase *usecase.AnalyticsUsecase
+}
+
+func NewAnalyticsHandler(cfg *config.Config) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		Usecase: usecase.NewAnalyticsUsecase(cfg, dependency.GetAnalyticsRepository()),
+	}
+}
+
+// GetVolume godoc
+// @Summary Get the training volume
+// @Description Get the volume (sets x reps x weight) of the completed workouts per day, week or month, from the logged sets or the planned values of an exercise without logged sets
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), 90 days before to by default"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), today by default"
+// @Param period query string false "day, week (default) or month"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.VolumeResponse} "Volume response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/analytics/volume [get]
+// @Security AuthBearer
+func (h *AnalyticsHandler) Volume(c *gin.Context) {
+	analytics(c, dto.ToVolumeResponses, h.Usecase.Volume)
+}
+
+// GetExerciseTrends godoc
+// @Summary Get the exercise trends
+// @Description Get the max weight, volume and reps of every exercise per day, week or month, from the logged sets or the planned values of an exercise without logged sets
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), 90 days before to by default"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), today by default"
+// @Param period query string false "day, week (default) or month"
+// @Param exercise_id query int false "Only the trend of this exercise"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.ExerciseTrendResponse} "ExerciseTrend response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/analytics/exercises [get]
+// @Security AuthBearer
+func (h *AnalyticsHandler) ExerciseTrends(c *gin.Context) {
+	analytics(c, dto.ToExerciseTrendResponses, h.Usecase.ExerciseTrends)
+}
+
+// GetFrequency godoc
+// @Summary Get the workout frequency
+// @Description Get the scheduled and completed workouts per day, week or month
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), 90 days before to by default"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), today by default"
+// @Param period query string false "day, week (default) or month"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.FrequencyResponse} "Frequency response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/analytics/frequency [get]
+// @Security AuthBearer
+func (h *AnalyticsHandler) Frequency(c *gin.Context) {
+	analytics(c, dto.ToFrequencyResponses, h.Usecase.Frequency)
+}
+
+// GetStreaks godoc
+// @Summary Get the workout streaks
+// @Description Get the runs of consecutive days with a completed workout with the current and the longest one
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), 90 days before to by default"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), today by default"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.StreaksResponse} "Streaks response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/analytics/streaks [get]
+// @Security AuthBearer
+func (h *AnalyticsHandler) Streaks(c *gin.Context) {
+	analytics(c, dto.ToStreaksResponse, h.Usecase.Streaks)
+}
+
+// GetMuscleDistribution godoc
+// @Summary Get the muscle group distribution
+// @Description Get the sets and volume per primary muscle of the exercises of the completed workouts, from the logged sets or the planned values of an exercise without logged sets
+// @Tags Analytics
+// @Produce json
+// @Param from query string false "First day of the range (YYYY-MM-DD), 90 days before to by default"
+// @Param to query string false "Last day of the range (YYYY-MM-DD), today by default"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.MuscleShareResponse} "MuscleShare response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/analytics/muscles [get]
+// @Security AuthBearer
+func (h *AnalyticsHandler) MuscleDistribution(c *gin.Context) {
+	analytics(c, dto.ToMuscleShareResponses, h.Usecase.MuscleDistribution)
+}
+
+// analytics binds the range from the query and responds with the mapped result of the usecase
+func analytics[TUOutput any, TResponse any](c *gin.Context, responseMapper func(TUOutput) TResponse,
+	usecaseQuery func(ctx context.Context, req usecaseDto.AnalyticsRequest) (TUOutput, error)) {
+	query := dto.AnalyticsQuery{}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+
+	result, err := usecaseQuery(c, dto.ToAnalyticsRequest(query))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(responseMapper(result), true, 0))
+}
@@ -6,7 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/gin-gonic/gin"
 )
@@ -40,7 +40,7 @@ func Create[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	usecaseResult, err := usecaseCreate(c, usecaseInput)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 
@@ -92,7 +92,7 @@ func Update[TRequest any, TUInput any, TUOutput any, TResponse any](c *gin.Conte
 	usecaseResult, err := usecaseUpdate(c, id, usecaseInput)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 
@@ -118,7 +118,7 @@ func Delete(c *gin.Context, usecaseDelete func(ctx context.Context, id int) erro
 	err = usecaseDelete(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, 0))
@@ -148,7 +148,7 @@ func GetById[TUOutput any, TResponse any](c *gin.Context,
 	usecaseResult, err := usecaseGet(c, id)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 
@@ -179,7 +179,7 @@ func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
 	usecaseResult, err := usecaseList(c, *req)
 	if err != nil {
 		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
-			helper.GenerateBaseResponseWithError(nil, false, helper.InternalError, err))
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
 		return
 	}
 	response := filter.PagedList[TResponse]{
@@ -201,3 +201,14 @@ func GetByFilter[TUOutput any, TResponse any](c *gin.Context,
 
 	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
 }
+
+// Run an action on an entity, like a state change, that takes no request body
+// TUOutput: Usecase function output
+// TResponse: Http response body that mapped from TUOutput with TResponse := mapper(TUOutput)
+// responseMapper: this function map usecase output to endpoint output
+// usecaseAction: usecase method that is run for the id of the path
+func Action[TUOutput any, TResponse any](c *gin.Context,
+	responseMapper func(req TUOutput) (res TResponse),
+	usecaseAction func(c context.Context, id int) (TUOutput, error)) {
+	GetById(c, responseMapper, usecaseAction)
+}
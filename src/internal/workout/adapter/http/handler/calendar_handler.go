@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+type CalendarHandler struct {
+	Usecase *usecase.CalendarUsecase
+	Cfg     *config.Config
+}
+
+func NewCalendarHandler(cfg *config.Config) *CalendarHandler {
+	return &CalendarHandler{
+		Usecase: usecase.NewCalendarUsecase(cfg, dependency.GetScheduledWorkoutsRepository(), dependency.GetScheduleRuleRepository(),
+			dependency.GetWorkoutRepository(), dependency.GetCalendarFeedRepository()),
+		Cfg: cfg,
+	}
+}
+
+// ExportCalendar godoc
+// @Summary Export the schedule as iCalendar
+// @Description Export the recurring schedules and the scheduled workouts of the user as an RFC 5545 iCalendar (.ics)
+// @Tags Calendar
+// @Produce text/calendar
+// @Success 200 {string} string "iCalendar"
+// @Failure 401 {object} helper.BaseHttpResponse "Unauthorized"
+// @Router /v1/workouts/calendar.ics [get]
+// @Security AuthBearer
+func (h *CalendarHandler) Export(c *gin.Context) {
+	result, err := h.Usecase.Export(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="workouts.ics"`)
+	c.Data(http.StatusOK, calendarContentType, result)
+}
+
+// GetCalendarFeed godoc
+// @Summary Get a calendar subscription
+// @Description Export the schedule of the user the secret token belongs to as an iCalendar, for calendar apps subscribing without a Bearer token
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Secret token, optionally followed by .ics"
+// @Success 200 {string} string "iCalendar"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/calendar/feed/{token} [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	result, err := h.Usecase.ExportFeed(c, token)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.Data(http.StatusOK, calendarContentType, result)
+}
+
+// RegenerateCalendarToken godoc
+// @Summary Generate the calendar subscription token
+// @Description Create the calendar subscription of the user or replace its secret token, the previous URL stops working
+// @Tags Calendar
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.CalendarFeedResponse} "CalendarFeed response"
+// @Failure 401 {object} helper.BaseHttpResponse "Unauthorized"
+// @Router /v1/workouts/calendar/token [post]
+// @Security AuthBearer
+func (h *CalendarHandler) RegenerateToken(c *gin.Context) {
+	result, err := h.Usecase.RegenerateToken(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToCalendarFeedResponse(result, feedUrl(h.Cfg, c, result.Token)), true, 0))
+}
+
+// RevokeCalendarToken godoc
+// @Summary Revoke the calendar subscription
+// @Description Remove the calendar subscription of the user, its URL stops working
+// @Tags Calendar
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/calendar/token [delete]
+// @Security AuthBearer
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	err := h.Usecase.RevokeToken(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, 0))
+}
+
+// feedUrl is the subscription URL of a token on the configured domain, the Host and X-Forwarded-* headers
+// are sent by the client and are not trusted. The server is reached by https, in debug mode by http on its external port.
+func feedUrl(cfg *config.Config, c *gin.Context, token string) string {
+	base := "https://" + cfg.Server.Domain
+	if cfg.Server.RunMode == gin.DebugMode {
+		base = fmt.Sprintf("http://%s:%s", cfg.Server.Domain, cfg.Server.ExternalPort)
+	}
+	return base + strings.TrimSuffix(c.FullPath(), "/token") + "/feed/" + token + ".ics"
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+type ExerciseSetHandler struct {
+	Usecase *usecase.ExerciseSetUsecase
+}
+
+func NewExerciseSetHandler(cfg *config.Config) *ExerciseSetHandler {
+	return &ExerciseSetHandler{
+		Usecase: usecase.NewExerciseSetUsecase(cfg, dependency.GetExerciseSetRepository(), dependency.GetWorkoutExerciseRepository(), dependency.GetWorkoutRepository(),
+			dependency.GetExerciseRepository(), dependency.GetPersonalRecordRepository(), dependency.GetNotifier(cfg)),
+	}
+}
+
+// CreateExerciseSet godoc
+// @Summary Log a set of a WorkoutExercise
+// @Description Log a set of a WorkoutExercise, without set_index the set is appended after the last one
+// @Tags ExerciseSet
+// @Accept json
+// @produces json
+// @Param id path int true "WorkoutExercise ID"
+// @Param Request body dto.CreateExerciseSetRequest true "Create an ExerciseSet"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.ExerciseSetResponse} "ExerciseSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/workout-exercise/{id}/sets [post]
+// @Security AuthBearer
+func (h *ExerciseSetHandler) Create(c *gin.Context) {
+	workoutExerciseId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	request := dto.CreateExerciseSetRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+
+	usecaseInput := dto.ToCreateExerciseSetRequest(request)
+	usecaseInput.WorkoutExerciseId = workoutExerciseId
+	result, err := h.Usecase.Create(c, usecaseInput)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(dto.ToExerciseSetResponse(result), true, 0))
+}
+
+// GetExerciseSets godoc
+// @Summary Get the sets of a WorkoutExercise
+// @Description Get the sets of a WorkoutExercise ordered by set_index
+// @Tags ExerciseSet
+// @Accept json
+// @Produce json
+// @Param id path int true "WorkoutExercise ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.ExerciseSetResponse} "ExerciseSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/workout-exercise/{id}/sets [get]
+// @Security AuthBearer
+func (h *ExerciseSetHandler) GetByWorkoutExercise(c *gin.Context) {
+	workoutExerciseId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	result, err := h.Usecase.GetByWorkoutExercise(c, workoutExerciseId)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	response := []dto.ExerciseSetResponse{}
+	for _, set := range result {
+		response = append(response, dto.ToExerciseSetResponse(set))
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
+}
+
+// GetExerciseSet godoc
+// @Summary Get a set of a WorkoutExercise
+// @Description Get a set of a WorkoutExercise by ID
+// @Tags ExerciseSet
+// @Accept json
+// @Produce json
+// @Param id path int true "WorkoutExercise ID"
+// @Param setId path int true "ExerciseSet ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseSetResponse} "ExerciseSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/workout-exercise/{id}/sets/{setId} [get]
+// @Security AuthBearer
+func (h *ExerciseSetHandler) GetById(c *gin.Context) {
+	workoutExerciseId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := pathId(c, "setId")
+	if !ok {
+		return
+	}
+	result, err := h.Usecase.GetById(c, workoutExerciseId, id)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToExerciseSetResponse(result), true, 0))
+}
+
+// UpdateExerciseSet godoc
+// @Summary Update a set of a WorkoutExercise
+// @Description Update a set of a WorkoutExercise
+// @Tags ExerciseSet
+// @Accept json
+// @Produce json
+// @Param id path int true "WorkoutExercise ID"
+// @Param setId path int true "ExerciseSet ID"
+// @Param Request body dto.UpdateExerciseSetRequest true "Update an ExerciseSet"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseSetResponse} "ExerciseSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/workout-exercise/{id}/sets/{setId} [put]
+// @Security AuthBearer
+func (h *ExerciseSetHandler) Update(c *gin.Context) {
+	workoutExerciseId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := pathId(c, "setId")
+	if !ok {
+		return
+	}
+	request := dto.UpdateExerciseSetRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+
+	result, err := h.Usecase.Update(c, workoutExerciseId, id, dto.ToUpdateExerciseSetRequest(request))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToExerciseSetResponse(result), true, 0))
+}
+
+// DeleteExerciseSet godoc
+// @Summary Delete a set of a WorkoutExercise
+// @Description Delete a set of a WorkoutExercise
+// @Tags ExerciseSet
+// @Param id path int true "WorkoutExercise ID"
+// @Param setId path int true "ExerciseSet ID"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/workout-exercise/{id}/sets/{setId} [delete]
+// @Security AuthBearer
+func (h *ExerciseSetHandler) Delete(c *gin.Context) {
+	workoutExerciseId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	id, ok := pathId(c, "setId")
+	if !ok {
+		return
+	}
+	if err := h.Usecase.Delete(c, workoutExerciseId, id); err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(nil, true, 0))
+}
+
+// pathId reads a positive id from the path, it aborts the request when the id is invalid
+func pathId(c *gin.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Params.ByName(name))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, err))
+		return 0, false
+	}
+	if id <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithError(nil, false, helper.ValidationError, errors.New("invalid id")))
+		return 0, false
+	}
+	return id, true
+}
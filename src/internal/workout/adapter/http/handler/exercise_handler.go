@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+type ExerciseHandler struct {
+	Usecase *usecase.ExerciseUsecase
+}
+
+func NewExerciseHandler(cfg *config.Config) *ExerciseHandler {
+	return &ExerciseHandler{
+		Usecase: usecase.NewExerciseUsecase(cfg, dependency.GetExerciseRepository()),
+	}
+}
+
+// CreateExercise godoc
+// @Summary Create a custom Exercise
+// @Description Create a custom Exercise that is only visible to the user
+// @Tags Exercise
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateExerciseRequest true "Create an Exercise"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.ExerciseResponse} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/exercises/ [post]
+// @Security AuthBearer
+func (h *ExerciseHandler) Create(c *gin.Context) {
+	Create(c, dto.ToCreateExerciseRequest, dto.ToExerciseResponse, h.Usecase.Create)
+}
+
+// GetExercise godoc
+// @Summary Get an Exercise by ID
+// @Description Get a catalog Exercise or a custom Exercise of the user by ID
+// @Tags Exercise
+// @Accept json
+// @Produce json
+// @Param id path int true "Exercise ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseResponse} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/exercises/{id} [get]
+// @Security AuthBearer
+func (h *ExerciseHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToExerciseResponse, h.Usecase.GetById)
+}
+
+// UpdateExercise godoc
+// @Summary Update a custom Exercise
+// @Description Update a custom Exercise of the user, catalog exercises can not be changed
+// @Tags Exercise
+// @Accept json
+// @Produce json
+// @Param id path int true "Exercise ID"
+// @Param Request body dto.UpdateExerciseRequest true "Update an Exercise"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseResponse} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/exercises/{id} [put]
+// @Security AuthBearer
+func (h *ExerciseHandler) Update(c *gin.Context) {
+	Update(c, dto.ToUpdateExerciseRequest, dto.ToExerciseResponse, h.Usecase.Update)
+}
+
+// DeleteExercise godoc
+// @Summary Delete a custom Exercise
+// @Description Delete a custom Exercise of the user, catalog exercises can not be deleted
+// @Tags Exercise
+// @Param id path int true "Exercise ID"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/exercises/{id} [delete]
+// @Security AuthBearer
+func (h *ExerciseHandler) Delete(c *gin.Context) {
+	Delete(c, h.Usecase.Delete)
+}
+
+// GetExercisesByFilter godoc
+// @Summary Get Exercises by Filter
+// @Description Get the catalog Exercises together with the custom Exercises of the user by Filter
+// @Tags Exercise
+// @Accept json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ExerciseResponse]} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/exercises/get-by-filter [post]
+// @Security AuthBearer
+func (h *ExerciseHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, dto.ToExerciseResponse, h.Usecase.GetByFilter)
+}
+
+// CreateCatalogExercise godoc
+// @Summary Create a catalog Exercise
+// @Description Add an Exercise to the catalog, admin only
+// @Tags Exercise
+// @Accept json
+// @produces json
+// @Param Request body dto.CreateExerciseRequest true "Create an Exercise"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.ExerciseResponse} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/admin/exercises/ [post]
+// @Security AuthBearer
+func (h *ExerciseHandler) CreateCatalog(c *gin.Context) {
+	Create(c, dto.ToCreateExerciseRequest, dto.ToExerciseResponse, h.Usecase.CreateCatalog)
+}
+
+// UpdateCatalogExercise godoc
+// @Summary Update a catalog Exercise
+// @Description Update an Exercise of the catalog, admin only
+// @Tags Exercise
+// @Accept json
+// @Produce json
+// @Param id path int true "Exercise ID"
+// @Param Request body dto.UpdateExerciseRequest true "Update an Exercise"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseResponse} "Exercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/admin/exercises/{id} [put]
+// @Security AuthBearer
+func (h *ExerciseHandler) UpdateCatalog(c *gin.Context) {
+	Update(c, dto.ToUpdateExerciseRequest, dto.ToExerciseResponse, h.Usecase.UpdateCatalog)
+}
+
+// DeleteCatalogExercise godoc
+// @Summary Delete a catalog Exercise
+// @Description Delete an Exercise of the catalog, admin only
+// @Tags Exercise
+// @Param id path int true "Exercise ID"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/admin/exercises/{id} [delete]
+// @Security AuthBearer
+func (h *ExerciseHandler) DeleteCatalog(c *gin.Context) {
+	Delete(c, h.Usecase.DeleteCatalog)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+type PersonalRecordHandler struct {
+	Usecase *usecase.PersonalRecordUsecase
+}
+
+func NewPersonalRecordHandler(cfg *config.Config) *PersonalRecordHandler {
+	return &PersonalRecordHandler{
+		Usecase: usecase.NewPersonalRecordUsecase(cfg, dependency.GetPersonalRecordRepository(), dependency.GetExerciseRepository()),
+	}
+}
+
+// GetPersonalRecords godoc
+// @Summary Get the current personal records
+// @Description Get the current personal records of the user on every exercise
+// @Tags PersonalRecord
+// @Produce json
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.PersonalRecordResponse} "PersonalRecord response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/records [get]
+// @Security AuthBearer
+func (h *PersonalRecordHandler) GetCurrent(c *gin.Context) {
+	result, err := h.Usecase.GetCurrent(c)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	response := []dto.PersonalRecordResponse{}
+	for _, record := range result {
+		response = append(response, dto.ToPersonalRecordResponse(record))
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
+}
+
+// GetExercisePersonalRecords godoc
+// @Summary Get the personal records on an exercise
+// @Description Get the current personal records of the user on an exercise with their history, the latest first
+// @Tags PersonalRecord
+// @Produce json
+// @Param exercise path int true "Exercise ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ExerciseRecordsResponse} "PersonalRecord response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/records/{exercise} [get]
+// @Security AuthBearer
+func (h *PersonalRecordHandler) GetByExercise(c *gin.Context) {
+	exerciseId, ok := pathId(c, "exercise")
+	if !ok {
+		return
+	}
+	result, err := h.Usecase.GetByExercise(c, exerciseId)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToExerciseRecordsResponse(result), true, 0))
+}
@@ -1,13 +1,16 @@
 package handler
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/alielmi98/go-hexa-workout/dependency"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	_ "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	_ "github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
-	_ "github.com/alielmi98/go-hexa-workout/pkg/helper"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/gin-gonic/gin"
 )
 
@@ -17,13 +20,14 @@ type ScheduledWorkoutsHandler struct {
 
 func NewScheduledWorkoutsHandler(cfg *config.Config) *ScheduledWorkoutsHandler {
 	return &ScheduledWorkoutsHandler{
-		Usecase: usecase.NewScheduledWorkoutsUsecase(cfg, dependency.GetScheduledWorkoutsRepository(), dependency.GetWorkoutRepository()),
+		Usecase: usecase.NewScheduledWorkoutsUsecase(cfg, dependency.GetScheduledWorkoutsRepository(), dependency.GetWorkoutRepository(),
+			dependency.GetScheduleRuleRepository(), dependency.GetTransactor()),
 	}
 }
 
 // CreateScheduledWorkouts godoc
 // @Summary Create a ScheduledWorkouts
-// @Description Create a ScheduledWorkouts
+// @Description Create a ScheduledWorkouts, with a recurrence the first occurrence of the rule is created
 // @Tags ScheduledWorkouts
 // @Accept json
 // @produces json
@@ -54,7 +58,7 @@ func (h *ScheduledWorkoutsHandler) GetById(c *gin.Context) {
 
 // UpdateScheduledWorkouts godoc
 // @Summary Update a ScheduledWorkouts
-// @Description Update a ScheduledWorkouts
+// @Description Update a ScheduledWorkouts, the scope following or all moves the following or all the occurrences of its rule with it. A status change must be allowed from the current status.
 // @Tags ScheduledWorkouts
 // @Accept json
 // @Produce json
@@ -69,16 +73,111 @@ func (h *ScheduledWorkoutsHandler) Update(c *gin.Context) {
 	Update(c, dto.ToUpdateScheduledWorkoutsRequest, dto.ToScheduledWorkoutsResponse, h.Usecase.Update)
 }
 
+// CompleteScheduledWorkouts godoc
+// @Summary Complete a ScheduledWorkouts
+// @Description Mark a planned, in progress or missed ScheduledWorkouts as completed
+// @Tags ScheduledWorkouts
+// @Produce json
+// @Param id path int true "ScheduledWorkouts ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ScheduledWorkoutsResponse} "ScheduledWorkouts response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/scheduled-workouts/{id}/complete [post]
+// @Security AuthBearer
+func (h *ScheduledWorkoutsHandler) Complete(c *gin.Context) {
+	Action(c, dto.ToScheduledWorkoutsResponse, h.Usecase.Complete)
+}
+
+// CancelScheduledWorkouts godoc
+// @Summary Cancel a ScheduledWorkouts
+// @Description Mark a planned, in progress or missed ScheduledWorkouts as cancelled
+// @Tags ScheduledWorkouts
+// @Produce json
+// @Param id path int true "ScheduledWorkouts ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ScheduledWorkoutsResponse} "ScheduledWorkouts response"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/scheduled-workouts/{id}/cancel [post]
+// @Security AuthBearer
+func (h *ScheduledWorkoutsHandler) Cancel(c *gin.Context) {
+	Action(c, dto.ToScheduledWorkoutsResponse, h.Usecase.Cancel)
+}
+
+// RescheduleScheduledWorkouts godoc
+// @Summary Reschedule a ScheduledWorkouts
+// @Description Plan a planned or missed ScheduledWorkouts at another time, the scope following or all moves the following or all the occurrences of its rule with it
+// @Tags ScheduledWorkouts
+// @Accept json
+// @Produce json
+// @Param id path int true "ScheduledWorkouts ID"
+// @Param Request body dto.RescheduleScheduledWorkoutsRequest true "Reschedule a ScheduledWorkouts"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.ScheduledWorkoutsResponse} "ScheduledWorkouts response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/scheduled-workouts/{id}/reschedule [post]
+// @Security AuthBearer
+func (h *ScheduledWorkoutsHandler) Reschedule(c *gin.Context) {
+	Update(c, dto.ToRescheduleScheduledWorkoutsRequest, dto.ToScheduledWorkoutsResponse, h.Usecase.Reschedule)
+}
+
 // DeleteScheduledWorkouts godoc
 // @Summary Delete a ScheduledWorkouts
-// @Description Delete a ScheduledWorkouts
+// @Description Delete a ScheduledWorkouts, the scope following ends its rule before it and all deletes the rule
 // @Tags ScheduledWorkouts
 // @Param id path int true "ScheduledWorkouts ID"
+// @Param scope query string false "this (default), following or all"
 // @Success 204 {object} helper.BaseHttpResponse "No Content"
 // @Failure 400 {object} helper.BaseHttpResponse "Bad request"
 // @Failure 404 {object} helper.BaseHttpResponse "Not found"
 // @Router /v1/workouts/scheduled-workouts/{id} [delete]
 // @Security AuthBearer
 func (h *ScheduledWorkoutsHandler) Delete(c *gin.Context) {
-	Delete(c, h.Usecase.Delete)
+	scope := c.Query("scope")
+	Delete(c, func(ctx context.Context, id int) error {
+		return h.Usecase.DeleteOccurrences(ctx, id, scope)
+	})
+}
+
+// ExpandScheduledWorkouts godoc
+// @Summary Expand the recurring ScheduledWorkouts
+// @Description Create the missing occurrences of the rules of the user, or of a single rule, in a window of at most 366 days and return the occurrences of the window
+// @Tags ScheduledWorkouts
+// @Accept json
+// @Produce json
+// @Param Request body dto.ExpandScheduledWorkoutsRequest true "Expand the recurring ScheduledWorkouts"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.ScheduledWorkoutsResponse} "ScheduledWorkouts response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/scheduled-workouts/expand [post]
+// @Security AuthBearer
+func (h *ScheduledWorkoutsHandler) Expand(c *gin.Context) {
+	request := dto.ExpandScheduledWorkoutsRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+
+	occurrences, err := h.Usecase.Expand(c, dto.ToExpandScheduledWorkoutsRequest(request))
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(dto.ToScheduledWorkoutsResponses(occurrences), true, 0))
+}
+
+// GetScheduledWorkoutsByFilter godoc
+// @Summary Get ScheduledWorkouts by Filter
+// @Description Get ScheduledWorkouts by Filter, only rows of workouts owned by the user are returned
+// @Tags ScheduledWorkouts
+// @Accept json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.ScheduledWorkoutsResponse]} "ScheduledWorkouts response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/scheduled-workouts/get-by-filter [post]
+// @Security AuthBearer
+func (h *ScheduledWorkoutsHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, dto.ToScheduledWorkoutsResponse, h.Usecase.GetByFilter)
 }
@@ -4,8 +4,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/dependency"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	_ "github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
 	_ "github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/gin-gonic/gin"
 )
@@ -16,7 +16,7 @@ type WorkoutExerciseHandler struct {
 
 func NewWorkoutExerciseHandler(cfg *config.Config) *WorkoutExerciseHandler {
 	return &WorkoutExerciseHandler{
-		Usecase: usecase.NewWorkoutExerciseUsecase(cfg, dependency.GetWorkoutExerciseRepository(), dependency.GetWorkoutRepository()),
+		Usecase: usecase.NewWorkoutExerciseUsecase(cfg, dependency.GetWorkoutExerciseRepository(), dependency.GetWorkoutRepository(), dependency.GetExerciseRepository(), dependency.GetPersonalRecordRepository(), dependency.GetNotifier(cfg)),
 	}
 }
 
@@ -81,3 +81,17 @@ func (h *WorkoutExerciseHandler) Update(c *gin.Context) {
 func (h *WorkoutExerciseHandler) Delete(c *gin.Context) {
 	Delete(c, h.Usecase.Delete)
 }
+
+// GetWorkoutExercisesByFilter godoc
+// @Summary Get WorkoutExercises by Filter
+// @Description Get WorkoutExercises by Filter, only rows of workouts owned by the user are returned
+// @Tags WorkoutExercise
+// @Accept json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WorkoutExerciseResponse]} "WorkoutExercise response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/workout-exercise/get-by-filter [post]
+// @Security AuthBearer
+func (h *WorkoutExerciseHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, dto.ToWorkoutExerciseResponse, h.Usecase.GetByFilter)
+}
@@ -4,8 +4,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/dependency"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	_ "github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
 	_ "github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/gin-gonic/gin"
 )
@@ -16,7 +16,8 @@ type WorkoutReportHandler struct {
 
 func NewWorkoutReportHandler(cfg *config.Config) *WorkoutReportHandler {
 	return &WorkoutReportHandler{
-		Usecase: usecase.NewWorkoutReportUsecase(cfg, dependency.GetWorkoutReportRepository(), dependency.GetWorkoutRepository()),
+		Usecase: usecase.NewWorkoutReportUsecase(cfg, dependency.GetWorkoutReportRepository(), dependency.GetWorkoutRepository(),
+			dependency.GetAnalyticsRepository(), dependency.GetPersonalRecordRepository(), dependency.GetExerciseRepository()),
 	}
 }
 
@@ -35,6 +36,22 @@ func (h *WorkoutReportHandler) Create(c *gin.Context) {
 	Create(c, dto.ToCreateWorkoutReportRequest, dto.ToWorkoutReportResponse, h.Usecase.Create)
 }
 
+// GenerateWorkoutReport godoc
+// @Summary Generate a WorkoutReport
+// @Description Generate a report on the completed workouts of a date range, or of a single workout, with the exercises done, the total volume, the personal records hit, the completion rate of the scheduled workouts and a comparison to the previous period
+// @Tags WorkoutReport
+// @Accept json
+// @produces json
+// @Param Request body dto.GenerateWorkoutReportRequest true "Generate a WorkoutReport"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.WorkoutReportResponse} "WorkoutReport response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Router /v1/workouts/workout-report/generate [post]
+// @Security AuthBearer
+func (h *WorkoutReportHandler) Generate(c *gin.Context) {
+	Create(c, dto.ToGenerateWorkoutReportRequest, dto.ToWorkoutReportResponse, h.Usecase.Generate)
+}
+
 // GetWorkoutReports godoc
 // @Summary Get a WorkoutReport by ID
 // @Description Get a WorkoutReport by ID
@@ -81,3 +98,17 @@ func (h *WorkoutReportHandler) Update(c *gin.Context) {
 func (h *WorkoutReportHandler) Delete(c *gin.Context) {
 	Delete(c, h.Usecase.Delete)
 }
+
+// GetWorkoutReportsByFilter godoc
+// @Summary Get WorkoutReports by Filter
+// @Description Get WorkoutReports by Filter, only rows of workouts owned by the user are returned
+// @Tags WorkoutReport
+// @Accept json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WorkoutReportResponse]} "WorkoutReport response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/workout-report/get-by-filter [post]
+// @Security AuthBearer
+func (h *WorkoutReportHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, dto.ToWorkoutReportResponse, h.Usecase.GetByFilter)
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/alielmi98/go-hexa-workout/dependency"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkoutSessionHandler struct {
+	Usecase *usecase.WorkoutSessionUsecase
+}
+
+func NewWorkoutSessionHandler(cfg *config.Config) *WorkoutSessionHandler {
+	return &WorkoutSessionHandler{
+		Usecase: usecase.NewWorkoutSessionUsecase(cfg, dependency.GetWorkoutSessionRepository(), dependency.GetWorkoutSessionSetRepository(),
+			dependency.GetWorkoutRepository(), dependency.GetWorkoutExerciseRepository(), dependency.GetScheduledWorkoutsRepository(),
+			dependency.GetExerciseRepository(), dependency.GetPersonalRecordRepository(), dependency.GetNotifier(cfg), dependency.GetTransactor()),
+	}
+}
+
+// StartWorkoutSession godoc
+// @Summary Start a WorkoutSession
+// @Description Start a WorkoutSession from a Workout, optionally for one of its ScheduledWorkouts
+// @Tags WorkoutSession
+// @Accept json
+// @produces json
+// @Param Request body dto.StartWorkoutSessionRequest true "Start a WorkoutSession"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionResponse} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/sessions/ [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) Start(c *gin.Context) {
+	Create(c, dto.ToStartWorkoutSessionRequest, dto.ToWorkoutSessionResponse, h.Usecase.Start)
+}
+
+// PauseWorkoutSession godoc
+// @Summary Pause a WorkoutSession
+// @Description Pause a running WorkoutSession
+// @Tags WorkoutSession
+// @Produce json
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionResponse} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/sessions/{id}/pause [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) Pause(c *gin.Context) {
+	Action(c, dto.ToWorkoutSessionResponse, h.Usecase.Pause)
+}
+
+// ResumeWorkoutSession godoc
+// @Summary Resume a WorkoutSession
+// @Description Resume a paused WorkoutSession
+// @Tags WorkoutSession
+// @Produce json
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionResponse} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/sessions/{id}/resume [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) Resume(c *gin.Context) {
+	Action(c, dto.ToWorkoutSessionResponse, h.Usecase.Resume)
+}
+
+// FinishWorkoutSession godoc
+// @Summary Finish a WorkoutSession
+// @Description Finish a WorkoutSession, its ScheduledWorkout is marked as completed
+// @Tags WorkoutSession
+// @Produce json
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionResponse} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Router /v1/workouts/sessions/{id}/finish [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) Finish(c *gin.Context) {
+	Action(c, dto.ToWorkoutSessionResponse, h.Usecase.Finish)
+}
+
+// GetWorkoutSession godoc
+// @Summary Get a WorkoutSession by ID
+// @Description Get a WorkoutSession with its sets by ID
+// @Tags WorkoutSession
+// @Accept json
+// @Produce json
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionResponse} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/sessions/{id} [get]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) GetById(c *gin.Context) {
+	GetById(c, dto.ToWorkoutSessionResponse, h.Usecase.GetById)
+}
+
+// DeleteWorkoutSession godoc
+// @Summary Delete a WorkoutSession
+// @Description Delete a WorkoutSession
+// @Tags WorkoutSession
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse "Success"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/sessions/{id} [delete]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) Delete(c *gin.Context) {
+	Delete(c, h.Usecase.Delete)
+}
+
+// GetWorkoutSessionsByFilter godoc
+// @Summary Get WorkoutSessions by Filter
+// @Description Get the WorkoutSessions of the user by Filter, without their sets
+// @Tags WorkoutSession
+// @Accept json
+// @Param Request body filter.PaginationInputWithFilter true "Request"
+// @Success 200 {object} helper.BaseHttpResponse{result=filter.PagedList[dto.WorkoutSessionResponse]} "WorkoutSession response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Router /v1/workouts/sessions/get-by-filter [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) GetByFilter(c *gin.Context) {
+	GetByFilter(c, dto.ToWorkoutSessionResponse, h.Usecase.GetByFilter)
+}
+
+// LogWorkoutSessionSet godoc
+// @Summary Log a set of a WorkoutSession
+// @Description Log a set performed during a running WorkoutSession, performed_at defaults to now
+// @Tags WorkoutSession
+// @Accept json
+// @produces json
+// @Param id path int true "WorkoutSession ID"
+// @Param Request body dto.CreateWorkoutSessionSetRequest true "Log a set"
+// @Success 201 {object} helper.BaseHttpResponse{result=dto.WorkoutSessionSetResponse} "WorkoutSessionSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 409 {object} helper.BaseHttpResponse "Conflict"
+// @Failure 422 {object} helper.BaseHttpResponse "Unprocessable entity"
+// @Router /v1/workouts/sessions/{id}/sets [post]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) LogSet(c *gin.Context) {
+	sessionId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	request := dto.CreateWorkoutSessionSetRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest,
+			helper.GenerateBaseResponseWithValidationError(nil, false, helper.ValidationError, err))
+		return
+	}
+
+	usecaseInput := dto.ToCreateWorkoutSessionSetRequest(request)
+	usecaseInput.WorkoutSessionId = sessionId
+	result, err := h.Usecase.LogSet(c, usecaseInput)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	c.JSON(http.StatusCreated, helper.GenerateBaseResponse(dto.ToWorkoutSessionSetResponse(result), true, 0))
+}
+
+// GetWorkoutSessionSets godoc
+// @Summary Get the sets of a WorkoutSession
+// @Description Get the sets of a WorkoutSession in the order they were performed
+// @Tags WorkoutSession
+// @Produce json
+// @Param id path int true "WorkoutSession ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=[]dto.WorkoutSessionSetResponse} "WorkoutSessionSet response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/sessions/{id}/sets [get]
+// @Security AuthBearer
+func (h *WorkoutSessionHandler) GetSets(c *gin.Context) {
+	sessionId, ok := pathId(c, "id")
+	if !ok {
+		return
+	}
+	result, err := h.Usecase.GetSets(c, sessionId)
+	if err != nil {
+		c.AbortWithStatusJSON(helper.TranslateErrorToStatusCode(err),
+			helper.GenerateBaseResponseWithError(nil, false, helper.TranslateErrorToResultCode(err), err))
+		return
+	}
+	response := []dto.WorkoutSessionSetResponse{}
+	for _, set := range result {
+		response = append(response, dto.ToWorkoutSessionSetResponse(set))
+	}
+	c.JSON(http.StatusOK, helper.GenerateBaseResponse(response, true, 0))
+}
@@ -5,8 +5,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	_ "github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	_ "github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	_ "github.com/alielmi98/go-hexa-workout/pkg/filter"
 	_ "github.com/alielmi98/go-hexa-workout/pkg/helper"
 
 	"github.com/gin-gonic/gin"
@@ -53,6 +53,23 @@ func (h *WorkoutHandler) GetById(c *gin.Context) {
 	GetById(c, dto.ToWorkoutResponse, h.Usecase.GetById)
 }
 
+// GetAnyWorkout godoc
+// @Summary Get any Workout by ID
+// @Description Get a Workout of any user by ID, admin only
+// @Tags Workout
+// @Accept json
+// @Produce json
+// @Param id path int true "Workout ID"
+// @Success 200 {object} helper.BaseHttpResponse{result=dto.WorkoutResponse} "Workout response"
+// @Failure 400 {object} helper.BaseHttpResponse "Bad request"
+// @Failure 403 {object} helper.BaseHttpResponse "Forbidden"
+// @Failure 404 {object} helper.BaseHttpResponse "Not found"
+// @Router /v1/workouts/admin/workout/{id} [get]
+// @Security AuthBearer
+func (h *WorkoutHandler) GetAnyById(c *gin.Context) {
+	GetById(c, dto.ToWorkoutResponse, h.Usecase.GetAnyById)
+}
+
 // UpdateWorkout godoc
 // @Summary Update a Workout
 // @Description Update a Workout
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/middlewares"
 	"github.com/alielmi98/go-hexa-workout/internal/user/port"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
@@ -16,6 +17,7 @@ func WorkoutRouters(r *gin.RouterGroup, cfg *config.Config, tokenProvider port.T
 	r.GET("/workout/:id", middlewares.Authentication(cfg, tokenProvider), workoutHandler.GetById)
 	r.DELETE("/workout/:id", middlewares.Authentication(cfg, tokenProvider), workoutHandler.Delete)
 	r.POST("/workout/get-by-filter", middlewares.Authentication(cfg, tokenProvider), workoutHandler.GetByFilter)
+	r.GET("/admin/workout/:id", middlewares.Authentication(cfg, tokenProvider), middlewares.Authorization(constants.AdminRoleName), workoutHandler.GetAnyById)
 
 	// WorkoutExercise
 	workoutExerciseHandler := handler.NewWorkoutExerciseHandler(cfg)
@@ -23,18 +25,77 @@ func WorkoutRouters(r *gin.RouterGroup, cfg *config.Config, tokenProvider port.T
 	r.PUT("/workout-exercise/:id", middlewares.Authentication(cfg, tokenProvider), workoutExerciseHandler.Update)
 	r.GET("/workout-exercise/:id", middlewares.Authentication(cfg, tokenProvider), workoutExerciseHandler.GetById)
 	r.DELETE("/workout-exercise/:id", middlewares.Authentication(cfg, tokenProvider), workoutExerciseHandler.Delete)
+	r.POST("/workout-exercise/get-by-filter", middlewares.Authentication(cfg, tokenProvider), workoutExerciseHandler.GetByFilter)
+
+	// ExerciseSet
+	exerciseSetHandler := handler.NewExerciseSetHandler(cfg)
+	r.POST("/workout-exercise/:id/sets", middlewares.Authentication(cfg, tokenProvider), exerciseSetHandler.Create)
+	r.GET("/workout-exercise/:id/sets", middlewares.Authentication(cfg, tokenProvider), exerciseSetHandler.GetByWorkoutExercise)
+	r.GET("/workout-exercise/:id/sets/:setId", middlewares.Authentication(cfg, tokenProvider), exerciseSetHandler.GetById)
+	r.PUT("/workout-exercise/:id/sets/:setId", middlewares.Authentication(cfg, tokenProvider), exerciseSetHandler.Update)
+	r.DELETE("/workout-exercise/:id/sets/:setId", middlewares.Authentication(cfg, tokenProvider), exerciseSetHandler.Delete)
+
+	// Exercise
+	exerciseHandler := handler.NewExerciseHandler(cfg)
+	r.POST("/exercises/", middlewares.Authentication(cfg, tokenProvider), exerciseHandler.Create)
+	r.PUT("/exercises/:id", middlewares.Authentication(cfg, tokenProvider), exerciseHandler.Update)
+	r.GET("/exercises/:id", middlewares.Authentication(cfg, tokenProvider), exerciseHandler.GetById)
+	r.DELETE("/exercises/:id", middlewares.Authentication(cfg, tokenProvider), exerciseHandler.Delete)
+	r.POST("/exercises/get-by-filter", middlewares.Authentication(cfg, tokenProvider), exerciseHandler.GetByFilter)
+	r.POST("/admin/exercises/", middlewares.Authentication(cfg, tokenProvider), middlewares.Authorization(constants.AdminRoleName), exerciseHandler.CreateCatalog)
+	r.PUT("/admin/exercises/:id", middlewares.Authentication(cfg, tokenProvider), middlewares.Authorization(constants.AdminRoleName), exerciseHandler.UpdateCatalog)
+	r.DELETE("/admin/exercises/:id", middlewares.Authentication(cfg, tokenProvider), middlewares.Authorization(constants.AdminRoleName), exerciseHandler.DeleteCatalog)
 
 	// ScheduledWorkout
 	scheduledWorkoutHandler := handler.NewScheduledWorkoutsHandler(cfg)
 	r.POST("/scheduled-workouts/", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Create)
+	r.POST("/scheduled-workouts/expand", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Expand)
 	r.PUT("/scheduled-workouts/:id", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Update)
 	r.GET("/scheduled-workouts/:id", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.GetById)
 	r.DELETE("/scheduled-workouts/:id", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Delete)
+	r.POST("/scheduled-workouts/:id/complete", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Complete)
+	r.POST("/scheduled-workouts/:id/cancel", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Cancel)
+	r.POST("/scheduled-workouts/:id/reschedule", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.Reschedule)
+	r.POST("/scheduled-workouts/get-by-filter", middlewares.Authentication(cfg, tokenProvider), scheduledWorkoutHandler.GetByFilter)
+
+	// Calendar
+	calendarHandler := handler.NewCalendarHandler(cfg)
+	r.GET("/calendar.ics", middlewares.Authentication(cfg, tokenProvider), calendarHandler.Export)
+	r.GET("/calendar/feed/:token", calendarHandler.Feed)
+	r.POST("/calendar/token", middlewares.Authentication(cfg, tokenProvider), calendarHandler.RegenerateToken)
+	r.DELETE("/calendar/token", middlewares.Authentication(cfg, tokenProvider), calendarHandler.RevokeToken)
+
+	// WorkoutSession
+	workoutSessionHandler := handler.NewWorkoutSessionHandler(cfg)
+	r.POST("/sessions/", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.Start)
+	r.GET("/sessions/:id", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.GetById)
+	r.DELETE("/sessions/:id", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.Delete)
+	r.POST("/sessions/get-by-filter", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.GetByFilter)
+	r.POST("/sessions/:id/pause", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.Pause)
+	r.POST("/sessions/:id/resume", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.Resume)
+	r.POST("/sessions/:id/finish", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.Finish)
+	r.POST("/sessions/:id/sets", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.LogSet)
+	r.GET("/sessions/:id/sets", middlewares.Authentication(cfg, tokenProvider), workoutSessionHandler.GetSets)
+
+	// PersonalRecord
+	personalRecordHandler := handler.NewPersonalRecordHandler(cfg)
+	r.GET("/records", middlewares.Authentication(cfg, tokenProvider), personalRecordHandler.GetCurrent)
+	r.GET("/records/:exercise", middlewares.Authentication(cfg, tokenProvider), personalRecordHandler.GetByExercise)
+
+	// Analytics
+	analyticsHandler := handler.NewAnalyticsHandler(cfg)
+	r.GET("/analytics/volume", middlewares.Authentication(cfg, tokenProvider), analyticsHandler.Volume)
+	r.GET("/analytics/exercises", middlewares.Authentication(cfg, tokenProvider), analyticsHandler.ExerciseTrends)
+	r.GET("/analytics/frequency", middlewares.Authentication(cfg, tokenProvider), analyticsHandler.Frequency)
+	r.GET("/analytics/streaks", middlewares.Authentication(cfg, tokenProvider), analyticsHandler.Streaks)
+	r.GET("/analytics/muscles", middlewares.Authentication(cfg, tokenProvider), analyticsHandler.MuscleDistribution)
 
 	// WorkoutReport
 	workoutReportHandler := handler.NewWorkoutReportHandler(cfg)
 	r.POST("/workout-report/", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.Create)
+	r.POST("/workout-report/generate", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.Generate)
 	r.PUT("/workout-report/:id", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.Update)
 	r.GET("/workout-report/:id", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.GetById)
 	r.DELETE("/workout-report/:id", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.Delete)
+	r.POST("/workout-report/get-by-filter", middlewares.Authentication(cfg, tokenProvider), workoutReportHandler.GetByFilter)
 }
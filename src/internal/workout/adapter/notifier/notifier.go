@@ -0,0 +1,71 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	notificationModels "github.com/alielmi98/go-hexa-workout/internal/notification/core/models"
+	notificationUsecase "github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/notification/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+)
+
+// recordNames are the names of the personal record types in the notifications
+var recordNames = map[string]string{
+	models.MaxWeightRecord:          "Max weight",
+	models.EstimatedOneRepMaxRecord: "Estimated 1RM",
+	models.MaxRepsRecord:            "Max reps",
+	models.MaxVolumeRecord:          "Max volume",
+}
+
+// Notifier writes the notifications of the workout context and hands them to the notification context
+type Notifier struct {
+	usecase *notificationUsecase.NotificationUsecase
+}
+
+func NewNotifier(notificationUsecase *notificationUsecase.NotificationUsecase) *Notifier {
+	return &Notifier{usecase: notificationUsecase}
+}
+
+func (n *Notifier) RemindSchedule(ctx context.Context, reminder models.DueReminder) error {
+	return n.usecase.Notify(ctx, dto.NotifyRequest{
+		UserId: reminder.UserId,
+		Kind:   notificationModels.KindScheduleReminder,
+		Title:  fmt.Sprintf("Upcoming workout: %s", reminder.WorkoutName),
+		Body: fmt.Sprintf("Your workout %s is scheduled for %s UTC.",
+			reminder.WorkoutName, reminder.ScheduledTime.UTC().Format("Mon, 02 Jan 2006 15:04")),
+		Data: map[string]interface{}{
+			"scheduled_workout_id": reminder.ScheduledWorkoutId,
+			"scheduled_time":       reminder.ScheduledTime.UTC(),
+		},
+	})
+}
+
+func (n *Notifier) NotifyAchievement(ctx context.Context, achievement models.Achievement) error {
+	lines := make([]string, 0, len(achievement.Records))
+	recordTypes := make([]string, 0, len(achievement.Records))
+	for _, record := range achievement.Records {
+		name, ok := recordNames[record.RecordType]
+		if !ok {
+			name = record.RecordType
+		}
+		line := fmt.Sprintf("%s: %g", name, record.Value)
+		if record.PreviousValue != nil {
+			line += fmt.Sprintf(" (previous %g)", *record.PreviousValue)
+		}
+		lines = append(lines, line)
+		recordTypes = append(recordTypes, record.RecordType)
+	}
+	return n.usecase.Notify(ctx, dto.NotifyRequest{
+		UserId: achievement.UserId,
+		Kind:   notificationModels.KindPersonalRecord,
+		Title:  fmt.Sprintf("New personal record on %s", achievement.ExerciseName),
+		Body:   fmt.Sprintf("You set a new personal record on %s.\n%s", achievement.ExerciseName, strings.Join(lines, "\n")),
+		Data: map[string]interface{}{
+			"workout_exercise_id": achievement.WorkoutExerciseId,
+			"exercise_id":         achievement.ExerciseId,
+			"record_types":        recordTypes,
+		},
+	})
+}
@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"gorm.io/gorm"
+)
+
+// DeleteAccountData soft deletes the workouts of a user, its custom exercises and the rows that belong to them.
+// The user repository runs it in the transaction that deletes the account.
+func DeleteAccountData(tx *gorm.DB, userId int, deleteMap map[string]interface{}) error {
+	// Children first, they are selected through the workouts and sessions that are still active
+	ownedByWorkout := "deleted_by is null and workout_id in (select id from workouts where user_id = ? and deleted_by is null)"
+	cascade := []struct {
+		model interface{}
+		query string
+	}{
+		{&models.ExerciseSet{}, `deleted_by is null and workout_exercise_id in (select workout_exercises.id from workout_exercises
+			join workouts on workouts.id = workout_exercises.workout_id
+			where workouts.user_id = ? and workouts.deleted_by is null and workout_exercises.deleted_by is null)`},
+		{&models.WorkoutSessionSet{}, "deleted_by is null and workout_session_id in (select id from workout_sessions where user_id = ? and deleted_by is null)"},
+		{&models.WorkoutSession{}, "deleted_by is null and user_id = ?"},
+		{&models.PersonalRecord{}, "deleted_by is null and user_id = ?"},
+		{&models.WorkoutExercise{}, ownedByWorkout},
+		{&models.ScheduledWorkouts{}, ownedByWorkout},
+		{&models.ScheduleRule{}, ownedByWorkout},
+		// The generated reports have no workout, every report is selected through its user
+		{&models.WorkoutReport{}, "deleted_by is null and user_id = ?"},
+		{&models.Workout{}, "deleted_by is null and user_id = ?"},
+		{&models.Exercise{}, "deleted_by is null and user_id = ?"},
+		{&models.CalendarFeed{}, "deleted_by is null and user_id = ?"},
+		{&models.ScheduleReminder{}, "deleted_by is null and user_id = ?"},
+	}
+	for _, item := range cascade {
+		if err := tx.Model(item.model).Where(item.query, userId).Updates(deleteMap).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// The exercises of the workouts a user completed in a range with the sets logged of them in the sessions
+// of the scheduled workout, the arguments of completedWhere are the user id and the range
+const (
+	completedFrom = `FROM scheduled_workouts s
+		JOIN workouts w ON w.id = s.workout_id AND w.deleted_by is null
+		JOIN workout_exercises e ON e.workout_id = w.id AND e.deleted_by is null
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS sets, SUM(l.repetitions) AS reps, SUM(l.repetitions * l.weight) AS volume, MAX(l.weight) AS max_weight
+			FROM workout_session_sets l
+			JOIN workout_sessions ws ON ws.id = l.workout_session_id AND ws.deleted_by is null
+			WHERE ws.scheduled_workout_id = s.id AND l.workout_exercise_id = e.id AND l.deleted_by is null
+		) logged ON true`
+	completedWhere = `WHERE w.user_id = ? AND s.deleted_by is null AND s.status = 'completed'
+		AND s.scheduled_time >= ? AND s.scheduled_time < ?`
+	// The periods are computed in UTC, its argument is the period
+	periodColumn = `date_trunc(?, s.scheduled_time AT TIME ZONE 'UTC') AS period`
+	// An exercise counts with the sets logged of it, and with its planned sets, reps and weight
+	// when no set was logged
+	setCount     = `CASE WHEN logged.sets > 0 THEN logged.sets ELSE e.sets END`
+	setReps      = `CASE WHEN logged.sets > 0 THEN logged.reps ELSE e.sets * e.repetitions END`
+	setVolume    = `CASE WHEN logged.sets > 0 THEN logged.volume ELSE e.sets * e.repetitions * e.weight END`
+	setMaxWeight = `CASE WHEN logged.sets > 0 THEN logged.max_weight ELSE e.weight END`
+)
+
+type AnalyticsRepository struct {
+	database *gorm.DB
+}
+
+func NewAnalyticsRepository() *AnalyticsRepository {
+	return &AnalyticsRepository{database: db.GetDb()}
+}
+
+func (r AnalyticsRepository) Volume(ctx context.Context, req models.AnalyticsRange) ([]models.VolumePoint, error) {
+	workoutWhere, args := workoutFilter(req, req.Period, req.UserId, req.From, req.To)
+	query := `SELECT ` + periodColumn + `, SUM(` + setVolume + `) AS volume, COUNT(DISTINCT s.id) AS workouts
+		` + completedFrom + `
+		` + completedWhere + ` ` + workoutWhere + `
+		GROUP BY period ORDER BY period`
+	rows := []models.VolumePoint{}
+	return rows, r.scan(ctx, &rows, query, args...)
+}
+
+func (r AnalyticsRepository) ExerciseTrends(ctx context.Context, req models.AnalyticsRange) ([]models.ExerciseTrendPoint, error) {
+	workoutWhere, args := workoutFilter(req, req.Period, req.UserId, req.From, req.To)
+	exerciseWhere := ""
+	if req.ExerciseId != nil {
+		exerciseWhere = "AND e.exercise_id = ?"
+		args = append(args, *req.ExerciseId)
+	}
+	// Workout exercises without an exercise of the catalog are grouped by their name
+	query := `SELECT e.exercise_id, MIN(e.name) AS name, ` + periodColumn + `,
+			MAX(` + setMaxWeight + `) AS max_weight, SUM(` + setVolume + `) AS volume, SUM(` + setReps + `) AS reps
+		` + completedFrom + `
+		` + completedWhere + ` ` + workoutWhere + ` ` + exerciseWhere + `
+		GROUP BY e.exercise_id, CASE WHEN e.exercise_id is null THEN lower(e.name) END, period
+		ORDER BY name, period`
+	rows := []models.ExerciseTrendPoint{}
+	return rows, r.scan(ctx, &rows, query, args...)
+}
+
+func (r AnalyticsRepository) Frequency(ctx context.Context, req models.AnalyticsRange) ([]models.FrequencyPoint, error) {
+	workoutWhere, args := workoutFilter(req, req.Period, req.UserId, req.From, req.To)
+	query := `SELECT ` + periodColumn + `, COUNT(*) AS scheduled,
+			COUNT(*) FILTER (WHERE s.status = 'completed') AS completed
+		FROM scheduled_workouts s
+		JOIN workouts w ON w.id = s.workout_id AND w.deleted_by is null
+		WHERE w.user_id = ? AND s.deleted_by is null AND s.scheduled_time >= ? AND s.scheduled_time < ? ` + workoutWhere + `
+		GROUP BY period ORDER BY period`
+	rows := []models.FrequencyPoint{}
+	return rows, r.scan(ctx, &rows, query, args...)
+}
+
+func (r AnalyticsRepository) Streaks(ctx context.Context, req models.AnalyticsRange) ([]models.Streak, error) {
+	workoutWhere, args := workoutFilter(req, req.UserId, req.From, req.To)
+	// Consecutive days minus their row number are the same day, which groups every streak
+	query := `SELECT MIN(day) AS start_day, MAX(day) AS end_day, COUNT(*) AS days FROM (
+			SELECT day, day - CAST(ROW_NUMBER() OVER (ORDER BY day) AS int) AS island FROM (
+				SELECT DISTINCT CAST(s.scheduled_time AT TIME ZONE 'UTC' AS date) AS day
+				FROM scheduled_workouts s
+				JOIN workouts w ON w.id = s.workout_id AND w.deleted_by is null
+				` + completedWhere + ` ` + workoutWhere + `
+			) days
+		) islands
+		GROUP BY island ORDER BY start_day`
+	rows := []models.Streak{}
+	return rows, r.scan(ctx, &rows, query, args...)
+}
+
+func (r AnalyticsRepository) MuscleDistribution(ctx context.Context, req models.AnalyticsRange) ([]models.MuscleVolume, error) {
+	workoutWhere, args := workoutFilter(req, req.UserId, req.From, req.To)
+	query := `SELECT muscle, SUM(sets) AS sets, SUM(volume) AS volume FROM (
+			SELECT unnest(string_to_array(x.primary_muscles, ',')) AS muscle, ` + setCount + ` AS sets, ` + setVolume + ` AS volume
+			` + completedFrom + `
+			JOIN exercises x ON x.id = e.exercise_id
+			` + completedWhere + ` ` + workoutWhere + `
+		) muscles
+		GROUP BY muscle ORDER BY sets DESC, muscle`
+	rows := []models.MuscleVolume{}
+	return rows, r.scan(ctx, &rows, query, args...)
+}
+
+// workoutFilter restricts the scheduled workouts to the workout of the range, if any,
+// and appends its argument to the arguments of the query
+func workoutFilter(req models.AnalyticsRange, args ...interface{}) (string, []interface{}) {
+	if req.WorkoutId == nil {
+		return "", args
+	}
+	return "AND s.workout_id = ?", append(args, *req.WorkoutId)
+}
+
+func (r AnalyticsRepository) scan(ctx context.Context, rows interface{}, query string, args ...interface{}) error {
+	err := r.database.WithContext(ctx).Raw(query, args...).Scan(rows).Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return db.TranslateError(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// systemUserId is the creator of the rows the service creates on its own, like the GORM hooks without a user
+const systemUserId = -1
+
+type ScheduleJobsRepository struct {
+	database *gorm.DB
+}
+
+func NewScheduleJobsRepository() *ScheduleJobsRepository {
+	return &ScheduleJobsRepository{database: db.GetDb()}
+}
+
+func (r ScheduleJobsRepository) MarkMissed(ctx context.Context, before time.Time) (int64, error) {
+	query := `UPDATE scheduled_workouts SET status = ?, modified_at = ?
+		WHERE status = ? AND deleted_by is null AND scheduled_time < ?`
+	return r.exec(ctx, constants.Update, query, models.ScheduledMissed, time.Now().UTC(), models.ScheduledPlanned, before)
+}
+
+func (r ScheduleJobsRepository) EnqueueReminders(ctx context.Context, from time.Time, to time.Time) (int64, error) {
+	// A workout is reminded once per scheduled time, the unique index skips the ones already enqueued
+	query := `INSERT INTO schedule_reminders (scheduled_workout_id, user_id, scheduled_time, created_at, created_by)
+		SELECT s.id, w.user_id, s.scheduled_time, ?, ?
+		FROM scheduled_workouts s
+		JOIN workouts w ON w.id = s.workout_id AND w.deleted_by is null
+		WHERE s.status = ? AND s.deleted_by is null AND s.scheduled_time > ? AND s.scheduled_time <= ?
+		ON CONFLICT (scheduled_workout_id, scheduled_time) DO NOTHING`
+	return r.exec(ctx, constants.Insert, query, time.Now().UTC(), systemUserId, models.ScheduledPlanned, from, to)
+}
+
+func (r ScheduleJobsRepository) PendingReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	// A reminder is stale once its scheduled workout moved to another time, so the times are compared
+	query := `SELECT r.id, r.scheduled_workout_id, r.user_id, r.scheduled_time, w.name AS workout_name,
+			(s.status = ? AND s.deleted_by is null AND w.deleted_by is null
+				AND s.scheduled_time = r.scheduled_time AND r.scheduled_time > ?) AS due
+		FROM schedule_reminders r
+		JOIN scheduled_workouts s ON s.id = r.scheduled_workout_id
+		JOIN workouts w ON w.id = s.workout_id
+		WHERE r.sent_at is null AND r.deleted_by is null
+		ORDER BY r.scheduled_time
+		LIMIT ?`
+	var reminders []models.DueReminder
+	err := r.database.WithContext(ctx).Raw(query, models.ScheduledPlanned, now, limit).Scan(&reminders).Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return nil, db.TranslateError(err)
+	}
+	return reminders, nil
+}
+
+func (r ScheduleJobsRepository) MarkRemindersSent(ctx context.Context, ids []int, sentAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query := `UPDATE schedule_reminders SET sent_at = ?, modified_at = ? WHERE id IN ?`
+	_, err := r.exec(ctx, constants.Update, query, sentAt, time.Now().UTC(), ids)
+	return err
+}
+
+func (r ScheduleJobsRepository) exec(ctx context.Context, sub constants.SubCategory, query string, args ...interface{}) (int64, error) {
+	result := r.database.WithContext(ctx).Exec(query, args...)
+	if result.Error != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, sub, result.Error.Error(), nil)
+		return 0, db.TranslateError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
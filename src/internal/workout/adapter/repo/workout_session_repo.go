@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+// WorkoutSessionRepository is the BaseRepository of the sessions with the status updates,
+// which only change a session that still has the status it was read with
+type WorkoutSessionRepository struct {
+	*db.BaseRepository[models.WorkoutSession]
+	database *gorm.DB
+}
+
+func NewWorkoutSessionRepository(preloads []db.PreloadEntity) *WorkoutSessionRepository {
+	return &WorkoutSessionRepository{
+		BaseRepository: db.NewBaseRepository[models.WorkoutSession](preloads),
+		database:       db.GetDb(),
+	}
+}
+
+func (r WorkoutSessionRepository) UpdateStatus(ctx context.Context, id int, from string, session models.WorkoutSession) error {
+	result := db.Conn(ctx, r.database).
+		Model(&session).
+		Where("id = ? and status = ? and deleted_by is null", id, from).
+		Updates(&session)
+	if result.Error != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, result.Error.Error(), nil)
+		return db.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Periods the analytics are grouped by
+const (
+	DayPeriod   = "day"
+	WeekPeriod  = "week"
+	MonthPeriod = "month"
+)
+
+// AnalyticsRange selects the completed scheduled workouts of a user between From and To, To excluded,
+// WorkoutId restricts them to the schedules of a single workout
+type AnalyticsRange struct {
+	UserId     int
+	From       time.Time
+	To         time.Time
+	Period     string
+	ExerciseId *int
+	WorkoutId  *int
+}
+
+// The rows below are aggregates computed by the database, they are not tables
+
+type VolumePoint struct {
+	Period   time.Time
+	Volume   float64
+	Workouts int
+}
+
+type ExerciseTrendPoint struct {
+	ExerciseId *int
+	Name       string
+	Period     time.Time
+	MaxWeight  float64
+	Volume     float64
+	Reps       int
+}
+
+type FrequencyPoint struct {
+	Period    time.Time
+	Scheduled int
+	Completed int
+}
+
+// Streak is a run of consecutive days with a completed workout
+type Streak struct {
+	StartDay time.Time
+	EndDay   time.Time
+	Days     int
+}
+
+type MuscleVolume struct {
+	Muscle string
+	Sets   int
+	Volume float64
+}
@@ -26,6 +26,7 @@ type Workout struct {
 type WorkoutExercise struct {
 	Id          int     `gorm:"primarykey"`
 	WorkoutId   int     `gorm:"not null"`
+	ExerciseId  *int    `gorm:"null"`
 	Name        string  `gorm:"type:string;size:100;not null"`
 	Description string  `gorm:"type:string;size:255;null"`
 	Repetitions int     `gorm:"not null"`
@@ -40,11 +41,151 @@ type WorkoutExercise struct {
 	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
 }
 
+// States of a WorkoutSession
+const (
+	SessionInProgress = "in_progress"
+	SessionPaused     = "paused"
+	SessionFinished   = "finished"
+)
+
+// WorkoutSession is a performed workout, started from a workout template.
+// PausedAt is the last time the session was paused, PausedSeconds sums up the pauses.
+type WorkoutSession struct {
+	Id                 int        `gorm:"primarykey"`
+	UserId             int        `gorm:"not null"`
+	WorkoutId          int        `gorm:"not null"`
+	ScheduledWorkoutId *int       `gorm:"null"`
+	Status             string     `gorm:"type:string;size:20;not null"`
+	StartedAt          time.Time  `gorm:"type:TIMESTAMP with time zone;not null"`
+	PausedAt           *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+	FinishedAt         *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+	PausedSeconds      int        `gorm:"not null;default:0"`
+	DurationSeconds    int        `gorm:"not null;default:0"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// WorkoutSessionSet is a set performed during a session
+type WorkoutSessionSet struct {
+	Id                int       `gorm:"primarykey"`
+	WorkoutSessionId  int       `gorm:"not null"`
+	WorkoutExerciseId int       `gorm:"not null"`
+	ExerciseId        *int      `gorm:"null"`
+	SetIndex          int       `gorm:"not null"`
+	Repetitions       int       `gorm:"not null"`
+	Weight            float64   `gorm:"not null"`
+	Rpe               *float64  `gorm:"null"`
+	SetType           string    `gorm:"type:string;size:20;not null"`
+	PerformedAt       time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// Types of a PersonalRecord
+const (
+	MaxWeightRecord          = "max_weight"
+	EstimatedOneRepMaxRecord = "estimated_1rm"
+	MaxRepsRecord            = "max_reps"
+	MaxVolumeRecord          = "max_volume"
+)
+
+// PersonalRecord is a record of a user on an exercise, a row is added every time a record is broken
+// so the rows of an exercise are its history. Weight is the weight the record was set with,
+// for MaxRepsRecord the record only counts at that weight. A record set by a logged set has the
+// ExerciseSetId of the set or the WorkoutSessionId of its session, one set by the planned values
+// of the workout exercise has neither.
+type PersonalRecord struct {
+	Id                int       `gorm:"primarykey"`
+	UserId            int       `gorm:"not null"`
+	ExerciseId        int       `gorm:"not null"`
+	WorkoutExerciseId int       `gorm:"not null"`
+	ExerciseSetId     *int      `gorm:"null"`
+	WorkoutSessionId  *int      `gorm:"null"`
+	RecordType        string    `gorm:"type:string;size:20;not null"`
+	Value             float64   `gorm:"not null"`
+	Weight            float64   `gorm:"not null"`
+	Repetitions       int       `gorm:"not null"`
+	PreviousValue     *float64  `gorm:"null"`
+	AchievedAt        time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// Types of an ExerciseSet
+const (
+	WarmupSet  = "warmup"
+	WorkingSet = "working"
+	DropSet    = "drop"
+	FailureSet = "failure"
+)
+
+// ExerciseSet is a single set performed of a workout exercise.
+// Rpe and Completed are pointers so they can be updated to their zero values.
+type ExerciseSet struct {
+	Id                int      `gorm:"primarykey"`
+	WorkoutExerciseId int      `gorm:"not null"`
+	SetIndex          int      `gorm:"not null"`
+	Repetitions       int      `gorm:"not null"`
+	Weight            float64  `gorm:"not null"`
+	Rpe               *float64 `gorm:"null"`
+	RestSeconds       int      `gorm:"not null;default:0"`
+	SetType           string   `gorm:"type:string;size:20;not null"`
+	Completed         *bool    `gorm:"not null;default:false"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// Exercise is an entry of the exercise catalog. Catalog exercises have no UserId,
+// custom exercises belong to the user that created them.
+// Muscle groups are stored as comma separated lists.
+type Exercise struct {
+	Id               int    `gorm:"primarykey"`
+	UserId           *int   `gorm:"null"`
+	Name             string `gorm:"type:string;size:100;not null"`
+	Description      string `gorm:"type:string;size:255;null"`
+	Category         string `gorm:"type:string;size:30;not null"`
+	PrimaryMuscles   string `gorm:"type:string;size:255;not null"`
+	SecondaryMuscles string `gorm:"type:string;size:255;null"`
+	Equipment        string `gorm:"type:string;size:30;not null"`
+	UnitType         string `gorm:"type:string;size:20;not null"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// ScheduledWorkouts is a workout planned at ScheduledTime. The occurrences of a ScheduleRule keep
+// the rule and the time the rule generated them at, which stays the same when a single one is moved.
 type ScheduledWorkouts struct {
-	Id            int       `gorm:"primarykey" `
-	WorkoutId     int       `gorm:"not null" `
-	ScheduledTime time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
-	Status        string    `gorm:"type:string;size:20;not null"`
+	Id             int             `gorm:"primarykey" `
+	WorkoutId      int             `gorm:"not null" `
+	ScheduledTime  time.Time       `gorm:"type:TIMESTAMP with time zone;not null"`
+	Status         ScheduledStatus `gorm:"type:string;size:20;not null"`
+	ScheduleRuleId *int            `gorm:"null"`
+	OccurrenceTime *time.Time      `gorm:"type:TIMESTAMP with time zone;null"`
 
 	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
 	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
@@ -54,11 +195,62 @@ type ScheduledWorkouts struct {
 	DeletedBy  *sql.NullInt64 `gorm:"null" `
 }
 
+// Frequencies of a ScheduleRule
+const (
+	DailyFrequency   = "daily"
+	WeeklyFrequency  = "weekly"
+	MonthlyFrequency = "monthly"
+)
+
+// ScheduleRule repeats a workout like an iCalendar RRULE. The occurrences start at StartTime and repeat
+// every Interval days, weeks or months at the wall clock time of StartTime in TimeZone. ByDay lists
+// the weekdays of a weekly rule (MO,WE,FR) or restricts a daily rule to them. The rule ends after
+// Count occurrences or after Until, Exceptions lists the removed occurrences (RFC 3339, comma separated).
+type ScheduleRule struct {
+	Id         int        `gorm:"primarykey"`
+	WorkoutId  int        `gorm:"not null"`
+	Frequency  string     `gorm:"type:string;size:10;not null"`
+	Interval   int        `gorm:"not null;default:1"`
+	ByDay      string     `gorm:"type:string;size:20;null"`
+	StartTime  time.Time  `gorm:"type:TIMESTAMP with time zone;not null"`
+	TimeZone   string     `gorm:"type:string;size:64;not null"`
+	Count      *int       `gorm:"null"`
+	Until      *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+	Exceptions string     `gorm:"type:text;null" filter:"-"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// WorkoutReport is a report on a workout, or on every workout of the user when WorkoutId is null.
+// Generated reports keep the days they cover and their structured content in Payload.
 type WorkoutReport struct {
+	Id          int        `gorm:"primarykey"`
+	WorkoutId   *int       `gorm:"null"`
+	UserId      int        `gorm:"not null"`
+	Details     string     `gorm:"type:text;not null"`
+	PeriodStart *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+	PeriodEnd   *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+	Payload     *string    `gorm:"type:jsonb;null" filter:"-"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// CalendarFeed is the calendar subscription of a user. Only the SHA-256 of the secret token of the
+// subscription URL is stored, regenerating the token replaces it.
+type CalendarFeed struct {
 	Id        int    `gorm:"primarykey"`
-	WorkoutId int    `gorm:"not null"`
 	UserId    int    `gorm:"not null"`
-	Details   string `gorm:"type:string;size:255;not null"`
+	TokenHash string `gorm:"type:string;size:64;not null"`
 
 	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
 	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
@@ -68,6 +260,83 @@ type WorkoutReport struct {
 	DeletedBy  *sql.NullInt64 `gorm:"null"`
 }
 
+// ScheduleReminder is a reminder of an upcoming scheduled workout, it is enqueued by a background job
+// for the scheduled time and SentAt is set once it is delivered. A rescheduled workout is reminded again.
+type ScheduleReminder struct {
+	Id                 int        `gorm:"primarykey"`
+	ScheduledWorkoutId int        `gorm:"not null"`
+	UserId             int        `gorm:"not null"`
+	ScheduledTime      time.Time  `gorm:"type:TIMESTAMP with time zone;not null"`
+	SentAt             *time.Time `gorm:"type:TIMESTAMP with time zone;null"`
+
+	CreatedAt  time.Time      `gorm:"type:TIMESTAMP with time zone;not null"`
+	ModifiedAt sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	DeletedAt  sql.NullTime   `gorm:"type:TIMESTAMP with time zone;null"`
+	CreatedBy  int            `gorm:"not null"`
+	ModifiedBy *sql.NullInt64 `gorm:"null"`
+	DeletedBy  *sql.NullInt64 `gorm:"null"`
+}
+
+// ownedWorkoutScope matches rows whose workout belongs to the user
+const ownedWorkoutScope = "workout_id IN (SELECT id FROM workouts WHERE user_id = ? AND deleted_by is null)"
+
+func (Workout) OwnerScope() string {
+	return "user_id = ?"
+}
+
+func (WorkoutExercise) OwnerScope() string {
+	return ownedWorkoutScope
+}
+
+func (ScheduledWorkouts) OwnerScope() string {
+	return ownedWorkoutScope
+}
+
+func (ScheduleRule) OwnerScope() string {
+	return ownedWorkoutScope
+}
+
+// Reports without a workout only belong to their user, the reports of deleted workouts are hidden
+func (WorkoutReport) OwnerScope() string {
+	return "user_id = ? AND (workout_id is null OR workout_id IN (SELECT id FROM workouts WHERE deleted_by is null))"
+}
+
+func (ExerciseSet) OwnerScope() string {
+	return `workout_exercise_id IN (SELECT workout_exercises.id FROM workout_exercises
+		JOIN workouts ON workouts.id = workout_exercises.workout_id
+		WHERE workouts.user_id = ? AND workouts.deleted_by is null AND workout_exercises.deleted_by is null)`
+}
+
+func (WorkoutSession) OwnerScope() string {
+	return "user_id = ?"
+}
+
+func (WorkoutSessionSet) OwnerScope() string {
+	return "workout_session_id IN (SELECT id FROM workout_sessions WHERE user_id = ? AND deleted_by is null)"
+}
+
+func (PersonalRecord) OwnerScope() string {
+	return "user_id = ?"
+}
+
+func (CalendarFeed) OwnerScope() string {
+	return "user_id = ?"
+}
+
+func (ScheduleReminder) OwnerScope() string {
+	return "user_id = ?"
+}
+
+// Every user sees the catalog next to their own custom exercises
+func (Exercise) OwnerScope() string {
+	return "(user_id is null OR user_id = ?)"
+}
+
+// IsCustom reports whether the exercise was created by a user instead of being part of the catalog
+func (m Exercise) IsCustom() bool {
+	return m.UserId != nil
+}
+
 func (m *Workout) BeforeCreate(tx *gorm.DB) (err error) {
 	value := tx.Statement.Context.Value(constants.UserIdKey)
 	var userId = -1
@@ -200,3 +469,275 @@ func (m *WorkoutReport) BeforeDelete(tx *gorm.DB) (err error) {
 	m.DeletedBy = userId
 	return
 }
+
+// GORM hooks for Exercise
+func (m *Exercise) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *Exercise) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *Exercise) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for ExerciseSet
+func (m *ExerciseSet) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *ExerciseSet) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *ExerciseSet) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for WorkoutSession
+func (m *WorkoutSession) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *WorkoutSession) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *WorkoutSession) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for WorkoutSessionSet
+func (m *WorkoutSessionSet) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *WorkoutSessionSet) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *WorkoutSessionSet) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for PersonalRecord
+func (m *PersonalRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *PersonalRecord) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *PersonalRecord) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for ScheduleRule
+func (m *ScheduleRule) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *ScheduleRule) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *ScheduleRule) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for CalendarFeed
+func (m *CalendarFeed) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *CalendarFeed) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *CalendarFeed) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
+
+// GORM hooks for ScheduleReminder
+func (m *ScheduleReminder) BeforeCreate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = -1
+	if value != nil {
+		userId = int(value.(float64))
+	}
+	m.CreatedAt = time.Now().UTC()
+	m.CreatedBy = userId
+	return
+}
+
+func (m *ScheduleReminder) BeforeUpdate(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.ModifiedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.ModifiedBy = userId
+	return
+}
+
+func (m *ScheduleReminder) BeforeDelete(tx *gorm.DB) (err error) {
+	value := tx.Statement.Context.Value(constants.UserIdKey)
+	var userId = &sql.NullInt64{Valid: false}
+	if value != nil {
+		userId = &sql.NullInt64{Valid: true, Int64: int64(value.(float64))}
+	}
+	m.DeletedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	m.DeletedBy = userId
+	return
+}
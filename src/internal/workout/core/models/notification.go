@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// DueReminder is an unsent ScheduleReminder with the name of its workout. Due is false when the reminder
+// became stale, because its scheduled workout was rescheduled, left the planned status or is deleted,
+// or because its time already passed.
+type DueReminder struct {
+	Id                 int
+	ScheduledWorkoutId int
+	UserId             int
+	ScheduledTime      time.Time
+	WorkoutName        string
+	Due                bool
+}
+
+// Achievement is the personal records a user broke with a workout exercise
+type Achievement struct {
+	UserId            int
+	WorkoutExerciseId int
+	ExerciseId        int
+	ExerciseName      string
+	Records           []AchievedRecord
+}
+
+type AchievedRecord struct {
+	RecordType    string
+	Value         float64
+	PreviousValue *float64
+}
@@ -0,0 +1,205 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WeekdayCodes are the days of ByDay as in the BYDAY part of an RRULE, indexed by time.Weekday
+var WeekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// maxRecurrenceSteps bounds the days, weeks or months walked through to expand a rule
+const maxRecurrenceSteps = 100000
+
+// Location is the time zone of the rule, UTC when it is unknown
+func (r ScheduleRule) Location() *time.Location {
+	location, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Weekdays parses ByDay, the days are sorted from Monday as the weeks of a rule start on Monday
+func (r ScheduleRule) Weekdays() []time.Weekday {
+	weekdays := []time.Weekday{}
+	for _, code := range strings.Split(r.ByDay, ",") {
+		for weekday, weekdayCode := range WeekdayCodes {
+			if strings.TrimSpace(code) == weekdayCode {
+				weekdays = append(weekdays, time.Weekday(weekday))
+			}
+		}
+	}
+	sort.Slice(weekdays, func(i, j int) bool {
+		return mondayFirst(weekdays[i]) < mondayFirst(weekdays[j])
+	})
+	return weekdays
+}
+
+// ExceptionTimes parses Exceptions
+func (r ScheduleRule) ExceptionTimes() []time.Time {
+	exceptions := []time.Time{}
+	for _, value := range strings.Split(r.Exceptions, ",") {
+		if exception, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+			exceptions = append(exceptions, exception)
+		}
+	}
+	return exceptions
+}
+
+// JoinExceptions formats the exceptions of a rule
+func JoinExceptions(exceptions []time.Time) string {
+	values := []string{}
+	for _, exception := range exceptions {
+		values = append(values, exception.UTC().Format(time.RFC3339))
+	}
+	return strings.Join(values, ",")
+}
+
+// RRule formats the rule as the value of an iCalendar RRULE, Until is in UTC as RFC 5545 requires it
+// with a DTSTART in a time zone
+func (r ScheduleRule) RRule() string {
+	parts := []string{"FREQ=" + strings.ToUpper(r.Frequency), fmt.Sprintf("INTERVAL=%d", max(r.Interval, 1))}
+	if weekdays := r.Weekdays(); len(weekdays) > 0 {
+		codes := []string{}
+		for _, weekday := range weekdays {
+			codes = append(codes, WeekdayCodes[weekday])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Count != nil {
+		parts = append(parts, fmt.Sprintf("COUNT=%d", *r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences lists the occurrences of the rule from from, included, to to, excluded
+func (r ScheduleRule) Occurrences(from time.Time, to time.Time) []time.Time {
+	occurrences := []time.Time{}
+	r.walk(func(occurrence time.Time, excepted bool) bool {
+		if !occurrence.Before(to) {
+			return false
+		}
+		if !excepted && !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// Next returns the first occurrence of the rule at or after a time
+func (r ScheduleRule) Next(after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.walk(func(occurrence time.Time, excepted bool) bool {
+		if excepted || occurrence.Before(after) {
+			return true
+		}
+		next, found = occurrence, true
+		return false
+	})
+	return next, found
+}
+
+// IsOccurrence reports whether the rule generates an occurrence at a time
+func (r ScheduleRule) IsOccurrence(t time.Time) bool {
+	next, ok := r.Next(t)
+	return ok && next.Equal(t)
+}
+
+// CountBefore counts the occurrences before a time, the exceptions included as they count for Count
+func (r ScheduleRule) CountBefore(t time.Time) int {
+	count := 0
+	r.walk(func(occurrence time.Time, excepted bool) bool {
+		if !occurrence.Before(t) {
+			return false
+		}
+		count++
+		return true
+	})
+	return count
+}
+
+// walk calls visit with the occurrences of the rule in order until it returns false or the rule ends,
+// the exceptions are visited as they count for Count
+func (r ScheduleRule) walk(visit func(occurrence time.Time, excepted bool) bool) {
+	location := r.Location()
+	start := r.StartTime.In(location)
+	interval := max(r.Interval, 1)
+	weekdays := r.Weekdays()
+	exceptions := r.ExceptionTimes()
+
+	count := 0
+	for step := 0; step < maxRecurrenceSteps; step++ {
+		for _, occurrence := range r.period(start, step*interval, weekdays) {
+			if occurrence.Before(r.StartTime) {
+				continue
+			}
+			if (r.Until != nil && occurrence.After(*r.Until)) || (r.Count != nil && count >= *r.Count) {
+				return
+			}
+			count++
+			excepted := false
+			for _, exception := range exceptions {
+				excepted = excepted || exception.Equal(occurrence)
+			}
+			if !visit(occurrence, excepted) {
+				return
+			}
+		}
+	}
+}
+
+// period lists the candidate occurrences of the n-th day, week or month after the start
+func (r ScheduleRule) period(start time.Time, n int, weekdays []time.Weekday) []time.Time {
+	year, month, day := start.Date()
+	at := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+	}
+
+	switch r.Frequency {
+	case MonthlyFrequency:
+		// Months without the day of the start are skipped
+		occurrence := at(month+time.Month(n), day)
+		if occurrence.Day() != day {
+			return nil
+		}
+		return []time.Time{occurrence}
+	case WeeklyFrequency:
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		monday := day - mondayFirst(start.Weekday()) + 7*n
+		occurrences := []time.Time{}
+		for _, weekday := range weekdays {
+			occurrences = append(occurrences, at(month, monday+mondayFirst(weekday)))
+		}
+		return occurrences
+	default:
+		occurrence := at(month, day+n)
+		if len(weekdays) > 0 && !containsWeekday(weekdays, occurrence.Weekday()) {
+			return nil
+		}
+		return []time.Time{occurrence}
+	}
+}
+
+// mondayFirst numbers the weekdays from Monday
+func mondayFirst(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
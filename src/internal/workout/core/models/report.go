@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ReportPayload is the structured content of a generated WorkoutReport, it is stored as JSON.
+// From and To are the first and the last day of the report.
+type ReportPayload struct {
+	WorkoutId       *int             `json:"workout_id,omitempty"`
+	From            time.Time        `json:"from"`
+	To              time.Time        `json:"to"`
+	Scheduled       int              `json:"scheduled"`
+	Completed       int              `json:"completed"`
+	CompletionRate  float64          `json:"completion_rate"`
+	TotalVolume     float64          `json:"total_volume"`
+	Exercises       []ReportExercise `json:"exercises"`
+	PersonalRecords []ReportRecord   `json:"personal_records"`
+	Previous        ReportComparison `json:"previous_period"`
+}
+
+// ReportExercise is an exercise done in the completed workouts of a report
+type ReportExercise struct {
+	ExerciseId *int    `json:"exercise_id,omitempty"`
+	Name       string  `json:"name"`
+	Reps       int     `json:"reps"`
+	MaxWeight  float64 `json:"max_weight"`
+	Volume     float64 `json:"volume"`
+}
+
+// ReportRecord is a personal record broken during a report
+type ReportRecord struct {
+	ExerciseId    int       `json:"exercise_id"`
+	ExerciseName  string    `json:"exercise_name"`
+	RecordType    string    `json:"record_type"`
+	Value         float64   `json:"value"`
+	PreviousValue *float64  `json:"previous_value,omitempty"`
+	AchievedAt    time.Time `json:"achieved_at"`
+}
+
+// ReportComparison is the period of the same length right before a report, the changes are
+// in percent of the previous period and are null when it had nothing to compare with
+type ReportComparison struct {
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	Scheduled       int       `json:"scheduled"`
+	Completed       int       `json:"completed"`
+	CompletionRate  float64   `json:"completion_rate"`
+	TotalVolume     float64   `json:"total_volume"`
+	VolumeChange    *float64  `json:"volume_change"`
+	CompletedChange *float64  `json:"completed_change"`
+}
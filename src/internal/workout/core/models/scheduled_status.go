@@ -0,0 +1,54 @@
+package models
+
+// ScheduledStatus is the state of a ScheduledWorkouts
+type ScheduledStatus string
+
+// States of a ScheduledWorkouts. A scheduled workout is planned, it is in progress while a session of it
+// runs and ends completed, skipped or cancelled. Missed is only assigned by the service to the planned
+// workouts whose time has passed.
+const (
+	ScheduledPlanned    ScheduledStatus = "planned"
+	ScheduledInProgress ScheduledStatus = "in_progress"
+	ScheduledCompleted  ScheduledStatus = "completed"
+	ScheduledSkipped    ScheduledStatus = "skipped"
+	ScheduledCancelled  ScheduledStatus = "cancelled"
+	ScheduledMissed     ScheduledStatus = "missed"
+)
+
+// ScheduledStatuses lists the states in the order of the transitions
+var ScheduledStatuses = []ScheduledStatus{
+	ScheduledPlanned, ScheduledInProgress, ScheduledCompleted, ScheduledSkipped, ScheduledCancelled, ScheduledMissed,
+}
+
+// scheduledTransitions are the states a state can change to, completed, skipped and cancelled are final.
+// A missed workout can still be done late or planned again.
+var scheduledTransitions = map[ScheduledStatus][]ScheduledStatus{
+	ScheduledPlanned:    {ScheduledInProgress, ScheduledCompleted, ScheduledSkipped, ScheduledCancelled, ScheduledMissed},
+	ScheduledInProgress: {ScheduledCompleted, ScheduledSkipped, ScheduledCancelled},
+	ScheduledMissed:     {ScheduledPlanned, ScheduledInProgress, ScheduledCompleted, ScheduledSkipped, ScheduledCancelled},
+}
+
+// IsValid reports whether the status is one of the states
+func (s ScheduledStatus) IsValid() bool {
+	for _, status := range ScheduledStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether the status can not change anymore
+func (s ScheduledStatus) IsFinal() bool {
+	return s.IsValid() && len(scheduledTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether the status can change to next
+func (s ScheduledStatus) CanTransitionTo(next ScheduledStatus) bool {
+	for _, status := range scheduledTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
+)
+
+const (
+	// DefaultAnalyticsDays is the length of the range when no start is given
+	DefaultAnalyticsDays = 90
+	// MaxAnalyticsDays is the longest range the analytics are computed for
+	MaxAnalyticsDays = 731
+)
+
+const oneDay = 24 * time.Hour
+
+type AnalyticsUsecase struct {
+	repository port.AnalyticsRepository
+}
+
+func NewAnalyticsUsecase(cfg *config.Config, analyticsRepository port.AnalyticsRepository) *AnalyticsUsecase {
+	return &AnalyticsUsecase{
+		repository: analyticsRepository,
+	}
+}
+
+// Volume returns the volume (sets x reps x weight) of the completed workouts per period
+func (u *AnalyticsUsecase) Volume(ctx context.Context, req dto.AnalyticsRequest) ([]dto.VolumeResponse, error) {
+	r, err := u.analyticsRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repository.Volume(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return common.TypeConverter[[]dto.VolumeResponse](points)
+}
+
+// ExerciseTrends returns the max weight, volume and reps of every exercise per period
+func (u *AnalyticsUsecase) ExerciseTrends(ctx context.Context, req dto.AnalyticsRequest) ([]dto.ExerciseTrendResponse, error) {
+	r, err := u.analyticsRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repository.ExerciseTrends(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The workout exercises without an exercise of the catalog are told apart by their name
+	trends := []dto.ExerciseTrendResponse{}
+	indexes := map[string]int{}
+	for _, point := range points {
+		key := "name:" + strings.ToLower(point.Name)
+		if point.ExerciseId != nil {
+			key = "id:" + strconv.Itoa(*point.ExerciseId)
+		}
+		index, ok := indexes[key]
+		if !ok {
+			index = len(trends)
+			indexes[key] = index
+			trends = append(trends, dto.ExerciseTrendResponse{ExerciseId: point.ExerciseId, Name: point.Name})
+		}
+		trends[index].Points = append(trends[index].Points, dto.ExerciseTrendPointResponse{
+			Period:    point.Period,
+			MaxWeight: point.MaxWeight,
+			Volume:    point.Volume,
+			Reps:      point.Reps,
+		})
+	}
+	return trends, nil
+}
+
+// Frequency returns the scheduled and completed workouts per period
+func (u *AnalyticsUsecase) Frequency(ctx context.Context, req dto.AnalyticsRequest) ([]dto.FrequencyResponse, error) {
+	r, err := u.analyticsRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	points, err := u.repository.Frequency(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	response := []dto.FrequencyResponse{}
+	for _, point := range points {
+		response = append(response, dto.FrequencyResponse{
+			Period:         point.Period,
+			Scheduled:      point.Scheduled,
+			Completed:      point.Completed,
+			CompletionRate: percent(float64(point.Completed), float64(point.Scheduled)),
+		})
+	}
+	return response, nil
+}
+
+// Streaks returns the runs of consecutive days with a completed workout, the current streak
+// is the one that reaches the last day of the range or the day before
+func (u *AnalyticsUsecase) Streaks(ctx context.Context, req dto.AnalyticsRequest) (dto.StreaksResponse, error) {
+	r, err := u.analyticsRange(ctx, req)
+	if err != nil {
+		return dto.StreaksResponse{}, err
+	}
+	streaks, err := u.repository.Streaks(ctx, r)
+	if err != nil {
+		return dto.StreaksResponse{}, err
+	}
+
+	response := dto.StreaksResponse{Streaks: []dto.StreakResponse{}}
+	for _, streak := range streaks {
+		current := dto.StreakResponse{StartDay: streak.StartDay, EndDay: streak.EndDay, Days: streak.Days}
+		response.Streaks = append(response.Streaks, current)
+		if current.Days >= response.Longest.Days {
+			response.Longest = current
+		}
+	}
+	lastDay := r.To.Add(-oneDay)
+	if len(streaks) > 0 && !response.Streaks[len(streaks)-1].EndDay.Before(lastDay.Add(-oneDay)) {
+		response.Current = response.Streaks[len(streaks)-1]
+	}
+	return response, nil
+}
+
+// MuscleDistribution returns the sets and volume per primary muscle of the exercises,
+// a set of an exercise with several primary muscles counts for each of them
+func (u *AnalyticsUsecase) MuscleDistribution(ctx context.Context, req dto.AnalyticsRequest) ([]dto.MuscleShareResponse, error) {
+	r, err := u.analyticsRange(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	muscles, err := u.repository.MuscleDistribution(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	totalSets := 0
+	for _, muscle := range muscles {
+		totalSets += muscle.Sets
+	}
+	response := []dto.MuscleShareResponse{}
+	for _, muscle := range muscles {
+		response = append(response, dto.MuscleShareResponse{
+			Muscle:  muscle.Muscle,
+			Sets:    muscle.Sets,
+			Volume:  muscle.Volume,
+			Percent: percent(float64(muscle.Sets), float64(totalSets)),
+		})
+	}
+	return response, nil
+}
+
+// analyticsRange scopes the request to the user, the range covers whole UTC days from From to To,
+// To defaults to today and From to DefaultAnalyticsDays before it
+func (u *AnalyticsUsecase) analyticsRange(ctx context.Context, req dto.AnalyticsRequest) (models.AnalyticsRange, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return models.AnalyticsRange{}, err
+	}
+
+	to := truncateDay(req.To)
+	if req.To.IsZero() {
+		to = truncateDay(time.Now())
+	}
+	from := truncateDay(req.From)
+	if req.From.IsZero() {
+		from = to.Add(-(DefaultAnalyticsDays - 1) * oneDay)
+	}
+	if from.After(to) {
+		return models.AnalyticsRange{}, service_errors.Wrap(service_errors.CodeValidationError, validation.ValidationErrors{{
+			Field:   "from",
+			Tag:     "ltefield",
+			Param:   "to",
+			Message: "must not be after to",
+		}})
+	}
+	if to.Sub(from) >= MaxAnalyticsDays*oneDay {
+		return models.AnalyticsRange{}, service_errors.Wrap(service_errors.CodeValidationError, validation.ValidationErrors{{
+			Field:   "from",
+			Tag:     "max",
+			Param:   strconv.Itoa(MaxAnalyticsDays),
+			Message: fmt.Sprintf("the range must be at most %d days", MaxAnalyticsDays),
+		}})
+	}
+
+	period := req.Period
+	switch period {
+	case "":
+		period = models.WeekPeriod
+	case models.DayPeriod, models.WeekPeriod, models.MonthPeriod:
+	default:
+		return models.AnalyticsRange{}, service_errors.Wrap(service_errors.CodeValidationError, validation.ValidationErrors{{
+			Field:   "period",
+			Tag:     "oneof",
+			Param:   "day week month",
+			Message: "must be one of day week month",
+		}})
+	}
+
+	return models.AnalyticsRange{
+		UserId:     userId,
+		From:       from,
+		To:         to.Add(oneDay),
+		Period:     period,
+		ExerciseId: req.ExerciseId,
+		WorkoutId:  req.WorkoutId,
+	}, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	year, month, dayOfMonth := t.UTC().Date()
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC)
+}
+
+// percent is part of total in percent with one decimal, it is 0 when the total is
+func percent(part float64, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part/total*1000) / 10
+}
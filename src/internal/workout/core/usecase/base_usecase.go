@@ -2,13 +2,11 @@ package usecase
 
 import (
 	"context"
-	"errors"
 
 	"github.com/alielmi98/go-hexa-workout/common"
-	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
@@ -68,35 +66,34 @@ func (u *BaseUsecase[TEntity, TCreate, TUpdate, TResponse]) GetByFilter(ctx cont
 		return response, err
 	}
 
-	return filter.Paginate[TEntity, TResponse](count, entities, req.PageNumber, int64(req.PageSize))
+	return filter.Paginate[TEntity, TResponse](count, entities, req.GetPageNumber(), int64(req.GetPageSize()))
+}
+
+// GetOwnedByFilter is GetByFilter restricted to the rows that belong to the user in the context
+func (u *BaseUsecase[TEntity, TCreate, TUpdate, TResponse]) GetOwnedByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[TResponse], error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.OwnerId = userId
+
+	return u.GetByFilter(ctx, req)
 }
 
 func (u *BaseUsecase[TEntity, TCreate, TUpdate, TResponse]) CheckOwnership(ctx context.Context, workoutRepo port.WorkoutRepository, workoutId int) error {
-	userId, err := u.getUserIdFromContext(ctx)
+	userId, err := common.UserIdFromContext(ctx)
 	if err != nil {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.UserIdNotFound, Err: err}
+		return err
 	}
 
 	workout, err := workoutRepo.GetById(ctx, workoutId)
 	if err != nil {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.FailedToFetchWorkout, Err: err}
+		return service_errors.Wrap(service_errors.CodeFailedToFetchWorkout, err)
 	}
 
 	if userId != workout.UserId {
-		return &service_errors.ServiceError{EndUserMessage: service_errors.UserNotOwner}
+		return service_errors.New(service_errors.CodeUserNotOwner)
 	}
 
 	return nil
 }
-
-func (u *BaseUsecase[TEntity, TCreate, TUpdate, TResponse]) getUserIdFromContext(ctx context.Context) (int, error) {
-	userIdValue := ctx.Value(constants.UserIdKey)
-	if userIdValue == nil {
-		return 0, errors.New("user ID not found in context")
-	}
-	userId, ok := userIdValue.(float64)
-	if !ok {
-		return 0, errors.New("invalid user ID type in context")
-	}
-	return int(userId), nil
-}
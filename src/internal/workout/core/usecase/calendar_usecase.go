@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/ical"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+const (
+	// CalendarPastDays is how far back the scheduled workouts are exported
+	CalendarPastDays = 90
+	// MaxCalendarEvents bounds the scheduled workouts of an export
+	MaxCalendarEvents = 5000
+	// CalendarEventDuration is the length of the events as scheduled workouts have no end
+	CalendarEventDuration = time.Hour
+)
+
+const (
+	calendarProductId = "-//go-hexa-workout//Scheduled Workouts//EN"
+	calendarName      = "Workouts"
+	calendarUidDomain = "go-hexa-workout"
+)
+
+type CalendarUsecase struct {
+	scheduledRepo port.ScheduledWorkoutsRepository
+	ruleRepo      port.ScheduleRuleRepository
+	workoutRepo   port.WorkoutRepository
+	feedRepo      port.CalendarFeedRepository
+}
+
+func NewCalendarUsecase(cfg *config.Config, scheduledWorkoutsRepository port.ScheduledWorkoutsRepository, scheduleRuleRepository port.ScheduleRuleRepository,
+	workoutRepository port.WorkoutRepository, calendarFeedRepository port.CalendarFeedRepository) *CalendarUsecase {
+	return &CalendarUsecase{
+		scheduledRepo: scheduledWorkoutsRepository,
+		ruleRepo:      scheduleRuleRepository,
+		workoutRepo:   workoutRepository,
+		feedRepo:      calendarFeedRepository,
+	}
+}
+
+// Export renders the schedule of the user as an iCalendar
+func (u *CalendarUsecase) Export(ctx context.Context) ([]byte, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return u.export(ctx, userId)
+}
+
+// ExportFeed renders the schedule of the user a subscription token belongs to
+func (u *CalendarUsecase) ExportFeed(ctx context.Context, token string) ([]byte, error) {
+	_, feeds, err := u.feedRepo.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: 1, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{"TokenHash": {Type: "equals", From: hashFeedToken(token), FilterType: "text"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(*feeds) == 0 {
+		return nil, service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return u.export(ctx, (*feeds)[0].UserId)
+}
+
+// RegenerateToken creates the calendar subscription of the user or replaces its token,
+// the previous subscription URL stops working
+func (u *CalendarUsecase) RegenerateToken(ctx context.Context) (dto.CalendarFeedResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+	token, err := newFeedToken()
+	if err != nil {
+		return dto.CalendarFeedResponse{}, service_errors.Wrap(service_errors.CodeUnExpectedError, err)
+	}
+
+	feed, err := u.userFeed(ctx, userId)
+	switch {
+	case err == nil:
+		_, err = u.feedRepo.Update(ctx, feed.Id, models.CalendarFeed{TokenHash: hashFeedToken(token)})
+	case service_errors.HasCode(err, service_errors.CodeRecordNotFound):
+		_, err = u.feedRepo.Create(ctx, models.CalendarFeed{UserId: userId, TokenHash: hashFeedToken(token)})
+	}
+	if err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+	return dto.CalendarFeedResponse{Token: token}, nil
+}
+
+// RevokeToken removes the calendar subscription of the user
+func (u *CalendarUsecase) RevokeToken(ctx context.Context) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	feed, err := u.userFeed(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return u.feedRepo.Delete(ctx, feed.Id)
+}
+
+func (u *CalendarUsecase) userFeed(ctx context.Context, userId int) (models.CalendarFeed, error) {
+	_, feeds, err := u.feedRepo.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: 1, PageNumber: 1},
+		DynamicFilter:   filter.DynamicFilter{OwnerId: userId},
+	})
+	if err != nil {
+		return models.CalendarFeed{}, err
+	}
+	if len(*feeds) == 0 {
+		return models.CalendarFeed{}, service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return (*feeds)[0], nil
+}
+
+// export renders the recurring schedules of the user as recurring events and the scheduled workouts of the
+// last CalendarPastDays onwards as events, or as overrides of the occurrences of their recurring event
+func (u *CalendarUsecase) export(ctx context.Context, userId int) ([]byte, error) {
+	now := time.Now().UTC()
+	from := now.AddDate(0, 0, -CalendarPastDays)
+
+	_, rules, err := u.ruleRepo.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxScheduleRules, PageNumber: 1},
+		DynamicFilter:   filter.DynamicFilter{OwnerId: userId},
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, rows, err := u.scheduledRepo.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxCalendarEvents, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{
+				"ScheduledTime": {Type: "greaterThanOrEqual", From: from.Format(time.RFC3339Nano), FilterType: "date"},
+			},
+			Sort:    &[]filter.Sort{{ColId: "ScheduledTime", Sort: "asc"}},
+			OwnerId: userId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workouts := map[int]models.Workout{}
+	workout := func(id int) (models.Workout, error) {
+		if _, ok := workouts[id]; !ok {
+			w, err := u.workoutRepo.GetById(ctx, id)
+			if err != nil {
+				return w, err
+			}
+			workouts[id] = w
+		}
+		return workouts[id], nil
+	}
+
+	calendar := ical.Calendar{ProductId: calendarProductId, Name: calendarName}
+	recurring := map[int]models.ScheduleRule{}
+	sort.Slice(*rules, func(i, j int) bool { return (*rules)[i].Id < (*rules)[j].Id })
+	for _, rule := range *rules {
+		// Rules that ended before the exported days are left out
+		first, ok := rule.Next(rule.StartTime)
+		if _, active := rule.Next(from); !ok || !active {
+			continue
+		}
+		w, err := workout(rule.WorkoutId)
+		if err != nil {
+			return nil, err
+		}
+		recurring[rule.Id] = rule
+		calendar.Events = append(calendar.Events, ical.Event{
+			Uid:            calendarUid("schedule-rule", rule.Id),
+			Stamp:          now,
+			Start:          first.In(rule.Location()),
+			Duration:       CalendarEventDuration,
+			Summary:        w.Name,
+			Description:    w.Description,
+			Status:         ical.StatusConfirmed,
+			RecurrenceRule: rule.RRule(),
+			ExceptionDates: rule.ExceptionTimes(),
+		})
+	}
+
+	for _, row := range *rows {
+		w, err := workout(row.WorkoutId)
+		if err != nil {
+			return nil, err
+		}
+		event := ical.Event{
+			Uid:         calendarUid("scheduled-workout", row.Id),
+			Stamp:       now,
+			Start:       row.ScheduledTime.UTC(),
+			Duration:    CalendarEventDuration,
+			Summary:     w.Name,
+			Description: w.Description,
+			Status:      ical.StatusConfirmed,
+		}
+		// A missed workout did not take place either
+		if row.Status == models.ScheduledCancelled || row.Status == models.ScheduledSkipped || row.Status == models.ScheduledMissed {
+			event.Status = ical.StatusCancelled
+		}
+		if row.ScheduleRuleId != nil && row.OccurrenceTime != nil {
+			if rule, ok := recurring[*row.ScheduleRuleId]; ok && rule.IsOccurrence(*row.OccurrenceTime) {
+				occurrence := row.OccurrenceTime.In(rule.Location())
+				event.Uid = calendarUid("schedule-rule", rule.Id)
+				event.Start = row.ScheduledTime.In(rule.Location())
+				event.RecurrenceId = &occurrence
+			}
+		}
+		calendar.Events = append(calendar.Events, event)
+	}
+	return calendar.Encode(), nil
+}
+
+func calendarUid(kind string, id int) string {
+	return fmt.Sprintf("%s-%d@%s", kind, id, calendarUidDomain)
+}
+
+// newFeedToken generates the secret token of a calendar subscription
+func newFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashFeedToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
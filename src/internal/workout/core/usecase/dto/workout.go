@@ -26,16 +26,19 @@ type WorkoutResponse struct {
 
 // WorkoutExercise
 type WorkoutExerciseResponse struct {
-	Id          int
-	WorkoutId   int
-	Name        string
-	Description string
-	Repetitions int
-	Sets        int
-	Weight      float64
+	Id              int
+	WorkoutId       int
+	ExerciseId      *int
+	Name            string
+	Description     string
+	Repetitions     int
+	Sets            int
+	Weight          float64
+	PersonalRecords []PersonalRecordResponse
 }
 type CreateWorkoutExerciseRequest struct {
 	WorkoutId   int
+	ExerciseId  *int
 	Name        string
 	Description string
 	Repetitions int
@@ -46,35 +49,140 @@ type CreateWorkoutExerciseRequest struct {
 type UpdateWorkoutExerciseRequest struct {
 	Name        string
 	WorkoutId   int
+	ExerciseId  *int
 	Description string
 	Repetitions int
 	Sets        int
 	Weight      float64
 }
 
+// ExerciseSet
+type ExerciseSetResponse struct {
+	Id                int
+	WorkoutExerciseId int
+	SetIndex          int
+	Repetitions       int
+	Weight            float64
+	Rpe               *float64
+	RestSeconds       int
+	SetType           string
+	Completed         *bool
+	PersonalRecords   []PersonalRecordResponse
+}
+
+type CreateExerciseSetRequest struct {
+	WorkoutExerciseId int
+	SetIndex          int
+	Repetitions       int
+	Weight            float64
+	Rpe               *float64
+	RestSeconds       int
+	SetType           string
+	Completed         *bool
+}
+
+type UpdateExerciseSetRequest struct {
+	SetIndex    int
+	Repetitions int
+	Weight      float64
+	Rpe         *float64
+	RestSeconds int
+	SetType     string
+	Completed   *bool
+}
+
+// Exercise
+// Muscle groups are comma separated, like in the model
+type ExerciseResponse struct {
+	Id               int
+	UserId           *int
+	Name             string
+	Description      string
+	Category         string
+	PrimaryMuscles   string
+	SecondaryMuscles string
+	Equipment        string
+	UnitType         string
+}
+
+type CreateExerciseRequest struct {
+	UserId           *int
+	Name             string
+	Description      string
+	Category         string
+	PrimaryMuscles   string
+	SecondaryMuscles string
+	Equipment        string
+	UnitType         string
+}
+
+type UpdateExerciseRequest struct {
+	Name             string
+	Description      string
+	Category         string
+	PrimaryMuscles   string
+	SecondaryMuscles string
+	Equipment        string
+	UnitType         string
+}
+
 // ScheduledWorkouts
 type ScheduledWorkoutsResponse struct {
-	Id            int
-	WorkoutId     int
-	ScheduledTime string
-	Status        string
+	Id             int
+	WorkoutId      int
+	ScheduledTime  string
+	Status         string
+	ScheduleRuleId *int
+	OccurrenceTime *time.Time
 }
 type CreateScheduledWorkoutsRequest struct {
 	WorkoutId     int
 	ScheduledTime time.Time
 	Status        string
+	Recurrence    *RecurrenceRequest
 }
 
+// UpdateScheduledWorkoutsRequest updates a scheduled workout, Scope tells whether the following or
+// all the occurrences of a recurring one move with it
 type UpdateScheduledWorkoutsRequest struct {
 	ScheduledTime time.Time
 	Status        string
+	Scope         string
+}
+
+// RecurrenceRequest repeats a scheduled workout from its scheduled time
+type RecurrenceRequest struct {
+	Frequency  string
+	Interval   int
+	ByDay      []string
+	Count      *int
+	Until      *time.Time
+	TimeZone   string
+	Exceptions []time.Time
+}
+
+// RescheduleScheduledWorkoutsRequest moves a scheduled workout, Scope as in UpdateScheduledWorkoutsRequest
+type RescheduleScheduledWorkoutsRequest struct {
+	ScheduledTime time.Time
+	Scope         string
+}
+
+// ExpandScheduledWorkoutsRequest selects the window the occurrences of the rules are materialized in,
+// To excluded, and optionally the rule
+type ExpandScheduledWorkoutsRequest struct {
+	From           time.Time
+	To             time.Time
+	ScheduleRuleId *int
 }
 
 type WorkoutReportResponse struct {
-	Id        int
-	WorkoutId int
-	UserId    int
-	Details   string
+	Id          int
+	WorkoutId   *int
+	UserId      int
+	Details     string
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Payload     *string
 }
 
 type CreateWorkoutReportRequest struct {
@@ -83,7 +191,146 @@ type CreateWorkoutReportRequest struct {
 	UserID    int
 }
 
+// UpdateWorkoutReportRequest keeps the workout of the report when WorkoutId is nil
 type UpdateWorkoutReportRequest struct {
-	WorkoutId int
+	WorkoutId *int
 	Details   string
 }
+
+// GenerateWorkoutReportRequest selects the days and optionally the workout a report is generated for
+type GenerateWorkoutReportRequest struct {
+	WorkoutId *int
+	From      time.Time
+	To        time.Time
+}
+
+// WorkoutSession
+type WorkoutSessionResponse struct {
+	Id                 int
+	UserId             int
+	WorkoutId          int
+	ScheduledWorkoutId *int
+	Status             string
+	StartedAt          time.Time
+	PausedAt           *time.Time
+	FinishedAt         *time.Time
+	PausedSeconds      int
+	DurationSeconds    int
+	Sets               []WorkoutSessionSetResponse
+}
+
+type StartWorkoutSessionRequest struct {
+	UserId             int
+	WorkoutId          int
+	ScheduledWorkoutId *int
+	Status             string
+	StartedAt          time.Time
+}
+
+type WorkoutSessionSetResponse struct {
+	Id                int
+	WorkoutSessionId  int
+	WorkoutExerciseId int
+	ExerciseId        *int
+	SetIndex          int
+	Repetitions       int
+	Weight            float64
+	Rpe               *float64
+	SetType           string
+	PerformedAt       time.Time
+	PersonalRecords   []PersonalRecordResponse
+}
+
+type CreateWorkoutSessionSetRequest struct {
+	WorkoutSessionId  int
+	WorkoutExerciseId int
+	ExerciseId        *int
+	SetIndex          int
+	Repetitions       int
+	Weight            float64
+	Rpe               *float64
+	SetType           string
+	PerformedAt       time.Time
+}
+
+// PersonalRecord
+type PersonalRecordResponse struct {
+	Id                int
+	ExerciseId        int
+	WorkoutExerciseId int
+	ExerciseSetId     *int
+	WorkoutSessionId  *int
+	RecordType        string
+	Value             float64
+	Weight            float64
+	Repetitions       int
+	PreviousValue     *float64
+	AchievedAt        time.Time
+}
+
+type ExerciseRecordsResponse struct {
+	ExerciseId   int
+	ExerciseName string
+	Records      []PersonalRecordResponse
+	History      []PersonalRecordResponse
+}
+
+// Analytics
+type AnalyticsRequest struct {
+	From       time.Time
+	To         time.Time
+	Period     string
+	ExerciseId *int
+	WorkoutId  *int
+}
+
+type VolumeResponse struct {
+	Period   time.Time
+	Volume   float64
+	Workouts int
+}
+
+type ExerciseTrendResponse struct {
+	ExerciseId *int
+	Name       string
+	Points     []ExerciseTrendPointResponse
+}
+
+type ExerciseTrendPointResponse struct {
+	Period    time.Time
+	MaxWeight float64
+	Volume    float64
+	Reps      int
+}
+
+type FrequencyResponse struct {
+	Period         time.Time
+	Scheduled      int
+	Completed      int
+	CompletionRate float64
+}
+
+type StreakResponse struct {
+	StartDay time.Time
+	EndDay   time.Time
+	Days     int
+}
+
+type StreaksResponse struct {
+	Current StreakResponse
+	Longest StreakResponse
+	Streaks []StreakResponse
+}
+
+type MuscleShareResponse struct {
+	Muscle  string
+	Sets    int
+	Volume  float64
+	Percent float64
+}
+
+// Calendar
+// CalendarFeedResponse holds the secret token of a calendar subscription, it is only returned when it is generated
+type CalendarFeedResponse struct {
+	Token string
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
+)
+
+// MaxSetsPerExercise is the highest set index of a workout exercise
+const MaxSetsPerExercise = 100
+
+type ExerciseSetUsecase struct {
+	base                *BaseUsecase[models.ExerciseSet, dto.CreateExerciseSetRequest, dto.UpdateExerciseSetRequest, dto.ExerciseSetResponse]
+	repository          port.ExerciseSetRepository
+	workoutExerciseRepo port.WorkoutExerciseRepository
+	workoutRepo         port.WorkoutRepository
+	records             *PersonalRecordUsecase
+	notifier            port.Notifier
+}
+
+func NewExerciseSetUsecase(cfg *config.Config, exerciseSetRepository port.ExerciseSetRepository, workoutExerciseRepository port.WorkoutExerciseRepository, workoutRepository port.WorkoutRepository,
+	exerciseRepository port.ExerciseRepository, personalRecordRepository port.PersonalRecordRepository, notifier port.Notifier) *ExerciseSetUsecase {
+	return &ExerciseSetUsecase{
+		base:                NewBaseUsecase[models.ExerciseSet, dto.CreateExerciseSetRequest, dto.UpdateExerciseSetRequest, dto.ExerciseSetResponse](cfg, exerciseSetRepository),
+		repository:          exerciseSetRepository,
+		workoutExerciseRepo: workoutExerciseRepository,
+		workoutRepo:         workoutRepository,
+		records:             NewPersonalRecordUsecase(cfg, personalRecordRepository, exerciseRepository),
+		notifier:            notifier,
+	}
+}
+
+// Create adds a set to the workout exercise, without a set index it is appended after the last set.
+// The records the set breaks are returned with it.
+func (u *ExerciseSetUsecase) Create(ctx context.Context, req dto.CreateExerciseSetRequest) (dto.ExerciseSetResponse, error) {
+	workoutExercise, err := u.checkOwnership(ctx, req.WorkoutExerciseId)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+
+	if req.SetIndex == 0 {
+		sets, err := u.GetByWorkoutExercise(ctx, req.WorkoutExerciseId)
+		if err != nil {
+			return dto.ExerciseSetResponse{}, err
+		}
+		req.SetIndex = 1
+		if len(sets) > 0 {
+			req.SetIndex = sets[len(sets)-1].SetIndex + 1
+		}
+		if req.SetIndex > MaxSetsPerExercise {
+			return dto.ExerciseSetResponse{}, service_errors.Wrap(service_errors.CodeValidationError, validation.ValidationErrors{{
+				Field:   "set_index",
+				Tag:     "max",
+				Param:   strconv.Itoa(MaxSetsPerExercise),
+				Message: fmt.Sprintf("must be at most %d", MaxSetsPerExercise),
+			}})
+		}
+	}
+	if req.SetType == "" {
+		req.SetType = models.WorkingSet
+	}
+	if req.Completed == nil {
+		completed := false
+		req.Completed = &completed
+	}
+
+	set, err := u.base.Create(ctx, req)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+	set.PersonalRecords = u.detectRecords(ctx, workoutExercise, set, false)
+	return set, nil
+}
+
+// Update changes a set of the workout exercise, the records of the set are detected again
+func (u *ExerciseSetUsecase) Update(ctx context.Context, workoutExerciseId int, id int, req dto.UpdateExerciseSetRequest) (dto.ExerciseSetResponse, error) {
+	_, workoutExercise, err := u.getSet(ctx, workoutExerciseId, id)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+	response, err := u.base.Update(ctx, id, req)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+	// The update only holds the changed fields, the records are detected on the stored row
+	updated, err := u.base.GetById(ctx, id)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+	response.PersonalRecords = u.detectRecords(ctx, workoutExercise, updated, true)
+	return response, nil
+}
+
+func (u *ExerciseSetUsecase) Delete(ctx context.Context, workoutExerciseId int, id int) error {
+	if _, _, err := u.getSet(ctx, workoutExerciseId, id); err != nil {
+		return err
+	}
+	if err := u.base.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := u.records.RemoveSet(ctx, id); err != nil {
+		logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "removing the personal records of an exercise set failed: "+err.Error(), nil)
+	}
+	return nil
+}
+
+func (u *ExerciseSetUsecase) GetById(ctx context.Context, workoutExerciseId int, id int) (dto.ExerciseSetResponse, error) {
+	set, _, err := u.getSet(ctx, workoutExerciseId, id)
+	if err != nil {
+		return dto.ExerciseSetResponse{}, err
+	}
+	return common.TypeConverter[dto.ExerciseSetResponse](set)
+}
+
+// GetByWorkoutExercise returns the sets of the workout exercise ordered by their index
+func (u *ExerciseSetUsecase) GetByWorkoutExercise(ctx context.Context, workoutExerciseId int) ([]dto.ExerciseSetResponse, error) {
+	_, err := u.checkOwnership(ctx, workoutExerciseId)
+	if err != nil {
+		return nil, err
+	}
+
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxSetsPerExercise, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{
+				"WorkoutExerciseId": {Type: "equals", From: strconv.Itoa(workoutExerciseId), FilterType: "number"},
+			},
+			Sort: &[]filter.Sort{{ColId: "SetIndex", Sort: "asc"}},
+		},
+	}
+	_, sets, err := u.repository.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return common.TypeConverter[[]dto.ExerciseSetResponse](sets)
+}
+
+// checkOwnership returns the workout exercise when its workout belongs to the user
+func (u *ExerciseSetUsecase) checkOwnership(ctx context.Context, workoutExerciseId int) (models.WorkoutExercise, error) {
+	workoutExercise, err := u.workoutExerciseRepo.GetById(ctx, workoutExerciseId)
+	if err != nil {
+		return models.WorkoutExercise{}, err
+	}
+	return workoutExercise, u.base.CheckOwnership(ctx, u.workoutRepo, workoutExercise.WorkoutId)
+}
+
+// getSet returns the set with its workout exercise when it belongs to the workout exercise of the user
+func (u *ExerciseSetUsecase) getSet(ctx context.Context, workoutExerciseId int, id int) (models.ExerciseSet, models.WorkoutExercise, error) {
+	workoutExercise, err := u.checkOwnership(ctx, workoutExerciseId)
+	if err != nil {
+		return models.ExerciseSet{}, models.WorkoutExercise{}, err
+	}
+	set, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return models.ExerciseSet{}, models.WorkoutExercise{}, err
+	}
+	if set.WorkoutExerciseId != workoutExerciseId {
+		return models.ExerciseSet{}, models.WorkoutExercise{}, service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return set, workoutExercise, nil
+}
+
+// detectRecords updates the personal records of the user with the set and returns the records it broke,
+// on an update the records of the previous values of the set are replaced
+func (u *ExerciseSetUsecase) detectRecords(ctx context.Context, workoutExercise models.WorkoutExercise, set dto.ExerciseSetResponse, replace bool) []dto.PersonalRecordResponse {
+	if replace {
+		if err := u.records.RemoveSet(ctx, set.Id); err != nil {
+			logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "removing the personal records of an exercise set failed: "+err.Error(), nil)
+			return nil
+		}
+	}
+	records, err := u.records.DetectSet(ctx, workoutExercise, set)
+	return reportRecords(ctx, u.notifier, workoutExercise.Name, records, err)
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+type ExerciseUsecase struct {
+	base       *BaseUsecase[models.Exercise, dto.CreateExerciseRequest, dto.UpdateExerciseRequest, dto.ExerciseResponse]
+	repository port.ExerciseRepository
+}
+
+func NewExerciseUsecase(cfg *config.Config, exerciseRepository port.ExerciseRepository) *ExerciseUsecase {
+	return &ExerciseUsecase{
+		base:       NewBaseUsecase[models.Exercise, dto.CreateExerciseRequest, dto.UpdateExerciseRequest, dto.ExerciseResponse](cfg, exerciseRepository),
+		repository: exerciseRepository,
+	}
+}
+
+// Create adds a custom exercise owned by the user
+func (u *ExerciseUsecase) Create(ctx context.Context, req dto.CreateExerciseRequest) (dto.ExerciseResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.ExerciseResponse{}, err
+	}
+	req.UserId = &userId
+	return u.base.Create(ctx, req)
+}
+
+// Update changes a custom exercise of the user, catalog exercises are managed by admins
+func (u *ExerciseUsecase) Update(ctx context.Context, id int, req dto.UpdateExerciseRequest) (dto.ExerciseResponse, error) {
+	if err := u.checkCustomOwner(ctx, id); err != nil {
+		return dto.ExerciseResponse{}, err
+	}
+	return u.base.Update(ctx, id, req)
+}
+
+// Delete removes a custom exercise of the user
+func (u *ExerciseUsecase) Delete(ctx context.Context, id int) error {
+	if err := u.checkCustomOwner(ctx, id); err != nil {
+		return err
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// GetById returns a catalog exercise or a custom exercise of the user
+func (u *ExerciseUsecase) GetById(ctx context.Context, id int) (dto.ExerciseResponse, error) {
+	exercise, err := u.GetVisible(ctx, id)
+	if err != nil {
+		return dto.ExerciseResponse{}, err
+	}
+	return common.TypeConverter[dto.ExerciseResponse](exercise)
+}
+
+// GetByFilter lists the catalog together with the custom exercises of the user
+func (u *ExerciseUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.ExerciseResponse], error) {
+	return u.base.GetOwnedByFilter(ctx, req)
+}
+
+// CreateCatalog adds an exercise to the catalog, it is meant for admins
+func (u *ExerciseUsecase) CreateCatalog(ctx context.Context, req dto.CreateExerciseRequest) (dto.ExerciseResponse, error) {
+	req.UserId = nil
+	return u.base.Create(ctx, req)
+}
+
+// UpdateCatalog changes a catalog exercise, it is meant for admins
+func (u *ExerciseUsecase) UpdateCatalog(ctx context.Context, id int, req dto.UpdateExerciseRequest) (dto.ExerciseResponse, error) {
+	if err := u.checkCatalog(ctx, id); err != nil {
+		return dto.ExerciseResponse{}, err
+	}
+	return u.base.Update(ctx, id, req)
+}
+
+// DeleteCatalog removes a catalog exercise, it is meant for admins
+func (u *ExerciseUsecase) DeleteCatalog(ctx context.Context, id int) error {
+	if err := u.checkCatalog(ctx, id); err != nil {
+		return err
+	}
+	return u.base.Delete(ctx, id)
+}
+
+// GetVisible returns the exercise when it is in the catalog or is a custom exercise of the user.
+// Custom exercises of other users are reported as not found.
+func (u *ExerciseUsecase) GetVisible(ctx context.Context, id int) (models.Exercise, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	exercise, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return models.Exercise{}, err
+	}
+	if exercise.IsCustom() && *exercise.UserId != userId {
+		return models.Exercise{}, service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return exercise, nil
+}
+
+func (u *ExerciseUsecase) checkCustomOwner(ctx context.Context, id int) error {
+	exercise, err := u.GetVisible(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exercise.IsCustom() {
+		return service_errors.New(service_errors.CodePermissionDenied)
+	}
+	return nil
+}
+
+func (u *ExerciseUsecase) checkCatalog(ctx context.Context, id int) error {
+	exercise, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if exercise.IsCustom() {
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return nil
+}
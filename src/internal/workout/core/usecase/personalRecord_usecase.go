@@ -0,0 +1,382 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+// MaxRecordHistory is the number of records read to find the current records of a user
+const MaxRecordHistory = 10000
+
+type PersonalRecordUsecase struct {
+	base       *BaseUsecase[models.PersonalRecord, struct{}, struct{}, dto.PersonalRecordResponse]
+	repository port.PersonalRecordRepository
+	exercises  *ExerciseUsecase
+}
+
+func NewPersonalRecordUsecase(cfg *config.Config, personalRecordRepository port.PersonalRecordRepository, exerciseRepository port.ExerciseRepository) *PersonalRecordUsecase {
+	return &PersonalRecordUsecase{
+		base:       NewBaseUsecase[models.PersonalRecord, struct{}, struct{}, dto.PersonalRecordResponse](cfg, personalRecordRepository),
+		repository: personalRecordRepository,
+		exercises:  NewExerciseUsecase(cfg, exerciseRepository),
+	}
+}
+
+// recordKey identifies a record of an exercise, max reps records are kept per weight
+type recordKey struct {
+	RecordType string
+	Weight     float64
+}
+
+func keyOf(record models.PersonalRecord) recordKey {
+	if record.RecordType == models.MaxRepsRecord {
+		return recordKey{RecordType: record.RecordType, Weight: record.Weight}
+	}
+	return recordKey{RecordType: record.RecordType}
+}
+
+// performance is what a user did of an exercise, the planned values of a workout exercise or a logged set.
+// Sets is 0 for a logged set, which sets no volume record.
+type performance struct {
+	ExerciseId        int
+	WorkoutExerciseId int
+	ExerciseSetId     *int
+	WorkoutSessionId  *int
+	Sets              int
+	Repetitions       int
+	Weight            float64
+}
+
+// Detect compares a workout exercise of the user with the records on its exercise
+// and stores the records it breaks, workout exercises without an exercise set no records
+func (u *PersonalRecordUsecase) Detect(ctx context.Context, workoutExercise dto.WorkoutExerciseResponse) ([]dto.PersonalRecordResponse, error) {
+	if workoutExercise.ExerciseId == nil {
+		return nil, nil
+	}
+	return u.detect(ctx, performance{
+		ExerciseId:        *workoutExercise.ExerciseId,
+		WorkoutExerciseId: workoutExercise.Id,
+		Sets:              max(workoutExercise.Sets, 1),
+		Repetitions:       workoutExercise.Repetitions,
+		Weight:            workoutExercise.Weight,
+	})
+}
+
+// DetectSet compares a set logged of a workout exercise with the records on the exercise,
+// warmup sets and sets of a workout exercise without an exercise set no records
+func (u *PersonalRecordUsecase) DetectSet(ctx context.Context, workoutExercise models.WorkoutExercise, set dto.ExerciseSetResponse) ([]dto.PersonalRecordResponse, error) {
+	if workoutExercise.ExerciseId == nil || set.SetType == models.WarmupSet {
+		return nil, nil
+	}
+	return u.detect(ctx, performance{
+		ExerciseId:        *workoutExercise.ExerciseId,
+		WorkoutExerciseId: workoutExercise.Id,
+		ExerciseSetId:     &set.Id,
+		Repetitions:       set.Repetitions,
+		Weight:            set.Weight,
+	})
+}
+
+// DetectSessionSet compares a set performed during a session with the records on its exercise,
+// warmup sets and sets without an exercise set no records
+func (u *PersonalRecordUsecase) DetectSessionSet(ctx context.Context, set dto.WorkoutSessionSetResponse) ([]dto.PersonalRecordResponse, error) {
+	if set.ExerciseId == nil || set.SetType == models.WarmupSet {
+		return nil, nil
+	}
+	return u.detect(ctx, performance{
+		ExerciseId:        *set.ExerciseId,
+		WorkoutExerciseId: set.WorkoutExerciseId,
+		WorkoutSessionId:  &set.WorkoutSessionId,
+		Repetitions:       set.Repetitions,
+		Weight:            set.Weight,
+	})
+}
+
+func (u *PersonalRecordUsecase) detect(ctx context.Context, performed performance) ([]dto.PersonalRecordResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := u.history(ctx, userId, map[string]filter.Filter{
+		"ExerciseId": {Type: "equals", From: strconv.Itoa(performed.ExerciseId), FilterType: "number"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	bests := currentRecords(history)
+
+	broken := []dto.PersonalRecordResponse{}
+	now := time.Now().UTC()
+	for _, record := range candidateRecords(performed) {
+		best, ok := bests[keyOf(record)]
+		if ok && record.Value <= best.Value {
+			continue
+		}
+		if ok {
+			record.PreviousValue = &best.Value
+		}
+		record.UserId = userId
+		record.AchievedAt = now
+		record, err = u.repository.Create(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+		response, _ := common.TypeConverter[dto.PersonalRecordResponse](record)
+		broken = append(broken, response)
+	}
+	return broken, nil
+}
+
+// Remove deletes every record set by a workout exercise and its sets, the records they broke are current again
+func (u *PersonalRecordUsecase) Remove(ctx context.Context, workoutExerciseId int) error {
+	return u.remove(ctx, "WorkoutExerciseId", workoutExerciseId, func(models.PersonalRecord) bool { return true })
+}
+
+// RemovePlanned deletes the records set by the planned values of a workout exercise, the records of its sets are kept
+func (u *PersonalRecordUsecase) RemovePlanned(ctx context.Context, workoutExerciseId int) error {
+	return u.remove(ctx, "WorkoutExerciseId", workoutExerciseId, func(record models.PersonalRecord) bool {
+		return record.ExerciseSetId == nil && record.WorkoutSessionId == nil
+	})
+}
+
+// RemoveSet deletes the records set by a logged set of a workout exercise
+func (u *PersonalRecordUsecase) RemoveSet(ctx context.Context, exerciseSetId int) error {
+	return u.remove(ctx, "ExerciseSetId", exerciseSetId, func(models.PersonalRecord) bool { return true })
+}
+
+// RemoveSession deletes the records set by the sets of a session
+func (u *PersonalRecordUsecase) RemoveSession(ctx context.Context, workoutSessionId int) error {
+	return u.remove(ctx, "WorkoutSessionId", workoutSessionId, func(models.PersonalRecord) bool { return true })
+}
+
+// remove deletes the records of the user with the id in the field that match
+func (u *PersonalRecordUsecase) remove(ctx context.Context, field string, id int, match func(models.PersonalRecord) bool) error {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	records, err := u.history(ctx, userId, map[string]filter.Filter{
+		field: {Type: "equals", From: strconv.Itoa(id), FilterType: "number"},
+	})
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if !match(record) {
+			continue
+		}
+		if err := u.repository.Delete(ctx, record.Id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCurrent returns the current records of the user on every exercise
+func (u *PersonalRecordUsecase) GetCurrent(ctx context.Context) ([]dto.PersonalRecordResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	history, err := u.history(ctx, userId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byExercise := map[int][]models.PersonalRecord{}
+	exerciseIds := []int{}
+	for _, record := range history {
+		if _, ok := byExercise[record.ExerciseId]; !ok {
+			exerciseIds = append(exerciseIds, record.ExerciseId)
+		}
+		byExercise[record.ExerciseId] = append(byExercise[record.ExerciseId], record)
+	}
+	records := []models.PersonalRecord{}
+	for _, exerciseId := range exerciseIds {
+		records = append(records, sortedRecords(currentRecords(byExercise[exerciseId]), byExercise[exerciseId])...)
+	}
+	return common.TypeConverter[[]dto.PersonalRecordResponse](records)
+}
+
+// GetByExercise returns the current records of the user on an exercise with their history
+func (u *PersonalRecordUsecase) GetByExercise(ctx context.Context, exerciseId int) (dto.ExerciseRecordsResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.ExerciseRecordsResponse{}, err
+	}
+	exercise, err := u.exercises.GetVisible(ctx, exerciseId)
+	if err != nil {
+		return dto.ExerciseRecordsResponse{}, err
+	}
+	history, err := u.history(ctx, userId, map[string]filter.Filter{
+		"ExerciseId": {Type: "equals", From: strconv.Itoa(exerciseId), FilterType: "number"},
+	})
+	if err != nil {
+		return dto.ExerciseRecordsResponse{}, err
+	}
+
+	response := dto.ExerciseRecordsResponse{ExerciseId: exercise.Id, ExerciseName: exercise.Name}
+	response.Records, err = common.TypeConverter[[]dto.PersonalRecordResponse](sortedRecords(currentRecords(history), history))
+	if err != nil {
+		return dto.ExerciseRecordsResponse{}, err
+	}
+	response.History, err = common.TypeConverter[[]dto.PersonalRecordResponse](history)
+	if err != nil {
+		return dto.ExerciseRecordsResponse{}, err
+	}
+	return response, nil
+}
+
+// history lists the records of the user, the latest first
+func (u *PersonalRecordUsecase) history(ctx context.Context, userId int, filters map[string]filter.Filter) ([]models.PersonalRecord, error) {
+	if filters == nil {
+		filters = map[string]filter.Filter{}
+	}
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxRecordHistory, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter:  filters,
+			Sort:    &[]filter.Sort{{ColId: "AchievedAt", Sort: "desc"}, {ColId: "Id", Sort: "desc"}},
+			OwnerId: userId,
+		},
+	}
+	_, records, err := u.repository.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return *records, nil
+}
+
+// currentRecords picks the best record of every key from the records of an exercise
+func currentRecords(history []models.PersonalRecord) map[recordKey]models.PersonalRecord {
+	bests := map[recordKey]models.PersonalRecord{}
+	for _, record := range history {
+		best, ok := bests[keyOf(record)]
+		if !ok || record.Value > best.Value {
+			bests[keyOf(record)] = record
+		}
+	}
+	return bests
+}
+
+// sortedRecords lists the current records in the order of the history they were picked from
+func sortedRecords(bests map[recordKey]models.PersonalRecord, history []models.PersonalRecord) []models.PersonalRecord {
+	records := []models.PersonalRecord{}
+	for _, record := range history {
+		if bests[keyOf(record)].Id == record.Id {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// candidateRecords lists the records a performance would set on its exercise
+func candidateRecords(performed performance) []models.PersonalRecord {
+	if performed.Repetitions <= 0 {
+		return nil
+	}
+	candidate := func(recordType string, value float64) models.PersonalRecord {
+		return models.PersonalRecord{
+			ExerciseId:        performed.ExerciseId,
+			WorkoutExerciseId: performed.WorkoutExerciseId,
+			ExerciseSetId:     performed.ExerciseSetId,
+			WorkoutSessionId:  performed.WorkoutSessionId,
+			RecordType:        recordType,
+			Value:             value,
+			Weight:            performed.Weight,
+			Repetitions:       performed.Repetitions,
+		}
+	}
+
+	// Reps at body weight are a record as well
+	records := []models.PersonalRecord{candidate(models.MaxRepsRecord, float64(performed.Repetitions))}
+	if performed.Weight <= 0 {
+		return records
+	}
+	records = append(records,
+		candidate(models.MaxWeightRecord, performed.Weight),
+		candidate(models.EstimatedOneRepMaxRecord, EstimatedOneRepMax(performed.Weight, performed.Repetitions)),
+	)
+	if volume := float64(performed.Sets*performed.Repetitions) * performed.Weight; volume > 0 {
+		records = append(records, candidate(models.MaxVolumeRecord, volume))
+	}
+	return records
+}
+
+// reportRecords returns the records a stored workout exercise or set broke and notifies the user of them.
+// What broke them is already stored, so a failed detection is logged instead of failing the request.
+func reportRecords(ctx context.Context, notifier port.Notifier, exerciseName string, records []dto.PersonalRecordResponse, err error) []dto.PersonalRecordResponse {
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "detecting personal records failed: "+err.Error(), nil)
+		return nil
+	}
+	if len(records) > 0 {
+		achievement, err := newAchievement(ctx, exerciseName, records)
+		if err != nil {
+			return records
+		}
+		// The user is notified in the background, the request does not wait for the delivery. The request
+		// context is reused by gin once the handler returns, so the goroutine gets a context of its own.
+		notifyCtx := logging.NewContext(context.Background(), logging.FromContext(ctx))
+		notifyCtx = context.WithValue(notifyCtx, constants.UserIdKey, float64(achievement.UserId))
+		go notifyAchievement(notifyCtx, notifier, achievement)
+	}
+	return records
+}
+
+// newAchievement builds the notification of the personal records broken on an exercise
+func newAchievement(ctx context.Context, exerciseName string, records []dto.PersonalRecordResponse) (models.Achievement, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return models.Achievement{}, err
+	}
+	achievement := models.Achievement{
+		UserId:            userId,
+		WorkoutExerciseId: records[0].WorkoutExerciseId,
+		ExerciseId:        records[0].ExerciseId,
+		ExerciseName:      exerciseName,
+	}
+	for _, record := range records {
+		achievement.Records = append(achievement.Records, models.AchievedRecord{
+			RecordType:    record.RecordType,
+			Value:         record.Value,
+			PreviousValue: record.PreviousValue,
+		})
+	}
+	return achievement, nil
+}
+
+func notifyAchievement(ctx context.Context, notifier port.Notifier, achievement models.Achievement) {
+	if err := notifier.NotifyAchievement(ctx, achievement); err != nil {
+		logging.FromContext(ctx).Warn(constants.Internal, constants.Notification, "notifying personal records failed: "+err.Error(), nil)
+	}
+}
+
+// EstimatedOneRepMax estimates the heaviest single repetition from a set. It uses the Brzycki formula
+// up to 10 repetitions and the Epley formula above, where Brzycki overestimates.
+func EstimatedOneRepMax(weight float64, repetitions int) float64 {
+	var estimate float64
+	switch {
+	case repetitions <= 0:
+		return 0
+	case repetitions == 1:
+		estimate = weight
+	case repetitions <= 10:
+		estimate = weight * 36 / float64(37-repetitions)
+	default:
+		estimate = weight * (1 + float64(repetitions)/30)
+	}
+	return math.Round(estimate*100) / 100
+}
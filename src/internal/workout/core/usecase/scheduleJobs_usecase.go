@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+// reminderBatchSize is the most reminders a run of SendReminders sends, the rest wait for the next run
+const reminderBatchSize = 200
+
+// ScheduleJobsUsecase holds the background jobs that keep the scheduled workouts of all the users up to date
+type ScheduleJobsUsecase struct {
+	repository   port.ScheduleJobsRepository
+	notifier     port.Notifier
+	missedAfter  time.Duration
+	reminderLead time.Duration
+}
+
+func NewScheduleJobsUsecase(cfg *config.Config, scheduleJobsRepository port.ScheduleJobsRepository, notifier port.Notifier) *ScheduleJobsUsecase {
+	return &ScheduleJobsUsecase{
+		repository:   scheduleJobsRepository,
+		notifier:     notifier,
+		missedAfter:  cfg.Jobs.MissedAfter * time.Minute,
+		reminderLead: cfg.Jobs.ReminderLead * time.Minute,
+	}
+}
+
+// MarkMissed marks the planned scheduled workouts whose time passed more than MissedAfter ago as missed
+func (u *ScheduleJobsUsecase) MarkMissed(ctx context.Context) error {
+	count, err := u.repository.MarkMissed(ctx, time.Now().UTC().Add(-u.missedAfter))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logging.FromContext(ctx).Info(constants.Internal, constants.Job, fmt.Sprintf("%d scheduled workouts missed", count), nil)
+	}
+	return nil
+}
+
+// EnqueueReminders enqueues a reminder for the planned scheduled workouts of the next ReminderLead.
+// The reminders already enqueued are kept, so a run that was skipped is caught up by the next one.
+func (u *ScheduleJobsUsecase) EnqueueReminders(ctx context.Context) error {
+	now := time.Now().UTC()
+	count, err := u.repository.EnqueueReminders(ctx, now, now.Add(u.reminderLead))
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		logging.FromContext(ctx).Info(constants.Internal, constants.Job, fmt.Sprintf("%d reminders enqueued", count), nil)
+	}
+	return nil
+}
+
+// SendReminders delivers the due reminders and marks every pending one as sent, the stale ones included.
+// A reminder is sent at most once, a failed delivery is not retried.
+func (u *ScheduleJobsUsecase) SendReminders(ctx context.Context) error {
+	now := time.Now().UTC()
+	reminders, err := u.repository.PendingReminders(ctx, now, reminderBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(reminders) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(reminders))
+	for _, reminder := range reminders {
+		ids = append(ids, reminder.Id)
+	}
+	// The reminders are marked first so a crash during the delivery can not send them twice
+	if err := u.repository.MarkRemindersSent(ctx, ids, now); err != nil {
+		return err
+	}
+
+	var errs []error
+	sent := 0
+	for _, reminder := range reminders {
+		if !reminder.Due {
+			continue
+		}
+		if err := u.notifier.RemindSchedule(ctx, reminder); err != nil {
+			errs = append(errs, fmt.Errorf("reminder %d: %w", reminder.Id, err))
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		logging.FromContext(ctx).Info(constants.Internal, constants.Job, fmt.Sprintf("%d reminders sent", sent), nil)
+	}
+	return errors.Join(errs...)
+}
@@ -2,23 +2,53 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/alielmi98/go-hexa-workout/common"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
+)
+
+// Scopes of a change to an occurrence of a recurring scheduled workout
+const (
+	ThisOccurrence       = "this"
+	FollowingOccurrences = "following"
+	AllOccurrences       = "all"
+)
+
+const (
+	// MaxExpansionDays is the longest window the occurrences of the rules are materialized in
+	MaxExpansionDays = 366
+	// MaxRuleOccurrences is the number of materialized occurrences of a rule read at once
+	MaxRuleOccurrences = 10000
+	// MaxScheduleRules is the number of rules of a user expanded at once
+	MaxScheduleRules = 1000
 )
 
 type ScheduledWorkoutsUseCase struct {
 	base        *BaseUsecase[models.ScheduledWorkouts, dto.CreateScheduledWorkoutsRequest, dto.UpdateScheduledWorkoutsRequest, dto.ScheduledWorkoutsResponse]
+	repository  port.ScheduledWorkoutsRepository
+	ruleRepo    port.ScheduleRuleRepository
 	workoutRepo port.WorkoutRepository
+	transactor  port.Transactor
 }
 
-func NewScheduledWorkoutsUsecase(cfg *config.Config, ScheduledWorkoutsRepository port.ScheduledWorkoutsRepository, workoutRepository port.WorkoutRepository) *ScheduledWorkoutsUseCase {
+func NewScheduledWorkoutsUsecase(cfg *config.Config, ScheduledWorkoutsRepository port.ScheduledWorkoutsRepository, workoutRepository port.WorkoutRepository, scheduleRuleRepository port.ScheduleRuleRepository, transactor port.Transactor) *ScheduledWorkoutsUseCase {
 	return &ScheduledWorkoutsUseCase{
 		base:        NewBaseUsecase[models.ScheduledWorkouts, dto.CreateScheduledWorkoutsRequest, dto.UpdateScheduledWorkoutsRequest, dto.ScheduledWorkoutsResponse](cfg, ScheduledWorkoutsRepository),
+		repository:  ScheduledWorkoutsRepository,
+		ruleRepo:    scheduleRuleRepository,
 		workoutRepo: workoutRepository,
+		transactor:  transactor,
 	}
 }
 
@@ -28,8 +58,16 @@ func (u *ScheduledWorkoutsUseCase) Create(ctx context.Context, req dto.CreateSch
 	if err != nil {
 		return dto.ScheduledWorkoutsResponse{}, err
 	}
-	if req.Status != "active" && req.Status != "completed" && req.Status != "cancelled" {
-		return dto.ScheduledWorkoutsResponse{}, &service_errors.ServiceError{EndUserMessage: service_errors.InvalidStatus}
+	// A scheduled workout starts planned
+	req.Status = string(models.ScheduledPlanned)
+	if req.Recurrence != nil {
+		// The rule and its first occurrence are created together
+		var response dto.ScheduledWorkoutsResponse
+		err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			response, err = u.createRecurring(ctx, req)
+			return err
+		})
+		return response, err
 	}
 
 	return u.base.Create(ctx, req)
@@ -46,14 +84,75 @@ func (u *ScheduledWorkoutsUseCase) Update(ctx context.Context, id int, req dto.U
 		return dto.ScheduledWorkoutsResponse{}, err
 	}
 
-	if req.Status != "active" && req.Status != "completed" && req.Status != "cancelled" {
-		return dto.ScheduledWorkoutsResponse{}, &service_errors.ServiceError{EndUserMessage: service_errors.InvalidStatus}
+	err = checkTransition(models.ScheduledStatus(ScheduledWorkouts.Status), req.Status)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+
+	switch req.Scope {
+	case "", ThisOccurrence:
+		return u.base.Update(ctx, id, req)
+	case FollowingOccurrences, AllOccurrences:
+		// The rules and their occurrences change together
+		var response dto.ScheduledWorkoutsResponse
+		err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			response, err = u.updateSeries(ctx, id, req)
+			return err
+		})
+		return response, err
+	}
+	return dto.ScheduledWorkoutsResponse{}, invalidScope()
+}
+
+// Complete marks a scheduled workout as done
+func (u *ScheduledWorkoutsUseCase) Complete(ctx context.Context, id int) (dto.ScheduledWorkoutsResponse, error) {
+	return u.transition(ctx, id, models.ScheduledCompleted)
+}
+
+// Cancel marks a scheduled workout as cancelled
+func (u *ScheduledWorkoutsUseCase) Cancel(ctx context.Context, id int) (dto.ScheduledWorkoutsResponse, error) {
+	return u.transition(ctx, id, models.ScheduledCancelled)
+}
+
+// Reschedule plans a planned or missed scheduled workout at another time, with the following or all
+// the occurrences of a recurring one as the scope of an update
+func (u *ScheduledWorkoutsUseCase) Reschedule(ctx context.Context, id int, req dto.RescheduleScheduledWorkoutsRequest) (dto.ScheduledWorkoutsResponse, error) {
+	return u.Update(ctx, id, dto.UpdateScheduledWorkoutsRequest{
+		ScheduledTime: req.ScheduledTime,
+		Status:        string(models.ScheduledPlanned),
+		Scope:         req.Scope,
+	})
+}
+
+func (u *ScheduledWorkoutsUseCase) transition(ctx context.Context, id int, status models.ScheduledStatus) (dto.ScheduledWorkoutsResponse, error) {
+	// Check if the user is Owner of the Workout
+	scheduled, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	err = u.base.CheckOwnership(ctx, u.workoutRepo, scheduled.WorkoutId)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
 	}
 
-	return u.base.Update(ctx, id, req)
+	if !scheduled.Status.CanTransitionTo(status) {
+		return dto.ScheduledWorkoutsResponse{}, service_errors.New(service_errors.CodeInvalidStatusTransition)
+	}
+	_, err = u.repository.Update(ctx, id, models.ScheduledWorkouts{Status: status})
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	return u.base.GetById(ctx, id)
 }
 
 func (u *ScheduledWorkoutsUseCase) Delete(ctx context.Context, id int) error {
+	return u.DeleteOccurrences(ctx, id, ThisOccurrence)
+}
+
+// DeleteOccurrences deletes a scheduled workout. An occurrence of a rule becomes an exception of the rule
+// so it is not materialized again, the following scope ends the rule before it and the all scope
+// deletes the rule. The other occurrences of the rule that are no longer planned are kept.
+func (u *ScheduledWorkoutsUseCase) DeleteOccurrences(ctx context.Context, id int, scope string) error {
 	// Check if the user is Owner of the Workout
 	ScheduledWorkouts, err := u.base.GetById(ctx, id)
 	if err != nil {
@@ -63,7 +162,62 @@ func (u *ScheduledWorkoutsUseCase) Delete(ctx context.Context, id int) error {
 	if err != nil {
 		return err
 	}
-	return u.base.Delete(ctx, id)
+
+	switch scope {
+	case "", ThisOccurrence:
+		if ScheduledWorkouts.ScheduleRuleId == nil {
+			return u.base.Delete(ctx, id)
+		}
+	case FollowingOccurrences, AllOccurrences:
+	default:
+		return invalidScope()
+	}
+	// The rule and its occurrences change together
+	return u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		return u.deleteRuleOccurrences(ctx, id, scope)
+	})
+}
+
+// deleteRuleOccurrences deletes an occurrence of a rule with the scope of DeleteOccurrences
+func (u *ScheduledWorkoutsUseCase) deleteRuleOccurrences(ctx context.Context, id int, scope string) error {
+	scheduled, rule, err := u.occurrenceRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	pivot := *scheduled.OccurrenceTime
+
+	from := time.Time{}
+	switch {
+	case scope == "" || scope == ThisOccurrence:
+		exceptions := models.JoinExceptions(append(rule.ExceptionTimes(), pivot))
+		if _, err := u.ruleRepo.Update(ctx, rule.Id, models.ScheduleRule{Exceptions: exceptions}); err != nil {
+			return err
+		}
+		return u.base.Delete(ctx, id)
+	case scope == FollowingOccurrences && rule.CountBefore(pivot) > 0:
+		from = pivot
+		if _, err := u.ruleRepo.Update(ctx, rule.Id, endedBefore(rule, pivot)); err != nil {
+			return err
+		}
+	default:
+		if err := u.ruleRepo.Delete(ctx, rule.Id); err != nil {
+			return err
+		}
+	}
+
+	rows, err := u.ruleOccurrences(ctx, rule.Id, from)
+	if err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if row.Id != id && row.Status != models.ScheduledPlanned {
+			continue
+		}
+		if err := u.repository.Delete(ctx, row.Id); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (u *ScheduledWorkoutsUseCase) GetById(ctx context.Context, id int) (dto.ScheduledWorkoutsResponse, error) {
@@ -80,3 +234,400 @@ func (u *ScheduledWorkoutsUseCase) GetById(ctx context.Context, id int) (dto.Sch
 
 	return ScheduledWorkouts, nil
 }
+
+func (u *ScheduledWorkoutsUseCase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.ScheduledWorkoutsResponse], error) {
+	// Only list rows that belong to workouts owned by the user
+	return u.base.GetOwnedByFilter(ctx, req)
+}
+
+// Expand materializes the occurrences of the rules of the user between From and To, or of a single rule,
+// and returns the occurrences of the window. Occurrences that exist are returned as they are, so the
+// ones moved or done stay as they were.
+func (u *ScheduledWorkoutsUseCase) Expand(ctx context.Context, req dto.ExpandScheduledWorkoutsRequest) ([]dto.ScheduledWorkoutsResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !req.To.After(req.From) {
+		return nil, invalidScheduleField("to", "gtfield", "from", "must be after from")
+	}
+	if req.To.Sub(req.From) > MaxExpansionDays*oneDay {
+		return nil, invalidScheduleField("to", "max", strconv.Itoa(MaxExpansionDays),
+			fmt.Sprintf("the window must be at most %d days", MaxExpansionDays))
+	}
+
+	rules := []models.ScheduleRule{}
+	if req.ScheduleRuleId != nil {
+		rule, err := u.ruleRepo.GetById(ctx, *req.ScheduleRuleId)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.base.CheckOwnership(ctx, u.workoutRepo, rule.WorkoutId); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	} else {
+		_, owned, err := u.ruleRepo.GetByFilter(ctx, filter.PaginationInputWithFilter{
+			PaginationInput: filter.PaginationInput{PageSize: MaxScheduleRules, PageNumber: 1},
+			DynamicFilter:   filter.DynamicFilter{OwnerId: userId},
+		})
+		if err != nil {
+			return nil, err
+		}
+		rules = *owned
+	}
+
+	occurrences := []models.ScheduledWorkouts{}
+	for _, rule := range rules {
+		_, existing, err := u.repository.GetByFilter(ctx, filter.PaginationInputWithFilter{
+			PaginationInput: filter.PaginationInput{PageSize: MaxRuleOccurrences, PageNumber: 1},
+			DynamicFilter: filter.DynamicFilter{
+				Filter: map[string]filter.Filter{
+					"ScheduleRuleId": {Type: "equals", From: strconv.Itoa(rule.Id), FilterType: "number"},
+					"OccurrenceTime": {
+						Type:       "inRange",
+						From:       req.From.UTC().Format(time.RFC3339Nano),
+						To:         req.To.Add(-time.Nanosecond).UTC().Format(time.RFC3339Nano),
+						FilterType: "date",
+					},
+				},
+				OwnerId: userId,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		materialized := map[int64]bool{}
+		for _, row := range *existing {
+			materialized[row.OccurrenceTime.Unix()] = true
+			occurrences = append(occurrences, row)
+		}
+
+		for _, occurrence := range rule.Occurrences(req.From, req.To) {
+			if materialized[occurrence.Unix()] {
+				continue
+			}
+			occurrence := occurrence.UTC()
+			row, err := u.repository.Create(ctx, models.ScheduledWorkouts{
+				WorkoutId:      rule.WorkoutId,
+				ScheduledTime:  occurrence,
+				Status:         models.ScheduledPlanned,
+				ScheduleRuleId: &rule.Id,
+				OccurrenceTime: &occurrence,
+			})
+			if err != nil {
+				return nil, err
+			}
+			occurrences = append(occurrences, row)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].ScheduledTime.Before(occurrences[j].ScheduledTime)
+	})
+	return common.TypeConverter[[]dto.ScheduledWorkoutsResponse](occurrences)
+}
+
+// createRecurring creates the rule of a recurring scheduled workout starting at its scheduled time
+// and materializes its first occurrence
+func (u *ScheduledWorkoutsUseCase) createRecurring(ctx context.Context, req dto.CreateScheduledWorkoutsRequest) (dto.ScheduledWorkoutsResponse, error) {
+	rule, err := newScheduleRule(req)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	first, ok := rule.Next(rule.StartTime)
+	if !ok {
+		return dto.ScheduledWorkoutsResponse{}, invalidScheduleField("recurrence", "required", "", "the recurrence has no occurrence")
+	}
+	rule, err = u.ruleRepo.Create(ctx, rule)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+
+	first = first.UTC()
+	scheduled, err := u.repository.Create(ctx, models.ScheduledWorkouts{
+		WorkoutId:      req.WorkoutId,
+		ScheduledTime:  first,
+		Status:         models.ScheduledStatus(req.Status),
+		ScheduleRuleId: &rule.Id,
+		OccurrenceTime: &first,
+	})
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	return common.TypeConverter[dto.ScheduledWorkoutsResponse](scheduled)
+}
+
+// updateSeries moves an occurrence of a rule with the following or all the occurrences of the rule,
+// the rule is split at the occurrence to move the following ones. The planned occurrences move to the
+// new time, the other ones keep their time and only follow the rule.
+func (u *ScheduledWorkoutsUseCase) updateSeries(ctx context.Context, id int, req dto.UpdateScheduledWorkoutsRequest) (dto.ScheduledWorkoutsResponse, error) {
+	scheduled, rule, err := u.occurrenceRule(ctx, id)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	pivot := *scheduled.OccurrenceTime
+	move := newOccurrenceMove(rule.Location(), pivot, req.ScheduledTime)
+
+	from := time.Time{}
+	moved := move.rule(rule, pivot)
+	if req.Scope == FollowingOccurrences && rule.CountBefore(pivot) > 0 {
+		from = pivot
+		_, err = u.ruleRepo.Update(ctx, rule.Id, endedBefore(rule, pivot))
+		if err == nil {
+			moved, err = u.ruleRepo.Create(ctx, following(moved, rule, pivot, move))
+		}
+	} else {
+		_, err = u.ruleRepo.Update(ctx, rule.Id, moved)
+	}
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+
+	rows, err := u.ruleOccurrences(ctx, rule.Id, from)
+	if err != nil {
+		return dto.ScheduledWorkoutsResponse{}, err
+	}
+	// An occurrence must not take the time of another one that has not moved yet
+	if move.apply(pivot).After(pivot) {
+		sort.SliceStable(rows, func(i, j int) bool { return rows[i].OccurrenceTime.After(*rows[j].OccurrenceTime) })
+	}
+	for _, row := range rows {
+		occurrence := move.apply(*row.OccurrenceTime).UTC()
+		update := models.ScheduledWorkouts{ScheduleRuleId: &moved.Id, OccurrenceTime: &occurrence}
+		switch {
+		case row.Id == id:
+			update.ScheduledTime = occurrence
+			update.Status = models.ScheduledStatus(req.Status)
+		case row.Status != models.ScheduledPlanned:
+			// Occurrences that are done or missed keep their time
+		case moved.IsOccurrence(occurrence):
+			update.ScheduledTime = occurrence
+		default:
+			// Moved by whole days an occurrence can fall on a day the rule skips, such as the 31st of a month
+			if err := u.repository.Delete(ctx, row.Id); err != nil {
+				return dto.ScheduledWorkoutsResponse{}, err
+			}
+			continue
+		}
+		if _, err := u.repository.Update(ctx, row.Id, update); err != nil {
+			return dto.ScheduledWorkoutsResponse{}, err
+		}
+	}
+	return u.base.GetById(ctx, id)
+}
+
+// occurrenceRule reads a scheduled workout with its rule, it fails when the scheduled workout does not repeat
+func (u *ScheduledWorkoutsUseCase) occurrenceRule(ctx context.Context, id int) (models.ScheduledWorkouts, models.ScheduleRule, error) {
+	scheduled, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return models.ScheduledWorkouts{}, models.ScheduleRule{}, err
+	}
+	if scheduled.ScheduleRuleId == nil || scheduled.OccurrenceTime == nil {
+		return models.ScheduledWorkouts{}, models.ScheduleRule{}, invalidScheduleField("scope", "oneof", ThisOccurrence,
+			"the scheduled workout does not repeat")
+	}
+	rule, err := u.ruleRepo.GetById(ctx, *scheduled.ScheduleRuleId)
+	if err != nil {
+		return models.ScheduledWorkouts{}, models.ScheduleRule{}, err
+	}
+	return scheduled, rule, nil
+}
+
+// ruleOccurrences lists the materialized occurrences of a rule from a time, all of them when it is zero
+func (u *ScheduledWorkoutsUseCase) ruleOccurrences(ctx context.Context, ruleId int, from time.Time) ([]models.ScheduledWorkouts, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filters := map[string]filter.Filter{
+		"ScheduleRuleId": {Type: "equals", From: strconv.Itoa(ruleId), FilterType: "number"},
+	}
+	if !from.IsZero() {
+		filters["OccurrenceTime"] = filter.Filter{Type: "greaterThanOrEqual", From: from.UTC().Format(time.RFC3339Nano), FilterType: "date"}
+	}
+	_, rows, err := u.repository.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxRuleOccurrences, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter:  filters,
+			Sort:    &[]filter.Sort{{ColId: "OccurrenceTime", Sort: "asc"}},
+			OwnerId: userId,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *rows, nil
+}
+
+// newScheduleRule validates the recurrence of a scheduled workout, ByDay defaults to the day of the
+// scheduled time for weekly rules and TimeZone to UTC
+func newScheduleRule(req dto.CreateScheduledWorkoutsRequest) (models.ScheduleRule, error) {
+	recurrence := req.Recurrence
+	rule := models.ScheduleRule{
+		WorkoutId: req.WorkoutId,
+		Frequency: recurrence.Frequency,
+		Interval:  recurrence.Interval,
+		StartTime: req.ScheduledTime.UTC(),
+		TimeZone:  recurrence.TimeZone,
+		Count:     recurrence.Count,
+		Until:     recurrence.Until,
+	}
+	switch rule.Frequency {
+	case models.DailyFrequency, models.WeeklyFrequency, models.MonthlyFrequency:
+	default:
+		return rule, invalidScheduleField("frequency", "oneof", "daily weekly monthly", "must be one of daily weekly monthly")
+	}
+	if rule.Interval == 0 {
+		rule.Interval = 1
+	}
+	if rule.Interval < 0 {
+		return rule, invalidScheduleField("interval", "min", "1", "must be at least 1")
+	}
+	if rule.TimeZone == "" {
+		rule.TimeZone = "UTC"
+	}
+	if _, err := time.LoadLocation(rule.TimeZone); err != nil {
+		return rule, invalidScheduleField("time_zone", "timezone", "", "must be an IANA time zone such as Europe/Berlin")
+	}
+
+	days := []string{}
+	seen := map[string]bool{}
+	for _, day := range recurrence.ByDay {
+		day = strings.ToUpper(day)
+		known := false
+		for _, code := range models.WeekdayCodes {
+			known = known || code == day
+		}
+		if !known {
+			return rule, invalidScheduleField("by_day", "oneof", strings.Join(models.WeekdayCodes, " "), "must be one of "+strings.Join(models.WeekdayCodes, " "))
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	if len(days) > 0 && rule.Frequency == models.MonthlyFrequency {
+		return rule, invalidScheduleField("by_day", "excluded_if", "frequency monthly", "monthly rules repeat on the day of the month of the scheduled time")
+	}
+	rule.ByDay = strings.Join(days, ",")
+
+	if rule.Count != nil && rule.Until != nil {
+		return rule, invalidScheduleField("count", "excluded_with", "until", "count and until can not be used together")
+	}
+	if rule.Count != nil && *rule.Count < 1 {
+		return rule, invalidScheduleField("count", "min", "1", "must be at least 1")
+	}
+	if rule.Until != nil && rule.Until.Before(rule.StartTime) {
+		return rule, invalidScheduleField("until", "gtefield", "scheduled_time", "must not be before the scheduled time")
+	}
+	rule.Exceptions = models.JoinExceptions(recurrence.Exceptions)
+	return rule, nil
+}
+
+// checkTransition validates the status requested for a scheduled workout, an empty one keeps the status.
+// Missed is assigned by the service only.
+func checkTransition(current models.ScheduledStatus, requested string) error {
+	next := models.ScheduledStatus(requested)
+	switch {
+	case requested == "" || next == current:
+		return nil
+	case !next.IsValid() || next == models.ScheduledMissed:
+		return service_errors.New(service_errors.CodeInvalidStatus)
+	case !current.CanTransitionTo(next):
+		return service_errors.New(service_errors.CodeInvalidStatusTransition)
+	}
+	return nil
+}
+
+// endedBefore is the update that ends a rule right before an occurrence, a rule with a count keeps
+// a count as a rule can not have both
+func endedBefore(rule models.ScheduleRule, pivot time.Time) models.ScheduleRule {
+	ended := models.ScheduleRule{}
+	if rule.Count != nil {
+		count := rule.CountBefore(pivot)
+		ended.Count = &count
+	} else {
+		until := pivot.Add(-time.Second)
+		ended.Until = &until
+	}
+	return ended
+}
+
+// following is the rule of the occurrences of a rule from an occurrence on, moved as the occurrence was
+func following(moved models.ScheduleRule, rule models.ScheduleRule, pivot time.Time, move occurrenceMove) models.ScheduleRule {
+	next := models.ScheduleRule{
+		WorkoutId: rule.WorkoutId,
+		Frequency: rule.Frequency,
+		Interval:  rule.Interval,
+		ByDay:     moved.ByDay,
+		StartTime: move.apply(pivot).UTC(),
+		TimeZone:  rule.TimeZone,
+		Until:     moved.Until,
+	}
+	if rule.Count != nil {
+		count := *rule.Count - rule.CountBefore(pivot)
+		next.Count = &count
+	}
+	exceptions := []time.Time{}
+	for _, exception := range rule.ExceptionTimes() {
+		if !exception.Before(pivot) {
+			exceptions = append(exceptions, move.apply(exception))
+		}
+	}
+	next.Exceptions = models.JoinExceptions(exceptions)
+	return next
+}
+
+// occurrenceMove moves occurrences by whole days in the time zone of their rule to a new wall clock
+// time, so they keep their time of day over daylight saving changes
+type occurrenceMove struct {
+	location *time.Location
+	days     int
+	clock    time.Time
+}
+
+func newOccurrenceMove(location *time.Location, from time.Time, to time.Time) occurrenceMove {
+	fromYear, fromMonth, fromDay := from.In(location).Date()
+	toYear, toMonth, toDay := to.In(location).Date()
+	days := time.Date(toYear, toMonth, toDay, 0, 0, 0, 0, time.UTC).Sub(time.Date(fromYear, fromMonth, fromDay, 0, 0, 0, 0, time.UTC))
+	return occurrenceMove{location: location, days: int(days / oneDay), clock: to.In(location)}
+}
+
+func (m occurrenceMove) apply(t time.Time) time.Time {
+	year, month, day := t.In(m.location).Date()
+	return time.Date(year, month, day+m.days, m.clock.Hour(), m.clock.Minute(), m.clock.Second(), 0, m.location)
+}
+
+// rule moves the start, the end, the weekdays and the exceptions of a rule as an occurrence of it moves
+func (m occurrenceMove) rule(rule models.ScheduleRule, pivot time.Time) models.ScheduleRule {
+	rule.StartTime = m.apply(rule.StartTime).UTC()
+	if rule.Until != nil {
+		until := rule.Until.Add(m.apply(pivot).Sub(pivot))
+		rule.Until = &until
+	}
+	days := []string{}
+	for _, weekday := range rule.Weekdays() {
+		days = append(days, models.WeekdayCodes[((int(weekday)+m.days)%7+7)%7])
+	}
+	rule.ByDay = strings.Join(days, ",")
+	exceptions := []time.Time{}
+	for _, exception := range rule.ExceptionTimes() {
+		exceptions = append(exceptions, m.apply(exception))
+	}
+	rule.Exceptions = models.JoinExceptions(exceptions)
+	return rule
+}
+
+func invalidScope() error {
+	return invalidScheduleField("scope", "oneof", "this following all", "must be one of this following all")
+}
+
+func invalidScheduleField(field string, tag string, param string, message string) error {
+	return service_errors.Wrap(service_errors.CodeValidationError, validation.ValidationErrors{{
+		Field:   field,
+		Tag:     tag,
+		Param:   param,
+		Message: message,
+	}})
+}
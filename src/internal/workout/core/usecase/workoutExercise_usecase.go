@@ -3,21 +3,32 @@ package usecase
 import (
 	"context"
 
+	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
 type WorkoutExerciseUsecase struct {
 	base        *BaseUsecase[models.WorkoutExercise, dto.CreateWorkoutExerciseRequest, dto.UpdateWorkoutExerciseRequest, dto.WorkoutExerciseResponse]
 	workoutRepo port.WorkoutRepository
+	exercises   *ExerciseUsecase
+	records     *PersonalRecordUsecase
+	notifier    port.Notifier
 }
 
-func NewWorkoutExerciseUsecase(cfg *config.Config, workoutExerciseRepository port.WorkoutExerciseRepository, workoutRepository port.WorkoutRepository) *WorkoutExerciseUsecase {
+func NewWorkoutExerciseUsecase(cfg *config.Config, workoutExerciseRepository port.WorkoutExerciseRepository, workoutRepository port.WorkoutRepository,
+	exerciseRepository port.ExerciseRepository, personalRecordRepository port.PersonalRecordRepository, notifier port.Notifier) *WorkoutExerciseUsecase {
 	return &WorkoutExerciseUsecase{
 		base:        NewBaseUsecase[models.WorkoutExercise, dto.CreateWorkoutExerciseRequest, dto.UpdateWorkoutExerciseRequest, dto.WorkoutExerciseResponse](cfg, workoutExerciseRepository),
 		workoutRepo: workoutRepository,
+		exercises:   NewExerciseUsecase(cfg, exerciseRepository),
+		records:     NewPersonalRecordUsecase(cfg, personalRecordRepository, exerciseRepository),
+		notifier:    notifier,
 	}
 }
 
@@ -28,7 +39,17 @@ func (u *WorkoutExerciseUsecase) Create(ctx context.Context, req dto.CreateWorko
 		return dto.WorkoutExerciseResponse{}, err
 	}
 
-	return u.base.Create(ctx, req)
+	req.Name, err = u.exerciseName(ctx, req.ExerciseId, req.Name)
+	if err != nil {
+		return dto.WorkoutExerciseResponse{}, err
+	}
+
+	response, err := u.base.Create(ctx, req)
+	if err != nil {
+		return dto.WorkoutExerciseResponse{}, err
+	}
+	response.PersonalRecords = u.detectRecords(ctx, response, false)
+	return response, nil
 }
 func (u *WorkoutExerciseUsecase) Update(ctx context.Context, id int, req dto.UpdateWorkoutExerciseRequest) (dto.WorkoutExerciseResponse, error) {
 	// Check if the user is Owner of the Workout
@@ -47,7 +68,22 @@ func (u *WorkoutExerciseUsecase) Update(ctx context.Context, id int, req dto.Upd
 		return dto.WorkoutExerciseResponse{}, err
 	}
 
-	return u.base.Update(ctx, id, req)
+	req.Name, err = u.exerciseName(ctx, req.ExerciseId, req.Name)
+	if err != nil {
+		return dto.WorkoutExerciseResponse{}, err
+	}
+
+	response, err := u.base.Update(ctx, id, req)
+	if err != nil {
+		return dto.WorkoutExerciseResponse{}, err
+	}
+	// The update only holds the changed fields, the records are detected on the stored row
+	updated, err := u.base.GetById(ctx, id)
+	if err != nil {
+		return dto.WorkoutExerciseResponse{}, err
+	}
+	response.PersonalRecords = u.detectRecords(ctx, updated, true)
+	return response, nil
 }
 func (u *WorkoutExerciseUsecase) Delete(ctx context.Context, id int) error {
 	// Check if the user is Owner of the Workout
@@ -60,7 +96,14 @@ func (u *WorkoutExerciseUsecase) Delete(ctx context.Context, id int) error {
 		return err
 	}
 
-	return u.base.Delete(ctx, id)
+	err = u.base.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := u.records.Remove(ctx, id); err != nil {
+		logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "removing the personal records of a workout exercise failed: "+err.Error(), nil)
+	}
+	return nil
 }
 func (u *WorkoutExerciseUsecase) GetById(ctx context.Context, id int) (dto.WorkoutExerciseResponse, error) {
 	// Check if the user is Owner of the Workout
@@ -75,3 +118,40 @@ func (u *WorkoutExerciseUsecase) GetById(ctx context.Context, id int) (dto.Worko
 
 	return workoutExercise, nil
 }
+
+func (u *WorkoutExerciseUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WorkoutExerciseResponse], error) {
+	// Only list rows that belong to workouts owned by the user
+	return u.base.GetOwnedByFilter(ctx, req)
+}
+
+// exerciseName checks that the referenced exercise is in the catalog or is a custom exercise of the user,
+// and names the row after it when no name is given
+func (u *WorkoutExerciseUsecase) exerciseName(ctx context.Context, exerciseId *int, name string) (string, error) {
+	if exerciseId == nil {
+		return name, nil
+	}
+	exercise, err := u.exercises.GetVisible(ctx, *exerciseId)
+	if err != nil {
+		if service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+			return "", invalidReference("exercise_id", err)
+		}
+		return "", err
+	}
+	if name == "" {
+		return exercise.Name, nil
+	}
+	return name, nil
+}
+
+// detectRecords updates the personal records of the user with the workout exercise and returns the records it broke.
+// On an update the records of the previous planned values are replaced, those of the logged sets stay.
+func (u *WorkoutExerciseUsecase) detectRecords(ctx context.Context, workoutExercise dto.WorkoutExerciseResponse, replace bool) []dto.PersonalRecordResponse {
+	if replace {
+		if err := u.records.RemovePlanned(ctx, workoutExercise.Id); err != nil {
+			logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "removing the personal records of a workout exercise failed: "+err.Error(), nil)
+			return nil
+		}
+	}
+	records, err := u.records.Detect(ctx, workoutExercise)
+	return reportRecords(ctx, u.notifier, workoutExercise.Name, records, err)
+}
@@ -2,23 +2,40 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/alielmi98/go-hexa-workout/common"
 	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
+// DefaultReportDays is the number of days a report is generated for when no start is given
+const DefaultReportDays = 7
+
 type WorkoutReportUsecase struct {
 	base        *BaseUsecase[models.WorkoutReport, dto.CreateWorkoutReportRequest, dto.UpdateWorkoutReportRequest, dto.WorkoutReportResponse]
+	repository  port.WorkoutReportRepository
 	workoutRepo port.WorkoutRepository
+	analytics   *AnalyticsUsecase
+	records     *PersonalRecordUsecase
 }
 
-func NewWorkoutReportUsecase(cfg *config.Config, workoutReportRepository port.WorkoutReportRepository, workoutRepository port.WorkoutRepository) *WorkoutReportUsecase {
+func NewWorkoutReportUsecase(cfg *config.Config, workoutReportRepository port.WorkoutReportRepository, workoutRepository port.WorkoutRepository,
+	analyticsRepository port.AnalyticsRepository, personalRecordRepository port.PersonalRecordRepository, exerciseRepository port.ExerciseRepository) *WorkoutReportUsecase {
 	return &WorkoutReportUsecase{
 		base:        NewBaseUsecase[models.WorkoutReport, dto.CreateWorkoutReportRequest, dto.UpdateWorkoutReportRequest, dto.WorkoutReportResponse](cfg, workoutReportRepository),
+		repository:  workoutReportRepository,
 		workoutRepo: workoutRepository,
+		analytics:   NewAnalyticsUsecase(cfg, analyticsRepository),
+		records:     NewPersonalRecordUsecase(cfg, personalRecordRepository, exerciseRepository),
 	}
 }
 
@@ -41,14 +58,16 @@ func (u *WorkoutReportUsecase) Update(ctx context.Context, id int, req dto.Updat
 	if err != nil {
 		return dto.WorkoutReportResponse{}, err
 	}
-	err = u.base.CheckOwnership(ctx, u.workoutRepo, workoutReport.WorkoutId)
+	err = u.checkOwnership(ctx, workoutReport)
 	if err != nil {
 		return dto.WorkoutReportResponse{}, err
 	}
-	// Check if the user is Owner of the Workout to update the report
-	err = u.base.CheckOwnership(ctx, u.workoutRepo, req.WorkoutId)
-	if err != nil {
-		return dto.WorkoutReportResponse{}, err
+	// The report can only be moved to a workout the user owns, generated reports may have no workout
+	if req.WorkoutId != nil {
+		err = u.base.CheckOwnership(ctx, u.workoutRepo, *req.WorkoutId)
+		if err != nil {
+			return dto.WorkoutReportResponse{}, err
+		}
 	}
 
 	return u.base.Update(ctx, id, req)
@@ -60,7 +79,7 @@ func (u *WorkoutReportUsecase) Delete(ctx context.Context, id int) error {
 	if err != nil {
 		return err
 	}
-	err = u.base.CheckOwnership(ctx, u.workoutRepo, workoutReport.WorkoutId)
+	err = u.checkOwnership(ctx, workoutReport)
 	if err != nil {
 		return err
 	}
@@ -74,10 +93,237 @@ func (u *WorkoutReportUsecase) GetById(ctx context.Context, id int) (dto.Workout
 	if err != nil {
 		return dto.WorkoutReportResponse{}, err
 	}
-	err = u.base.CheckOwnership(ctx, u.workoutRepo, workoutReport.WorkoutId)
+	err = u.checkOwnership(ctx, workoutReport)
 	if err != nil {
 		return dto.WorkoutReportResponse{}, err
 	}
 
 	return workoutReport, nil
 }
+
+func (u *WorkoutReportUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WorkoutReportResponse], error) {
+	// Only list rows that belong to workouts owned by the user
+	return u.base.GetOwnedByFilter(ctx, req)
+}
+
+// Generate builds a report on the completed workouts of the user between From and To, or of a single
+// workout when WorkoutId is set, and stores it with a text summary. The report is compared to the
+// period of the same length right before it.
+func (u *WorkoutReportUsecase) Generate(ctx context.Context, req dto.GenerateWorkoutReportRequest) (dto.WorkoutReportResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+	if req.WorkoutId != nil {
+		if err := u.base.CheckOwnership(ctx, u.workoutRepo, *req.WorkoutId); err != nil {
+			return dto.WorkoutReportResponse{}, err
+		}
+	}
+	if req.From.IsZero() {
+		to := req.To
+		if to.IsZero() {
+			to = time.Now()
+		}
+		req.From = to.Add(-(DefaultReportDays - 1) * oneDay)
+	}
+	r, err := u.analytics.analyticsRange(ctx, dto.AnalyticsRequest{From: req.From, To: req.To, WorkoutId: req.WorkoutId})
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+
+	payload := models.ReportPayload{
+		WorkoutId:       req.WorkoutId,
+		From:            r.From,
+		To:              r.To.Add(-oneDay),
+		Exercises:       []models.ReportExercise{},
+		PersonalRecords: []models.ReportRecord{},
+	}
+	current := dto.AnalyticsRequest{From: payload.From, To: payload.To, WorkoutId: req.WorkoutId}
+	payload.Scheduled, payload.Completed, payload.TotalVolume, err = u.totals(ctx, current)
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+	payload.CompletionRate = percent(float64(payload.Completed), float64(payload.Scheduled))
+	payload.Exercises, err = u.exercisesDone(ctx, current)
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+	payload.PersonalRecords, err = u.recordsHit(ctx, userId, r, payload.Exercises)
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+
+	previous := &payload.Previous
+	previous.From = r.From.Add(-r.To.Sub(r.From))
+	previous.To = r.From.Add(-oneDay)
+	previous.Scheduled, previous.Completed, previous.TotalVolume, err = u.totals(ctx,
+		dto.AnalyticsRequest{From: previous.From, To: previous.To, WorkoutId: req.WorkoutId})
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+	previous.CompletionRate = percent(float64(previous.Completed), float64(previous.Scheduled))
+	previous.VolumeChange = change(payload.TotalVolume, previous.TotalVolume)
+	previous.CompletedChange = change(float64(payload.Completed), float64(previous.Completed))
+
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return dto.WorkoutReportResponse{}, service_errors.Wrap(service_errors.CodeUnExpectedError, err)
+	}
+	encoded := string(content)
+	report, err := u.repository.Create(ctx, models.WorkoutReport{
+		WorkoutId:   req.WorkoutId,
+		UserId:      userId,
+		Details:     summarize(payload),
+		PeriodStart: &payload.From,
+		PeriodEnd:   &payload.To,
+		Payload:     &encoded,
+	})
+	if err != nil {
+		return dto.WorkoutReportResponse{}, err
+	}
+	return common.TypeConverter[dto.WorkoutReportResponse](report)
+}
+
+// checkOwnership checks the user owns the workout of a report, or the report itself when it has no workout
+func (u *WorkoutReportUsecase) checkOwnership(ctx context.Context, workoutReport dto.WorkoutReportResponse) error {
+	if workoutReport.WorkoutId != nil {
+		return u.base.CheckOwnership(ctx, u.workoutRepo, *workoutReport.WorkoutId)
+	}
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if userId != workoutReport.UserId {
+		return service_errors.New(service_errors.CodeUserNotOwner)
+	}
+	return nil
+}
+
+// totals sums the scheduled and the completed workouts and the volume of a range
+func (u *WorkoutReportUsecase) totals(ctx context.Context, req dto.AnalyticsRequest) (scheduled int, completed int, volume float64, err error) {
+	frequency, err := u.analytics.Frequency(ctx, req)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, point := range frequency {
+		scheduled += point.Scheduled
+		completed += point.Completed
+	}
+	points, err := u.analytics.Volume(ctx, req)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	for _, point := range points {
+		volume += point.Volume
+	}
+	return scheduled, completed, volume, nil
+}
+
+// exercisesDone sums the trends of every exercise done in a range
+func (u *WorkoutReportUsecase) exercisesDone(ctx context.Context, req dto.AnalyticsRequest) ([]models.ReportExercise, error) {
+	trends, err := u.analytics.ExerciseTrends(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	exercises := []models.ReportExercise{}
+	for _, trend := range trends {
+		exercise := models.ReportExercise{ExerciseId: trend.ExerciseId, Name: trend.Name}
+		for _, point := range trend.Points {
+			exercise.Reps += point.Reps
+			exercise.Volume += point.Volume
+			exercise.MaxWeight = max(exercise.MaxWeight, point.MaxWeight)
+		}
+		exercises = append(exercises, exercise)
+	}
+	return exercises, nil
+}
+
+// recordsHit lists the personal records the user broke in a range, the oldest first. A report on
+// a workout only keeps the records on the exercises done in it.
+func (u *WorkoutReportUsecase) recordsHit(ctx context.Context, userId int, r models.AnalyticsRange, exercises []models.ReportExercise) ([]models.ReportRecord, error) {
+	history, err := u.records.history(ctx, userId, map[string]filter.Filter{
+		"AchievedAt": {
+			Type:       "inRange",
+			From:       r.From.Format(time.RFC3339Nano),
+			To:         r.To.Add(-time.Nanosecond).Format(time.RFC3339Nano),
+			FilterType: "date",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[int]string{}
+	for _, exercise := range exercises {
+		if exercise.ExerciseId != nil {
+			names[*exercise.ExerciseId] = exercise.Name
+		}
+	}
+	records := []models.ReportRecord{}
+	for i := len(history) - 1; i >= 0; i-- {
+		record := history[i]
+		name, ok := names[record.ExerciseId]
+		if !ok && r.WorkoutId != nil {
+			continue
+		}
+		if !ok {
+			// Records on a deleted custom exercise keep no name
+			if exercise, err := u.records.exercises.GetVisible(ctx, record.ExerciseId); err == nil {
+				name = exercise.Name
+			}
+			names[record.ExerciseId] = name
+		}
+		records = append(records, models.ReportRecord{
+			ExerciseId:    record.ExerciseId,
+			ExerciseName:  name,
+			RecordType:    record.RecordType,
+			Value:         record.Value,
+			PreviousValue: record.PreviousValue,
+			AchievedAt:    record.AchievedAt,
+		})
+	}
+	return records, nil
+}
+
+// change is the change from previous to current in percent of previous, nil when previous is 0
+func change(current float64, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	value := percent(current-previous, previous)
+	return &value
+}
+
+// summarize writes the text summary of a generated report
+func summarize(payload models.ReportPayload) string {
+	lines := []string{}
+	title := fmt.Sprintf("Report from %s to %s", payload.From.Format(time.DateOnly), payload.To.Format(time.DateOnly))
+	if payload.WorkoutId != nil {
+		title += fmt.Sprintf(" on workout %d", *payload.WorkoutId)
+	}
+	lines = append(lines, title)
+	lines = append(lines, fmt.Sprintf("Completed %d of %d scheduled workouts (%g%%)",
+		payload.Completed, payload.Scheduled, payload.CompletionRate))
+
+	volume := fmt.Sprintf("Total volume %g", payload.TotalVolume)
+	if payload.Previous.VolumeChange != nil {
+		volume += fmt.Sprintf(" (%+g%% compared to the previous period)", *payload.Previous.VolumeChange)
+	}
+	lines = append(lines, volume)
+
+	if len(payload.Exercises) == 0 {
+		lines = append(lines, "No exercises done")
+	}
+	for _, exercise := range payload.Exercises {
+		lines = append(lines, fmt.Sprintf("- %s: %d reps, max weight %g, volume %g",
+			exercise.Name, exercise.Reps, exercise.MaxWeight, exercise.Volume))
+	}
+
+	if len(payload.PersonalRecords) > 0 {
+		lines = append(lines, fmt.Sprintf("%d personal records", len(payload.PersonalRecords)))
+	}
+	for _, record := range payload.PersonalRecords {
+		lines = append(lines, fmt.Sprintf("- %s: %s %g", record.ExerciseName, record.RecordType, record.Value))
+	}
+	return strings.Join(lines, "\n")
+}
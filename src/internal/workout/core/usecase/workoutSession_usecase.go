@@ -0,0 +1,359 @@
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+const (
+	// MaxSetsPerSession is the number of sets listed for a session
+	MaxSetsPerSession = 1000
+	// MaxSessionsPerSchedule is the number of sessions of a scheduled workout read to find the running one
+	MaxSessionsPerSchedule = 100
+)
+
+type WorkoutSessionUsecase struct {
+	base                *BaseUsecase[models.WorkoutSession, dto.StartWorkoutSessionRequest, struct{}, dto.WorkoutSessionResponse]
+	repository          port.WorkoutSessionRepository
+	setRepo             port.WorkoutSessionSetRepository
+	workoutRepo         port.WorkoutRepository
+	workoutExerciseRepo port.WorkoutExerciseRepository
+	scheduledRepo       port.ScheduledWorkoutsRepository
+	records             *PersonalRecordUsecase
+	notifier            port.Notifier
+	transactor          port.Transactor
+}
+
+func NewWorkoutSessionUsecase(cfg *config.Config, sessionRepository port.WorkoutSessionRepository, sessionSetRepository port.WorkoutSessionSetRepository,
+	workoutRepository port.WorkoutRepository, workoutExerciseRepository port.WorkoutExerciseRepository, scheduledWorkoutsRepository port.ScheduledWorkoutsRepository,
+	exerciseRepository port.ExerciseRepository, personalRecordRepository port.PersonalRecordRepository, notifier port.Notifier, transactor port.Transactor) *WorkoutSessionUsecase {
+	return &WorkoutSessionUsecase{
+		base:                NewBaseUsecase[models.WorkoutSession, dto.StartWorkoutSessionRequest, struct{}, dto.WorkoutSessionResponse](cfg, sessionRepository),
+		repository:          sessionRepository,
+		setRepo:             sessionSetRepository,
+		workoutRepo:         workoutRepository,
+		workoutExerciseRepo: workoutExerciseRepository,
+		scheduledRepo:       scheduledWorkoutsRepository,
+		records:             NewPersonalRecordUsecase(cfg, personalRecordRepository, exerciseRepository),
+		notifier:            notifier,
+		transactor:          transactor,
+	}
+}
+
+// Start begins a session of a workout of the user, optionally for one of its scheduled workouts.
+// The session and the status of its scheduled workout are written in one transaction.
+func (u *WorkoutSessionUsecase) Start(ctx context.Context, req dto.StartWorkoutSessionRequest) (dto.WorkoutSessionResponse, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	err = u.base.CheckOwnership(ctx, u.workoutRepo, req.WorkoutId)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+
+	var session dto.WorkoutSessionResponse
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if req.ScheduledWorkoutId != nil {
+			if err := u.checkStartable(ctx, userId, req); err != nil {
+				return err
+			}
+		}
+
+		req.UserId = userId
+		req.Status = models.SessionInProgress
+		req.StartedAt = time.Now().UTC()
+		session, err = u.base.Create(ctx, req)
+		if err != nil {
+			return err
+		}
+		if req.ScheduledWorkoutId != nil {
+			_, err = u.scheduledRepo.Update(ctx, *req.ScheduledWorkoutId, models.ScheduledWorkouts{Status: models.ScheduledInProgress})
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	return session, nil
+}
+
+// checkStartable checks that the scheduled workout of a new session belongs to its workout, can be
+// in progress and has no other session in progress or paused
+func (u *WorkoutSessionUsecase) checkStartable(ctx context.Context, userId int, req dto.StartWorkoutSessionRequest) error {
+	scheduled, err := u.scheduledRepo.GetById(ctx, *req.ScheduledWorkoutId)
+	if err != nil && !service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+		return err
+	}
+	if err != nil || scheduled.WorkoutId != req.WorkoutId {
+		return invalidReference("scheduled_workout_id", err)
+	}
+	// A scheduled workout already in progress can be started again when its session was removed
+	if scheduled.Status != models.ScheduledInProgress && !scheduled.Status.CanTransitionTo(models.ScheduledInProgress) {
+		return service_errors.New(service_errors.CodeScheduledWorkoutNotActive)
+	}
+
+	_, sessions, err := u.repository.GetByFilter(ctx, filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxSessionsPerSchedule, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{
+				"ScheduledWorkoutId": {Type: "equals", From: strconv.Itoa(scheduled.Id), FilterType: "number"},
+			},
+			OwnerId: userId,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	for _, session := range *sessions {
+		if session.Status != models.SessionFinished {
+			return service_errors.New(service_errors.CodeSessionAlreadyActive)
+		}
+	}
+	return nil
+}
+
+// Pause stops the clock of a running session
+func (u *WorkoutSessionUsecase) Pause(ctx context.Context, id int) (dto.WorkoutSessionResponse, error) {
+	session, err := u.getOwned(ctx, id)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	if session.Status != models.SessionInProgress {
+		return dto.WorkoutSessionResponse{}, service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+
+	now := time.Now().UTC()
+	session.Status = models.SessionPaused
+	session.PausedAt = &now
+	err = u.repository.UpdateStatus(ctx, id, models.SessionInProgress, models.WorkoutSession{Status: session.Status, PausedAt: session.PausedAt})
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	return common.TypeConverter[dto.WorkoutSessionResponse](session)
+}
+
+// Resume continues a paused session, the pause is not counted in the duration
+func (u *WorkoutSessionUsecase) Resume(ctx context.Context, id int) (dto.WorkoutSessionResponse, error) {
+	session, err := u.getOwned(ctx, id)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	if session.Status != models.SessionPaused {
+		return dto.WorkoutSessionResponse{}, service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+
+	session.Status = models.SessionInProgress
+	session.PausedSeconds += pausedSeconds(session, time.Now().UTC())
+	err = u.repository.UpdateStatus(ctx, id, models.SessionPaused, models.WorkoutSession{Status: session.Status, PausedSeconds: session.PausedSeconds})
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	return common.TypeConverter[dto.WorkoutSessionResponse](session)
+}
+
+// Finish ends the session and marks its scheduled workout as completed, unless it was skipped or cancelled meanwhile.
+// The session and its scheduled workout are written in one transaction.
+func (u *WorkoutSessionUsecase) Finish(ctx context.Context, id int) (dto.WorkoutSessionResponse, error) {
+	session, err := u.getOwned(ctx, id)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	if session.Status != models.SessionInProgress && session.Status != models.SessionPaused {
+		return dto.WorkoutSessionResponse{}, service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+
+	from := session.Status
+	now := time.Now().UTC()
+	if session.Status == models.SessionPaused {
+		session.PausedSeconds += pausedSeconds(session, now)
+	}
+	session.Status = models.SessionFinished
+	session.FinishedAt = &now
+	session.DurationSeconds = max(int(now.Sub(session.StartedAt).Seconds())-session.PausedSeconds, 0)
+
+	err = u.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := u.repository.UpdateStatus(ctx, id, from, models.WorkoutSession{
+			Status:          session.Status,
+			FinishedAt:      session.FinishedAt,
+			PausedSeconds:   session.PausedSeconds,
+			DurationSeconds: session.DurationSeconds,
+		})
+		if err != nil || session.ScheduledWorkoutId == nil {
+			return err
+		}
+
+		scheduled, err := u.scheduledRepo.GetById(ctx, *session.ScheduledWorkoutId)
+		if err != nil {
+			if service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if scheduled.Status.CanTransitionTo(models.ScheduledCompleted) {
+			_, err = u.scheduledRepo.Update(ctx, scheduled.Id, models.ScheduledWorkouts{Status: models.ScheduledCompleted})
+		}
+		return err
+	})
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	return common.TypeConverter[dto.WorkoutSessionResponse](session)
+}
+
+// LogSet records a set performed of an exercise of the session's workout, the records the set breaks are returned with it
+func (u *WorkoutSessionUsecase) LogSet(ctx context.Context, req dto.CreateWorkoutSessionSetRequest) (dto.WorkoutSessionSetResponse, error) {
+	session, err := u.getOwned(ctx, req.WorkoutSessionId)
+	if err != nil {
+		return dto.WorkoutSessionSetResponse{}, err
+	}
+	if session.Status != models.SessionInProgress {
+		return dto.WorkoutSessionSetResponse{}, service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+
+	workoutExercise, err := u.workoutExerciseRepo.GetById(ctx, req.WorkoutExerciseId)
+	if err != nil && !service_errors.HasCode(err, service_errors.CodeRecordNotFound) {
+		return dto.WorkoutSessionSetResponse{}, err
+	}
+	if err != nil || workoutExercise.WorkoutId != session.WorkoutId {
+		return dto.WorkoutSessionSetResponse{}, invalidReference("workout_exercise_id", err)
+	}
+	req.ExerciseId = workoutExercise.ExerciseId
+
+	if req.SetIndex == 0 {
+		sets, err := u.getSets(ctx, session.Id, map[string]filter.Filter{
+			"WorkoutExerciseId": {Type: "equals", From: strconv.Itoa(req.WorkoutExerciseId), FilterType: "number"},
+		})
+		if err != nil {
+			return dto.WorkoutSessionSetResponse{}, err
+		}
+		req.SetIndex = 1
+		for _, set := range sets {
+			req.SetIndex = max(req.SetIndex, set.SetIndex+1)
+		}
+	}
+	if req.SetType == "" {
+		req.SetType = models.WorkingSet
+	}
+	if req.PerformedAt.IsZero() {
+		req.PerformedAt = time.Now().UTC()
+	}
+
+	set, _ := common.TypeConverter[models.WorkoutSessionSet](req)
+	set, err = u.setRepo.Create(ctx, set)
+	if err != nil {
+		return dto.WorkoutSessionSetResponse{}, err
+	}
+	response, err := common.TypeConverter[dto.WorkoutSessionSetResponse](set)
+	if err != nil {
+		return dto.WorkoutSessionSetResponse{}, err
+	}
+	records, err := u.records.DetectSessionSet(ctx, response)
+	response.PersonalRecords = reportRecords(ctx, u.notifier, workoutExercise.Name, records, err)
+	return response, nil
+}
+
+// GetSets returns the sets of the session in the order they were performed
+func (u *WorkoutSessionUsecase) GetSets(ctx context.Context, id int) ([]dto.WorkoutSessionSetResponse, error) {
+	if _, err := u.getOwned(ctx, id); err != nil {
+		return nil, err
+	}
+	return u.getSets(ctx, id, nil)
+}
+
+// GetById returns the session with its sets
+func (u *WorkoutSessionUsecase) GetById(ctx context.Context, id int) (dto.WorkoutSessionResponse, error) {
+	session, err := u.getOwned(ctx, id)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	response, err := common.TypeConverter[dto.WorkoutSessionResponse](session)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	response.Sets, err = u.getSets(ctx, id, nil)
+	if err != nil {
+		return dto.WorkoutSessionResponse{}, err
+	}
+	return response, nil
+}
+
+// Delete removes the session, the records its sets broke are removed with it
+func (u *WorkoutSessionUsecase) Delete(ctx context.Context, id int) error {
+	if _, err := u.getOwned(ctx, id); err != nil {
+		return err
+	}
+	if err := u.base.Delete(ctx, id); err != nil {
+		return err
+	}
+	if err := u.records.RemoveSession(ctx, id); err != nil {
+		logging.FromContext(ctx).Error(constants.Internal, constants.UseCase, "removing the personal records of a workout session failed: "+err.Error(), nil)
+	}
+	return nil
+}
+
+// GetByFilter lists the sessions of the user, without their sets
+func (u *WorkoutSessionUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WorkoutSessionResponse], error) {
+	return u.base.GetOwnedByFilter(ctx, req)
+}
+
+func (u *WorkoutSessionUsecase) getOwned(ctx context.Context, id int) (models.WorkoutSession, error) {
+	userId, err := common.UserIdFromContext(ctx)
+	if err != nil {
+		return models.WorkoutSession{}, err
+	}
+	session, err := u.repository.GetById(ctx, id)
+	if err != nil {
+		return models.WorkoutSession{}, err
+	}
+	if session.UserId != userId {
+		return models.WorkoutSession{}, service_errors.New(service_errors.CodeUserNotOwner)
+	}
+	return session, nil
+}
+
+func (u *WorkoutSessionUsecase) getSets(ctx context.Context, sessionId int, filters map[string]filter.Filter) ([]dto.WorkoutSessionSetResponse, error) {
+	if filters == nil {
+		filters = map[string]filter.Filter{}
+	}
+	filters["WorkoutSessionId"] = filter.Filter{Type: "equals", From: strconv.Itoa(sessionId), FilterType: "number"}
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageSize: MaxSetsPerSession, PageNumber: 1},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: filters,
+			Sort:   &[]filter.Sort{{ColId: "PerformedAt", Sort: "asc"}, {ColId: "Id", Sort: "asc"}},
+		},
+	}
+	_, sets, err := u.setRepo.GetByFilter(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return common.TypeConverter[[]dto.WorkoutSessionSetResponse](sets)
+}
+
+// pausedSeconds is the length of the current pause of the session
+func pausedSeconds(session models.WorkoutSession, now time.Time) int {
+	if session.PausedAt == nil {
+		return 0
+	}
+	return max(int(now.Sub(*session.PausedAt).Seconds()), 0)
+}
+
+// invalidReference reports a field that references a record the user can not use
+func invalidReference(field string, err error) error {
+	referenceErr := service_errors.Wrap(service_errors.CodeInvalidReference, err)
+	referenceErr.TechnicalMessage = field
+	return referenceErr
+}
@@ -8,8 +8,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/port"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
@@ -55,11 +55,17 @@ func (u *WorkoutUsecase) GetById(ctx context.Context, id int) (dto.WorkoutRespon
 		return dto.WorkoutResponse{}, err
 	}
 	if workout.UserId != userId {
-		return dto.WorkoutResponse{}, &service_errors.ServiceError{EndUserMessage: service_errors.UserNotOwner}
+		return dto.WorkoutResponse{}, service_errors.New(service_errors.CodeUserNotOwner)
 	}
 
 	return workout, nil
 }
+
+// GetAnyById returns a workout without checking the owner, it is meant for admins
+func (u *WorkoutUsecase) GetAnyById(ctx context.Context, id int) (dto.WorkoutResponse, error) {
+	return u.base.GetById(ctx, id)
+}
+
 func (u *WorkoutUsecase) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (*filter.PagedList[dto.WorkoutResponse], error) {
 	// Add user filter to ensure users only see their own workouts
 	userId := int(ctx.Value(constants.UserIdKey).(float64))
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+)
+
+// Notifier tells the users about their workouts, the users choose how they are reached
+type Notifier interface {
+	RemindSchedule(ctx context.Context, reminder models.DueReminder) error
+	NotifyAchievement(ctx context.Context, achievement models.Achievement) error
+}
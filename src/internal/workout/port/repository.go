@@ -2,9 +2,10 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 )
 
 type BaseRepository[TEntity any] interface {
@@ -15,6 +16,12 @@ type BaseRepository[TEntity any] interface {
 	GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]TEntity, error)
 }
 
+// Transactor runs a unit of work in one transaction, the repositories called with the context fn
+// receives take part in it. The work is kept when fn returns nil and undone otherwise.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type WorkoutRepository interface {
 	BaseRepository[models.Workout]
 }
@@ -23,10 +30,63 @@ type WorkoutExerciseRepository interface {
 	BaseRepository[models.WorkoutExercise]
 }
 
+type ExerciseSetRepository interface {
+	BaseRepository[models.ExerciseSet]
+}
+
 type ScheduledWorkoutsRepository interface {
 	BaseRepository[models.ScheduledWorkouts]
 }
 
+type ScheduleRuleRepository interface {
+	BaseRepository[models.ScheduleRule]
+}
+
+type CalendarFeedRepository interface {
+	BaseRepository[models.CalendarFeed]
+}
+
 type WorkoutReportRepository interface {
 	BaseRepository[models.WorkoutReport]
 }
+
+type ExerciseRepository interface {
+	BaseRepository[models.Exercise]
+}
+
+type WorkoutSessionRepository interface {
+	BaseRepository[models.WorkoutSession]
+	// UpdateStatus writes the changes of a session whose status is still from, it fails with
+	// CodeInvalidSessionState when another request changed the status first
+	UpdateStatus(ctx context.Context, id int, from string, session models.WorkoutSession) error
+}
+
+type WorkoutSessionSetRepository interface {
+	BaseRepository[models.WorkoutSessionSet]
+}
+
+type PersonalRecordRepository interface {
+	BaseRepository[models.PersonalRecord]
+}
+
+// AnalyticsRepository aggregates the training of a user over the completed scheduled workouts
+type AnalyticsRepository interface {
+	Volume(ctx context.Context, r models.AnalyticsRange) ([]models.VolumePoint, error)
+	ExerciseTrends(ctx context.Context, r models.AnalyticsRange) ([]models.ExerciseTrendPoint, error)
+	Frequency(ctx context.Context, r models.AnalyticsRange) ([]models.FrequencyPoint, error)
+	Streaks(ctx context.Context, r models.AnalyticsRange) ([]models.Streak, error)
+	MuscleDistribution(ctx context.Context, r models.AnalyticsRange) ([]models.MuscleVolume, error)
+}
+
+// ScheduleJobsRepository changes the scheduled workouts of all the users in bulk for the background jobs
+type ScheduleJobsRepository interface {
+	// MarkMissed marks the planned scheduled workouts before a time as missed and returns how many were
+	MarkMissed(ctx context.Context, before time.Time) (int64, error)
+	// EnqueueReminders creates the missing reminders of the planned scheduled workouts after from and up to to,
+	// and returns how many were created
+	EnqueueReminders(ctx context.Context, from time.Time, to time.Time) (int64, error)
+	// PendingReminders returns up to limit unsent reminders by scheduled time, the ones whose time is
+	// after now and whose scheduled workout is still planned at that time are due
+	PendingReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error)
+	MarkRemindersSent(ctx context.Context, ids []int, sentAt time.Time) error
+}
@@ -0,0 +1,223 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ==================== ANALYTICS USECASE TESTS ====================
+
+func TestAnalyticsRange_CoversWholeDays(t *testing.T) {
+	repo := &MockAnalyticsRepository{}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	_, err := useCase.Volume(createContextWithUserId(3), usecaseDto.AnalyticsRequest{
+		From: date(2024, 3, 1).Add(15 * time.Hour), To: date(2024, 3, 31), Period: models.MonthPeriod,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, repo.Range.UserId)
+	assert.Equal(t, date(2024, 3, 1), repo.Range.From)
+	assert.Equal(t, date(2024, 4, 1), repo.Range.To)
+	assert.Equal(t, models.MonthPeriod, repo.Range.Period)
+}
+
+func TestAnalyticsRange_Defaults(t *testing.T) {
+	repo := &MockAnalyticsRepository{}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	_, err := useCase.Volume(createContextWithUserId(1), usecaseDto.AnalyticsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.WeekPeriod, repo.Range.Period)
+	assert.Equal(t, usecase.DefaultAnalyticsDays*24*time.Hour, repo.Range.To.Sub(repo.Range.From))
+	assert.True(t, repo.Range.To.After(time.Now()))
+}
+
+func TestAnalyticsRange_FromAfterTo(t *testing.T) {
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})
+
+	_, err := useCase.Volume(createContextWithUserId(1), usecaseDto.AnalyticsRequest{From: date(2024, 3, 2), To: date(2024, 3, 1)})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+	assert.Equal(t, http.StatusBadRequest, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestAnalyticsRange_TooLong(t *testing.T) {
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})
+
+	_, err := useCase.Volume(createContextWithUserId(1), usecaseDto.AnalyticsRequest{From: date(2020, 1, 1), To: date(2024, 1, 1)})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+}
+
+func TestAnalyticsRange_InvalidPeriod(t *testing.T) {
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})
+
+	_, err := useCase.Frequency(createContextWithUserId(1), usecaseDto.AnalyticsRequest{Period: "year"})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+}
+
+func TestExerciseTrends_GroupedByExercise(t *testing.T) {
+	benchId := 9
+	repo := &MockAnalyticsRepository{
+		ExerciseTrendsFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.ExerciseTrendPoint, error) {
+			return []models.ExerciseTrendPoint{
+				{ExerciseId: &benchId, Name: "Bench Press", Period: date(2024, 3, 4), MaxWeight: 80},
+				{ExerciseId: &benchId, Name: "Bench Press", Period: date(2024, 3, 11), MaxWeight: 82.5},
+				{Name: "Farmer Walk", Period: date(2024, 3, 4), MaxWeight: 40},
+				{Name: "farmer walk", Period: date(2024, 3, 11), MaxWeight: 45},
+			}, nil
+		},
+	}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	trends, err := useCase.ExerciseTrends(createContextWithUserId(1), usecaseDto.AnalyticsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(trends))
+	assert.Equal(t, 9, *trends[0].ExerciseId)
+	assert.Equal(t, 2, len(trends[0].Points))
+	assert.Equal(t, 82.5, trends[0].Points[1].MaxWeight)
+	assert.Equal(t, 2, len(trends[1].Points))
+}
+
+func TestFrequency_CompletionRate(t *testing.T) {
+	repo := &MockAnalyticsRepository{
+		FrequencyFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.FrequencyPoint, error) {
+			return []models.FrequencyPoint{{Period: date(2024, 3, 4), Scheduled: 3, Completed: 2}, {Period: date(2024, 3, 11)}}, nil
+		},
+	}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	frequency, err := useCase.Frequency(createContextWithUserId(1), usecaseDto.AnalyticsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 66.7, frequency[0].CompletionRate)
+	assert.Equal(t, 0.0, frequency[1].CompletionRate)
+}
+
+func TestStreaks_CurrentAndLongest(t *testing.T) {
+	repo := &MockAnalyticsRepository{
+		StreaksFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.Streak, error) {
+			return []models.Streak{
+				{StartDay: date(2024, 3, 1), EndDay: date(2024, 3, 5), Days: 5},
+				{StartDay: date(2024, 3, 20), EndDay: date(2024, 3, 30), Days: 11},
+				{StartDay: date(2024, 4, 2), EndDay: date(2024, 4, 3), Days: 2},
+			}, nil
+		},
+	}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	// The last workout was the day before the end of the range
+	streaks, err := useCase.Streaks(createContextWithUserId(1), usecaseDto.AnalyticsRequest{From: date(2024, 3, 1), To: date(2024, 4, 4)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(streaks.Streaks))
+	assert.Equal(t, 11, streaks.Longest.Days)
+	assert.Equal(t, 2, streaks.Current.Days)
+}
+
+func TestStreaks_BrokenStreakIsNotCurrent(t *testing.T) {
+	repo := &MockAnalyticsRepository{
+		StreaksFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.Streak, error) {
+			return []models.Streak{{StartDay: date(2024, 3, 20), EndDay: date(2024, 3, 30), Days: 11}}, nil
+		},
+	}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	streaks, err := useCase.Streaks(createContextWithUserId(1), usecaseDto.AnalyticsRequest{From: date(2024, 3, 1), To: date(2024, 4, 4)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, streaks.Current.Days)
+	assert.Equal(t, 11, streaks.Longest.Days)
+}
+
+func TestMuscleDistribution_Percent(t *testing.T) {
+	repo := &MockAnalyticsRepository{
+		MuscleDistributionFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.MuscleVolume, error) {
+			return []models.MuscleVolume{{Muscle: "chest", Sets: 6, Volume: 2400}, {Muscle: "triceps", Sets: 3, Volume: 900}, {Muscle: "abs", Sets: 3}}, nil
+		},
+	}
+	useCase := usecase.NewAnalyticsUsecase(&config.Config{}, repo)
+
+	muscles, err := useCase.MuscleDistribution(createContextWithUserId(1), usecaseDto.AnalyticsRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 50.0, muscles[0].Percent)
+	assert.Equal(t, 25.0, muscles[2].Percent)
+}
+
+// ==================== ANALYTICS HANDLER TESTS ====================
+
+func TestVolume_Handler_Success(t *testing.T) {
+	repo := &MockAnalyticsRepository{
+		VolumeFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.VolumePoint, error) {
+			return []models.VolumePoint{{Period: date(2024, 3, 4), Volume: 12500, Workouts: 3}}, nil
+		},
+	}
+	analyticsHandler := &handler.AnalyticsHandler{Usecase: usecase.NewAnalyticsUsecase(&config.Config{}, repo)}
+	c, w := createAuthenticatedGinContext("GET", "/v1/workouts/analytics/volume?from=2024-03-01&to=2024-03-31&period=day", nil, &MockTokenProvider{}, &config.Config{})
+
+	analyticsHandler.Volume(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, date(2024, 3, 1), repo.Range.From)
+	assert.Equal(t, models.DayPeriod, repo.Range.Period)
+	var response struct {
+		Result []dto.VolumeResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 12500.0, response.Result[0].Volume)
+}
+
+func TestVolume_Handler_InvalidPeriod(t *testing.T) {
+	analyticsHandler := &handler.AnalyticsHandler{Usecase: usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})}
+	c, w := createAuthenticatedGinContext("GET", "/v1/workouts/analytics/volume?period=year", nil, &MockTokenProvider{}, &config.Config{})
+
+	analyticsHandler.Volume(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestVolume_Handler_InvalidDate(t *testing.T) {
+	analyticsHandler := &handler.AnalyticsHandler{Usecase: usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})}
+	c, w := createAuthenticatedGinContext("GET", "/v1/workouts/analytics/volume?from=03/01/2024", nil, &MockTokenProvider{}, &config.Config{})
+
+	analyticsHandler.Volume(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestStreaks_Handler_EmptyList(t *testing.T) {
+	analyticsHandler := &handler.AnalyticsHandler{Usecase: usecase.NewAnalyticsUsecase(&config.Config{}, &MockAnalyticsRepository{})}
+	c, w := createAuthenticatedGinContext("GET", "/v1/workouts/analytics/streaks", nil, &MockTokenProvider{}, &config.Config{})
+
+	analyticsHandler.Streaks(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result dto.StreaksResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 0, len(response.Result.Streaks))
+	assert.Equal(t, 0, response.Result.Current.Days)
+}
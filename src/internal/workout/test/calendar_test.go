@@ -0,0 +1,289 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+func setupCalendarUsecase(rules []models.ScheduleRule, rows []models.ScheduledWorkouts, workoutRepo *MockWorkoutRepository, feedRepo *MockCalendarFeedRepository) *usecase.CalendarUsecase {
+	ruleRepo := &MockScheduleRuleRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduleRule, error) {
+			return int64(len(rules)), &rules, nil
+		},
+	}
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			return int64(len(rows)), &rows, nil
+		},
+	}
+	return usecase.NewCalendarUsecase(&config.Config{}, scheduledRepo, ruleRepo, workoutRepo, feedRepo)
+}
+
+// memoryFeedRepository keeps the calendar subscription of user 1
+func memoryFeedRepository() *MockCalendarFeedRepository {
+	var feed *models.CalendarFeed
+	return &MockCalendarFeedRepository{
+		CreateFn: func(ctx context.Context, entity models.CalendarFeed) (models.CalendarFeed, error) {
+			entity.Id = 1
+			feed = &entity
+			return entity, nil
+		},
+		UpdateFn: func(ctx context.Context, id int, entity models.CalendarFeed) (models.CalendarFeed, error) {
+			feed.TokenHash = entity.TokenHash
+			return *feed, nil
+		},
+		DeleteFn: func(ctx context.Context, id int) error {
+			feed = nil
+			return nil
+		},
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.CalendarFeed, error) {
+			if feed == nil || (req.OwnerId != 0 && req.OwnerId != feed.UserId) {
+				return 0, &[]models.CalendarFeed{}, nil
+			}
+			if hash, ok := req.Filter["TokenHash"]; ok && hash.From != feed.TokenHash {
+				return 0, &[]models.CalendarFeed{}, nil
+			}
+			return 1, &[]models.CalendarFeed{*feed}, nil
+		},
+	}
+}
+
+// ==================== CALENDAR EXPORT TESTS ====================
+
+func TestScheduleRule_RRule(t *testing.T) {
+	count := 10
+	until := time.Date(2024, 6, 30, 22, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+
+	weekly := models.ScheduleRule{Frequency: models.WeeklyFrequency, Interval: 2, ByDay: "FR,MO", Count: &count}
+	monthly := models.ScheduleRule{Frequency: models.MonthlyFrequency, Until: &until}
+
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10", weekly.RRule())
+	assert.Equal(t, "FREQ=MONTHLY;INTERVAL=1;UNTIL=20240630T200000Z", monthly.RRule())
+}
+
+func TestExportCalendar_RecurringSchedule(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	ruleId := 3
+	occurrence := time.Date(2024, 3, 8, 7, 0, 0, 0, berlin).UTC()
+	rules := []models.ScheduleRule{{
+		Id:         ruleId,
+		WorkoutId:  1,
+		Frequency:  models.WeeklyFrequency,
+		Interval:   1,
+		ByDay:      "MO,WE,FR",
+		StartTime:  time.Date(2024, 3, 3, 7, 0, 0, 0, berlin).UTC(),
+		TimeZone:   "Europe/Berlin",
+		Exceptions: models.JoinExceptions([]time.Time{time.Date(2024, 3, 6, 7, 0, 0, 0, berlin)}),
+	}}
+	rows := []models.ScheduledWorkouts{
+		{Id: 6, WorkoutId: 1, ScheduledTime: occurrence.Add(2 * time.Hour), Status: "cancelled", ScheduleRuleId: &ruleId, OccurrenceTime: &occurrence},
+		{Id: 7, WorkoutId: 1, ScheduledTime: time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC), Status: "planned"},
+	}
+	useCase := setupCalendarUsecase(rules, rows, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})
+
+	result, err := useCase.Export(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	calendar := string(result)
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	for _, line := range []string{
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20240331T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nRRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"BEGIN:STANDARD\r\nDTSTART:20241027T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\nRRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		// The event starts on the first occurrence instead of the Sunday the schedule started on
+		"UID:schedule-rule-3@go-hexa-workout",
+		"DTSTART;TZID=Europe/Berlin:20240304T070000",
+		"RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE,FR",
+		"EXDATE;TZID=Europe/Berlin:20240306T070000",
+		"RECURRENCE-ID;TZID=Europe/Berlin:20240308T070000\r\nDTSTART;TZID=Europe/Berlin:20240308T090000",
+		"STATUS:CANCELLED",
+		"UID:scheduled-workout-7@go-hexa-workout",
+		"DTSTART:20240501T180000Z",
+		"DURATION:PT1H",
+		"SUMMARY:Test Workout",
+		"DESCRIPTION:Test Description",
+	} {
+		assert.Contains(t, calendar, line)
+	}
+	assert.Equal(t, 1, strings.Count(calendar, "BEGIN:VTIMEZONE"))
+	assert.Equal(t, 3, strings.Count(calendar, "BEGIN:VEVENT"))
+}
+
+func TestExportCalendar_LeavesOutEndedSchedules(t *testing.T) {
+	count := 2
+	rules := []models.ScheduleRule{{
+		Id: 1, WorkoutId: 1, Frequency: models.DailyFrequency, Interval: 1,
+		StartTime: time.Date(2024, 3, 4, 7, 0, 0, 0, time.UTC), TimeZone: "UTC", Count: &count,
+	}}
+	useCase := setupCalendarUsecase(rules, []models.ScheduledWorkouts{}, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})
+
+	result, err := useCase.Export(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), "BEGIN:VEVENT")
+	assert.NotContains(t, string(result), "BEGIN:VTIMEZONE")
+}
+
+func TestExportCalendar_EscapesAndFoldsText(t *testing.T) {
+	workoutRepo := &MockWorkoutRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Workout, error) {
+			return models.Workout{Id: id, Name: "Legs, glutes; core", Description: strings.Repeat("Squats à la barre\n", 8)}, nil
+		},
+	}
+	rows := []models.ScheduledWorkouts{{Id: 1, WorkoutId: 1, ScheduledTime: time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC), Status: "planned"}}
+	useCase := setupCalendarUsecase([]models.ScheduleRule{}, rows, workoutRepo, &MockCalendarFeedRepository{})
+
+	result, err := useCase.Export(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	calendar := string(result)
+	assert.Contains(t, calendar, `SUMMARY:Legs\, glutes\; core`)
+	for _, line := range strings.Split(calendar, "\r\n") {
+		assert.True(t, len(line) <= 75, "line %q is longer than 75 octets", line)
+	}
+	unfolded := strings.ReplaceAll(calendar, "\r\n ", "")
+	assert.Contains(t, unfolded, "DESCRIPTION:"+strings.Repeat(`Squats à la barre\n`, 8)+"\r\n")
+}
+
+func TestExportCalendar_MissedWorkoutIsCancelled(t *testing.T) {
+	rows := []models.ScheduledWorkouts{
+		{Id: 1, WorkoutId: 1, ScheduledTime: time.Date(2024, 5, 1, 18, 0, 0, 0, time.UTC), Status: "missed"},
+		{Id: 2, WorkoutId: 1, ScheduledTime: time.Date(2024, 5, 2, 18, 0, 0, 0, time.UTC), Status: "completed"},
+	}
+	useCase := setupCalendarUsecase([]models.ScheduleRule{}, rows, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})
+
+	result, err := useCase.Export(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	events := strings.Split(string(result), "BEGIN:VEVENT")
+	assert.Equal(t, 3, len(events))
+	assert.Contains(t, events[1], "UID:scheduled-workout-1@go-hexa-workout")
+	assert.Contains(t, events[1], "STATUS:CANCELLED")
+	assert.Contains(t, events[2], "STATUS:CONFIRMED")
+}
+
+func TestExportCalendar_WithoutUser(t *testing.T) {
+	useCase := setupCalendarUsecase(nil, nil, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})
+
+	_, err := useCase.Export(context.Background())
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserIdNotFound))
+}
+
+// ==================== CALENDAR FEED TESTS ====================
+
+func TestCalendarFeed_RegenerateToken(t *testing.T) {
+	feedRepo := memoryFeedRepository()
+	useCase := setupCalendarUsecase([]models.ScheduleRule{}, []models.ScheduledWorkouts{}, &MockWorkoutRepository{}, feedRepo)
+	ctx := createContextWithUserId(1)
+
+	first, err := useCase.RegenerateToken(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 64, len(first.Token))
+	_, err = useCase.ExportFeed(context.Background(), first.Token)
+	assert.NoError(t, err)
+
+	second, err := useCase.RegenerateToken(ctx)
+	assert.NoError(t, err)
+	assert.NotEqual(t, first.Token, second.Token)
+
+	// The previous token stops working
+	_, err = useCase.ExportFeed(context.Background(), first.Token)
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+	result, err := useCase.ExportFeed(context.Background(), second.Token)
+	assert.NoError(t, err)
+	assert.Contains(t, string(result), "BEGIN:VCALENDAR")
+}
+
+func TestCalendarFeed_RevokeToken(t *testing.T) {
+	feedRepo := memoryFeedRepository()
+	useCase := setupCalendarUsecase([]models.ScheduleRule{}, []models.ScheduledWorkouts{}, &MockWorkoutRepository{}, feedRepo)
+	ctx := createContextWithUserId(1)
+	feed, err := useCase.RegenerateToken(ctx)
+	assert.NoError(t, err)
+
+	err = useCase.RevokeToken(ctx)
+
+	assert.NoError(t, err)
+	_, err = useCase.ExportFeed(context.Background(), feed.Token)
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+	err = useCase.RevokeToken(ctx)
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+}
+
+func TestCalendarFeed_StoresTokenHash(t *testing.T) {
+	var stored models.CalendarFeed
+	feedRepo := &MockCalendarFeedRepository{
+		CreateFn: func(ctx context.Context, entity models.CalendarFeed) (models.CalendarFeed, error) {
+			stored = entity
+			return entity, nil
+		},
+	}
+	useCase := setupCalendarUsecase(nil, nil, &MockWorkoutRepository{}, feedRepo)
+
+	feed, err := useCase.RegenerateToken(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, stored.UserId)
+	assert.Equal(t, 64, len(stored.TokenHash))
+	assert.NotEqual(t, feed.Token, stored.TokenHash)
+}
+
+// ==================== CALENDAR HANDLER TESTS ====================
+
+func TestCalendarHandler_Export(t *testing.T) {
+	calendarHandler := &handler.CalendarHandler{Usecase: setupCalendarUsecase([]models.ScheduleRule{}, []models.ScheduledWorkouts{}, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})}
+	c, w := createAuthenticatedGinContext("GET", "/v1/workouts/calendar.ics", nil, &MockTokenProvider{}, &config.Config{})
+
+	calendarHandler.Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "BEGIN:VCALENDAR"))
+}
+
+func TestCalendarHandler_Feed_UnknownToken(t *testing.T) {
+	calendarHandler := &handler.CalendarHandler{Usecase: setupCalendarUsecase(nil, nil, &MockWorkoutRepository{}, &MockCalendarFeedRepository{})}
+	c, w := createAuthenticatedGinContextWithParams("GET", "/v1/workouts/calendar/feed/unknown.ics", nil,
+		gin.Params{{Key: "token", Value: "unknown.ics"}}, &MockTokenProvider{}, &config.Config{})
+
+	calendarHandler.Feed(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCalendarHandler_RegenerateToken(t *testing.T) {
+	cfg := &config.Config{Server: config.ServerConfig{Domain: "workouts.example.com", RunMode: "release"}}
+	calendarHandler := &handler.CalendarHandler{Usecase: setupCalendarUsecase(nil, nil, &MockWorkoutRepository{}, memoryFeedRepository()), Cfg: cfg}
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/calendar/token", nil, &MockTokenProvider{}, cfg)
+	// The url is built from the configured domain, not from the headers of the client
+	c.Request.Host = "attacker.example.com"
+	c.Request.Header.Set("X-Forwarded-Proto", "http")
+
+	calendarHandler.RegenerateToken(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result struct {
+			Token string `json:"token"`
+			Url   string `json:"url"`
+		} `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.True(t, strings.HasPrefix(response.Result.Url, "https://workouts.example.com/"))
+	assert.True(t, strings.HasSuffix(response.Result.Url, "/feed/"+response.Result.Token+".ics"))
+}
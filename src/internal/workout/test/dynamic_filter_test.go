@@ -0,0 +1,152 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+// ==================== DYNAMIC FILTER TESTS ====================
+
+func TestGenerateDynamicQuery_NoFilter(t *testing.T) {
+	query, args, err := db.GenerateDynamicQuery[models.Workout](&filter.DynamicFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null", query)
+	assert.Equal(t, 0, len(args))
+}
+
+func TestGenerateDynamicQuery_TextValueIsBound(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"Name": {Type: "equals", From: "x' OR '1'='1", FilterType: "text"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND name = ?", query)
+	assert.Equal(t, []interface{}{"x' OR '1'='1"}, args)
+}
+
+func TestGenerateDynamicQuery_ContainsEscapesWildcards(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"Name": {Type: "contains", From: "50%_off", FilterType: "text"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND name ILIKE ?", query)
+	assert.Equal(t, []interface{}{`%50\%\_off%`}, args)
+}
+
+func TestGenerateDynamicQuery_NumberCoercion(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"UserId": {Type: "equals", From: "7", FilterType: "number"},
+			"Name":   {Type: "startsWith", From: "Leg"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND name ILIKE ? AND user_id = ?", query)
+	assert.Equal(t, []interface{}{"Leg%", int64(7)}, args)
+}
+
+func TestGenerateDynamicQuery_InvalidNumber(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"UserId": {Type: "equals", From: "1 OR 1=1", FilterType: "number"},
+		},
+	}
+
+	_, _, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.Error(t, err)
+	var serviceErr *service_errors.ServiceError
+	assert.True(t, errors.As(err, &serviceErr))
+	assert.Equal(t, service_errors.InvalidFilter, serviceErr.EndUserMessage)
+}
+
+func TestGenerateDynamicQuery_DateRange(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"ScheduledTime": {Type: "inRange", From: "2024-01-01", To: "2024-01-31T23:59:59Z", FilterType: "date"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.ScheduledWorkouts](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND scheduled_time >= ? AND scheduled_time <= ?", query)
+	assert.Equal(t, []interface{}{
+		time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+	}, args)
+}
+
+func TestGenerateDynamicQuery_FilterTypeMismatch(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"Weight": {Type: "contains", From: "5", FilterType: "text"},
+		},
+	}
+
+	_, _, err := db.GenerateDynamicQuery[models.WorkoutExercise](dynamicFilter)
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.InvalidFilter, err.Error())
+}
+
+func TestGenerateDynamicQuery_UnsupportedOperator(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"Name": {Type: "1=1; DROP TABLE workouts", From: "x"},
+		},
+	}
+
+	_, _, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.Error(t, err)
+}
+
+func TestGenerateDynamicQuery_UnknownFieldIgnored(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		Filter: map[string]filter.Filter{
+			"Unknown": {Type: "equals", From: "x"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.Workout](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null", query)
+	assert.Equal(t, 0, len(args))
+}
+
+func TestGenerateDynamicQuery_OwnerScope(t *testing.T) {
+	dynamicFilter := &filter.DynamicFilter{
+		OwnerId: 4,
+		Filter: map[string]filter.Filter{
+			"Sets": {Type: "greaterThan", From: "2"},
+		},
+	}
+
+	query, args, err := db.GenerateDynamicQuery[models.WorkoutExercise](dynamicFilter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND workout_id IN (SELECT id FROM workouts WHERE user_id = ? AND deleted_by is null) AND sets > ?", query)
+	assert.Equal(t, []interface{}{4, int64(2)}, args)
+}
@@ -0,0 +1,206 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+func setupExerciseSetUsecase(setRepo *MockExerciseSetRepository, workoutRepo *MockWorkoutRepository) *usecase.ExerciseSetUsecase {
+	return usecase.NewExerciseSetUsecase(&config.Config{}, setRepo, &MockWorkoutExerciseRepository{}, workoutRepo, &MockExerciseRepository{}, &MockPersonalRecordRepository{}, &MockNotifier{})
+}
+
+func setsWithIndexes(indexes ...int) *MockExerciseSetRepository {
+	return &MockExerciseSetRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ExerciseSet, error) {
+			sets := []models.ExerciseSet{}
+			for _, index := range indexes {
+				sets = append(sets, models.ExerciseSet{Id: index, WorkoutExerciseId: 1, SetIndex: index})
+			}
+			return int64(len(sets)), &sets, nil
+		},
+	}
+}
+
+// ==================== EXERCISE SET USECASE TESTS ====================
+
+func TestCreateExerciseSet_AppendedAfterLastSet(t *testing.T) {
+	var created models.ExerciseSet
+	setRepo := setsWithIndexes(1, 2, 3)
+	setRepo.CreateFn = func(ctx context.Context, entity models.ExerciseSet) (models.ExerciseSet, error) {
+		created = entity
+		return entity, nil
+	}
+	useCase := setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 1, Repetitions: 8, Weight: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, created.SetIndex)
+	assert.Equal(t, models.WorkingSet, created.SetType)
+	assert.False(t, *created.Completed)
+	assert.Equal(t, 4, response.SetIndex)
+}
+
+func TestCreateExerciseSet_KeepsGivenIndexAndType(t *testing.T) {
+	setRepo := &MockExerciseSetRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ExerciseSet, error) {
+			t.Fatal("sets must not be listed when the index is given")
+			return 0, nil, nil
+		},
+	}
+	useCase := setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})
+	completed := true
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 1, SetIndex: 2, SetType: models.DropSet, Completed: &completed})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.SetIndex)
+	assert.Equal(t, models.DropSet, response.SetType)
+	assert.True(t, *response.Completed)
+}
+
+func TestCreateExerciseSet_TooManySets(t *testing.T) {
+	useCase := setupExerciseSetUsecase(setsWithIndexes(usecase.MaxSetsPerExercise), &MockWorkoutRepository{})
+
+	_, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 1})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+	assert.Equal(t, http.StatusBadRequest, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestCreateExerciseSet_UnauthorizedUser(t *testing.T) {
+	workoutRepo := &MockWorkoutRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Workout, error) {
+			return models.Workout{Id: id, UserId: 2}, nil
+		},
+	}
+	useCase := setupExerciseSetUsecase(&MockExerciseSetRepository{}, workoutRepo)
+
+	_, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 1, SetIndex: 1})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserNotOwner))
+}
+
+func TestUpdateExerciseSet_CanUncomplete(t *testing.T) {
+	var updated models.ExerciseSet
+	setRepo := &MockExerciseSetRepository{
+		UpdateFn: func(ctx context.Context, id int, entity models.ExerciseSet) (models.ExerciseSet, error) {
+			updated = entity
+			return entity, nil
+		},
+	}
+	useCase := setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})
+	completed := false
+
+	_, err := useCase.Update(createContextWithUserId(1), 1, 3, usecaseDto.UpdateExerciseSetRequest{Repetitions: 6, Completed: &completed})
+
+	assert.NoError(t, err)
+	assert.True(t, updated.Completed != nil)
+	assert.False(t, *updated.Completed)
+}
+
+func TestUpdateExerciseSet_SetOfAnotherWorkoutExercise(t *testing.T) {
+	setRepo := &MockExerciseSetRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.ExerciseSet, error) {
+			return models.ExerciseSet{Id: id, WorkoutExerciseId: 2}, nil
+		},
+	}
+	useCase := setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})
+
+	_, err := useCase.Update(createContextWithUserId(1), 1, 3, usecaseDto.UpdateExerciseSetRequest{Repetitions: 6})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+}
+
+func TestGetExerciseSets_FilteredAndOrdered(t *testing.T) {
+	var request filter.PaginationInputWithFilter
+	setRepo := &MockExerciseSetRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ExerciseSet, error) {
+			request = req
+			return 0, &[]models.ExerciseSet{}, nil
+		},
+	}
+	useCase := setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})
+
+	sets, err := useCase.GetByWorkoutExercise(createContextWithUserId(1), 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(sets))
+	assert.Equal(t, "7", request.Filter["WorkoutExerciseId"].From)
+	assert.Equal(t, []filter.Sort{{ColId: "SetIndex", Sort: "asc"}}, *request.Sort)
+	assert.Equal(t, usecase.MaxSetsPerExercise, request.PageSize)
+}
+
+// ==================== EXERCISE SET HANDLER TESTS ====================
+
+func TestCreateExerciseSet_Handler_UsesPathId(t *testing.T) {
+	var created models.ExerciseSet
+	setRepo := &MockExerciseSetRepository{
+		CreateFn: func(ctx context.Context, entity models.ExerciseSet) (models.ExerciseSet, error) {
+			created = entity
+			return entity, nil
+		},
+	}
+	setHandler := &handler.ExerciseSetHandler{Usecase: setupExerciseSetUsecase(setRepo, &MockWorkoutRepository{})}
+	rpe := 8.5
+	body, _ := json.Marshal(dto.CreateExerciseSetRequest{SetIndex: 1, Reps: 5, Weight: 100, Rpe: &rpe, RestSeconds: 180, SetType: "working"})
+	c, w := createAuthenticatedGinContextWithParams("POST", "/v1/workouts/workout-exercise/4/sets", body, gin.Params{{Key: "id", Value: "4"}}, &MockTokenProvider{}, &config.Config{})
+
+	setHandler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 4, created.WorkoutExerciseId)
+	assert.Equal(t, 8.5, *created.Rpe)
+	assert.Equal(t, 180, created.RestSeconds)
+}
+
+func TestCreateExerciseSet_Handler_InvalidRpe(t *testing.T) {
+	setHandler := &handler.ExerciseSetHandler{Usecase: setupExerciseSetUsecase(&MockExerciseSetRepository{}, &MockWorkoutRepository{})}
+	body := []byte(`{"reps": 5, "weight": 100, "rpe": 11, "set_type": "working"}`)
+	c, w := createAuthenticatedGinContextWithParams("POST", "/v1/workouts/workout-exercise/4/sets", body, gin.Params{{Key: "id", Value: "4"}}, &MockTokenProvider{}, &config.Config{})
+
+	setHandler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "rpe", (*response.ValidationErrors)[0].Field)
+}
+
+func TestDeleteExerciseSet_Handler_InvalidSetId(t *testing.T) {
+	setHandler := &handler.ExerciseSetHandler{Usecase: setupExerciseSetUsecase(&MockExerciseSetRepository{}, &MockWorkoutRepository{})}
+	c, w := createAuthenticatedGinContextWithParams("DELETE", "/v1/workouts/workout-exercise/4/sets/abc", nil, gin.Params{{Key: "id", Value: "4"}, {Key: "setId", Value: "abc"}}, &MockTokenProvider{}, &config.Config{})
+
+	setHandler.Delete(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetExerciseSets_Handler_Success(t *testing.T) {
+	setHandler := &handler.ExerciseSetHandler{Usecase: setupExerciseSetUsecase(setsWithIndexes(1, 2), &MockWorkoutRepository{})}
+	c, w := createAuthenticatedGinContextWithParams("GET", "/v1/workouts/workout-exercise/1/sets", nil, gin.Params{{Key: "id", Value: "1"}}, &MockTokenProvider{}, &config.Config{})
+
+	setHandler.GetByWorkoutExercise(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result []dto.ExerciseSetResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, len(response.Result))
+	assert.Equal(t, 2, response.Result[1].SetIndex)
+}
@@ -0,0 +1,271 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/migrations"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func customExercise(id int, userId int) models.Exercise {
+	return models.Exercise{Id: id, UserId: &userId, Name: "Landmine Press", Category: "strength", PrimaryMuscles: "shoulders", Equipment: "barbell", UnitType: "weight_reps"}
+}
+
+func exerciseRepoWith(exercise models.Exercise) *MockExerciseRepository {
+	return &MockExerciseRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Exercise, error) {
+			return exercise, nil
+		},
+	}
+}
+
+// ==================== EXERCISE USECASE TESTS ====================
+
+func TestCreateExercise_IsOwnedByTheUser(t *testing.T) {
+	var created models.Exercise
+	repo := &MockExerciseRepository{
+		CreateFn: func(ctx context.Context, entity models.Exercise) (models.Exercise, error) {
+			created = entity
+			entity.Id = 7
+			return entity, nil
+		},
+	}
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, repo)
+
+	response, err := useCase.Create(createContextWithUserId(3), usecaseDto.CreateExerciseRequest{
+		Name: "Landmine Press", Category: "strength", PrimaryMuscles: "shoulders,triceps", Equipment: "barbell", UnitType: "weight_reps",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *created.UserId)
+	assert.Equal(t, "shoulders,triceps", created.PrimaryMuscles)
+	assert.Equal(t, 7, response.Id)
+}
+
+func TestCreateCatalogExercise_HasNoOwner(t *testing.T) {
+	var created models.Exercise
+	repo := &MockExerciseRepository{
+		CreateFn: func(ctx context.Context, entity models.Exercise) (models.Exercise, error) {
+			created = entity
+			return entity, nil
+		},
+	}
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, repo)
+	userId := 3
+
+	_, err := useCase.CreateCatalog(createContextWithUserId(1), usecaseDto.CreateExerciseRequest{UserId: &userId, Name: "Zercher Squat"})
+
+	assert.NoError(t, err)
+	assert.True(t, created.UserId == nil)
+}
+
+func TestUpdateExercise_CatalogIsReadOnly(t *testing.T) {
+	repo := &MockExerciseRepository{
+		UpdateFn: func(ctx context.Context, id int, entity models.Exercise) (models.Exercise, error) {
+			t.Fatal("catalog exercise must not be updated")
+			return entity, nil
+		},
+	}
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, repo)
+
+	_, err := useCase.Update(createContextWithUserId(1), 1, usecaseDto.UpdateExerciseRequest{Name: "Bench"})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodePermissionDenied))
+}
+
+func TestDeleteExercise_OwnCustomExercise(t *testing.T) {
+	deleted := 0
+	repo := exerciseRepoWith(customExercise(5, 1))
+	repo.DeleteFn = func(ctx context.Context, id int) error {
+		deleted = id
+		return nil
+	}
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, repo)
+
+	err := useCase.Delete(createContextWithUserId(1), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, deleted)
+}
+
+func TestGetExerciseById_OtherUsersCustomExerciseIsNotFound(t *testing.T) {
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, exerciseRepoWith(customExercise(5, 2)))
+
+	_, err := useCase.GetById(createContextWithUserId(1), 5)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+	assert.Equal(t, http.StatusNotFound, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestGetExercisesByFilter_ScopedToCatalogAndUser(t *testing.T) {
+	var ownerId int
+	repo := &MockExerciseRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Exercise, error) {
+			ownerId = req.OwnerId
+			return 0, &[]models.Exercise{}, nil
+		},
+	}
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, repo)
+
+	_, err := useCase.GetByFilter(createContextWithUserId(4), filter.PaginationInputWithFilter{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, ownerId)
+}
+
+func TestExerciseOwnerScope_IncludesCatalog(t *testing.T) {
+	query, args, err := db.GenerateDynamicQuery[models.Exercise](&filter.DynamicFilter{OwnerId: 4})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "deleted_by is null AND (user_id is null OR user_id = ?)", query)
+	assert.Equal(t, []interface{}{4}, args)
+}
+
+func TestUpdateCatalogExercise_CustomExerciseIsNotFound(t *testing.T) {
+	useCase := usecase.NewExerciseUsecase(&config.Config{}, exerciseRepoWith(customExercise(5, 2)))
+
+	_, err := useCase.UpdateCatalog(createContextWithUserId(1), 5, usecaseDto.UpdateExerciseRequest{Name: "Press"})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+}
+
+// ==================== WORKOUT EXERCISE REFERENCE TESTS ====================
+
+func TestCreateWorkoutExercise_NamedAfterCatalogExercise(t *testing.T) {
+	var created models.WorkoutExercise
+	exerciseRepo := &MockWorkoutExerciseRepository{
+		CreateFn: func(ctx context.Context, entity models.WorkoutExercise) (models.WorkoutExercise, error) {
+			created = entity
+			return entity, nil
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, exerciseRepo, &MockWorkoutRepository{}, &MockExerciseRepository{}, &MockPersonalRecordRepository{}, &MockNotifier{})
+	exerciseId := 9
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 5, Sets: 5, Weight: 80})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9, *created.ExerciseId)
+	assert.Equal(t, "Barbell Bench Press", created.Name)
+	assert.Equal(t, 9, *response.ExerciseId)
+}
+
+func TestCreateWorkoutExercise_KeepsGivenName(t *testing.T) {
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, &MockPersonalRecordRepository{}, &MockNotifier{})
+	exerciseId := 9
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Name: "Paused Bench"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Paused Bench", response.Name)
+}
+
+func TestCreateWorkoutExercise_UnknownExercise(t *testing.T) {
+	exercises := &MockExerciseRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Exercise, error) {
+			return models.Exercise{}, service_errors.New(service_errors.CodeRecordNotFound)
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, exercises, &MockPersonalRecordRepository{}, &MockNotifier{})
+	exerciseId := 404
+
+	_, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidReference))
+	assert.Equal(t, http.StatusUnprocessableEntity, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestUpdateWorkoutExercise_OtherUsersCustomExercise(t *testing.T) {
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, exerciseRepoWith(customExercise(5, 2)), &MockPersonalRecordRepository{}, &MockNotifier{})
+	exerciseId := 5
+
+	_, err := useCase.Update(createContextWithUserId(1), 1, usecaseDto.UpdateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidReference))
+}
+
+// ==================== EXERCISE HANDLER TESTS ====================
+
+func TestCreateExercise_Handler_Success(t *testing.T) {
+	exerciseHandler := &handler.ExerciseHandler{Usecase: usecase.NewExerciseUsecase(&config.Config{}, &MockExerciseRepository{})}
+	body, _ := json.Marshal(dto.CreateExerciseRequest{
+		Name: "Landmine Press", Category: "strength", PrimaryMuscles: []string{"shoulders", "triceps"}, Equipment: "barbell", UnitType: "weight_reps",
+	})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/exercises/", body, &MockTokenProvider{}, &config.Config{})
+
+	exerciseHandler.Create(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Result dto.ExerciseResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, []string{"shoulders", "triceps"}, response.Result.PrimaryMuscles)
+	assert.Equal(t, []string{}, response.Result.SecondaryMuscles)
+	assert.True(t, response.Result.Custom)
+}
+
+func TestCreateExercise_Handler_UnknownMuscleGroup(t *testing.T) {
+	exerciseHandler := &handler.ExerciseHandler{Usecase: usecase.NewExerciseUsecase(&config.Config{}, &MockExerciseRepository{})}
+	body, _ := json.Marshal(dto.CreateExerciseRequest{
+		Name: "Landmine Press", Category: "strength", PrimaryMuscles: []string{"wings"}, Equipment: "barbell", UnitType: "weight_reps",
+	})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/exercises/", body, &MockTokenProvider{}, &config.Config{})
+
+	exerciseHandler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "primary_muscles[0]", (*response.ValidationErrors)[0].Field)
+	assert.Equal(t, "oneof", (*response.ValidationErrors)[0].Tag)
+}
+
+func TestCreateWorkoutExercise_Handler_NameOrExerciseRequired(t *testing.T) {
+	workoutExerciseHandler, tokenProvider, cfg := setupWorkoutExerciseHandler(&MockWorkoutExerciseRepository{}, &MockWorkoutRepository{})
+	body, _ := json.Marshal(map[string]interface{}{"workout_id": 1, "reps": 5, "sets": 5, "weight": 80})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/workout-exercise/", body, tokenProvider, cfg)
+
+	workoutExerciseHandler.Create(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "exercise_id", (*response.ValidationErrors)[0].Field)
+	assert.Equal(t, "required_without", (*response.ValidationErrors)[0].Tag)
+}
+
+// ==================== EXERCISE CATALOG SEED TESTS ====================
+
+func TestExerciseCatalog_EntriesAreValid(t *testing.T) {
+	seeds, err := migrations.ExerciseCatalog()
+	assert.NoError(t, err)
+	assert.True(t, len(seeds) > 0)
+
+	names := map[string]bool{}
+	for _, seed := range seeds {
+		raw, _ := json.Marshal(seed)
+		var req dto.CreateExerciseRequest
+		assert.NoError(t, json.Unmarshal(raw, &req))
+		assert.NoError(t, binding.Validator.ValidateStruct(req), "seed %q", seed.Name)
+
+		name := strings.ToLower(seed.Name)
+		assert.False(t, names[name], "seed %q is listed twice", seed.Name)
+		names[name] = true
+	}
+}
@@ -0,0 +1,390 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/jobs"
+)
+
+// ==================== SCHEDULE TESTS ====================
+
+func TestParseSchedule_Next(t *testing.T) {
+	after := time.Date(2024, 3, 4, 10, 17, 30, 0, time.UTC) // a Monday
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, 3, 4, 10, 18, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2024, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{"30 6 * * 6,7", time.Date(2024, 3, 9, 6, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week
+		{"0 8 15 * 3", time.Date(2024, 3, 6, 8, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2024, 3, 4, 10, 19, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		schedule, err := jobs.Parse(test.spec)
+		assert.NoError(t, err, test.spec)
+		assert.Equal(t, test.next, schedule.Next(after), test.spec)
+	}
+}
+
+func TestParseSchedule_NeverMatches(t *testing.T) {
+	schedule, err := jobs.Parse("0 0 30 2 *")
+
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 10ms", "@every soon", "@often"} {
+		_, err := jobs.Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// ==================== SCHEDULER TESTS ====================
+
+// fakeElector is the leader while leader is set and records that it resigned
+type fakeElector struct {
+	leader   atomic.Bool
+	resigned atomic.Bool
+}
+
+func (e *fakeElector) IsLeader(ctx context.Context) (bool, error) {
+	return e.leader.Load(), nil
+}
+
+func (e *fakeElector) Resign(ctx context.Context) error {
+	e.resigned.Store(true)
+	return nil
+}
+
+func TestScheduler_RunsWhileLeader(t *testing.T) {
+	elector := &fakeElector{}
+	elector.leader.Store(true)
+	scheduler := jobs.NewScheduler(elector)
+	runs := make(chan struct{}, 10)
+	err := scheduler.Add("count", "@every 1s", func(ctx context.Context) error {
+		runs <- struct{}{}
+		return errors.New("failures are logged only")
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+	select {
+	case <-runs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the job did not run")
+	}
+	cancel()
+	scheduler.Wait()
+
+	assert.True(t, elector.resigned.Load())
+}
+
+func TestScheduler_FollowerDoesNotRun(t *testing.T) {
+	scheduler := jobs.NewScheduler(&fakeElector{})
+	var runs atomic.Int32
+	err := scheduler.Add("count", "@every 1s", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	scheduler.Start(ctx)
+	scheduler.Wait()
+
+	assert.Equal(t, int32(0), runs.Load())
+}
+
+func TestScheduler_WaitsForRunningJobs(t *testing.T) {
+	elector := &fakeElector{}
+	elector.leader.Store(true)
+	scheduler := jobs.NewScheduler(elector)
+	started := make(chan struct{})
+	var once sync.Once
+	var finished atomic.Bool
+	err := scheduler.Add("slow", "@every 1s", func(ctx context.Context) error {
+		once.Do(func() { close(started) })
+		<-ctx.Done()
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+		return ctx.Err()
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+	<-started
+	cancel()
+	scheduler.Wait()
+
+	assert.True(t, finished.Load())
+}
+
+func TestScheduler_InvalidSchedule(t *testing.T) {
+	err := jobs.NewScheduler(&fakeElector{}).Add("broken", "every minute", func(ctx context.Context) error { return nil })
+
+	assert.Error(t, err)
+}
+
+// ==================== POSTGRES ELECTOR TESTS ====================
+
+// electorConnector opens fake postgres sessions that grant the advisory lock and count the sessions
+// opened and closed, unlockErr fails the unlock and pingErr the pings
+type electorConnector struct {
+	opened    atomic.Int32
+	closed    atomic.Int32
+	unlockErr error
+	pingErr   error
+}
+
+func (c *electorConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.opened.Add(1)
+	return &electorConn{connector: c}, nil
+}
+
+func (c *electorConnector) Driver() driver.Driver { return nil }
+
+type electorConn struct {
+	connector *electorConnector
+}
+
+func (c *electorConn) Prepare(query string) (driver.Stmt, error) {
+	return &electorStmt{connector: c.connector, query: query}, nil
+}
+func (c *electorConn) Close() error {
+	c.connector.closed.Add(1)
+	return nil
+}
+func (c *electorConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+func (c *electorConn) Ping(ctx context.Context) error {
+	return c.connector.pingErr
+}
+
+type electorStmt struct {
+	connector *electorConnector
+	query     string
+}
+
+func (s *electorStmt) Close() error  { return nil }
+func (s *electorStmt) NumInput() int { return -1 }
+func (s *electorStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.Contains(s.query, "unlock") && s.connector.unlockErr != nil {
+		return nil, s.connector.unlockErr
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s *electorStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &electorRows{}, nil
+}
+
+// electorRows is the single true row of pg_try_advisory_lock
+type electorRows struct {
+	done bool
+}
+
+func (r *electorRows) Columns() []string { return []string{"locked"} }
+func (r *electorRows) Close() error      { return nil }
+func (r *electorRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = true
+	return nil
+}
+
+func TestPostgresElector_ResignReturnsTheSessionToThePool(t *testing.T) {
+	connector := &electorConnector{}
+	elector := jobs.NewPostgresElector(sql.OpenDB(connector), 42)
+
+	leader, err := elector.IsLeader(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, leader)
+
+	assert.NoError(t, elector.Resign(context.Background()))
+	assert.Equal(t, int32(0), connector.closed.Load())
+}
+
+func TestPostgresElector_FailedUnlockClosesTheSession(t *testing.T) {
+	connector := &electorConnector{unlockErr: errors.New("connection reset")}
+	elector := jobs.NewPostgresElector(sql.OpenDB(connector), 42)
+	_, err := elector.IsLeader(context.Background())
+	assert.NoError(t, err)
+
+	err = elector.Resign(context.Background())
+
+	assert.EqualError(t, err, "connection reset")
+	// The session may still hold the lock, so it does not go back to the pool
+	assert.Equal(t, int32(1), connector.closed.Load())
+}
+
+func TestPostgresElector_FailedPingClosesTheSession(t *testing.T) {
+	connector := &electorConnector{}
+	elector := jobs.NewPostgresElector(sql.OpenDB(connector), 42)
+	_, err := elector.IsLeader(context.Background())
+	assert.NoError(t, err)
+	connector.pingErr = errors.New("timeout")
+
+	leader, err := elector.IsLeader(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, leader)
+	// The lock is taken again on a new session
+	assert.Equal(t, int32(1), connector.closed.Load())
+	assert.Equal(t, int32(2), connector.opened.Load())
+}
+
+// ==================== SCHEDULE JOBS USECASE TESTS ====================
+
+func TestScheduleJobs_MarkMissedAfterGracePeriod(t *testing.T) {
+	var before time.Time
+	repo := &MockScheduleJobsRepository{
+		MarkMissedFn: func(ctx context.Context, t time.Time) (int64, error) {
+			before = t
+			return 2, nil
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{Jobs: config.JobsConfig{MissedAfter: 120}}, repo, &MockNotifier{})
+
+	err := useCase.MarkMissed(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, time.Since(before) >= 2*time.Hour && time.Since(before) < 2*time.Hour+time.Minute, "before %s", before)
+}
+
+func TestScheduleJobs_EnqueueRemindersOfTheLead(t *testing.T) {
+	var from, to time.Time
+	repo := &MockScheduleJobsRepository{
+		EnqueueRemindersFn: func(ctx context.Context, f time.Time, t time.Time) (int64, error) {
+			from, to = f, t
+			return 0, nil
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{Jobs: config.JobsConfig{ReminderLead: 60}}, repo, &MockNotifier{})
+
+	err := useCase.EnqueueReminders(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, to.Sub(from))
+	assert.True(t, time.Since(from) < time.Minute)
+}
+
+func TestScheduleJobs_RepositoryError(t *testing.T) {
+	repo := &MockScheduleJobsRepository{
+		MarkMissedFn: func(ctx context.Context, before time.Time) (int64, error) {
+			return 0, errors.New("database error")
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{}, repo, &MockNotifier{})
+
+	err := useCase.MarkMissed(context.Background())
+
+	assert.EqualError(t, err, "database error")
+}
+
+func TestScheduleJobs_SendRemindersOnlyDueOnes(t *testing.T) {
+	var marked []int
+	repo := &MockScheduleJobsRepository{
+		PendingRemindersFn: func(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+			return []models.DueReminder{
+				{Id: 1, UserId: 3, WorkoutName: "Push day", Due: true},
+				{Id: 2, UserId: 3, WorkoutName: "Rescheduled", Due: false},
+				{Id: 3, UserId: 4, WorkoutName: "Leg day", Due: true},
+			}, nil
+		},
+		MarkRemindersSentFn: func(ctx context.Context, ids []int, sentAt time.Time) error {
+			marked = ids
+			return nil
+		},
+	}
+	var reminded []int
+	notifier := &MockNotifier{
+		RemindScheduleFn: func(ctx context.Context, reminder models.DueReminder) error {
+			reminded = append(reminded, reminder.Id)
+			return nil
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{}, repo, notifier)
+
+	err := useCase.SendReminders(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, marked)
+	assert.Equal(t, []int{1, 3}, reminded)
+}
+
+func TestScheduleJobs_SendRemindersAtMostOnce(t *testing.T) {
+	var marked []int
+	repo := &MockScheduleJobsRepository{
+		PendingRemindersFn: func(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+			return []models.DueReminder{{Id: 1, Due: true}, {Id: 2, Due: true}}, nil
+		},
+		MarkRemindersSentFn: func(ctx context.Context, ids []int, sentAt time.Time) error {
+			marked = ids
+			return nil
+		},
+	}
+	var reminded int
+	notifier := &MockNotifier{
+		RemindScheduleFn: func(ctx context.Context, reminder models.DueReminder) error {
+			reminded++
+			if reminder.Id == 1 {
+				return errors.New("smtp unavailable")
+			}
+			return nil
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{}, repo, notifier)
+
+	err := useCase.SendReminders(context.Background())
+
+	// The failed reminder is reported but stays sent, and the next one is still delivered
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, marked)
+	assert.Equal(t, 2, reminded)
+}
+
+func TestScheduleJobs_SendRemindersNotMarkedIsNotSent(t *testing.T) {
+	repo := &MockScheduleJobsRepository{
+		PendingRemindersFn: func(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+			return []models.DueReminder{{Id: 1, Due: true}}, nil
+		},
+		MarkRemindersSentFn: func(ctx context.Context, ids []int, sentAt time.Time) error {
+			return errors.New("database error")
+		},
+	}
+	notifier := &MockNotifier{
+		RemindScheduleFn: func(ctx context.Context, reminder models.DueReminder) error {
+			t.Fatal("a reminder that could not be marked was sent")
+			return nil
+		},
+	}
+	useCase := usecase.NewScheduleJobsUsecase(&config.Config{}, repo, notifier)
+
+	err := useCase.SendReminders(context.Background())
+
+	assert.EqualError(t, err, "database error")
+}
@@ -13,8 +13,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/user/entity"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt"
 )
@@ -83,6 +83,18 @@ func (m *MockWorkoutRepository) GetByFilter(ctx context.Context, req filter.Pagi
 	return 1, &workouts, nil
 }
 
+// MockTransactor implements Transactor interface for testing, by default it runs the work without a transaction
+type MockTransactor struct {
+	WithinTransactionFn func(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+func (m *MockTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.WithinTransactionFn != nil {
+		return m.WithinTransactionFn(ctx, fn)
+	}
+	return fn(ctx)
+}
+
 // MockScheduledWorkoutsRepository implements ScheduledWorkoutsRepository interface for testing
 type MockScheduledWorkoutsRepository struct {
 	CreateFn      func(ctx context.Context, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error)
@@ -126,7 +138,7 @@ func (m *MockScheduledWorkoutsRepository) GetById(ctx context.Context, id int) (
 		Id:            id,
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
+		Status:        "planned",
 		CreatedAt:     time.Now(),
 	}, nil
 }
@@ -140,7 +152,7 @@ func (m *MockScheduledWorkoutsRepository) GetByFilter(ctx context.Context, req f
 			Id:            1,
 			WorkoutId:     1,
 			ScheduledTime: time.Now(),
-			Status:        "active",
+			Status:        "planned",
 			CreatedAt:     time.Now(),
 		},
 	}
@@ -215,6 +227,108 @@ func (m *MockWorkoutExerciseRepository) GetByFilter(ctx context.Context, req fil
 	return 1, &exercises, nil
 }
 
+// MockScheduleRuleRepository implements ScheduleRuleRepository interface for testing
+type MockScheduleRuleRepository struct {
+	CreateFn      func(ctx context.Context, entity models.ScheduleRule) (models.ScheduleRule, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.ScheduleRule) (models.ScheduleRule, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.ScheduleRule, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduleRule, error)
+}
+
+func (m *MockScheduleRuleRepository) Create(ctx context.Context, entity models.ScheduleRule) (models.ScheduleRule, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockScheduleRuleRepository) Update(ctx context.Context, id int, entity models.ScheduleRule) (models.ScheduleRule, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockScheduleRuleRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockScheduleRuleRepository) GetById(ctx context.Context, id int) (models.ScheduleRule, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.ScheduleRule{
+		Id:        id,
+		WorkoutId: 1,
+		Frequency: models.WeeklyFrequency,
+		Interval:  1,
+		StartTime: time.Now(),
+		TimeZone:  "UTC",
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (m *MockScheduleRuleRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduleRule, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	return 0, &[]models.ScheduleRule{}, nil
+}
+
+// MockCalendarFeedRepository implements CalendarFeedRepository interface for testing
+type MockCalendarFeedRepository struct {
+	CreateFn      func(ctx context.Context, entity models.CalendarFeed) (models.CalendarFeed, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.CalendarFeed) (models.CalendarFeed, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.CalendarFeed, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.CalendarFeed, error)
+}
+
+func (m *MockCalendarFeedRepository) Create(ctx context.Context, entity models.CalendarFeed) (models.CalendarFeed, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockCalendarFeedRepository) Update(ctx context.Context, id int, entity models.CalendarFeed) (models.CalendarFeed, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockCalendarFeedRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockCalendarFeedRepository) GetById(ctx context.Context, id int) (models.CalendarFeed, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.CalendarFeed{Id: id, UserId: 1, CreatedAt: time.Now()}, nil
+}
+
+func (m *MockCalendarFeedRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.CalendarFeed, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	return 0, &[]models.CalendarFeed{}, nil
+}
+
 // MockWorkoutReportRepository implements WorkoutReportRepository interface for testing
 type MockWorkoutReportRepository struct {
 	CreateFn      func(ctx context.Context, entity models.WorkoutReport) (models.WorkoutReport, error)
@@ -252,9 +366,10 @@ func (m *MockWorkoutReportRepository) GetById(ctx context.Context, id int) (mode
 	if m.GetByIdFn != nil {
 		return m.GetByIdFn(ctx, id)
 	}
+	workoutId := 1
 	return models.WorkoutReport{
 		Id:        id,
-		WorkoutId: 1,
+		WorkoutId: &workoutId,
 		UserId:    1,
 		Details:   "Test Report Details",
 		CreatedAt: time.Now(),
@@ -265,10 +380,11 @@ func (m *MockWorkoutReportRepository) GetByFilter(ctx context.Context, req filte
 	if m.GetByFilterFn != nil {
 		return m.GetByFilterFn(ctx, req)
 	}
+	workoutId := 1
 	reports := []models.WorkoutReport{
 		{
 			Id:        1,
-			WorkoutId: 1,
+			WorkoutId: &workoutId,
 			UserId:    1,
 			Details:   "Test Report Details",
 			CreatedAt: time.Now(),
@@ -277,6 +393,138 @@ func (m *MockWorkoutReportRepository) GetByFilter(ctx context.Context, req filte
 	return 1, &reports, nil
 }
 
+// MockExerciseSetRepository implements ExerciseSetRepository interface for testing
+type MockExerciseSetRepository struct {
+	CreateFn      func(ctx context.Context, entity models.ExerciseSet) (models.ExerciseSet, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.ExerciseSet) (models.ExerciseSet, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.ExerciseSet, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ExerciseSet, error)
+}
+
+func (m *MockExerciseSetRepository) Create(ctx context.Context, entity models.ExerciseSet) (models.ExerciseSet, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockExerciseSetRepository) Update(ctx context.Context, id int, entity models.ExerciseSet) (models.ExerciseSet, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockExerciseSetRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockExerciseSetRepository) GetById(ctx context.Context, id int) (models.ExerciseSet, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.ExerciseSet{
+		Id:                id,
+		WorkoutExerciseId: 1,
+		SetIndex:          1,
+		Repetitions:       10,
+		Weight:            50.0,
+		SetType:           models.WorkingSet,
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+func (m *MockExerciseSetRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ExerciseSet, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	sets := []models.ExerciseSet{
+		{
+			Id:                1,
+			WorkoutExerciseId: 1,
+			SetIndex:          1,
+			Repetitions:       10,
+			Weight:            50.0,
+			SetType:           models.WorkingSet,
+			CreatedAt:         time.Now(),
+		},
+	}
+	return 1, &sets, nil
+}
+
+// MockExerciseRepository implements ExerciseRepository interface for testing
+type MockExerciseRepository struct {
+	CreateFn      func(ctx context.Context, entity models.Exercise) (models.Exercise, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.Exercise) (models.Exercise, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.Exercise, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Exercise, error)
+}
+
+func (m *MockExerciseRepository) Create(ctx context.Context, entity models.Exercise) (models.Exercise, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockExerciseRepository) Update(ctx context.Context, id int, entity models.Exercise) (models.Exercise, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockExerciseRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockExerciseRepository) GetById(ctx context.Context, id int) (models.Exercise, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.Exercise{
+		Id:             id,
+		Name:           "Barbell Bench Press",
+		Category:       "strength",
+		PrimaryMuscles: "chest",
+		Equipment:      "barbell",
+		UnitType:       "weight_reps",
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+func (m *MockExerciseRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.Exercise, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	exercises := []models.Exercise{
+		{
+			Id:             1,
+			Name:           "Barbell Bench Press",
+			Category:       "strength",
+			PrimaryMuscles: "chest",
+			Equipment:      "barbell",
+			UnitType:       "weight_reps",
+			CreatedAt:      time.Now(),
+		},
+	}
+	return 1, &exercises, nil
+}
+
 // Helper function to create context with user ID
 func createContextWithUserId(userId float64) context.Context {
 	ctx := context.Background()
@@ -291,25 +539,25 @@ func setupWorkoutUsecase(workoutRepo *MockWorkoutRepository) *usecase.WorkoutUse
 
 func setupScheduledWorkoutUsecase(scheduledRepo *MockScheduledWorkoutsRepository, workoutRepo *MockWorkoutRepository) *usecase.ScheduledWorkoutsUseCase {
 	cfg := &config.Config{}
-	return usecase.NewScheduledWorkoutsUsecase(cfg, scheduledRepo, workoutRepo)
+	return usecase.NewScheduledWorkoutsUsecase(cfg, scheduledRepo, workoutRepo, &MockScheduleRuleRepository{}, &MockTransactor{})
 }
 
 func setupWorkoutExerciseUsecase(exerciseRepo *MockWorkoutExerciseRepository, workoutRepo *MockWorkoutRepository) *usecase.WorkoutExerciseUsecase {
 	cfg := &config.Config{}
-	return usecase.NewWorkoutExerciseUsecase(cfg, exerciseRepo, workoutRepo)
+	return usecase.NewWorkoutExerciseUsecase(cfg, exerciseRepo, workoutRepo, &MockExerciseRepository{}, &MockPersonalRecordRepository{}, &MockNotifier{})
 }
 
 func setupWorkoutReportUsecase(reportRepo *MockWorkoutReportRepository, workoutRepo *MockWorkoutRepository) *usecase.WorkoutReportUsecase {
 	cfg := &config.Config{}
-	return usecase.NewWorkoutReportUsecase(cfg, reportRepo, workoutRepo)
+	return usecase.NewWorkoutReportUsecase(cfg, reportRepo, workoutRepo, &MockAnalyticsRepository{}, &MockPersonalRecordRepository{}, &MockExerciseRepository{})
 }
 
 // MockTokenProvider implements TokenProvider interface for testing
 type MockTokenProvider struct {
-	GenerateTokenFn func(token *entity.TokenPayload) (*dto.TokenDetail, error)
-	VerifyTokenFn   func(token string) (*jwt.Token, error)
-	GetClaimsFn     func(token string) (map[string]interface{}, error)
-	RefreshTokenFn  func(refreshToken string) (*dto.TokenDetail, error)
+	GenerateTokenFn    func(token *entity.TokenPayload) (*dto.TokenDetail, error)
+	VerifyTokenFn      func(token string) (*jwt.Token, error)
+	GetClaimsFn        func(token string) (map[string]interface{}, error)
+	GetRefreshClaimsFn func(refreshToken string) (map[string]interface{}, error)
 }
 
 func (m *MockTokenProvider) GenerateToken(token *entity.TokenPayload) (*dto.TokenDetail, error) {
@@ -347,15 +595,14 @@ func (m *MockTokenProvider) GetClaims(token string) (map[string]interface{}, err
 	}, nil
 }
 
-func (m *MockTokenProvider) RefreshToken(refreshToken string) (*dto.TokenDetail, error) {
-	if m.RefreshTokenFn != nil {
-		return m.RefreshTokenFn(refreshToken)
+func (m *MockTokenProvider) GetRefreshClaims(refreshToken string) (map[string]interface{}, error) {
+	if m.GetRefreshClaimsFn != nil {
+		return m.GetRefreshClaimsFn(refreshToken)
 	}
-	return &dto.TokenDetail{
-		AccessToken:            "mock-new-access-token",
-		RefreshToken:           "mock-new-refresh-token",
-		AccessTokenExpireTime:  time.Now().Add(time.Hour).Unix(),
-		RefreshTokenExpireTime: time.Now().Add(24 * time.Hour).Unix(),
+	return map[string]interface{}{
+		constants.UserIdKey:    float64(1),
+		constants.SessionIdKey: "mock-session-id",
+		constants.TokenIdKey:   "mock-token-id",
 	}, nil
 }
 
@@ -396,3 +643,298 @@ func createAuthenticatedGinContextWithParams(method, url string, body []byte, pa
 	c.Params = params
 	return c, w
 }
+
+// MockWorkoutSessionRepository implements WorkoutSessionRepository interface for testing
+type MockWorkoutSessionRepository struct {
+	CreateFn       func(ctx context.Context, entity models.WorkoutSession) (models.WorkoutSession, error)
+	UpdateFn       func(ctx context.Context, id int, entity models.WorkoutSession) (models.WorkoutSession, error)
+	DeleteFn       func(ctx context.Context, id int) error
+	GetByIdFn      func(ctx context.Context, id int) (models.WorkoutSession, error)
+	GetByFilterFn  func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSession, error)
+	UpdateStatusFn func(ctx context.Context, id int, from string, entity models.WorkoutSession) error
+}
+
+func (m *MockWorkoutSessionRepository) Create(ctx context.Context, entity models.WorkoutSession) (models.WorkoutSession, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockWorkoutSessionRepository) Update(ctx context.Context, id int, entity models.WorkoutSession) (models.WorkoutSession, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockWorkoutSessionRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockWorkoutSessionRepository) GetById(ctx context.Context, id int) (models.WorkoutSession, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.WorkoutSession{
+		Id:        id,
+		UserId:    1,
+		WorkoutId: 1,
+		Status:    models.SessionInProgress,
+		StartedAt: time.Now().Add(-time.Hour),
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (m *MockWorkoutSessionRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSession, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	sessions := []models.WorkoutSession{
+		{
+			Id:        1,
+			UserId:    1,
+			WorkoutId: 1,
+			Status:    models.SessionInProgress,
+			StartedAt: time.Now().Add(-time.Hour),
+			CreatedAt: time.Now(),
+		},
+	}
+	return 1, &sessions, nil
+}
+
+func (m *MockWorkoutSessionRepository) UpdateStatus(ctx context.Context, id int, from string, entity models.WorkoutSession) error {
+	if m.UpdateStatusFn != nil {
+		return m.UpdateStatusFn(ctx, id, from, entity)
+	}
+	return nil
+}
+
+// MockWorkoutSessionSetRepository implements WorkoutSessionSetRepository interface for testing
+type MockWorkoutSessionSetRepository struct {
+	CreateFn      func(ctx context.Context, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.WorkoutSessionSet, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSessionSet, error)
+}
+
+func (m *MockWorkoutSessionSetRepository) Create(ctx context.Context, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockWorkoutSessionSetRepository) Update(ctx context.Context, id int, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockWorkoutSessionSetRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockWorkoutSessionSetRepository) GetById(ctx context.Context, id int) (models.WorkoutSessionSet, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.WorkoutSessionSet{
+		Id:                id,
+		WorkoutSessionId:  1,
+		WorkoutExerciseId: 1,
+		SetIndex:          1,
+		Repetitions:       10,
+		Weight:            50.0,
+		SetType:           models.WorkingSet,
+		PerformedAt:       time.Now(),
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+func (m *MockWorkoutSessionSetRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSessionSet, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	sets := []models.WorkoutSessionSet{}
+	return 0, &sets, nil
+}
+
+// MockPersonalRecordRepository implements PersonalRecordRepository interface for testing
+type MockPersonalRecordRepository struct {
+	CreateFn      func(ctx context.Context, entity models.PersonalRecord) (models.PersonalRecord, error)
+	UpdateFn      func(ctx context.Context, id int, entity models.PersonalRecord) (models.PersonalRecord, error)
+	DeleteFn      func(ctx context.Context, id int) error
+	GetByIdFn     func(ctx context.Context, id int) (models.PersonalRecord, error)
+	GetByFilterFn func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error)
+}
+
+func (m *MockPersonalRecordRepository) Create(ctx context.Context, entity models.PersonalRecord) (models.PersonalRecord, error) {
+	if m.CreateFn != nil {
+		return m.CreateFn(ctx, entity)
+	}
+	entity.Id = 1
+	entity.CreatedAt = time.Now()
+	return entity, nil
+}
+
+func (m *MockPersonalRecordRepository) Update(ctx context.Context, id int, entity models.PersonalRecord) (models.PersonalRecord, error) {
+	if m.UpdateFn != nil {
+		return m.UpdateFn(ctx, id, entity)
+	}
+	entity.Id = id
+	return entity, nil
+}
+
+func (m *MockPersonalRecordRepository) Delete(ctx context.Context, id int) error {
+	if m.DeleteFn != nil {
+		return m.DeleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockPersonalRecordRepository) GetById(ctx context.Context, id int) (models.PersonalRecord, error) {
+	if m.GetByIdFn != nil {
+		return m.GetByIdFn(ctx, id)
+	}
+	return models.PersonalRecord{
+		Id:                id,
+		UserId:            1,
+		ExerciseId:        1,
+		WorkoutExerciseId: 1,
+		RecordType:        models.MaxWeightRecord,
+		Value:             100,
+		Weight:            100,
+		Repetitions:       1,
+		AchievedAt:        time.Now(),
+		CreatedAt:         time.Now(),
+	}, nil
+}
+
+func (m *MockPersonalRecordRepository) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+	if m.GetByFilterFn != nil {
+		return m.GetByFilterFn(ctx, req)
+	}
+	records := []models.PersonalRecord{}
+	return 0, &records, nil
+}
+
+// MockAnalyticsRepository implements AnalyticsRepository interface for testing, it records the last range
+type MockAnalyticsRepository struct {
+	Range                models.AnalyticsRange
+	VolumeFn             func(ctx context.Context, r models.AnalyticsRange) ([]models.VolumePoint, error)
+	ExerciseTrendsFn     func(ctx context.Context, r models.AnalyticsRange) ([]models.ExerciseTrendPoint, error)
+	FrequencyFn          func(ctx context.Context, r models.AnalyticsRange) ([]models.FrequencyPoint, error)
+	StreaksFn            func(ctx context.Context, r models.AnalyticsRange) ([]models.Streak, error)
+	MuscleDistributionFn func(ctx context.Context, r models.AnalyticsRange) ([]models.MuscleVolume, error)
+}
+
+func (m *MockAnalyticsRepository) Volume(ctx context.Context, r models.AnalyticsRange) ([]models.VolumePoint, error) {
+	m.Range = r
+	if m.VolumeFn != nil {
+		return m.VolumeFn(ctx, r)
+	}
+	return []models.VolumePoint{}, nil
+}
+
+func (m *MockAnalyticsRepository) ExerciseTrends(ctx context.Context, r models.AnalyticsRange) ([]models.ExerciseTrendPoint, error) {
+	m.Range = r
+	if m.ExerciseTrendsFn != nil {
+		return m.ExerciseTrendsFn(ctx, r)
+	}
+	return []models.ExerciseTrendPoint{}, nil
+}
+
+func (m *MockAnalyticsRepository) Frequency(ctx context.Context, r models.AnalyticsRange) ([]models.FrequencyPoint, error) {
+	m.Range = r
+	if m.FrequencyFn != nil {
+		return m.FrequencyFn(ctx, r)
+	}
+	return []models.FrequencyPoint{}, nil
+}
+
+func (m *MockAnalyticsRepository) Streaks(ctx context.Context, r models.AnalyticsRange) ([]models.Streak, error) {
+	m.Range = r
+	if m.StreaksFn != nil {
+		return m.StreaksFn(ctx, r)
+	}
+	return []models.Streak{}, nil
+}
+
+func (m *MockAnalyticsRepository) MuscleDistribution(ctx context.Context, r models.AnalyticsRange) ([]models.MuscleVolume, error) {
+	m.Range = r
+	if m.MuscleDistributionFn != nil {
+		return m.MuscleDistributionFn(ctx, r)
+	}
+	return []models.MuscleVolume{}, nil
+}
+
+// MockScheduleJobsRepository implements ScheduleJobsRepository interface for testing
+type MockScheduleJobsRepository struct {
+	MarkMissedFn        func(ctx context.Context, before time.Time) (int64, error)
+	EnqueueRemindersFn  func(ctx context.Context, from time.Time, to time.Time) (int64, error)
+	PendingRemindersFn  func(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error)
+	MarkRemindersSentFn func(ctx context.Context, ids []int, sentAt time.Time) error
+}
+
+func (m *MockScheduleJobsRepository) MarkMissed(ctx context.Context, before time.Time) (int64, error) {
+	if m.MarkMissedFn != nil {
+		return m.MarkMissedFn(ctx, before)
+	}
+	return 0, nil
+}
+
+func (m *MockScheduleJobsRepository) EnqueueReminders(ctx context.Context, from time.Time, to time.Time) (int64, error) {
+	if m.EnqueueRemindersFn != nil {
+		return m.EnqueueRemindersFn(ctx, from, to)
+	}
+	return 0, nil
+}
+
+func (m *MockScheduleJobsRepository) PendingReminders(ctx context.Context, now time.Time, limit int) ([]models.DueReminder, error) {
+	if m.PendingRemindersFn != nil {
+		return m.PendingRemindersFn(ctx, now, limit)
+	}
+	return nil, nil
+}
+
+func (m *MockScheduleJobsRepository) MarkRemindersSent(ctx context.Context, ids []int, sentAt time.Time) error {
+	if m.MarkRemindersSentFn != nil {
+		return m.MarkRemindersSentFn(ctx, ids, sentAt)
+	}
+	return nil
+}
+
+// MockNotifier implements Notifier interface for testing
+type MockNotifier struct {
+	RemindScheduleFn    func(ctx context.Context, reminder models.DueReminder) error
+	NotifyAchievementFn func(ctx context.Context, achievement models.Achievement) error
+}
+
+func (m *MockNotifier) RemindSchedule(ctx context.Context, reminder models.DueReminder) error {
+	if m.RemindScheduleFn != nil {
+		return m.RemindScheduleFn(ctx, reminder)
+	}
+	return nil
+}
+
+func (m *MockNotifier) NotifyAchievement(ctx context.Context, achievement models.Achievement) error {
+	if m.NotifyAchievementFn != nil {
+		return m.NotifyAchievementFn(ctx, achievement)
+	}
+	return nil
+}
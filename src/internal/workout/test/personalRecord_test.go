@@ -0,0 +1,441 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// recordRepoWith stores the created records next to the given history
+func recordRepoWith(history ...models.PersonalRecord) (*MockPersonalRecordRepository, *[]models.PersonalRecord) {
+	created := []models.PersonalRecord{}
+	repo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			records := append([]models.PersonalRecord{}, history...)
+			return int64(len(records)), &records, nil
+		},
+		CreateFn: func(ctx context.Context, entity models.PersonalRecord) (models.PersonalRecord, error) {
+			entity.Id = 100 + len(created)
+			created = append(created, entity)
+			return entity, nil
+		},
+	}
+	return repo, &created
+}
+
+func recordTypes(records []models.PersonalRecord) map[string]models.PersonalRecord {
+	byType := map[string]models.PersonalRecord{}
+	for _, record := range records {
+		byType[record.RecordType] = record
+	}
+	return byType
+}
+
+func benchPress(reps int, sets int, weight float64) usecaseDto.WorkoutExerciseResponse {
+	exerciseId := 9
+	return usecaseDto.WorkoutExerciseResponse{Id: 3, WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: reps, Sets: sets, Weight: weight}
+}
+
+// ==================== PERSONAL RECORD USECASE TESTS ====================
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	assert.Equal(t, 100.0, usecase.EstimatedOneRepMax(100, 1))
+	// Brzycki up to 10 repetitions
+	assert.Equal(t, 112.5, usecase.EstimatedOneRepMax(100, 5))
+	// Epley above
+	assert.Equal(t, 140.0, usecase.EstimatedOneRepMax(100, 12))
+	assert.Equal(t, 0.0, usecase.EstimatedOneRepMax(100, 0))
+}
+
+func TestDetectPersonalRecords_FirstWorkoutSetsEveryRecord(t *testing.T) {
+	repo, created := recordRepoWith()
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	records, err := useCase.Detect(createContextWithUserId(1), benchPress(5, 3, 100))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(records))
+	byType := recordTypes(*created)
+	assert.Equal(t, 100.0, byType[models.MaxWeightRecord].Value)
+	assert.Equal(t, 112.5, byType[models.EstimatedOneRepMaxRecord].Value)
+	assert.Equal(t, 5.0, byType[models.MaxRepsRecord].Value)
+	assert.Equal(t, 1500.0, byType[models.MaxVolumeRecord].Value)
+	assert.Equal(t, 1, byType[models.MaxWeightRecord].UserId)
+	assert.Equal(t, 3, byType[models.MaxWeightRecord].WorkoutExerciseId)
+	assert.True(t, byType[models.MaxWeightRecord].PreviousValue == nil)
+}
+
+func TestDetectPersonalRecords_OnlyBrokenRecordsAreStored(t *testing.T) {
+	repo, created := recordRepoWith(
+		models.PersonalRecord{Id: 1, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 110, Weight: 110, Repetitions: 1},
+		models.PersonalRecord{Id: 2, ExerciseId: 9, RecordType: models.EstimatedOneRepMaxRecord, Value: 110, Weight: 110, Repetitions: 1},
+		models.PersonalRecord{Id: 3, ExerciseId: 9, RecordType: models.MaxVolumeRecord, Value: 2000, Weight: 100, Repetitions: 5},
+		models.PersonalRecord{Id: 4, ExerciseId: 9, RecordType: models.MaxRepsRecord, Value: 6, Weight: 100, Repetitions: 6},
+	)
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	_, err := useCase.Detect(createContextWithUserId(1), benchPress(5, 3, 100))
+
+	assert.NoError(t, err)
+	// 112.5 beats the estimated 1RM of 110, nothing else is broken
+	assert.Equal(t, 1, len(*created))
+	record := (*created)[0]
+	assert.Equal(t, models.EstimatedOneRepMaxRecord, record.RecordType)
+	assert.Equal(t, 110.0, *record.PreviousValue)
+}
+
+func TestDetectPersonalRecords_MaxRepsIsPerWeight(t *testing.T) {
+	repo, created := recordRepoWith(
+		models.PersonalRecord{Id: 1, ExerciseId: 9, RecordType: models.MaxRepsRecord, Value: 12, Weight: 60, Repetitions: 12},
+	)
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	_, err := useCase.Detect(createContextWithUserId(1), benchPress(8, 1, 80))
+
+	assert.NoError(t, err)
+	record, ok := recordTypes(*created)[models.MaxRepsRecord]
+	assert.True(t, ok)
+	assert.Equal(t, 8.0, record.Value)
+	assert.Equal(t, 80.0, record.Weight)
+	assert.True(t, record.PreviousValue == nil)
+}
+
+func TestDetectPersonalRecords_BodyWeightOnlySetsReps(t *testing.T) {
+	repo, created := recordRepoWith()
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	_, err := useCase.Detect(createContextWithUserId(1), benchPress(15, 3, 0))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(*created))
+	assert.Equal(t, models.MaxRepsRecord, (*created)[0].RecordType)
+}
+
+func TestDetectPersonalRecords_WithoutExercise(t *testing.T) {
+	repo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			t.Fatal("records must not be read for a workout exercise without an exercise")
+			return 0, nil, nil
+		},
+	}
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	records, err := useCase.Detect(createContextWithUserId(1), usecaseDto.WorkoutExerciseResponse{Id: 3, Repetitions: 5, Weight: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(records))
+}
+
+func TestGetCurrentPersonalRecords_BestOfEveryRecord(t *testing.T) {
+	var request filter.PaginationInputWithFilter
+	history := []models.PersonalRecord{
+		{Id: 5, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 120},
+		{Id: 4, ExerciseId: 2, RecordType: models.MaxWeightRecord, Value: 60},
+		{Id: 3, ExerciseId: 9, RecordType: models.MaxRepsRecord, Value: 10, Weight: 60},
+		{Id: 2, ExerciseId: 9, RecordType: models.MaxRepsRecord, Value: 8, Weight: 80},
+		{Id: 1, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 100},
+	}
+	repo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			request = req
+			return int64(len(history)), &history, nil
+		},
+	}
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})
+
+	records, err := useCase.GetCurrent(createContextWithUserId(1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, request.OwnerId)
+	ids := []int{}
+	for _, record := range records {
+		ids = append(ids, record.Id)
+	}
+	assert.Equal(t, []int{5, 3, 2, 4}, ids)
+}
+
+func TestGetExercisePersonalRecords_OtherUsersCustomExercise(t *testing.T) {
+	useCase := usecase.NewPersonalRecordUsecase(&config.Config{}, &MockPersonalRecordRepository{}, exerciseRepoWith(customExercise(5, 2)))
+
+	_, err := useCase.GetByExercise(createContextWithUserId(1), 5)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeRecordNotFound))
+}
+
+func TestCreateWorkoutExercise_ReturnsBrokenRecords(t *testing.T) {
+	repo, _ := recordRepoWith()
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+	exerciseId := 9
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 5, Sets: 3, Weight: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(response.PersonalRecords))
+}
+
+func TestCreateWorkoutExercise_NotifiesBrokenRecords(t *testing.T) {
+	repo, _ := recordRepoWith()
+	achievements := make(chan models.Achievement, 1)
+	notifier := &MockNotifier{
+		NotifyAchievementFn: func(ctx context.Context, achievement models.Achievement) error {
+			achievements <- achievement
+			return errors.New("delivery failures are logged only")
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, notifier)
+	exerciseId := 9
+
+	_, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 5, Sets: 3, Weight: 100})
+
+	assert.NoError(t, err)
+	select {
+	case achievement := <-achievements:
+		assert.Equal(t, 1, achievement.UserId)
+		assert.Equal(t, 9, achievement.ExerciseId)
+		assert.Equal(t, 4, len(achievement.Records))
+	case <-time.After(2 * time.Second):
+		t.Fatal("the records were not notified")
+	}
+}
+
+func TestCreateWorkoutExercise_NotifiesOutsideTheRequestContext(t *testing.T) {
+	repo, _ := recordRepoWith()
+	contexts := make(chan context.Context, 1)
+	notifier := &MockNotifier{
+		NotifyAchievementFn: func(ctx context.Context, achievement models.Achievement) error {
+			contexts <- ctx
+			return nil
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, notifier)
+	exerciseId := 9
+	// gin reuses its context for the next request once the handler returns
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	c.Set(constants.UserIdKey, float64(1))
+
+	_, err := useCase.Create(c, usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 5, Sets: 3, Weight: 100})
+	c.Keys = nil
+
+	assert.NoError(t, err)
+	select {
+	case ctx := <-contexts:
+		_, isGinContext := ctx.(*gin.Context)
+		assert.False(t, isGinContext)
+		assert.Equal(t, interface{}(float64(1)), ctx.Value(constants.UserIdKey))
+	case <-time.After(2 * time.Second):
+		t.Fatal("the records were not notified")
+	}
+}
+
+func TestCreateWorkoutExercise_RecordFailureIsNotFatal(t *testing.T) {
+	repo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			return 0, nil, errors.New("connection reset")
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+	exerciseId := 9
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateWorkoutExerciseRequest{WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 5, Sets: 3, Weight: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(response.PersonalRecords))
+}
+
+func TestUpdateWorkoutExercise_ReplacesItsRecords(t *testing.T) {
+	exerciseId := 9
+	deleted := []int{}
+	var removalFilter filter.PaginationInputWithFilter
+	repo, created := recordRepoWith()
+	listHistory := repo.GetByFilterFn
+	repo.GetByFilterFn = func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+		if _, ok := req.Filter["WorkoutExerciseId"]; ok {
+			removalFilter = req
+			// The record of a logged set of the workout exercise is kept
+			setId := 5
+			records := []models.PersonalRecord{{Id: 11, WorkoutExerciseId: 3}, {Id: 12, WorkoutExerciseId: 3}, {Id: 13, WorkoutExerciseId: 3, ExerciseSetId: &setId}}
+			return 3, &records, nil
+		}
+		return listHistory(ctx, req)
+	}
+	repo.DeleteFn = func(ctx context.Context, id int) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+	workoutExercises := &MockWorkoutExerciseRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+			return models.WorkoutExercise{Id: id, WorkoutId: 1, ExerciseId: &exerciseId, Repetitions: 3, Sets: 1, Weight: 140, CreatedAt: time.Now()}, nil
+		},
+	}
+	useCase := usecase.NewWorkoutExerciseUsecase(&config.Config{}, workoutExercises, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+
+	response, err := useCase.Update(createContextWithUserId(1), 3, usecaseDto.UpdateWorkoutExerciseRequest{WorkoutId: 1, Weight: 140, Repetitions: 3, Sets: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3", removalFilter.Filter["WorkoutExerciseId"].From)
+	assert.Equal(t, []int{11, 12}, deleted)
+	assert.Equal(t, 140.0, recordTypes(*created)[models.MaxWeightRecord].Value)
+	assert.Equal(t, 4, len(response.PersonalRecords))
+}
+
+func TestCreateExerciseSet_DetectsRecordsOfTheSet(t *testing.T) {
+	exerciseId := 9
+	repo, created := recordRepoWith(models.PersonalRecord{Id: 1, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 100})
+	workoutExercises := &MockWorkoutExerciseRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+			// The planned values of the workout exercise are lighter than the set
+			return models.WorkoutExercise{Id: id, WorkoutId: 1, ExerciseId: &exerciseId, Name: "Bench Press", Repetitions: 5, Sets: 3, Weight: 80}, nil
+		},
+	}
+	setRepo := &MockExerciseSetRepository{
+		CreateFn: func(ctx context.Context, entity models.ExerciseSet) (models.ExerciseSet, error) {
+			entity.Id = 21
+			return entity, nil
+		},
+	}
+	useCase := usecase.NewExerciseSetUsecase(&config.Config{}, setRepo, workoutExercises, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 3, SetIndex: 1, Repetitions: 2, Weight: 110})
+
+	assert.NoError(t, err)
+	maxWeight := recordTypes(*created)[models.MaxWeightRecord]
+	assert.Equal(t, 110.0, maxWeight.Value)
+	assert.Equal(t, 2, maxWeight.Repetitions)
+	assert.Equal(t, 21, *maxWeight.ExerciseSetId)
+	assert.Equal(t, 3, maxWeight.WorkoutExerciseId)
+	// A single set sets no volume record
+	_, ok := recordTypes(*created)[models.MaxVolumeRecord]
+	assert.False(t, ok)
+	assert.Equal(t, 3, len(response.PersonalRecords))
+}
+
+func TestCreateExerciseSet_WarmupSetsNoRecords(t *testing.T) {
+	exerciseId := 9
+	repo, created := recordRepoWith()
+	workoutExercises := &MockWorkoutExerciseRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+			return models.WorkoutExercise{Id: id, WorkoutId: 1, ExerciseId: &exerciseId}, nil
+		},
+	}
+	useCase := usecase.NewExerciseSetUsecase(&config.Config{}, &MockExerciseSetRepository{}, workoutExercises, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+
+	response, err := useCase.Create(createContextWithUserId(1), usecaseDto.CreateExerciseSetRequest{WorkoutExerciseId: 3, SetIndex: 1, Repetitions: 10, Weight: 40, SetType: models.WarmupSet})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(*created))
+	assert.Equal(t, 0, len(response.PersonalRecords))
+}
+
+func TestDeleteExerciseSet_RemovesItsRecords(t *testing.T) {
+	var removalFilter filter.PaginationInputWithFilter
+	deleted := []int{}
+	repo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			removalFilter = req
+			records := []models.PersonalRecord{{Id: 11}}
+			return 1, &records, nil
+		},
+		DeleteFn: func(ctx context.Context, id int) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	setRepo := &MockExerciseSetRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.ExerciseSet, error) {
+			return models.ExerciseSet{Id: id, WorkoutExerciseId: 3}, nil
+		},
+	}
+	useCase := usecase.NewExerciseSetUsecase(&config.Config{}, setRepo, &MockWorkoutExerciseRepository{}, &MockWorkoutRepository{}, &MockExerciseRepository{}, repo, &MockNotifier{})
+
+	err := useCase.Delete(createContextWithUserId(1), 3, 21)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "21", removalFilter.Filter["ExerciseSetId"].From)
+	assert.Equal(t, []int{11}, deleted)
+}
+
+func TestLogWorkoutSessionSet_DetectsRecordsOfTheSet(t *testing.T) {
+	exerciseId := 9
+	repo, created := recordRepoWith(models.PersonalRecord{Id: 1, ExerciseId: 9, RecordType: models.MaxRepsRecord, Weight: 60, Value: 8})
+	achievements := make(chan models.Achievement, 1)
+	mocks := newWorkoutSessionMocks()
+	mocks.exercises.GetByIdFn = func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+		return models.WorkoutExercise{Id: id, WorkoutId: 1, ExerciseId: &exerciseId, Name: "Bench Press", Repetitions: 8, Sets: 3, Weight: 60}, nil
+	}
+	mocks.sets.GetByFilterFn = func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSessionSet, error) {
+		return 0, &[]models.WorkoutSessionSet{}, nil
+	}
+	notifier := &MockNotifier{
+		NotifyAchievementFn: func(ctx context.Context, achievement models.Achievement) error {
+			achievements <- achievement
+			return nil
+		},
+	}
+	useCase := usecase.NewWorkoutSessionUsecase(&config.Config{}, mocks.sessions, mocks.sets, mocks.workouts, mocks.exercises, mocks.scheduled, &MockExerciseRepository{}, repo, notifier, &MockTransactor{})
+
+	// The set beats the planned 8 reps at 60
+	response, err := useCase.LogSet(createContextWithUserId(1), usecaseDto.CreateWorkoutSessionSetRequest{WorkoutSessionId: 1, WorkoutExerciseId: 3, Repetitions: 10, Weight: 60})
+
+	assert.NoError(t, err)
+	maxReps := recordTypes(*created)[models.MaxRepsRecord]
+	assert.Equal(t, 10.0, maxReps.Value)
+	assert.Equal(t, 8.0, *maxReps.PreviousValue)
+	assert.Equal(t, 1, *maxReps.WorkoutSessionId)
+	assert.Equal(t, 3, len(response.PersonalRecords))
+	select {
+	case achievement := <-achievements:
+		assert.Equal(t, "Bench Press", achievement.ExerciseName)
+		assert.Equal(t, 3, achievement.WorkoutExerciseId)
+	case <-time.After(time.Second):
+		t.Fatal("the user should be notified of the broken records")
+	}
+}
+
+// ==================== PERSONAL RECORD HANDLER TESTS ====================
+
+func TestGetExercisePersonalRecords_Handler_Success(t *testing.T) {
+	repo, _ := recordRepoWith(
+		models.PersonalRecord{Id: 2, ExerciseId: 1, RecordType: models.MaxWeightRecord, Value: 110},
+		models.PersonalRecord{Id: 1, ExerciseId: 1, RecordType: models.MaxWeightRecord, Value: 100},
+	)
+	recordHandler := &handler.PersonalRecordHandler{Usecase: usecase.NewPersonalRecordUsecase(&config.Config{}, repo, &MockExerciseRepository{})}
+	c, w := createAuthenticatedGinContextWithParams("GET", "/v1/workouts/records/1", nil, gin.Params{{Key: "exercise", Value: "1"}}, &MockTokenProvider{}, &config.Config{})
+
+	recordHandler.GetByExercise(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Result dto.ExerciseRecordsResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Barbell Bench Press", response.Result.ExerciseName)
+	assert.Equal(t, 1, len(response.Result.Records))
+	assert.Equal(t, 110.0, response.Result.Records[0].Value)
+	assert.Equal(t, 2, len(response.Result.History))
+}
+
+func TestGetExercisePersonalRecords_Handler_InvalidExercise(t *testing.T) {
+	recordHandler := &handler.PersonalRecordHandler{Usecase: usecase.NewPersonalRecordUsecase(&config.Config{}, &MockPersonalRecordRepository{}, &MockExerciseRepository{})}
+	c, w := createAuthenticatedGinContextWithParams("GET", "/v1/workouts/records/bench", nil, gin.Params{{Key: "exercise", Value: "bench"}}, &MockTokenProvider{}, &config.Config{})
+
+	recordHandler.GetByExercise(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,477 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+)
+
+func at(year int, month time.Month, day int, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// ==================== SCHEDULE RULE TESTS ====================
+
+func TestScheduleRule_WeeklyKeepsWallClockOverDaylightSaving(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	rule := models.ScheduleRule{
+		Frequency: models.WeeklyFrequency,
+		ByDay:     "FR,MO",
+		StartTime: time.Date(2024, 3, 25, 7, 0, 0, 0, berlin),
+		TimeZone:  "Europe/Berlin",
+	}
+
+	occurrences := rule.Occurrences(at(2024, 3, 1, 0), at(2024, 4, 3, 0))
+
+	assert.Equal(t, 3, len(occurrences))
+	assert.Equal(t, at(2024, 3, 25, 6), occurrences[0].UTC())
+	assert.Equal(t, at(2024, 3, 29, 6), occurrences[1].UTC())
+	// Summer time started on the 31st
+	assert.Equal(t, at(2024, 4, 1, 5), occurrences[2].UTC())
+}
+
+func TestScheduleRule_CountIncludesExceptions(t *testing.T) {
+	count := 5
+	rule := models.ScheduleRule{
+		Frequency:  models.DailyFrequency,
+		StartTime:  at(2024, 3, 4, 7),
+		TimeZone:   "UTC",
+		Count:      &count,
+		Exceptions: models.JoinExceptions([]time.Time{at(2024, 3, 5, 7)}),
+	}
+
+	occurrences := rule.Occurrences(at(2024, 3, 1, 0), at(2024, 4, 1, 0))
+
+	assert.Equal(t, 4, len(occurrences))
+	assert.Equal(t, at(2024, 3, 8, 7), occurrences[3])
+	assert.Equal(t, 1, rule.CountBefore(at(2024, 3, 5, 7)))
+}
+
+func TestScheduleRule_MonthlySkipsShortMonths(t *testing.T) {
+	rule := models.ScheduleRule{Frequency: models.MonthlyFrequency, StartTime: at(2024, 1, 31, 18), TimeZone: "UTC"}
+
+	occurrences := rule.Occurrences(at(2024, 1, 1, 0), at(2024, 7, 1, 0))
+
+	assert.Equal(t, []time.Time{at(2024, 1, 31, 18), at(2024, 3, 31, 18), at(2024, 5, 31, 18)}, utc(occurrences))
+}
+
+func TestScheduleRule_DailyByDayAndUntil(t *testing.T) {
+	until := at(2024, 3, 12, 7)
+	rule := models.ScheduleRule{
+		Frequency: models.DailyFrequency,
+		Interval:  1,
+		ByDay:     "MO,TU,WE,TH,FR",
+		StartTime: at(2024, 3, 8, 7),
+		TimeZone:  "UTC",
+		Until:     &until,
+	}
+
+	occurrences := rule.Occurrences(at(2024, 3, 1, 0), at(2024, 4, 1, 0))
+
+	// The weekend is skipped and until is included
+	assert.Equal(t, []time.Time{at(2024, 3, 8, 7), at(2024, 3, 11, 7), at(2024, 3, 12, 7)}, utc(occurrences))
+}
+
+func TestScheduleRule_WeeklyInterval(t *testing.T) {
+	rule := models.ScheduleRule{Frequency: models.WeeklyFrequency, Interval: 2, ByDay: "TU,SA", StartTime: at(2024, 3, 6, 7), TimeZone: "UTC"}
+
+	occurrences := rule.Occurrences(at(2024, 3, 1, 0), at(2024, 3, 25, 0))
+
+	// The Tuesday of the first week is before the start
+	assert.Equal(t, []time.Time{at(2024, 3, 9, 7), at(2024, 3, 19, 7), at(2024, 3, 23, 7)}, utc(occurrences))
+}
+
+func utc(times []time.Time) []time.Time {
+	converted := []time.Time{}
+	for _, t := range times {
+		converted = append(converted, t.UTC())
+	}
+	return converted
+}
+
+// ==================== RECURRING SCHEDULED WORKOUT TESTS ====================
+
+// scheduleStore keeps the rules and the scheduled workouts of the mocks in memory, a failed transaction
+// restores them. createRuleErr fails the creation of the rules and deleteRowErr the deletion of the scheduled workouts.
+type scheduleStore struct {
+	rules         map[int]models.ScheduleRule
+	rows          map[int]models.ScheduledWorkouts
+	nextId        int
+	createRuleErr error
+	deleteRowErr  error
+}
+
+func newScheduleStore() *scheduleStore {
+	return &scheduleStore{rules: map[int]models.ScheduleRule{}, rows: map[int]models.ScheduledWorkouts{}}
+}
+
+func (s *scheduleStore) usecase() *usecase.ScheduledWorkoutsUseCase {
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		CreateFn: func(ctx context.Context, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+			s.nextId++
+			entity.Id = s.nextId
+			s.rows[entity.Id] = entity
+			return entity, nil
+		},
+		UpdateFn: func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+			row := s.rows[id]
+			if !entity.ScheduledTime.IsZero() {
+				row.ScheduledTime = entity.ScheduledTime
+			}
+			if entity.Status != "" {
+				row.Status = entity.Status
+			}
+			if entity.ScheduleRuleId != nil {
+				row.ScheduleRuleId = entity.ScheduleRuleId
+			}
+			if entity.OccurrenceTime != nil {
+				row.OccurrenceTime = entity.OccurrenceTime
+			}
+			s.rows[id] = row
+			return row, nil
+		},
+		DeleteFn: func(ctx context.Context, id int) error {
+			if s.deleteRowErr != nil {
+				return s.deleteRowErr
+			}
+			delete(s.rows, id)
+			return nil
+		},
+		GetByIdFn: func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
+			row, ok := s.rows[id]
+			if !ok {
+				return row, service_errors.New(service_errors.CodeRecordNotFound)
+			}
+			return row, nil
+		},
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			rows := []models.ScheduledWorkouts{}
+			for _, row := range s.rows {
+				if ruleId, ok := req.Filter["ScheduleRuleId"]; ok && (row.ScheduleRuleId == nil || strconv.Itoa(*row.ScheduleRuleId) != ruleId.From) {
+					continue
+				}
+				if occurrence, ok := req.Filter["OccurrenceTime"]; ok {
+					from, _ := time.Parse(time.RFC3339Nano, occurrence.From)
+					if row.OccurrenceTime.Before(from) {
+						continue
+					}
+					to, err := time.Parse(time.RFC3339Nano, occurrence.To)
+					if err == nil && row.OccurrenceTime.After(to) {
+						continue
+					}
+				}
+				rows = append(rows, row)
+			}
+			sort.Slice(rows, func(i, j int) bool { return rows[i].OccurrenceTime.Before(*rows[j].OccurrenceTime) })
+			return int64(len(rows)), &rows, nil
+		},
+	}
+	ruleRepo := &MockScheduleRuleRepository{
+		CreateFn: func(ctx context.Context, entity models.ScheduleRule) (models.ScheduleRule, error) {
+			if s.createRuleErr != nil {
+				return entity, s.createRuleErr
+			}
+			s.nextId++
+			entity.Id = s.nextId
+			s.rules[entity.Id] = entity
+			return entity, nil
+		},
+		UpdateFn: func(ctx context.Context, id int, entity models.ScheduleRule) (models.ScheduleRule, error) {
+			rule := s.rules[id]
+			if !entity.StartTime.IsZero() {
+				rule.StartTime = entity.StartTime
+			}
+			if entity.ByDay != "" {
+				rule.ByDay = entity.ByDay
+			}
+			if entity.Exceptions != "" {
+				rule.Exceptions = entity.Exceptions
+			}
+			if entity.Count != nil {
+				rule.Count = entity.Count
+			}
+			if entity.Until != nil {
+				rule.Until = entity.Until
+			}
+			s.rules[id] = rule
+			return entity, nil
+		},
+		DeleteFn: func(ctx context.Context, id int) error {
+			delete(s.rules, id)
+			return nil
+		},
+		GetByIdFn: func(ctx context.Context, id int) (models.ScheduleRule, error) {
+			rule, ok := s.rules[id]
+			if !ok {
+				return rule, service_errors.New(service_errors.CodeRecordNotFound)
+			}
+			return rule, nil
+		},
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduleRule, error) {
+			rules := []models.ScheduleRule{}
+			for _, rule := range s.rules {
+				rules = append(rules, rule)
+			}
+			return int64(len(rules)), &rules, nil
+		},
+	}
+	transactor := &MockTransactor{
+		WithinTransactionFn: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			rules, rows := maps.Clone(s.rules), maps.Clone(s.rows)
+			if err := fn(ctx); err != nil {
+				s.rules, s.rows = rules, rows
+				return err
+			}
+			return nil
+		},
+	}
+	return usecase.NewScheduledWorkoutsUsecase(&config.Config{}, scheduledRepo, &MockWorkoutRepository{}, ruleRepo, transactor)
+}
+
+// sorted lists the scheduled workouts of the store by scheduled time
+func (s *scheduleStore) sorted() []models.ScheduledWorkouts {
+	rows := []models.ScheduledWorkouts{}
+	for _, row := range s.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ScheduledTime.Before(rows[j].ScheduledTime) })
+	return rows
+}
+
+// createWeekly schedules a workout on Monday, Wednesday and Friday at 7 from Sunday the 3rd of March 2024
+// and materializes the occurrences of the two following weeks
+func createWeekly(t *testing.T, s *scheduleStore, count *int) dto.ScheduledWorkoutsResponse {
+	useCase := s.usecase()
+	ctx := createContextWithUserId(1)
+	first, err := useCase.Create(ctx, dto.CreateScheduledWorkoutsRequest{
+		WorkoutId:     1,
+		ScheduledTime: at(2024, 3, 3, 7),
+		Status:        "planned",
+		Recurrence:    &dto.RecurrenceRequest{Frequency: models.WeeklyFrequency, ByDay: []string{"mo", "WE", "FR"}, Count: count},
+	})
+	assert.NoError(t, err)
+	_, err = useCase.Expand(ctx, dto.ExpandScheduledWorkoutsRequest{From: at(2024, 3, 1, 0), To: at(2024, 3, 16, 0)})
+	assert.NoError(t, err)
+	return first
+}
+
+func TestCreateScheduledWorkout_Recurring_FirstOccurrence(t *testing.T) {
+	store := newScheduleStore()
+
+	first := createWeekly(t, store, nil)
+
+	assert.Equal(t, at(2024, 3, 4, 7), *first.OccurrenceTime)
+	rule := store.rules[*first.ScheduleRuleId]
+	assert.Equal(t, "MO,WE,FR", rule.ByDay)
+	assert.Equal(t, "UTC", rule.TimeZone)
+	assert.Equal(t, 1, rule.Interval)
+}
+
+func TestCreateScheduledWorkout_Recurring_Invalid(t *testing.T) {
+	count := 3
+	until := at(2024, 4, 1, 0)
+	tests := map[string]dto.RecurrenceRequest{
+		"count and until": {Frequency: models.DailyFrequency, Count: &count, Until: &until},
+		"monthly by day":  {Frequency: models.MonthlyFrequency, ByDay: []string{"MO"}},
+		"time zone":       {Frequency: models.DailyFrequency, TimeZone: "Mars/Olympus"},
+		"weekday":         {Frequency: models.WeeklyFrequency, ByDay: []string{"XX"}},
+		"frequency":       {Frequency: "yearly"},
+	}
+	for name, recurrence := range tests {
+		t.Run(name, func(t *testing.T) {
+			useCase := newScheduleStore().usecase()
+
+			_, err := useCase.Create(createContextWithUserId(1), dto.CreateScheduledWorkoutsRequest{
+				WorkoutId: 1, ScheduledTime: at(2024, 3, 3, 7), Status: "planned", Recurrence: &recurrence,
+			})
+
+			assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+		})
+	}
+}
+
+func TestExpandScheduledWorkouts_MaterializesOnce(t *testing.T) {
+	store := newScheduleStore()
+	createWeekly(t, store, nil)
+	assert.Equal(t, 6, len(store.rows))
+
+	occurrences, err := store.usecase().Expand(createContextWithUserId(1),
+		dto.ExpandScheduledWorkoutsRequest{From: at(2024, 3, 6, 0), To: at(2024, 3, 9, 0)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(store.rows))
+	assert.Equal(t, 2, len(occurrences))
+	assert.Equal(t, at(2024, 3, 6, 7).Format(time.RFC3339), occurrences[0].ScheduledTime)
+}
+
+func TestExpandScheduledWorkouts_WindowTooLong(t *testing.T) {
+	useCase := newScheduleStore().usecase()
+
+	_, err := useCase.Expand(createContextWithUserId(1), dto.ExpandScheduledWorkoutsRequest{From: at(2024, 1, 1, 0), To: at(2025, 6, 1, 0)})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+}
+
+func TestDeleteScheduledWorkout_Occurrence_IsNotMaterializedAgain(t *testing.T) {
+	store := newScheduleStore()
+	createWeekly(t, store, nil)
+	useCase := store.usecase()
+	wednesday := store.sorted()[1]
+
+	err := useCase.Delete(createContextWithUserId(1), wednesday.Id)
+	assert.NoError(t, err)
+	_, err = useCase.Expand(createContextWithUserId(1), dto.ExpandScheduledWorkoutsRequest{From: at(2024, 3, 1, 0), To: at(2024, 3, 16, 0)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(store.rows))
+	_, ok := store.rows[wednesday.Id]
+	assert.False(t, ok)
+}
+
+func TestUpdateScheduledWorkout_ThisOccurrence(t *testing.T) {
+	store := newScheduleStore()
+	createWeekly(t, store, nil)
+	wednesday := store.sorted()[1]
+
+	_, err := store.usecase().Update(createContextWithUserId(1), wednesday.Id,
+		dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 6, 9), Status: "planned"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, at(2024, 3, 6, 9), store.rows[wednesday.Id].ScheduledTime)
+	assert.Equal(t, at(2024, 3, 6, 7), *store.rows[wednesday.Id].OccurrenceTime)
+	assert.Equal(t, at(2024, 3, 8, 7), store.sorted()[2].ScheduledTime)
+}
+
+func TestUpdateScheduledWorkout_FollowingOccurrences_SplitsRule(t *testing.T) {
+	store := newScheduleStore()
+	first := createWeekly(t, store, nil)
+	rows := store.sorted()
+	monday, friday := rows[3], rows[5]
+	store.rows[friday.Id] = models.ScheduledWorkouts{
+		Id: friday.Id, WorkoutId: 1, ScheduledTime: friday.ScheduledTime, Status: "completed",
+		ScheduleRuleId: friday.ScheduleRuleId, OccurrenceTime: friday.OccurrenceTime,
+	}
+
+	// The second Monday moves to Tuesday at 8 with the rest of the series
+	response, err := store.usecase().Update(createContextWithUserId(1), monday.Id,
+		dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 12, 8), Status: "planned", Scope: usecase.FollowingOccurrences})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, *first.ScheduleRuleId, *response.ScheduleRuleId)
+	assert.Equal(t, at(2024, 3, 11, 7).Add(-time.Second), *store.rules[*first.ScheduleRuleId].Until)
+	next := store.rules[*response.ScheduleRuleId]
+	assert.Equal(t, "TU,TH,SA", next.ByDay)
+	assert.Equal(t, at(2024, 3, 12, 8), next.StartTime)
+
+	rows = store.sorted()
+	assert.Equal(t, at(2024, 3, 8, 7), rows[2].ScheduledTime)
+	assert.Equal(t, *first.ScheduleRuleId, *rows[2].ScheduleRuleId)
+	assert.Equal(t, at(2024, 3, 12, 8), rows[3].ScheduledTime)
+	assert.Equal(t, at(2024, 3, 14, 8), rows[4].ScheduledTime)
+	// The completed occurrence keeps its time
+	assert.Equal(t, at(2024, 3, 15, 7), store.rows[friday.Id].ScheduledTime)
+	assert.Equal(t, at(2024, 3, 16, 8), *store.rows[friday.Id].OccurrenceTime)
+}
+
+func TestUpdateScheduledWorkout_FollowingOccurrences_FailureKeepsTheRule(t *testing.T) {
+	store := newScheduleStore()
+	first := createWeekly(t, store, nil)
+	before := store.sorted()
+	store.createRuleErr = errors.New("connection reset")
+
+	_, err := store.usecase().Update(createContextWithUserId(1), before[3].Id,
+		dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 12, 8), Status: "planned", Scope: usecase.FollowingOccurrences})
+
+	assert.Error(t, err)
+	// The rule is not ended when the following one can not be created
+	assert.Zero(t, store.rules[*first.ScheduleRuleId].Until)
+	assert.Equal(t, before, store.sorted())
+}
+
+func TestUpdateScheduledWorkout_AllOccurrences(t *testing.T) {
+	store := newScheduleStore()
+	first := createWeekly(t, store, nil)
+
+	_, err := store.usecase().Update(createContextWithUserId(1), store.sorted()[4].Id,
+		dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 13, 6), Status: "planned", Scope: usecase.AllOccurrences})
+
+	assert.NoError(t, err)
+	assert.Equal(t, at(2024, 3, 3, 6), store.rules[*first.ScheduleRuleId].StartTime)
+	for _, row := range store.sorted() {
+		assert.Equal(t, 6, row.ScheduledTime.Hour())
+		assert.Equal(t, *first.ScheduleRuleId, *row.ScheduleRuleId)
+	}
+	assert.Equal(t, 6, len(store.rows))
+}
+
+func TestUpdateScheduledWorkout_Scope_NotRecurring(t *testing.T) {
+	useCase := setupScheduledWorkoutUsecase(&MockScheduledWorkoutsRepository{}, &MockWorkoutRepository{})
+
+	_, err := useCase.Update(createContextWithUserId(1), 1,
+		dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 13, 6), Status: "planned", Scope: usecase.AllOccurrences})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+}
+
+func TestDeleteScheduledWorkout_FollowingOccurrences_EndsCount(t *testing.T) {
+	store := newScheduleStore()
+	count := 6
+	first := createWeekly(t, store, &count)
+	fourth := store.sorted()[3]
+
+	err := store.usecase().DeleteOccurrences(createContextWithUserId(1), fourth.Id, usecase.FollowingOccurrences)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, *store.rules[*first.ScheduleRuleId].Count)
+	assert.Equal(t, 3, len(store.rows))
+}
+
+func TestDeleteScheduledWorkout_FollowingOccurrences_FailureKeepsTheRule(t *testing.T) {
+	store := newScheduleStore()
+	count := 6
+	first := createWeekly(t, store, &count)
+	store.deleteRowErr = errors.New("connection reset")
+
+	err := store.usecase().DeleteOccurrences(createContextWithUserId(1), store.sorted()[3].Id, usecase.FollowingOccurrences)
+
+	assert.Error(t, err)
+	// The count of the rule is not cut when its occurrences can not be deleted
+	assert.Equal(t, 6, *store.rules[*first.ScheduleRuleId].Count)
+	assert.Equal(t, 6, len(store.rows))
+}
+
+func TestDeleteScheduledWorkout_AllOccurrences(t *testing.T) {
+	store := newScheduleStore()
+	first := createWeekly(t, store, nil)
+
+	err := store.usecase().DeleteOccurrences(createContextWithUserId(1), store.sorted()[2].Id, usecase.AllOccurrences)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(store.rows))
+	_, ok := store.rules[*first.ScheduleRuleId]
+	assert.False(t, ok)
+}
+
+func TestExpandScheduledWorkouts_Handler_MissingWindow(t *testing.T) {
+	scheduledHandler := &handler.ScheduledWorkoutsHandler{Usecase: newScheduleStore().usecase()}
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/scheduled-workouts/expand",
+		[]byte(`{"from":"2024-03-01T00:00:00Z"}`), &MockTokenProvider{}, &config.Config{})
+
+	scheduledHandler.Expand(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/pkg/db"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestTranslateError_GormErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		code   service_errors.ErrorCode
+		status int
+	}{
+		{gorm.ErrRecordNotFound, service_errors.CodeRecordNotFound, http.StatusNotFound},
+		{fmt.Errorf("first: %w", gorm.ErrRecordNotFound), service_errors.CodeRecordNotFound, http.StatusNotFound},
+		{gorm.ErrDuplicatedKey, service_errors.CodeRecordExists, http.StatusConflict},
+		{gorm.ErrForeignKeyViolated, service_errors.CodeInvalidReference, http.StatusUnprocessableEntity},
+		{gorm.ErrCheckConstraintViolated, service_errors.CodeConstraintViolation, http.StatusUnprocessableEntity},
+	}
+	for _, test := range tests {
+		err := db.TranslateError(test.err)
+
+		assert.True(t, service_errors.HasCode(err, test.code))
+		assert.Equal(t, test.status, helper.TranslateErrorToStatusCode(err))
+		assert.True(t, errors.Is(err, test.err))
+	}
+}
+
+func TestTranslateError_PostgresErrors(t *testing.T) {
+	tests := []struct {
+		pgCode string
+		code   service_errors.ErrorCode
+		status int
+	}{
+		{"23505", service_errors.CodeRecordExists, http.StatusConflict},
+		{"23503", service_errors.CodeInvalidReference, http.StatusUnprocessableEntity},
+		{"23514", service_errors.CodeConstraintViolation, http.StatusUnprocessableEntity},
+		{"40001", service_errors.CodeConcurrentUpdate, http.StatusConflict},
+		{"40P01", service_errors.CodeConcurrentUpdate, http.StatusConflict},
+	}
+	for _, test := range tests {
+		pgErr := &pgconn.PgError{Code: test.pgCode, ConstraintName: "fk_workout_exercises_workout"}
+
+		err := db.TranslateError(fmt.Errorf("insert: %w", pgErr))
+
+		assert.True(t, service_errors.HasCode(err, test.code))
+		assert.Equal(t, test.status, helper.TranslateErrorToStatusCode(err))
+		var serviceError *service_errors.ServiceError
+		assert.True(t, errors.As(err, &serviceError))
+		assert.Equal(t, "fk_workout_exercises_workout", serviceError.TechnicalMessage)
+	}
+}
+
+func TestTranslateError_OtherErrorsUnchanged(t *testing.T) {
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, db.TranslateError(plain))
+
+	pgErr := &pgconn.PgError{Code: "42P01"}
+	assert.Equal(t, error(pgErr), db.TranslateError(pgErr))
+	assert.Equal(t, http.StatusInternalServerError, helper.TranslateErrorToStatusCode(db.TranslateError(pgErr)))
+
+	assert.True(t, db.TranslateError(nil) == nil)
+}
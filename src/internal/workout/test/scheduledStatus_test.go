@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// ==================== SCHEDULED WORKOUT STATUS TESTS ====================
+
+func TestScheduledStatus_Transitions(t *testing.T) {
+	allowed := map[models.ScheduledStatus][]models.ScheduledStatus{
+		models.ScheduledPlanned:    {models.ScheduledInProgress, models.ScheduledCompleted, models.ScheduledSkipped, models.ScheduledCancelled, models.ScheduledMissed},
+		models.ScheduledInProgress: {models.ScheduledCompleted, models.ScheduledSkipped, models.ScheduledCancelled},
+		models.ScheduledMissed:     {models.ScheduledPlanned, models.ScheduledInProgress, models.ScheduledCompleted, models.ScheduledSkipped, models.ScheduledCancelled},
+	}
+	for _, from := range models.ScheduledStatuses {
+		for _, to := range models.ScheduledStatuses {
+			expected := false
+			for _, status := range allowed[from] {
+				expected = expected || status == to
+			}
+			assert.Equal(t, expected, from.CanTransitionTo(to), "%s to %s", from, to)
+		}
+		assert.Equal(t, len(allowed[from]) == 0, from.IsFinal(), "%s is final", from)
+	}
+	assert.False(t, models.ScheduledStatus("active").IsValid())
+}
+
+// statusStore holds a scheduled workout with a status
+func statusStore(status models.ScheduledStatus) *scheduleStore {
+	store := newScheduleStore()
+	store.nextId = 1
+	store.rows[1] = models.ScheduledWorkouts{Id: 1, WorkoutId: 1, ScheduledTime: at(2024, 3, 4, 7), Status: status}
+	return store
+}
+
+func TestCompleteScheduledWorkout_Success(t *testing.T) {
+	store := statusStore(models.ScheduledInProgress)
+
+	response, err := store.usecase().Complete(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "completed", response.Status)
+	assert.Equal(t, models.ScheduledCompleted, store.rows[1].Status)
+}
+
+func TestCancelScheduledWorkout_Success(t *testing.T) {
+	store := statusStore(models.ScheduledMissed)
+
+	response, err := store.usecase().Cancel(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", response.Status)
+}
+
+func TestCancelScheduledWorkout_AlreadyCompleted(t *testing.T) {
+	store := statusStore(models.ScheduledCompleted)
+
+	_, err := store.usecase().Cancel(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidStatusTransition))
+	assert.Equal(t, models.ScheduledCompleted, store.rows[1].Status)
+}
+
+func TestRescheduleScheduledWorkout_Missed(t *testing.T) {
+	store := statusStore(models.ScheduledMissed)
+
+	response, err := store.usecase().Reschedule(createContextWithUserId(1), 1, dto.RescheduleScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 6, 18)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "planned", response.Status)
+	assert.Equal(t, at(2024, 3, 6, 18), store.rows[1].ScheduledTime)
+}
+
+func TestRescheduleScheduledWorkout_Cancelled(t *testing.T) {
+	store := statusStore(models.ScheduledCancelled)
+
+	_, err := store.usecase().Reschedule(createContextWithUserId(1), 1, dto.RescheduleScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 6, 18)})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidStatusTransition))
+	assert.Equal(t, at(2024, 3, 4, 7), store.rows[1].ScheduledTime)
+}
+
+func TestUpdateScheduledWorkout_MissedIsAssignedByTheService(t *testing.T) {
+	store := statusStore(models.ScheduledPlanned)
+
+	_, err := store.usecase().Update(createContextWithUserId(1), 1, dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 4, 7), Status: "missed"})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidStatus))
+}
+
+func TestUpdateScheduledWorkout_KeepsStatusWhenEmpty(t *testing.T) {
+	store := statusStore(models.ScheduledSkipped)
+
+	response, err := store.usecase().Update(createContextWithUserId(1), 1, dto.UpdateScheduledWorkoutsRequest{ScheduledTime: at(2024, 3, 5, 7)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "skipped", response.Status)
+}
+
+func TestCompleteScheduledWorkout_Handler_Conflict(t *testing.T) {
+	store := statusStore(models.ScheduledCancelled)
+	h := &handler.ScheduledWorkoutsHandler{Usecase: store.usecase()}
+
+	params := gin.Params{{Key: "id", Value: "1"}}
+	c, w := createAuthenticatedGinContextWithParams("POST", "/v1/workouts/scheduled-workouts/1/complete", nil, params, &MockTokenProvider{}, &config.Config{})
+	h.Complete(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, helper.ConflictError, response.ResultCode)
+}
+
+func TestStartWorkoutSession_MissedSchedule(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.scheduled.GetByIdFn = func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
+		return models.ScheduledWorkouts{Id: id, WorkoutId: 1, Status: models.ScheduledMissed}, nil
+	}
+	scheduledWorkoutId := 5
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), dto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.NoError(t, err)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 	"github.com/gin-gonic/gin"
@@ -21,7 +22,7 @@ import (
 func setupScheduledWorkoutHandler(scheduledRepo *MockScheduledWorkoutsRepository, workoutRepo *MockWorkoutRepository) (*handler.ScheduledWorkoutsHandler, *MockTokenProvider, *config.Config) {
 	cfg := &config.Config{}
 	tokenProvider := &MockTokenProvider{}
-	useCase := usecase.NewScheduledWorkoutsUsecase(cfg, scheduledRepo, workoutRepo)
+	useCase := usecase.NewScheduledWorkoutsUsecase(cfg, scheduledRepo, workoutRepo, &MockScheduleRuleRepository{}, &MockTransactor{})
 	return &handler.ScheduledWorkoutsHandler{
 		Usecase: useCase,
 	}, tokenProvider, cfg
@@ -35,7 +36,6 @@ func TestCreateScheduledWorkout_Handler_Success(t *testing.T) {
 	requestBody := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -57,11 +57,8 @@ func TestCreateScheduledWorkout_Handler_ValidationError(t *testing.T) {
 	workoutRepo := &MockWorkoutRepository{}
 	handler, tokenProvider, cfg := setupScheduledWorkoutHandler(scheduledRepo, workoutRepo)
 
-	// Invalid request body - missing required fields
-	requestBody := dto.CreateScheduledWorkoutsRequest{
-		Status: "active",
-		// Missing WorkoutId and ScheduledTime
-	}
+	// Invalid request body - missing WorkoutId and ScheduledTime
+	requestBody := dto.CreateScheduledWorkoutsRequest{}
 
 	jsonBody, _ := json.Marshal(requestBody)
 	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/scheduled-workouts/", jsonBody, tokenProvider, cfg)
@@ -79,31 +76,31 @@ func TestCreateScheduledWorkout_Handler_ValidationError(t *testing.T) {
 
 }
 
-func TestCreateScheduledWorkout_Handler_InvalidStatus(t *testing.T) {
+func TestUpdateScheduledWorkout_Handler_InvalidStatus(t *testing.T) {
 	scheduledRepo := &MockScheduledWorkoutsRepository{}
 	workoutRepo := &MockWorkoutRepository{}
 	handler, tokenProvider, cfg := setupScheduledWorkoutHandler(scheduledRepo, workoutRepo)
 
-	requestBody := dto.CreateScheduledWorkoutsRequest{
-		WorkoutId:     1,
+	params := gin.Params{{Key: "id", Value: "1"}}
+	requestBody := dto.UpdateScheduledWorkoutsRequest{
 		ScheduledTime: time.Now(),
 		Status:        "invalid_status",
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
-	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/scheduled-workouts/", jsonBody, tokenProvider, cfg)
+	c, w := createAuthenticatedGinContextWithParams("PUT", "/v1/workouts/scheduled-workouts/1", jsonBody, params, tokenProvider, cfg)
 
 	// Set up the route and call the handler
-	c.Request.URL.Path = "/v1/workouts/scheduled-workouts/"
-	handler.Create(c)
+	c.Request.URL.Path = "/v1/workouts/scheduled-workouts/1"
+	handler.Update(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
 	var response helper.BaseHttpResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, false, response.Success)
-	assert.Equal(t, helper.InternalError, response.ResultCode)
-	assert.Equal(t, "invalid status. Status must be 'active' or 'completed' or 'canceled'", response.Error)
+	assert.Equal(t, helper.ValidationError, response.ResultCode)
+	assert.Equal(t, "invalid status. Status must be 'planned', 'in_progress', 'completed', 'skipped' or 'cancelled'", response.Error)
 
 }
 
@@ -123,7 +120,6 @@ func TestCreateScheduledWorkout_Handler_UnauthorizedUser(t *testing.T) {
 	requestBody := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
 	}
 
 	jsonBody, _ := json.Marshal(requestBody)
@@ -132,12 +128,12 @@ func TestCreateScheduledWorkout_Handler_UnauthorizedUser(t *testing.T) {
 	// Set up the route and call the handler
 	c.Request.URL.Path = "/v1/workouts/scheduled-workouts/"
 	handler.Create(c)
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
 	var response helper.BaseHttpResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Equal(t, false, response.Success)
-	assert.Equal(t, helper.InternalError, response.ResultCode)
+	assert.Equal(t, helper.ForbiddenError, response.ResultCode)
 	assert.Equal(t, "user is not the owner of this workout", response.Error)
 }
 
@@ -185,7 +181,7 @@ func TestGetScheduledWorkoutById_Handler_InvalidId(t *testing.T) {
 func TestGetScheduledWorkoutById_Handler_NotFound(t *testing.T) {
 	scheduledRepo := &MockScheduledWorkoutsRepository{
 		GetByIdFn: func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
-			return models.ScheduledWorkouts{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return models.ScheduledWorkouts{}, service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -215,7 +211,7 @@ func TestUpdateScheduledWorkout_Handler_Success(t *testing.T) {
 	// Use the helper function with parameters
 	params := gin.Params{{Key: "id", Value: "1"}}
 	requestBody := dto.UpdateScheduledWorkoutsRequest{
-		Status:        "active",
+		Status:        "planned",
 		ScheduledTime: time.Now(),
 	}
 	jsonBody, _ := json.Marshal(requestBody)
@@ -235,7 +231,7 @@ func TestUpdateScheduledWorkout_Handler_Success(t *testing.T) {
 func TestUpdateScheduledWorkout_Handler_InvalidId(t *testing.T) {
 	scheduledRepo := &MockScheduledWorkoutsRepository{
 		UpdateFn: func(ctx context.Context, id int, scheduledWorkouts models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
-			return models.ScheduledWorkouts{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return models.ScheduledWorkouts{}, service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -243,7 +239,7 @@ func TestUpdateScheduledWorkout_Handler_InvalidId(t *testing.T) {
 	// Use the helper function with parameters
 	params := gin.Params{{Key: "id", Value: "invalid"}}
 	requestBody := dto.UpdateScheduledWorkoutsRequest{
-		Status:        "active",
+		Status:        "planned",
 		ScheduledTime: time.Now(),
 	}
 	jsonBody, _ := json.Marshal(requestBody)
@@ -309,7 +305,7 @@ func TestDeleteScheduledWorkout_Handler_Success(t *testing.T) {
 func TestDeleteScheduledWorkout_Handler_NotFound(t *testing.T) {
 	scheduledRepo := &MockScheduledWorkoutsRepository{
 		DeleteFn: func(ctx context.Context, id int) error {
-			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -330,3 +326,59 @@ func TestDeleteScheduledWorkout_Handler_NotFound(t *testing.T) {
 	assert.Equal(t, false, response.Success)
 	assert.Equal(t, service_errors.RecordNotFound, response.Error)
 }
+
+func TestGetByFilterScheduledWorkout_Handler_Success(t *testing.T) {
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			assert.Equal(t, 1, req.OwnerId)
+			scheduled := []models.ScheduledWorkouts{
+				{Id: 1, WorkoutId: 1, ScheduledTime: time.Now(), Status: "planned"},
+			}
+			return 1, &scheduled, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	handler, tokenProvider, cfg := setupScheduledWorkoutHandler(scheduledRepo, workoutRepo)
+
+	requestBody := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: 1, PageSize: 10},
+	}
+	jsonBody, _ := json.Marshal(requestBody)
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/scheduled-workouts/get-by-filter", jsonBody, tokenProvider, cfg)
+
+	// Set up the route and call the handler
+	c.Request.URL.Path = "/v1/workouts/scheduled-workouts/get-by-filter"
+	handler.GetByFilter(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, true, response.Success)
+	result, _ := response.Result.(map[string]interface{})
+	assert.Equal(t, float64(1), result["totalRows"].(float64))
+}
+
+func TestGetByFilterScheduledWorkout_Handler_InvalidFilter(t *testing.T) {
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			return 0, nil, service_errors.New(service_errors.CodeInvalidFilter)
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	handler, tokenProvider, cfg := setupScheduledWorkoutHandler(scheduledRepo, workoutRepo)
+
+	jsonBody, _ := json.Marshal(filter.PaginationInputWithFilter{})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/scheduled-workouts/get-by-filter", jsonBody, tokenProvider, cfg)
+
+	// Set up the route and call the handler
+	c.Request.URL.Path = "/v1/workouts/scheduled-workouts/get-by-filter"
+	handler.GetByFilter(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response helper.BaseHttpResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, false, response.Success)
+	assert.Equal(t, service_errors.InvalidFilter, response.Error)
+}
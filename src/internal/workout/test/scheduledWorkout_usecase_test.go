@@ -9,6 +9,7 @@ import (
 	"github.com/alecthomas/assert/v2"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
@@ -21,7 +22,7 @@ func TestCreateScheduledWorkout_Success(t *testing.T) {
 	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
+		Status:        "planned",
 	}
 
 	response, err := useCase.Create(ctx, req)
@@ -29,11 +30,17 @@ func TestCreateScheduledWorkout_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, response.Id)
 	assert.Equal(t, 1, response.WorkoutId)
-	assert.Equal(t, "active", response.Status)
+	assert.Equal(t, "planned", response.Status)
 }
 
-func TestCreateScheduledWorkout_InvalidStatus(t *testing.T) {
-	scheduledRepo := &MockScheduledWorkoutsRepository{}
+func TestCreateScheduledWorkout_StartsPlanned(t *testing.T) {
+	var created models.ScheduledWorkouts
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		CreateFn: func(ctx context.Context, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+			created = entity
+			return entity, nil
+		},
+	}
 	workoutRepo := &MockWorkoutRepository{}
 	useCase := setupScheduledWorkoutUsecase(scheduledRepo, workoutRepo)
 
@@ -41,15 +48,13 @@ func TestCreateScheduledWorkout_InvalidStatus(t *testing.T) {
 	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "invalid_status",
+		Status:        "completed",
 	}
 
 	_, err := useCase.Create(ctx, req)
 
-	assert.Error(t, err)
-	serviceErr, ok := err.(*service_errors.ServiceError)
-	assert.True(t, ok)
-	assert.Equal(t, service_errors.InvalidStatus, serviceErr.EndUserMessage)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ScheduledPlanned, created.Status)
 }
 
 func TestCreateScheduledWorkout_WorkoutNotFound(t *testing.T) {
@@ -65,7 +70,7 @@ func TestCreateScheduledWorkout_WorkoutNotFound(t *testing.T) {
 	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     999,
 		ScheduledTime: time.Now(),
-		Status:        "active",
+		Status:        "planned",
 	}
 
 	_, err := useCase.Create(ctx, req)
@@ -90,7 +95,7 @@ func TestCreateScheduledWorkout_UnauthorizedUser(t *testing.T) {
 	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
+		Status:        "planned",
 	}
 
 	_, err := useCase.Create(ctx, req)
@@ -111,7 +116,7 @@ func TestCreateScheduledWorkout_RepositoryError(t *testing.T) {
 	req := dto.CreateScheduledWorkoutsRequest{
 		WorkoutId:     1,
 		ScheduledTime: time.Now(),
-		Status:        "active",
+		Status:        "planned",
 	}
 
 	_, err := useCase.Create(ctx, req)
@@ -261,7 +266,7 @@ func TestGetScheduledWorkoutById_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 1, response.Id)
 	assert.Equal(t, 1, response.WorkoutId)
-	assert.Equal(t, "active", response.Status)
+	assert.Equal(t, "planned", response.Status)
 }
 
 func TestGetScheduledWorkoutById_NotFound(t *testing.T) {
@@ -301,3 +306,50 @@ func TestGetScheduledWorkoutById_UnauthorizedUser(t *testing.T) {
 	assert.Equal(t, "user is not the owner of this workout", err.Error())
 
 }
+
+func TestGetByFilterScheduledWorkout_ScopedToOwner(t *testing.T) {
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			// Verify that the owner scope was set from the context
+			assert.Equal(t, 5, req.OwnerId)
+			scheduled := []models.ScheduledWorkouts{
+				{Id: 1, WorkoutId: 3, ScheduledTime: time.Now(), Status: "planned"},
+				{Id: 2, WorkoutId: 4, ScheduledTime: time.Now(), Status: "completed"},
+			}
+			return 2, &scheduled, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	useCase := setupScheduledWorkoutUsecase(scheduledRepo, workoutRepo)
+
+	ctx := createContextWithUserId(5)
+	req := filter.PaginationInputWithFilter{}
+
+	response, err := useCase.GetByFilter(ctx, req)
+
+	assert.NoError(t, err)
+	if response == nil {
+		t.Fatal("Expected response to not be nil")
+	}
+	assert.Equal(t, int64(2), response.TotalRows)
+	assert.Equal(t, 1, response.PageNumber)
+	assert.Equal(t, int64(10), response.PageSize)
+	assert.Equal(t, 2, len(*response.Items))
+	assert.Equal(t, "completed", (*response.Items)[1].Status)
+}
+
+func TestGetByFilterScheduledWorkout_NoUserInContext(t *testing.T) {
+	scheduledRepo := &MockScheduledWorkoutsRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.ScheduledWorkouts, error) {
+			t.Fatal("repository should not be called without a user")
+			return 0, nil, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	useCase := setupScheduledWorkoutUsecase(scheduledRepo, workoutRepo)
+
+	_, err := useCase.GetByFilter(context.Background(), filter.PaginationInputWithFilter{})
+
+	assert.Error(t, err)
+	assert.Equal(t, service_errors.UserIdNotFound, err.Error())
+}
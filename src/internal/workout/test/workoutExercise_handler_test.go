@@ -20,7 +20,7 @@ import (
 func setupWorkoutExerciseHandler(exerciseRepo *MockWorkoutExerciseRepository, workoutRepo *MockWorkoutRepository) (*handler.WorkoutExerciseHandler, *MockTokenProvider, *config.Config) {
 	cfg := &config.Config{}
 	tokenProvider := &MockTokenProvider{}
-	useCase := usecase.NewWorkoutExerciseUsecase(cfg, exerciseRepo, workoutRepo)
+	useCase := usecase.NewWorkoutExerciseUsecase(cfg, exerciseRepo, workoutRepo, &MockExerciseRepository{}, &MockPersonalRecordRepository{}, &MockNotifier{})
 	return &handler.WorkoutExerciseHandler{
 		Usecase: useCase,
 	}, tokenProvider, cfg
@@ -159,7 +159,7 @@ func TestGetWorkoutExerciseById_Handler_InvalidId(t *testing.T) {
 func TestGetWorkoutExerciseById_Handler_NotFound(t *testing.T) {
 	exerciseRepo := &MockWorkoutExerciseRepository{
 		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutExercise, error) {
-			return models.WorkoutExercise{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return models.WorkoutExercise{}, service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -284,7 +284,7 @@ func TestDeleteWorkoutExercise_Handler_Success(t *testing.T) {
 func TestDeleteWorkoutExercise_Handler_NotFound(t *testing.T) {
 	exerciseRepo := &MockWorkoutExerciseRepository{
 		DeleteFn: func(ctx context.Context, id int) error {
-			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
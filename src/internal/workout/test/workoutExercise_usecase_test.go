@@ -8,6 +8,7 @@ import (
 	"github.com/alecthomas/assert/v2"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 )
 
 // ==================== WORKOUT EXERCISE USECASE TESTS ====================
@@ -221,3 +222,56 @@ func TestGetWorkoutExerciseById_NotFound(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestGetByFilterWorkoutExercise_ScopedToOwner(t *testing.T) {
+	exerciseRepo := &MockWorkoutExerciseRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutExercise, error) {
+			// Verify that the owner scope was set and user filters are kept
+			assert.Equal(t, 1, req.OwnerId)
+			workoutFilter, exists := req.DynamicFilter.Filter["WorkoutId"]
+			assert.Equal(t, true, exists)
+			assert.Equal(t, "3", workoutFilter.From)
+
+			exercises := []models.WorkoutExercise{
+				{Id: 1, WorkoutId: 3, Name: "Squat", Repetitions: 5, Sets: 5, Weight: 100},
+			}
+			return 1, &exercises, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	useCase := setupWorkoutExerciseUsecase(exerciseRepo, workoutRepo)
+
+	ctx := createContextWithUserId(1)
+	req := filter.PaginationInputWithFilter{
+		PaginationInput: filter.PaginationInput{PageNumber: 1, PageSize: 10},
+		DynamicFilter: filter.DynamicFilter{
+			Filter: map[string]filter.Filter{
+				"WorkoutId": {Type: "equals", From: "3", FilterType: "number"},
+			},
+		},
+	}
+
+	response, err := useCase.GetByFilter(ctx, req)
+
+	assert.NoError(t, err)
+	if response == nil {
+		t.Fatal("Expected response to not be nil")
+	}
+	assert.Equal(t, 1, len(*response.Items))
+	assert.Equal(t, "Squat", (*response.Items)[0].Name)
+}
+
+func TestGetByFilterWorkoutExercise_RepositoryError(t *testing.T) {
+	exerciseRepo := &MockWorkoutExerciseRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutExercise, error) {
+			return 0, nil, errors.New("database error")
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	useCase := setupWorkoutExerciseUsecase(exerciseRepo, workoutRepo)
+
+	_, err := useCase.GetByFilter(createContextWithUserId(1), filter.PaginationInputWithFilter{})
+
+	assert.Error(t, err)
+	assert.Equal(t, "database error", err.Error())
+}
@@ -20,7 +20,7 @@ import (
 func setupWorkoutReportHandler(reportRepo *MockWorkoutReportRepository, workoutRepo *MockWorkoutRepository) (*handler.WorkoutReportHandler, *MockTokenProvider, *config.Config) {
 	cfg := &config.Config{}
 	tokenProvider := &MockTokenProvider{}
-	useCase := usecase.NewWorkoutReportUsecase(cfg, reportRepo, workoutRepo)
+	useCase := usecase.NewWorkoutReportUsecase(cfg, reportRepo, workoutRepo, &MockAnalyticsRepository{}, &MockPersonalRecordRepository{}, &MockExerciseRepository{})
 	return &handler.WorkoutReportHandler{
 		Usecase: useCase,
 	}, tokenProvider, cfg
@@ -100,7 +100,7 @@ func TestCreateWorkoutReport_Handler_UnauthorizedWorkout(t *testing.T) {
 	c.Request.URL.Path = "/v1/workouts/workout-report/"
 	handler.Create(c)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusForbidden, w.Code)
 	var response helper.BaseHttpResponse
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
@@ -149,7 +149,7 @@ func TestGetWorkoutReportById_Handler_InvalidId(t *testing.T) {
 func TestGetWorkoutReportById_Handler_NotFound(t *testing.T) {
 	reportRepo := &MockWorkoutReportRepository{
 		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutReport, error) {
-			return models.WorkoutReport{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return models.WorkoutReport{}, service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -178,8 +178,9 @@ func TestUpdateWorkoutReport_Handler_Success(t *testing.T) {
 
 	// Use the helper function with parameters
 	params := gin.Params{{Key: "id", Value: "1"}}
+	workoutId := 1
 	requestBody := dto.UpdateWorkoutReportRequest{
-		WorkoutId: 1,
+		WorkoutId: &workoutId,
 		Details:   "Updated workout report with additional notes about performance.",
 	}
 	jsonBody, _ := json.Marshal(requestBody)
@@ -196,6 +197,25 @@ func TestUpdateWorkoutReport_Handler_Success(t *testing.T) {
 	assert.Equal(t, true, response.Success)
 }
 
+func TestUpdateWorkoutReport_Handler_WithoutWorkout(t *testing.T) {
+	reportRepo := &MockWorkoutReportRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutReport, error) {
+			return models.WorkoutReport{Id: id, UserId: 1, Details: "Generated"}, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	handler, tokenProvider, cfg := setupWorkoutReportHandler(reportRepo, workoutRepo)
+
+	params := gin.Params{{Key: "id", Value: "1"}}
+	jsonBody := []byte(`{"details":"Edited notes of a generated report"}`)
+	c, w := createAuthenticatedGinContextWithParams("PUT", "/v1/workouts/workout-report/1", jsonBody, params, tokenProvider, cfg)
+
+	c.Request.URL.Path = "/v1/workouts/workout-report/1"
+	handler.Update(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestUpdateWorkoutReport_Handler_InvalidId(t *testing.T) {
 	reportRepo := &MockWorkoutReportRepository{}
 	workoutRepo := &MockWorkoutRepository{}
@@ -203,8 +223,9 @@ func TestUpdateWorkoutReport_Handler_InvalidId(t *testing.T) {
 
 	// Use the helper function with parameters
 	params := gin.Params{{Key: "id", Value: "invalid"}}
+	workoutId := 1
 	requestBody := dto.UpdateWorkoutReportRequest{
-		WorkoutId: 1,
+		WorkoutId: &workoutId,
 		Details:   "Updated workout report with additional notes about performance.",
 	}
 	jsonBody, _ := json.Marshal(requestBody)
@@ -266,7 +287,7 @@ func TestDeleteWorkoutReport_Handler_Success(t *testing.T) {
 func TestDeleteWorkoutReport_Handler_NotFound(t *testing.T) {
 	reportRepo := &MockWorkoutReportRepository{
 		DeleteFn: func(ctx context.Context, id int) error {
-			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	workoutRepo := &MockWorkoutRepository{}
@@ -287,3 +308,36 @@ func TestDeleteWorkoutReport_Handler_NotFound(t *testing.T) {
 	assert.Equal(t, false, response.Success)
 	assert.Equal(t, service_errors.RecordNotFound, response.Error)
 }
+
+func TestGenerateWorkoutReport_Handler_Success(t *testing.T) {
+	reportHandler := &handler.WorkoutReportHandler{
+		Usecase: usecase.NewWorkoutReportUsecase(&config.Config{}, &MockWorkoutReportRepository{}, &MockWorkoutRepository{},
+			reportAnalytics(), &MockPersonalRecordRepository{}, &MockExerciseRepository{}),
+	}
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/workout-report/generate",
+		[]byte(`{"from":"2024-03-04","to":"2024-03-10"}`), &MockTokenProvider{}, &config.Config{})
+
+	reportHandler.Generate(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Result dto.WorkoutReportResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Zero(t, response.Result.WorkoutId)
+	var payload struct {
+		TotalVolume float64 `json:"total_volume"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Result.Payload, &payload))
+	assert.Equal(t, 12000.0, payload.TotalVolume)
+}
+
+func TestGenerateWorkoutReport_Handler_InvalidDate(t *testing.T) {
+	handler, tokenProvider, cfg := setupWorkoutReportHandler(&MockWorkoutReportRepository{}, &MockWorkoutRepository{})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/workout-report/generate",
+		[]byte(`{"from":"03/04/2024"}`), tokenProvider, cfg)
+
+	handler.Generate(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
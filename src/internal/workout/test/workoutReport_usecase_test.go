@@ -2,12 +2,19 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/assert/v2"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
 // ==================== WORKOUT REPORT USECASE TESTS ====================
@@ -28,7 +35,7 @@ func TestCreateWorkoutReport_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, response.Id)
-	assert.Equal(t, 1, response.WorkoutId)
+	assert.Equal(t, 1, *response.WorkoutId)
 	assert.Equal(t, "Great workout session today!", response.Details)
 }
 
@@ -85,8 +92,9 @@ func TestUpdateWorkoutReport_Success(t *testing.T) {
 	useCase := setupWorkoutReportUsecase(reportRepo, workoutRepo)
 
 	ctx := createContextWithUserId(1)
+	workoutId := 1
 	req := dto.UpdateWorkoutReportRequest{
-		WorkoutId: 1,
+		WorkoutId: &workoutId,
 		Details:   "Updated workout report details",
 	}
 
@@ -111,8 +119,9 @@ func TestUpdateWorkoutReport_UnauthorizedUser(t *testing.T) {
 	useCase := setupWorkoutReportUsecase(reportRepo, workoutRepo)
 
 	ctx := createContextWithUserId(1) // User ID 1 trying to update report for workout owned by user ID 2
+	workoutId := 1
 	req := dto.UpdateWorkoutReportRequest{
-		WorkoutId: 1,
+		WorkoutId: &workoutId,
 		Details:   "Updated workout report details",
 	}
 
@@ -121,6 +130,33 @@ func TestUpdateWorkoutReport_UnauthorizedUser(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestUpdateWorkoutReport_GeneratedReportKeepsNoWorkout(t *testing.T) {
+	var updated models.WorkoutReport
+	reportRepo := &MockWorkoutReportRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutReport, error) {
+			return models.WorkoutReport{Id: id, UserId: 1, Details: "Generated"}, nil
+		},
+		UpdateFn: func(ctx context.Context, id int, entity models.WorkoutReport) (models.WorkoutReport, error) {
+			updated = entity
+			return models.WorkoutReport{Id: id, UserId: 1, Details: entity.Details}, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Workout, error) {
+			t.Fatal("no workout is checked when the request has none")
+			return models.Workout{}, nil
+		},
+	}
+	useCase := setupWorkoutReportUsecase(reportRepo, workoutRepo)
+
+	response, err := useCase.Update(createContextWithUserId(1), 1, dto.UpdateWorkoutReportRequest{Details: "Edited notes"})
+
+	assert.NoError(t, err)
+	assert.True(t, updated.WorkoutId == nil)
+	assert.True(t, response.WorkoutId == nil)
+	assert.Equal(t, "Edited notes", response.Details)
+}
+
 func TestDeleteWorkoutReport_Success(t *testing.T) {
 	reportRepo := &MockWorkoutReportRepository{}
 	workoutRepo := &MockWorkoutRepository{}
@@ -164,7 +200,7 @@ func TestGetWorkoutReportById_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, 1, response.Id)
-	assert.Equal(t, 1, response.WorkoutId)
+	assert.Equal(t, 1, *response.WorkoutId)
 	assert.Equal(t, "Test Report Details", response.Details)
 }
 
@@ -203,3 +239,187 @@ func TestGetWorkoutReportById_NotFound(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestGetByFilterWorkoutReport_ScopedToOwner(t *testing.T) {
+	reportRepo := &MockWorkoutReportRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutReport, error) {
+			// Verify that the owner scope was set from the context
+			assert.Equal(t, 2, req.OwnerId)
+			workoutId := 1
+			reports := []models.WorkoutReport{
+				{Id: 1, WorkoutId: &workoutId, UserId: 2, Details: "Great session"},
+			}
+			return 1, &reports, nil
+		},
+	}
+	workoutRepo := &MockWorkoutRepository{}
+	useCase := setupWorkoutReportUsecase(reportRepo, workoutRepo)
+
+	response, err := useCase.GetByFilter(createContextWithUserId(2), filter.PaginationInputWithFilter{})
+
+	assert.NoError(t, err)
+	if response == nil {
+		t.Fatal("Expected response to not be nil")
+	}
+	assert.Equal(t, int64(1), response.TotalRows)
+	assert.Equal(t, "Great session", (*response.Items)[0].Details)
+}
+
+func TestGetWorkoutReport_WithoutWorkout_NotOwner(t *testing.T) {
+	reportRepo := &MockWorkoutReportRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.WorkoutReport, error) {
+			return models.WorkoutReport{Id: id, UserId: 2, Details: "Report from 2024-03-04 to 2024-03-10"}, nil
+		},
+	}
+	useCase := setupWorkoutReportUsecase(reportRepo, &MockWorkoutRepository{})
+
+	_, err := useCase.GetById(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserNotOwner))
+}
+
+// ==================== GENERATED WORKOUT REPORT TESTS ====================
+
+// reportAnalytics returns 4 scheduled and 3 completed workouts with a volume of 12000 from the
+// 4th of March 2024 and 2 completed workouts with a volume of 10000 before
+func reportAnalytics() *MockAnalyticsRepository {
+	current := func(r models.AnalyticsRange) bool {
+		return !r.From.Before(date(2024, 3, 4))
+	}
+	benchId := 9
+	return &MockAnalyticsRepository{
+		FrequencyFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.FrequencyPoint, error) {
+			if current(r) {
+				return []models.FrequencyPoint{{Period: r.From, Scheduled: 4, Completed: 3}}, nil
+			}
+			return []models.FrequencyPoint{{Period: r.From, Scheduled: 2, Completed: 2}}, nil
+		},
+		VolumeFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.VolumePoint, error) {
+			if current(r) {
+				return []models.VolumePoint{{Period: r.From, Volume: 7000, Workouts: 2}, {Period: r.From, Volume: 5000, Workouts: 1}}, nil
+			}
+			return []models.VolumePoint{{Period: r.From, Volume: 10000, Workouts: 2}}, nil
+		},
+		ExerciseTrendsFn: func(ctx context.Context, r models.AnalyticsRange) ([]models.ExerciseTrendPoint, error) {
+			return []models.ExerciseTrendPoint{
+				{ExerciseId: &benchId, Name: "Bench Press", Period: date(2024, 3, 4), MaxWeight: 80, Volume: 4000, Reps: 50},
+				{ExerciseId: &benchId, Name: "Bench Press", Period: date(2024, 3, 11), MaxWeight: 85, Volume: 2550, Reps: 30},
+				{Name: "Farmer Walk", Period: date(2024, 3, 4), MaxWeight: 40, Volume: 800, Reps: 20},
+			}, nil
+		},
+	}
+}
+
+func setupReportGenerator(reportRepo *MockWorkoutReportRepository, analyticsRepo *MockAnalyticsRepository, recordRepo *MockPersonalRecordRepository) *usecase.WorkoutReportUsecase {
+	return usecase.NewWorkoutReportUsecase(&config.Config{}, reportRepo, &MockWorkoutRepository{}, analyticsRepo, recordRepo, &MockExerciseRepository{})
+}
+
+func TestGenerateWorkoutReport_Success(t *testing.T) {
+	var stored models.WorkoutReport
+	reportRepo := &MockWorkoutReportRepository{
+		CreateFn: func(ctx context.Context, entity models.WorkoutReport) (models.WorkoutReport, error) {
+			stored = entity
+			entity.Id = 1
+			return entity, nil
+		},
+	}
+	previousValue := 80.0
+	recordRepo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			achievedAt := req.Filter["AchievedAt"]
+			assert.Equal(t, "inRange", achievedAt.Type)
+			assert.Equal(t, "2024-03-04T00:00:00Z", achievedAt.From)
+			assert.True(t, strings.HasPrefix(achievedAt.To, "2024-03-10T23:59:59"))
+			records := []models.PersonalRecord{
+				{Id: 2, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 85, PreviousValue: &previousValue, AchievedAt: date(2024, 3, 8)},
+				{Id: 1, ExerciseId: 9, RecordType: models.MaxRepsRecord, Value: 12, AchievedAt: date(2024, 3, 5)},
+			}
+			return 2, &records, nil
+		},
+	}
+	useCase := setupReportGenerator(reportRepo, reportAnalytics(), recordRepo)
+
+	response, err := useCase.Generate(createContextWithUserId(1), dto.GenerateWorkoutReportRequest{From: date(2024, 3, 4), To: date(2024, 3, 10)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, response.Id)
+	assert.Equal(t, 1, stored.UserId)
+	assert.Zero(t, stored.WorkoutId)
+	assert.Equal(t, date(2024, 3, 4), *stored.PeriodStart)
+	assert.Equal(t, date(2024, 3, 10), *stored.PeriodEnd)
+	assert.True(t, strings.Contains(stored.Details, "Completed 3 of 4 scheduled workouts (75%)"))
+	assert.True(t, strings.Contains(stored.Details, "Total volume 12000 (+20% compared to the previous period)"))
+
+	var payload models.ReportPayload
+	assert.NoError(t, json.Unmarshal([]byte(*response.Payload), &payload))
+	assert.Equal(t, 12000.0, payload.TotalVolume)
+	assert.Equal(t, 75.0, payload.CompletionRate)
+	assert.Equal(t, 2, len(payload.Exercises))
+	assert.Equal(t, 85.0, payload.Exercises[0].MaxWeight)
+	assert.Equal(t, 6550.0, payload.Exercises[0].Volume)
+	assert.Equal(t, 80, payload.Exercises[0].Reps)
+	assert.Equal(t, 2, len(payload.PersonalRecords))
+	assert.Equal(t, models.MaxRepsRecord, payload.PersonalRecords[0].RecordType)
+	assert.Equal(t, "Bench Press", payload.PersonalRecords[1].ExerciseName)
+	assert.Equal(t, date(2024, 2, 26), payload.Previous.From)
+	assert.Equal(t, date(2024, 3, 3), payload.Previous.To)
+	assert.Equal(t, 100.0, payload.Previous.CompletionRate)
+	assert.Equal(t, 20.0, *payload.Previous.VolumeChange)
+	assert.Equal(t, 50.0, *payload.Previous.CompletedChange)
+}
+
+func TestGenerateWorkoutReport_DefaultsToLastWeek(t *testing.T) {
+	useCase := setupReportGenerator(&MockWorkoutReportRepository{}, &MockAnalyticsRepository{}, &MockPersonalRecordRepository{})
+
+	response, err := useCase.Generate(createContextWithUserId(1), dto.GenerateWorkoutReportRequest{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, (usecase.DefaultReportDays-1)*24*time.Hour, response.PeriodEnd.Sub(*response.PeriodStart))
+	var payload models.ReportPayload
+	assert.NoError(t, json.Unmarshal([]byte(*response.Payload), &payload))
+	assert.Equal(t, payload.From.Add(-24*time.Hour), payload.Previous.To)
+	assert.Zero(t, payload.Previous.VolumeChange)
+	assert.Equal(t, 0, len(payload.Exercises))
+}
+
+func TestGenerateWorkoutReport_Workout_KeepsItsRecords(t *testing.T) {
+	analyticsRepo := reportAnalytics()
+	recordRepo := &MockPersonalRecordRepository{
+		GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.PersonalRecord, error) {
+			records := []models.PersonalRecord{
+				{Id: 2, ExerciseId: 4, RecordType: models.MaxWeightRecord, Value: 140, AchievedAt: date(2024, 3, 8)},
+				{Id: 1, ExerciseId: 9, RecordType: models.MaxWeightRecord, Value: 85, AchievedAt: date(2024, 3, 5)},
+			}
+			return 2, &records, nil
+		},
+	}
+	useCase := setupReportGenerator(&MockWorkoutReportRepository{}, analyticsRepo, recordRepo)
+	workoutId := 5
+
+	response, err := useCase.Generate(createContextWithUserId(1), dto.GenerateWorkoutReportRequest{WorkoutId: &workoutId, From: date(2024, 3, 4), To: date(2024, 3, 10)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, *response.WorkoutId)
+	assert.Equal(t, 5, *analyticsRepo.Range.WorkoutId)
+	var payload models.ReportPayload
+	assert.NoError(t, json.Unmarshal([]byte(*response.Payload), &payload))
+	assert.Equal(t, 1, len(payload.PersonalRecords))
+	assert.Equal(t, 9, payload.PersonalRecords[0].ExerciseId)
+}
+
+func TestGenerateWorkoutReport_WorkoutNotOwned(t *testing.T) {
+	useCase := setupReportGenerator(&MockWorkoutReportRepository{}, &MockAnalyticsRepository{}, &MockPersonalRecordRepository{})
+	workoutId := 5
+
+	_, err := useCase.Generate(createContextWithUserId(2), dto.GenerateWorkoutReportRequest{WorkoutId: &workoutId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserNotOwner))
+}
+
+func TestGenerateWorkoutReport_FromAfterTo(t *testing.T) {
+	useCase := setupReportGenerator(&MockWorkoutReportRepository{}, &MockAnalyticsRepository{}, &MockPersonalRecordRepository{})
+
+	_, err := useCase.Generate(createContextWithUserId(1), dto.GenerateWorkoutReportRequest{From: date(2024, 3, 10), To: date(2024, 3, 4)})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeValidationError))
+}
@@ -0,0 +1,441 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/dto"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
+	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
+	usecaseDto "github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/helper"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/gin-gonic/gin"
+)
+
+// workoutSessionMocks records the steps of the usecase and the result of its transactions in steps
+type workoutSessionMocks struct {
+	sessions  *MockWorkoutSessionRepository
+	sets      *MockWorkoutSessionSetRepository
+	workouts  *MockWorkoutRepository
+	exercises *MockWorkoutExerciseRepository
+	scheduled *MockScheduledWorkoutsRepository
+	records   *MockPersonalRecordRepository
+	steps     []string
+}
+
+func newWorkoutSessionMocks() *workoutSessionMocks {
+	return &workoutSessionMocks{
+		sessions: &MockWorkoutSessionRepository{
+			// No session of the scheduled workout is running
+			GetByFilterFn: func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSession, error) {
+				return 0, &[]models.WorkoutSession{}, nil
+			},
+		},
+		sets:      &MockWorkoutSessionSetRepository{},
+		workouts:  &MockWorkoutRepository{},
+		exercises: &MockWorkoutExerciseRepository{},
+		scheduled: &MockScheduledWorkoutsRepository{},
+		records:   &MockPersonalRecordRepository{},
+	}
+}
+
+func (m *workoutSessionMocks) usecase() *usecase.WorkoutSessionUsecase {
+	transactor := &MockTransactor{
+		WithinTransactionFn: func(ctx context.Context, fn func(ctx context.Context) error) error {
+			m.steps = append(m.steps, "begin")
+			if err := fn(ctx); err != nil {
+				m.steps = append(m.steps, "rollback")
+				return err
+			}
+			m.steps = append(m.steps, "commit")
+			return nil
+		},
+	}
+	return usecase.NewWorkoutSessionUsecase(&config.Config{}, m.sessions, m.sets, m.workouts, m.exercises, m.scheduled, &MockExerciseRepository{}, m.records, &MockNotifier{}, transactor)
+}
+
+func sessionWithStatus(status string) func(ctx context.Context, id int) (models.WorkoutSession, error) {
+	return func(ctx context.Context, id int) (models.WorkoutSession, error) {
+		scheduledWorkoutId := 5
+		pausedAt := time.Now().Add(-10 * time.Minute)
+		return models.WorkoutSession{
+			Id:                 id,
+			UserId:             1,
+			WorkoutId:          1,
+			ScheduledWorkoutId: &scheduledWorkoutId,
+			Status:             status,
+			StartedAt:          time.Now().Add(-time.Hour),
+			PausedAt:           &pausedAt,
+			PausedSeconds:      300,
+		}, nil
+	}
+}
+
+// ==================== WORKOUT SESSION USECASE TESTS ====================
+
+func TestStartWorkoutSession_Success(t *testing.T) {
+	var created models.WorkoutSession
+	var started models.ScheduledWorkouts
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.CreateFn = func(ctx context.Context, entity models.WorkoutSession) (models.WorkoutSession, error) {
+		created = entity
+		return entity, nil
+	}
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		started = entity
+		return entity, nil
+	}
+	scheduledWorkoutId := 5
+
+	response, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created.UserId)
+	assert.Equal(t, models.SessionInProgress, created.Status)
+	assert.Equal(t, 5, *created.ScheduledWorkoutId)
+	assert.False(t, created.StartedAt.IsZero())
+	assert.Equal(t, models.SessionInProgress, response.Status)
+	assert.Equal(t, models.ScheduledInProgress, started.Status)
+}
+
+func TestStartWorkoutSession_ScheduleUpdateFailureRollsBack(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.CreateFn = func(ctx context.Context, entity models.WorkoutSession) (models.WorkoutSession, error) {
+		mocks.steps = append(mocks.steps, "create session")
+		return entity, nil
+	}
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		return entity, service_errors.New(service_errors.CodeConcurrentUpdate)
+	}
+	scheduledWorkoutId := 5
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeConcurrentUpdate))
+	// The created session is rolled back with the schedule
+	assert.Equal(t, []string{"begin", "create session", "rollback"}, mocks.steps)
+}
+
+func TestStartWorkoutSession_ScheduleHasActiveSession(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	var filtered filter.PaginationInputWithFilter
+	mocks.sessions.GetByFilterFn = func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSession, error) {
+		filtered = req
+		return 1, &[]models.WorkoutSession{{Id: 3, UserId: 1, Status: models.SessionPaused}}, nil
+	}
+	mocks.sessions.CreateFn = func(ctx context.Context, entity models.WorkoutSession) (models.WorkoutSession, error) {
+		t.Fatal("the session must not be created")
+		return entity, nil
+	}
+	scheduledWorkoutId := 5
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeSessionAlreadyActive))
+	assert.Equal(t, http.StatusConflict, helper.TranslateErrorToStatusCode(err))
+	assert.Equal(t, "5", filtered.Filter["ScheduledWorkoutId"].From)
+	assert.Equal(t, 1, filtered.OwnerId)
+}
+
+func TestStartWorkoutSession_WorkoutOfAnotherUser(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.workouts.GetByIdFn = func(ctx context.Context, id int) (models.Workout, error) {
+		return models.Workout{Id: id, UserId: 2}, nil
+	}
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserNotOwner))
+}
+
+func TestStartWorkoutSession_ScheduleOfAnotherWorkout(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.scheduled.GetByIdFn = func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
+		return models.ScheduledWorkouts{Id: id, WorkoutId: 2, Status: "planned"}, nil
+	}
+	scheduledWorkoutId := 5
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidReference))
+}
+
+func TestStartWorkoutSession_ScheduleNotActive(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.scheduled.GetByIdFn = func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
+		return models.ScheduledWorkouts{Id: id, WorkoutId: 1, Status: "completed"}, nil
+	}
+	scheduledWorkoutId := 5
+
+	_, err := mocks.usecase().Start(createContextWithUserId(1), usecaseDto.StartWorkoutSessionRequest{WorkoutId: 1, ScheduledWorkoutId: &scheduledWorkoutId})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeScheduledWorkoutNotActive))
+	assert.Equal(t, http.StatusConflict, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestPauseWorkoutSession_Success(t *testing.T) {
+	var updated models.WorkoutSession
+	var from string
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.UpdateStatusFn = func(ctx context.Context, id int, status string, entity models.WorkoutSession) error {
+		from = status
+		updated = entity
+		return nil
+	}
+
+	response, err := mocks.usecase().Pause(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionInProgress, from)
+	assert.Equal(t, models.SessionPaused, updated.Status)
+	assert.True(t, updated.PausedAt != nil)
+	assert.Equal(t, models.SessionPaused, response.Status)
+}
+
+func TestPauseWorkoutSession_AlreadyPaused(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionPaused)
+
+	_, err := mocks.usecase().Pause(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidSessionState))
+	assert.Equal(t, http.StatusConflict, helper.TranslateErrorToStatusCode(err))
+}
+
+func TestPauseWorkoutSession_ChangedMeanwhile(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	// Another request finished the session after it was read
+	mocks.sessions.UpdateStatusFn = func(ctx context.Context, id int, from string, entity models.WorkoutSession) error {
+		return service_errors.New(service_errors.CodeInvalidSessionState)
+	}
+
+	_, err := mocks.usecase().Pause(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidSessionState))
+}
+
+func TestPauseWorkoutSession_SessionOfAnotherUser(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+
+	_, err := mocks.usecase().Pause(createContextWithUserId(2), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeUserNotOwner))
+}
+
+func TestResumeWorkoutSession_AddsPause(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionPaused)
+
+	response, err := mocks.usecase().Resume(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionInProgress, response.Status)
+	// 300 seconds of earlier pauses and the 10 minute pause that just ended
+	assert.True(t, response.PausedSeconds >= 900 && response.PausedSeconds <= 905, "paused seconds %d", response.PausedSeconds)
+}
+
+func TestResumeWorkoutSession_NotPaused(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+
+	_, err := mocks.usecase().Resume(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidSessionState))
+}
+
+func TestFinishWorkoutSession_CompletesSchedule(t *testing.T) {
+	var completed models.ScheduledWorkouts
+	var completedId int
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionInProgress)
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		completedId = id
+		completed = entity
+		return entity, nil
+	}
+
+	response, err := mocks.usecase().Finish(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5, completedId)
+	assert.Equal(t, "completed", completed.Status)
+	assert.Equal(t, models.SessionFinished, response.Status)
+	assert.True(t, response.FinishedAt != nil)
+	// One hour since the start minus the 300 seconds of pauses
+	assert.True(t, response.DurationSeconds >= 3295 && response.DurationSeconds <= 3305, "duration seconds %d", response.DurationSeconds)
+}
+
+func TestFinishWorkoutSession_WritesTheSessionFirst(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionPaused)
+	mocks.sessions.UpdateStatusFn = func(ctx context.Context, id int, from string, entity models.WorkoutSession) error {
+		mocks.steps = append(mocks.steps, "finish "+from+" session")
+		return nil
+	}
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		mocks.steps = append(mocks.steps, "complete schedule")
+		return entity, service_errors.New(service_errors.CodeConcurrentUpdate)
+	}
+
+	_, err := mocks.usecase().Finish(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeConcurrentUpdate))
+	assert.Equal(t, []string{"begin", "finish paused session", "complete schedule", "rollback"}, mocks.steps)
+}
+
+func TestFinishWorkoutSession_KeepsCancelledSchedule(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionInProgress)
+	mocks.scheduled.GetByIdFn = func(ctx context.Context, id int) (models.ScheduledWorkouts, error) {
+		return models.ScheduledWorkouts{Id: id, WorkoutId: 1, Status: models.ScheduledCancelled}, nil
+	}
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		t.Fatal("the schedule must not be updated")
+		return entity, nil
+	}
+
+	response, err := mocks.usecase().Finish(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SessionFinished, response.Status)
+}
+
+func TestFinishWorkoutSession_WhilePaused(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionPaused)
+
+	response, err := mocks.usecase().Finish(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	// The running pause of 10 minutes is not counted either
+	assert.True(t, response.DurationSeconds >= 2695 && response.DurationSeconds <= 2705, "duration seconds %d", response.DurationSeconds)
+}
+
+func TestFinishWorkoutSession_AlreadyFinished(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionFinished)
+	mocks.scheduled.UpdateFn = func(ctx context.Context, id int, entity models.ScheduledWorkouts) (models.ScheduledWorkouts, error) {
+		t.Fatal("the schedule must not be updated")
+		return entity, nil
+	}
+
+	_, err := mocks.usecase().Finish(createContextWithUserId(1), 1)
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidSessionState))
+}
+
+func TestLogWorkoutSessionSet_AppendedAfterLastSet(t *testing.T) {
+	var created models.WorkoutSessionSet
+	var request filter.PaginationInputWithFilter
+	exerciseId := 9
+	mocks := newWorkoutSessionMocks()
+	mocks.exercises.GetByIdFn = func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+		return models.WorkoutExercise{Id: id, WorkoutId: 1, ExerciseId: &exerciseId}, nil
+	}
+	mocks.sets.GetByFilterFn = func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSessionSet, error) {
+		request = req
+		sets := []models.WorkoutSessionSet{{Id: 1, SetIndex: 1}, {Id: 2, SetIndex: 2}}
+		return 2, &sets, nil
+	}
+	mocks.sets.CreateFn = func(ctx context.Context, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error) {
+		created = entity
+		return entity, nil
+	}
+
+	response, err := mocks.usecase().LogSet(createContextWithUserId(1), usecaseDto.CreateWorkoutSessionSetRequest{WorkoutSessionId: 1, WorkoutExerciseId: 3, Repetitions: 8, Weight: 60})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1", request.Filter["WorkoutSessionId"].From)
+	assert.Equal(t, "3", request.Filter["WorkoutExerciseId"].From)
+	assert.Equal(t, 3, created.SetIndex)
+	assert.Equal(t, 9, *created.ExerciseId)
+	assert.Equal(t, models.WorkingSet, created.SetType)
+	assert.False(t, created.PerformedAt.IsZero())
+	assert.Equal(t, 3, response.SetIndex)
+}
+
+func TestLogWorkoutSessionSet_ExerciseOfAnotherWorkout(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.exercises.GetByIdFn = func(ctx context.Context, id int) (models.WorkoutExercise, error) {
+		return models.WorkoutExercise{Id: id, WorkoutId: 2}, nil
+	}
+
+	_, err := mocks.usecase().LogSet(createContextWithUserId(1), usecaseDto.CreateWorkoutSessionSetRequest{WorkoutSessionId: 1, WorkoutExerciseId: 3, SetIndex: 1})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidReference))
+}
+
+func TestLogWorkoutSessionSet_SessionPaused(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionPaused)
+
+	_, err := mocks.usecase().LogSet(createContextWithUserId(1), usecaseDto.CreateWorkoutSessionSetRequest{WorkoutSessionId: 1, WorkoutExerciseId: 3, SetIndex: 1})
+
+	assert.True(t, service_errors.HasCode(err, service_errors.CodeInvalidSessionState))
+}
+
+func TestGetWorkoutSession_WithSets(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sets.GetByFilterFn = func(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]models.WorkoutSessionSet, error) {
+		sets := []models.WorkoutSessionSet{{Id: 1, WorkoutSessionId: 1, SetIndex: 1}, {Id: 2, WorkoutSessionId: 1, SetIndex: 2}}
+		return 2, &sets, nil
+	}
+
+	response, err := mocks.usecase().GetById(createContextWithUserId(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(response.Sets))
+}
+
+// ==================== WORKOUT SESSION HANDLER TESTS ====================
+
+func TestStartWorkoutSession_Handler_Success(t *testing.T) {
+	sessionHandler := &handler.WorkoutSessionHandler{Usecase: newWorkoutSessionMocks().usecase()}
+	body, _ := json.Marshal(dto.StartWorkoutSessionRequest{WorkoutId: 1})
+	c, w := createAuthenticatedGinContext("POST", "/v1/workouts/sessions/", body, &MockTokenProvider{}, &config.Config{})
+
+	sessionHandler.Start(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Result dto.WorkoutSessionResponse `json:"result"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.SessionInProgress, response.Result.Status)
+}
+
+func TestPauseWorkoutSession_Handler_Conflict(t *testing.T) {
+	mocks := newWorkoutSessionMocks()
+	mocks.sessions.GetByIdFn = sessionWithStatus(models.SessionFinished)
+	sessionHandler := &handler.WorkoutSessionHandler{Usecase: mocks.usecase()}
+	c, w := createAuthenticatedGinContextWithParams("POST", "/v1/workouts/sessions/1/pause", nil, gin.Params{{Key: "id", Value: "1"}}, &MockTokenProvider{}, &config.Config{})
+
+	sessionHandler.Pause(c)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestLogWorkoutSessionSet_Handler_UsesPathId(t *testing.T) {
+	var created models.WorkoutSessionSet
+	mocks := newWorkoutSessionMocks()
+	mocks.sets.CreateFn = func(ctx context.Context, entity models.WorkoutSessionSet) (models.WorkoutSessionSet, error) {
+		created = entity
+		return entity, nil
+	}
+	sessionHandler := &handler.WorkoutSessionHandler{Usecase: mocks.usecase()}
+	body := []byte(`{"workout_exercise_id": 1, "reps": 5, "weight": 100, "set_type": "failure"}`)
+	c, w := createAuthenticatedGinContextWithParams("POST", "/v1/workouts/sessions/4/sets", body, gin.Params{{Key: "id", Value: "4"}}, &MockTokenProvider{}, &config.Config{})
+
+	sessionHandler.LogSet(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 4, created.WorkoutSessionId)
+	assert.Equal(t, models.FailureSet, created.SetType)
+}
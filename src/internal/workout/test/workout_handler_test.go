@@ -11,8 +11,8 @@ import (
 	"github.com/alielmi98/go-hexa-workout/internal/workout/adapter/http/handler"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/helper"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 	"github.com/gin-gonic/gin"
@@ -120,7 +120,7 @@ func TestGetWorkoutById_Handler_InvalidId(t *testing.T) {
 func TestGetWorkoutById_Handler_NotFound(t *testing.T) {
 	workoutRepo := &MockWorkoutRepository{
 		GetByIdFn: func(ctx context.Context, id int) (models.Workout, error) {
-			return models.Workout{}, &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return models.Workout{}, service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	handler, tokenProvider, cfg := setupWorkoutHandler(workoutRepo)
@@ -236,7 +236,7 @@ func TestDeleteWorkout_Handler_Success(t *testing.T) {
 func TestDeleteWorkout_Handler_NotFound(t *testing.T) {
 	workoutRepo := &MockWorkoutRepository{
 		DeleteFn: func(ctx context.Context, id int) error {
-			return &service_errors.ServiceError{EndUserMessage: service_errors.RecordNotFound}
+			return service_errors.New(service_errors.CodeRecordNotFound)
 		},
 	}
 	handler, tokenProvider, cfg := setupWorkoutHandler(workoutRepo)
@@ -8,7 +8,7 @@ import (
 	"github.com/alecthomas/assert/v2"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
 	"github.com/alielmi98/go-hexa-workout/internal/workout/core/usecase/dto"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
@@ -160,6 +160,26 @@ func TestGetWorkoutById_UnauthorizedUser(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestGetAnyWorkoutById_IgnoresOwner(t *testing.T) {
+	workoutRepo := &MockWorkoutRepository{
+		GetByIdFn: func(ctx context.Context, id int) (models.Workout, error) {
+			return models.Workout{
+				Id:     1,
+				UserId: 2,
+				Name:   "Test Workout",
+			}, nil
+		},
+	}
+	useCase := setupWorkoutUsecase(workoutRepo)
+
+	ctx := createContextWithUserId(1) // Admin with user ID 1 reading a workout owned by user ID 2
+
+	response, err := useCase.GetAnyById(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, response.UserId)
+}
+
 // ==================== GET BY FILTER USECASE TESTS ====================
 
 func TestGetByFilterWorkout_Success(t *testing.T) {
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 10, Name: "schedule_rules", Up: Up_10, Down: Down_10})
+}
+
+func Up_10(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE schedule_rules (
+			id bigserial PRIMARY KEY,
+			workout_id bigint NOT NULL CONSTRAINT fk_schedule_rules_workout REFERENCES workouts (id),
+			frequency varchar(10) NOT NULL
+				CONSTRAINT chk_schedule_rules_frequency CHECK (frequency IN ('daily', 'weekly', 'monthly')),
+			"interval" bigint NOT NULL DEFAULT 1,
+			by_day varchar(20),
+			start_time TIMESTAMP with time zone NOT NULL,
+			time_zone varchar(64) NOT NULL,
+			count bigint,
+			until TIMESTAMP with time zone,
+			exceptions text,
+			`+auditColumns+`,
+			CONSTRAINT chk_schedule_rules_end CHECK (count is null OR until is null))`,
+		`ALTER TABLE scheduled_workouts
+			ADD COLUMN schedule_rule_id bigint
+				CONSTRAINT fk_scheduled_workouts_schedule_rule REFERENCES schedule_rules (id),
+			ADD COLUMN occurrence_time TIMESTAMP with time zone`,
+		// An occurrence of a rule is materialized once
+		`CREATE UNIQUE INDEX idx_scheduled_workouts_occurrence ON scheduled_workouts (schedule_rule_id, occurrence_time)
+			WHERE deleted_by is null`,
+	)
+}
+
+func Down_10(tx *gorm.DB) error {
+	return exec(tx,
+		`ALTER TABLE scheduled_workouts DROP COLUMN occurrence_time, DROP COLUMN schedule_rule_id`,
+		`DROP TABLE schedule_rules`,
+	)
+}
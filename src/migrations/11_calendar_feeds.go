@@ -0,0 +1,26 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 11, Name: "calendar_feeds", Up: Up_11, Down: Down_11})
+}
+
+func Up_11(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE calendar_feeds (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL CONSTRAINT fk_calendar_feeds_user REFERENCES users (id),
+			token_hash varchar(64) NOT NULL,
+			`+auditColumns+`)`,
+		// A revoked feed is soft deleted, so a user can subscribe again
+		`CREATE UNIQUE INDEX idx_calendar_feeds_user ON calendar_feeds (user_id) WHERE deleted_by is null`,
+		`CREATE UNIQUE INDEX idx_calendar_feeds_token ON calendar_feeds (token_hash) WHERE deleted_by is null`,
+	)
+}
+
+func Down_11(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE calendar_feeds`)
+}
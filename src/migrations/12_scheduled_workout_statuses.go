@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 12, Name: "scheduled_workout_statuses", Up: Up_12, Down: Down_12})
+}
+
+func Up_12(tx *gorm.DB) error {
+	return exec(tx,
+		`UPDATE scheduled_workouts SET status = 'planned' WHERE status = 'active'`,
+		// Scheduled workouts with a running session are in progress
+		`UPDATE scheduled_workouts SET status = 'in_progress' WHERE status = 'planned' AND id IN (
+			SELECT scheduled_workout_id FROM workout_sessions
+			WHERE status IN ('in_progress', 'paused') AND deleted_by is null)`,
+		`ALTER TABLE scheduled_workouts ADD CONSTRAINT chk_scheduled_workouts_status
+			CHECK (status IN ('planned', 'in_progress', 'completed', 'skipped', 'cancelled', 'missed'))`,
+	)
+}
+
+func Down_12(tx *gorm.DB) error {
+	return exec(tx,
+		`ALTER TABLE scheduled_workouts DROP CONSTRAINT chk_scheduled_workouts_status`,
+		`UPDATE scheduled_workouts SET status = 'active' WHERE status IN ('planned', 'in_progress')`,
+		`UPDATE scheduled_workouts SET status = 'cancelled' WHERE status IN ('skipped', 'missed')`,
+	)
+}
@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 13, Name: "schedule_reminders", Up: Up_13, Down: Down_13})
+}
+
+func Up_13(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE schedule_reminders (
+			id bigserial PRIMARY KEY,
+			scheduled_workout_id bigint NOT NULL
+				CONSTRAINT fk_schedule_reminders_scheduled_workout REFERENCES scheduled_workouts (id),
+			user_id bigint NOT NULL CONSTRAINT fk_schedule_reminders_user REFERENCES users (id),
+			scheduled_time TIMESTAMP with time zone NOT NULL,
+			sent_at TIMESTAMP with time zone,
+			`+auditColumns+`)`,
+		// A scheduled workout is reminded once per scheduled time
+		`CREATE UNIQUE INDEX idx_schedule_reminders_occurrence ON schedule_reminders (scheduled_workout_id, scheduled_time)`,
+		`CREATE INDEX idx_schedule_reminders_pending ON schedule_reminders (scheduled_time) WHERE sent_at is null`,
+		// The jobs look for the planned scheduled workouts by time
+		`CREATE INDEX idx_scheduled_workouts_planned ON scheduled_workouts (scheduled_time)
+			WHERE status = 'planned' AND deleted_by is null`,
+	)
+}
+
+func Down_13(tx *gorm.DB) error {
+	return exec(tx,
+		`DROP INDEX idx_scheduled_workouts_planned`,
+		`DROP TABLE schedule_reminders`,
+	)
+}
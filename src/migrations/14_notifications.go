@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 14, Name: "notifications", Up: Up_14, Down: Down_14})
+}
+
+func Up_14(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE notifications (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL CONSTRAINT fk_notifications_user REFERENCES users (id),
+			kind varchar(32) NOT NULL,
+			title varchar(150) NOT NULL,
+			body text NOT NULL,
+			data jsonb,
+			read boolean NOT NULL DEFAULT false,
+			read_at TIMESTAMP with time zone,
+			`+auditColumns+`)`,
+		`CREATE INDEX idx_notifications_user ON notifications (user_id, created_at DESC) WHERE deleted_by is null`,
+		`CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read = false AND deleted_by is null`,
+		`CREATE TABLE notification_preferences (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL CONSTRAINT fk_notification_preferences_user REFERENCES users (id),
+			schedule_reminders boolean NOT NULL,
+			personal_records boolean NOT NULL,
+			email boolean NOT NULL,
+			in_app boolean NOT NULL,
+			webhook boolean NOT NULL,
+			webhook_url varchar(2048) NOT NULL DEFAULT '',
+			webhook_secret varchar(128) NOT NULL DEFAULT '',
+			`+auditColumns+`)`,
+		// A user has a single row of preferences, saving them again updates it
+		`CREATE UNIQUE INDEX idx_notification_preferences_user ON notification_preferences (user_id)`,
+	)
+}
+
+func Down_14(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE notification_preferences, notifications`)
+}
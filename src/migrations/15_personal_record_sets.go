@@ -0,0 +1,22 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 15, Name: "personal_record_sets", Up: Up_15, Down: Down_15})
+}
+
+func Up_15(tx *gorm.DB) error {
+	return exec(tx,
+		`ALTER TABLE personal_records
+			ADD COLUMN exercise_set_id bigint CONSTRAINT fk_personal_records_exercise_set REFERENCES exercise_sets (id),
+			ADD COLUMN workout_session_id bigint
+				CONSTRAINT fk_personal_records_workout_session REFERENCES workout_sessions (id)`,
+	)
+}
+
+func Down_15(tx *gorm.DB) error {
+	return exec(tx, `ALTER TABLE personal_records DROP COLUMN exercise_set_id, DROP COLUMN workout_session_id`)
+}
@@ -1,48 +1,58 @@
 package migrations
 
 import (
-	"log"
-
-	"github.com/alielmi98/go-hexa-workout/constants"
-	user_models "github.com/alielmi98/go-hexa-workout/internal/user/core/models"
-	workout_models "github.com/alielmi98/go-hexa-workout/internal/workout/core/models"
-	"github.com/alielmi98/go-hexa-workout/pkg/db"
-
 	"gorm.io/gorm"
 )
 
-func Up_1() {
-	database := db.GetDb()
-
-	createTables(database)
+func init() {
+	register(Migration{Version: 1, Name: "init", Up: Up_1, Down: Down_1})
 }
 
-func createTables(database *gorm.DB) {
-	tables := []interface{}{}
-
-	// Account
-	tables = addNewTable(database, user_models.User{}, tables)
-
-	// Workout
-	tables = addNewTable(database, workout_models.Workout{}, tables)
-	tables = addNewTable(database, workout_models.WorkoutExercise{}, tables)
-	tables = addNewTable(database, workout_models.ScheduledWorkouts{}, tables)
-	tables = addNewTable(database, workout_models.WorkoutReport{}, tables)
-
-	err := database.Migrator().CreateTable(tables...)
-	if err != nil {
-		log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Migration, err.Error())
-	}
-	log.Printf("Caller:%s Level:%s Msg:%s", constants.Postgres, constants.Migration, "tables created")
-}
-
-func addNewTable(database *gorm.DB, model interface{}, tables []interface{}) []interface{} {
-	if !database.Migrator().HasTable(model) {
-		tables = append(tables, model)
-	}
-	return tables
+// Up_1 creates the tables of the first release. The tables that exist are skipped so databases created
+// before the migration registry was introduced can be adopted as version 1.
+func Up_1(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE IF NOT EXISTS users (
+			id bigserial PRIMARY KEY,
+			username varchar(20) NOT NULL CONSTRAINT uni_users_username UNIQUE,
+			first_name varchar(15),
+			last_name varchar(25),
+			mobile_number varchar(11) DEFAULT null CONSTRAINT uni_users_mobile_number UNIQUE,
+			email varchar(64) DEFAULT null CONSTRAINT uni_users_email UNIQUE,
+			password varchar(64) NOT NULL,
+			enabled boolean DEFAULT true,
+			`+auditColumns+`)`,
+		`CREATE TABLE IF NOT EXISTS workouts (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL,
+			name varchar(100) NOT NULL,
+			description varchar(255),
+			comments varchar(255),
+			`+auditColumns+`)`,
+		`CREATE TABLE IF NOT EXISTS workout_exercises (
+			id bigserial PRIMARY KEY,
+			workout_id bigint NOT NULL,
+			name varchar(100) NOT NULL,
+			description varchar(255),
+			repetitions bigint NOT NULL,
+			sets bigint NOT NULL,
+			weight decimal NOT NULL,
+			`+auditColumns+`)`,
+		`CREATE TABLE IF NOT EXISTS scheduled_workouts (
+			id bigserial PRIMARY KEY,
+			workout_id bigint NOT NULL,
+			scheduled_time TIMESTAMP with time zone NOT NULL,
+			status varchar(20) NOT NULL,
+			`+auditColumns+`)`,
+		`CREATE TABLE IF NOT EXISTS workout_reports (
+			id bigserial PRIMARY KEY,
+			workout_id bigint NOT NULL,
+			user_id bigint NOT NULL,
+			details varchar(255) NOT NULL,
+			`+auditColumns+`)`,
+	)
 }
 
-func Down_1() {
-
+func Down_1(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE workout_reports, scheduled_workouts, workout_exercises, workouts, users`)
 }
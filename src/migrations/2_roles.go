@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 2, Name: "roles", Up: Up_2, Down: Down_2})
+}
+
+func Up_2(tx *gorm.DB) error {
+	err := exec(tx,
+		`CREATE TABLE roles (
+			id bigserial PRIMARY KEY,
+			name varchar(10) NOT NULL CONSTRAINT uni_roles_name UNIQUE,
+			`+auditColumns+`)`,
+		`CREATE TABLE user_roles (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL CONSTRAINT fk_user_roles_user REFERENCES users (id),
+			role_id bigint NOT NULL CONSTRAINT fk_user_roles_role REFERENCES roles (id),
+			`+auditColumns+`)`,
+		`CREATE UNIQUE INDEX idx_user_role ON user_roles (user_id, role_id)`,
+	)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	err = tx.Exec(`INSERT INTO roles (name, created_at, created_by) VALUES (?, ?, -1), (?, ?, -1)`,
+		constants.AdminRoleName, now, constants.DefaultRoleName, now).Error
+	if err != nil {
+		return err
+	}
+
+	// Every existing user gets the default role
+	return tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at, created_by)
+		SELECT users.id, roles.id, ?, -1 FROM users, roles WHERE roles.name = ?`, now, constants.DefaultRoleName).Error
+}
+
+func Down_2(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE user_roles, roles`)
+}
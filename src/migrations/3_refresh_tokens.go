@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 3, Name: "refresh_tokens", Up: Up_3, Down: Down_3})
+}
+
+func Up_3(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE refresh_tokens (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL CONSTRAINT fk_refresh_tokens_user REFERENCES users (id),
+			token_id varchar(32) NOT NULL CONSTRAINT uni_refresh_tokens_token_id UNIQUE,
+			session_id varchar(32) NOT NULL,
+			expires_at TIMESTAMP with time zone NOT NULL,
+			revoked_at TIMESTAMP with time zone,
+			`+auditColumns+`)`,
+		`CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id)`,
+		`CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens (session_id)`,
+	)
+}
+
+func Down_3(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE refresh_tokens`)
+}
@@ -0,0 +1,19 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 4, Name: "account_lockout", Up: Up_4, Down: Down_4})
+}
+
+func Up_4(tx *gorm.DB) error {
+	return exec(tx, `ALTER TABLE users
+		ADD COLUMN failed_login_attempts int NOT NULL DEFAULT 0,
+		ADD COLUMN locked_until TIMESTAMP with time zone`)
+}
+
+func Down_4(tx *gorm.DB) error {
+	return exec(tx, `ALTER TABLE users DROP COLUMN failed_login_attempts, DROP COLUMN locked_until`)
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 5, Name: "exercise_catalog", Up: Up_5, Down: Down_5})
+}
+
+func Up_5(tx *gorm.DB) error {
+	err := exec(tx,
+		`CREATE TABLE exercises (
+			id bigserial PRIMARY KEY,
+			user_id bigint,
+			name varchar(100) NOT NULL,
+			description varchar(255),
+			category varchar(30) NOT NULL,
+			primary_muscles varchar(255) NOT NULL,
+			secondary_muscles varchar(255),
+			equipment varchar(30) NOT NULL,
+			unit_type varchar(20) NOT NULL,
+			`+auditColumns+`)`,
+		// Names are unique within the catalog and within the custom exercises of a user
+		`CREATE UNIQUE INDEX idx_exercises_name ON exercises (lower(name), COALESCE(user_id, 0))
+			WHERE deleted_by is null`,
+		`ALTER TABLE workout_exercises ADD COLUMN exercise_id bigint
+			CONSTRAINT fk_workout_exercises_exercise REFERENCES exercises (id)`,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := SeedExercises(tx); err != nil {
+		return err
+	}
+
+	// Link the existing rows to the catalog exercise of the same name
+	return tx.Exec(`UPDATE workout_exercises SET exercise_id = exercises.id FROM exercises
+		WHERE exercises.user_id is null AND exercises.deleted_by is null
+		AND lower(exercises.name) = lower(trim(workout_exercises.name))`).Error
+}
+
+func Down_5(tx *gorm.DB) error {
+	return exec(tx,
+		`ALTER TABLE workout_exercises DROP COLUMN exercise_id`,
+		`DROP TABLE exercises`,
+	)
+}
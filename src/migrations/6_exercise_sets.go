@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 6, Name: "exercise_sets", Up: Up_6, Down: Down_6})
+}
+
+func Up_6(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE exercise_sets (
+			id bigserial PRIMARY KEY,
+			workout_exercise_id bigint NOT NULL
+				CONSTRAINT fk_exercise_sets_workout_exercise REFERENCES workout_exercises (id),
+			set_index bigint NOT NULL,
+			repetitions bigint NOT NULL,
+			weight decimal NOT NULL,
+			rpe decimal,
+			rest_seconds bigint NOT NULL DEFAULT 0,
+			set_type varchar(20) NOT NULL
+				CONSTRAINT chk_exercise_sets_set_type CHECK (set_type IN ('warmup', 'working', 'drop', 'failure')),
+			completed boolean NOT NULL DEFAULT false,
+			`+auditColumns+`)`,
+		// A set index is used once per workout exercise
+		`CREATE UNIQUE INDEX idx_exercise_sets_index ON exercise_sets (workout_exercise_id, set_index)
+			WHERE deleted_by is null`,
+	)
+}
+
+func Down_6(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE exercise_sets`)
+}
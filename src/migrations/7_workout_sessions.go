@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 7, Name: "workout_sessions", Up: Up_7, Down: Down_7})
+}
+
+func Up_7(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE workout_sessions (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL,
+			workout_id bigint NOT NULL CONSTRAINT fk_workout_sessions_workout REFERENCES workouts (id),
+			scheduled_workout_id bigint
+				CONSTRAINT fk_workout_sessions_scheduled_workout REFERENCES scheduled_workouts (id),
+			status varchar(20) NOT NULL
+				CONSTRAINT chk_workout_sessions_status CHECK (status IN ('in_progress', 'paused', 'finished')),
+			started_at TIMESTAMP with time zone NOT NULL,
+			paused_at TIMESTAMP with time zone,
+			finished_at TIMESTAMP with time zone,
+			paused_seconds bigint NOT NULL DEFAULT 0,
+			duration_seconds bigint NOT NULL DEFAULT 0,
+			`+auditColumns+`)`,
+		// A user runs one session at a time
+		`CREATE UNIQUE INDEX idx_workout_sessions_running ON workout_sessions (user_id)
+			WHERE status IN ('in_progress', 'paused') AND deleted_by is null`,
+		`CREATE TABLE workout_session_sets (
+			id bigserial PRIMARY KEY,
+			workout_session_id bigint NOT NULL
+				CONSTRAINT fk_workout_session_sets_session REFERENCES workout_sessions (id),
+			workout_exercise_id bigint NOT NULL
+				CONSTRAINT fk_workout_session_sets_workout_exercise REFERENCES workout_exercises (id),
+			exercise_id bigint CONSTRAINT fk_workout_session_sets_exercise REFERENCES exercises (id),
+			set_index bigint NOT NULL,
+			repetitions bigint NOT NULL,
+			weight decimal NOT NULL,
+			rpe decimal,
+			set_type varchar(20) NOT NULL,
+			performed_at TIMESTAMP with time zone NOT NULL,
+			`+auditColumns+`)`,
+		`CREATE INDEX idx_workout_session_sets_session ON workout_session_sets (workout_session_id)`,
+	)
+}
+
+func Down_7(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE workout_session_sets, workout_sessions`)
+}
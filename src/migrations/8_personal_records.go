@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 8, Name: "personal_records", Up: Up_8, Down: Down_8})
+}
+
+func Up_8(tx *gorm.DB) error {
+	return exec(tx,
+		`CREATE TABLE personal_records (
+			id bigserial PRIMARY KEY,
+			user_id bigint NOT NULL,
+			exercise_id bigint NOT NULL CONSTRAINT fk_personal_records_exercise REFERENCES exercises (id),
+			workout_exercise_id bigint NOT NULL
+				CONSTRAINT fk_personal_records_workout_exercise REFERENCES workout_exercises (id),
+			record_type varchar(20) NOT NULL CONSTRAINT chk_personal_records_record_type
+				CHECK (record_type IN ('max_weight', 'estimated_1rm', 'max_reps', 'max_volume')),
+			value decimal NOT NULL,
+			weight decimal NOT NULL,
+			repetitions bigint NOT NULL,
+			previous_value decimal,
+			achieved_at TIMESTAMP with time zone NOT NULL,
+			`+auditColumns+`)`,
+		`CREATE INDEX idx_personal_records_user_exercise ON personal_records (user_id, exercise_id)
+			WHERE deleted_by is null`,
+	)
+}
+
+func Down_8(tx *gorm.DB) error {
+	return exec(tx, `DROP TABLE personal_records`)
+}
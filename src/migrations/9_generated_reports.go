@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+func init() {
+	register(Migration{Version: 9, Name: "generated_reports", Up: Up_9, Down: Down_9})
+}
+
+func Up_9(tx *gorm.DB) error {
+	return exec(tx,
+		`ALTER TABLE workout_reports
+			ADD COLUMN period_start TIMESTAMP with time zone,
+			ADD COLUMN period_end TIMESTAMP with time zone,
+			ADD COLUMN payload jsonb`,
+		// Reports on a date range have no workout and the generated summaries are longer than 255 characters
+		`ALTER TABLE workout_reports ALTER COLUMN workout_id DROP NOT NULL`,
+		`ALTER TABLE workout_reports ALTER COLUMN details TYPE text`,
+		`CREATE INDEX idx_workout_reports_user ON workout_reports (user_id) WHERE deleted_by is null`,
+	)
+}
+
+func Down_9(tx *gorm.DB) error {
+	return exec(tx,
+		`DROP INDEX idx_workout_reports_user`,
+		`DELETE FROM workout_reports WHERE workout_id is null`,
+		`ALTER TABLE workout_reports ALTER COLUMN workout_id SET NOT NULL`,
+		`ALTER TABLE workout_reports ALTER COLUMN details TYPE varchar(255) USING left(details, 255)`,
+		`ALTER TABLE workout_reports DROP COLUMN period_start, DROP COLUMN period_end, DROP COLUMN payload`,
+	)
+}
@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed data/exercises.json
+var exerciseCatalog []byte
+
+// ExerciseSeed is an entry of the embedded exercise catalog, it has the json shape of a create exercise request
+type ExerciseSeed struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Category         string   `json:"category"`
+	PrimaryMuscles   []string `json:"primary_muscles"`
+	SecondaryMuscles []string `json:"secondary_muscles"`
+	Equipment        string   `json:"equipment"`
+	UnitType         string   `json:"unit_type"`
+}
+
+// exerciseRow is the row of the exercises table the seed writes, it is kept apart from the model so the
+// migrations don't change with it
+type exerciseRow struct {
+	Name             string
+	Description      string
+	Category         string
+	PrimaryMuscles   string
+	SecondaryMuscles string
+	Equipment        string
+	UnitType         string
+	CreatedAt        time.Time
+	CreatedBy        int
+}
+
+func (exerciseRow) TableName() string {
+	return "exercises"
+}
+
+// ExerciseCatalog returns the exercises of the embedded catalog
+func ExerciseCatalog() ([]ExerciseSeed, error) {
+	var seeds []ExerciseSeed
+	if err := json.Unmarshal(exerciseCatalog, &seeds); err != nil {
+		return nil, err
+	}
+	return seeds, nil
+}
+
+// SeedExercises adds the catalog exercises that are not in the database yet, matching them by name.
+// Names that exist, even soft deleted, are skipped, so it can be called again when the catalog grows.
+func SeedExercises(tx *gorm.DB) error {
+	seeds, err := ExerciseCatalog()
+	if err != nil {
+		return err
+	}
+
+	var existing []string
+	err = tx.Table("exercises").
+		Where("user_id is null").
+		Pluck("lower(name)", &existing).
+		Error
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+
+	now := time.Now().UTC()
+	exercises := []exerciseRow{}
+	for _, seed := range seeds {
+		if known[strings.ToLower(seed.Name)] {
+			continue
+		}
+		exercises = append(exercises, exerciseRow{
+			Name:             seed.Name,
+			Description:      seed.Description,
+			Category:         seed.Category,
+			PrimaryMuscles:   strings.Join(seed.PrimaryMuscles, ","),
+			SecondaryMuscles: strings.Join(seed.SecondaryMuscles, ","),
+			Equipment:        seed.Equipment,
+			UnitType:         seed.UnitType,
+			CreatedAt:        now,
+			CreatedBy:        -1,
+		})
+	}
+	if len(exercises) == 0 {
+		return nil
+	}
+	return tx.Create(&exercises).Error
+}
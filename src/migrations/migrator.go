@@ -0,0 +1,231 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"gorm.io/gorm"
+)
+
+// Migration is a single numbered, reversible schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// SchemaMigration is a row of the schema_migrations tracking table
+type SchemaMigration struct {
+	Version   int       `gorm:"primarykey;autoIncrement:false"`
+	Name      string    `gorm:"type:string;size:100;not null"`
+	AppliedAt time.Time `gorm:"type:TIMESTAMP with time zone;not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes whether a registered migration has been applied
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+var registry = map[int]Migration{}
+
+// register adds a migration to the registry, it is called from the init function of every migration file
+func register(m Migration) {
+	if _, ok := registry[m.Version]; ok {
+		panic(fmt.Sprintf("migration %d is registered twice", m.Version))
+	}
+	if m.Up == nil || m.Down == nil {
+		panic(fmt.Sprintf("migration %d must define both up and down", m.Version))
+	}
+	registry[m.Version] = m
+}
+
+// Registered returns the registered migrations ordered by version
+func Registered() []Migration {
+	migrations := make([]Migration, 0, len(registry))
+	for _, m := range registry {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations
+}
+
+type Migrator struct {
+	database   *gorm.DB
+	migrations []Migration
+}
+
+func NewMigrator(database *gorm.DB) *Migrator {
+	return &Migrator{
+		database:   database,
+		migrations: Registered(),
+	}
+}
+
+// Up applies every pending migration
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.latestVersion())
+}
+
+// Down rolls back the last n applied migrations
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	for i := len(m.migrations) - 1; i >= 0 && n > 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if err := m.rollback(ctx, migration); err != nil {
+			return err
+		}
+		n--
+	}
+	return nil
+}
+
+// To migrates the schema up or down until version is the latest applied migration
+func (m *Migrator) To(ctx context.Context, version int) error {
+	if version != 0 {
+		if _, ok := registry[version]; !ok {
+			return fmt.Errorf("migration %d is not registered", version)
+		}
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Roll back newer migrations first, newest to oldest
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.rollback(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(ctx, migration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Status lists every registered migration with its applied state
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		statuses = append(statuses, MigrationStatus{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Applied:   ok,
+			AppliedAt: row.AppliedAt,
+		})
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	err := m.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := migration.Up(tx); err != nil {
+			return err
+		}
+		return tx.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now().UTC(),
+		}).Error
+	})
+	if err != nil {
+		logging.GetLogger().Error(constants.Postgres, constants.Migration, err.Error(), migrationExtra(migration))
+		return fmt.Errorf("apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	logging.GetLogger().Info(constants.Postgres, constants.Migration, "migration applied", migrationExtra(migration))
+	return nil
+}
+
+func (m *Migrator) rollback(ctx context.Context, migration Migration) error {
+	err := m.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := migration.Down(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		logging.GetLogger().Error(constants.Postgres, constants.Rollback, err.Error(), migrationExtra(migration))
+		return fmt.Errorf("rollback migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	logging.GetLogger().Info(constants.Postgres, constants.Rollback, "migration rolled back", migrationExtra(migration))
+	return nil
+}
+
+// applied makes sure the tracking table exists and returns its rows by version
+func (m *Migrator) applied(ctx context.Context) (map[int]SchemaMigration, error) {
+	database := m.database.WithContext(ctx)
+	if !database.Migrator().HasTable(&SchemaMigration{}) {
+		if err := database.Migrator().CreateTable(&SchemaMigration{}); err != nil {
+			return nil, err
+		}
+	}
+	var rows []SchemaMigration
+	if err := database.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+func (m *Migrator) latestVersion() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func migrationExtra(migration Migration) map[constants.ExtraKey]interface{} {
+	return map[constants.ExtraKey]interface{}{constants.MigrationVersion: migration.Version, constants.MigrationName: migration.Name}
+}
+
+// auditColumns are the bookkeeping columns every table of the schema has. The migrations are frozen,
+// so the definition must not change, a new migration alters the tables instead.
+const auditColumns = `created_at TIMESTAMP with time zone NOT NULL,
+	modified_at TIMESTAMP with time zone,
+	deleted_at TIMESTAMP with time zone,
+	created_by bigint NOT NULL,
+	modified_by bigint,
+	deleted_by bigint`
+
+// exec runs the statements of a migration in order and stops at the first failure
+func exec(tx *gorm.DB, statements ...string) error {
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
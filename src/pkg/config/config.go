@@ -10,11 +10,18 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Postgres PostgresConfig
-	Password PasswordConfig
-	Cors     CorsConfig
-	JWT      JWTConfig
+	Server       ServerConfig
+	Postgres     PostgresConfig
+	Password     PasswordConfig
+	Cors         CorsConfig
+	JWT          JWTConfig
+	Admin        AdminConfig
+	Logger       LoggerConfig
+	Redis        RedisConfig
+	RateLimit    RateLimitConfig
+	Lockout      LockoutConfig
+	Jobs         JobsConfig
+	Notification NotificationConfig
 }
 
 type ServerConfig struct {
@@ -54,6 +61,98 @@ type JWTConfig struct {
 	RefreshTokenExpireDuration time.Duration
 	Secret                     string
 	RefreshSecret              string
+	// RefreshTokenCookie also delivers the refresh token in an HttpOnly cookie
+	RefreshTokenCookie bool
+}
+
+// LoggerConfig selects the minimum level (debug, info, warn, error or fatal), the encoding (json or text)
+// and the output (stdout, stderr or a file path) of the logger, LogBodies adds the request and response
+// bodies up to MaxBodySize bytes to the access log
+type LoggerConfig struct {
+	Level       string
+	Encoding    string
+	Output      string
+	LogBodies   bool
+	MaxBodySize int
+}
+
+type RedisConfig struct {
+	Host     string
+	Port     string
+	Password string
+	Db       int
+	PoolSize int
+}
+
+// RateLimitConfig limits the requests of every user, or client ip before login, to Requests per Window
+// seconds, a route whose path starts with the Path of one of the Routes uses that limit instead and the
+// longest matching path wins. Store is memory or redis, a limit with zero Requests is disabled
+type RateLimitConfig struct {
+	Store    string
+	Requests int
+	Window   time.Duration
+	Routes   []RouteRateLimitConfig
+}
+
+type RouteRateLimitConfig struct {
+	Path     string
+	Requests int
+	Window   time.Duration
+}
+
+// LockoutConfig locks an account for Duration minutes after MaxFailedAttempts wrong passwords in a row,
+// and rejects the logins of a client ip for IpWindow minutes after MaxFailedAttemptsPerIp wrong
+// passwords within that window. A zero maximum disables the check
+type LockoutConfig struct {
+	MaxFailedAttempts      int
+	Duration               time.Duration
+	MaxFailedAttemptsPerIp int
+	IpWindow               time.Duration
+}
+
+// JobsConfig runs the background jobs on the replica that holds the LeaderLockKey advisory lock of postgres.
+// The schedules are read by jobs.Parse and an empty one disables its job. A planned scheduled workout is
+// marked as missed MissedAfter minutes after its time, and its reminder is enqueued ReminderLead minutes before it
+// and sent by the SendReminderSchedule job
+type JobsConfig struct {
+	Enabled              bool
+	LeaderLockKey        int64
+	MissedSchedule       string
+	MissedAfter          time.Duration
+	ReminderSchedule     string
+	ReminderLead         time.Duration
+	SendReminderSchedule string
+}
+
+// NotificationConfig sends the email notifications through the Smtp server and gives the webhooks
+// WebhookTimeout seconds to respond. Webhooks on loopback, private and link-local addresses are refused
+// unless AllowPrivateWebhooks is set. The emails and webhooks are queued for DeliveryWorkers workers,
+// a notification is dropped when DeliveryQueueSize deliveries are already waiting.
+type NotificationConfig struct {
+	Smtp                 SmtpConfig
+	WebhookTimeout       time.Duration
+	AllowPrivateWebhooks bool
+	DeliveryWorkers      int
+	DeliveryQueueSize    int
+}
+
+// SmtpConfig is the server the emails are sent through, STARTTLS is used when the server offers it and
+// the client authenticates when Username is set. Timeout is in seconds and the email channel is disabled
+// when Host is empty
+type SmtpConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// AdminConfig is the account that is created with the admin role on startup,
+// nothing is created when the password is empty
+type AdminConfig struct {
+	Username string
+	Password string
 }
 
 func GetConfig() *Config {
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"gorm.io/gorm"
+)
+
+const softDeleteExp string = "id = ? and deleted_by is null"
+
+// BaseRepository stores the entities of a model with soft deletes, the repositories of the modules embed it
+type BaseRepository[TEntity any] struct {
+	database *gorm.DB
+	preloads []PreloadEntity
+}
+
+func NewBaseRepository[TEntity any](preloads []PreloadEntity) *BaseRepository[TEntity] {
+	return &BaseRepository[TEntity]{
+		database: GetDb(),
+		preloads: preloads,
+	}
+}
+
+// The writes run in the transaction of the context when there is one, see Transactor,
+// GORM runs each of them in a transaction of its own otherwise
+func (r BaseRepository[TEntity]) Create(ctx context.Context, entity TEntity) (TEntity, error) {
+	err := Conn(ctx, r.database).
+		Create(&entity).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Insert, err.Error(), nil)
+		return entity, TranslateError(err)
+	}
+	return entity, nil
+}
+
+func (r BaseRepository[TEntity]) Update(ctx context.Context, id int, entity TEntity) (TEntity, error) {
+	model := new(TEntity)
+
+	database := Conn(ctx, r.database)
+	err := database.Where(softDeleteExp, id).First(model).Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, err.Error(), nil)
+		return *model, TranslateError(err)
+	}
+
+	*model = entity
+
+	if err := database.Model(model).Where("id = ?", id).Updates(model).Error; err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Update, err.Error(), nil)
+		return *model, TranslateError(err)
+	}
+	return *model, nil
+}
+func (r BaseRepository[TEntity]) Delete(ctx context.Context, id int) error {
+	model := new(TEntity)
+
+	if ctx.Value(constants.UserIdKey) == nil {
+		return service_errors.New(service_errors.CodePermissionDenied)
+	}
+
+	deleteMap := map[string]interface{}{
+		"deleted_by": &sql.NullInt64{Int64: int64(ctx.Value(constants.UserIdKey).(float64)), Valid: true},
+		"deleted_at": sql.NullTime{Valid: true, Time: time.Now().UTC()},
+	}
+
+	result := Conn(ctx, r.database).
+		Model(model).
+		Where(softDeleteExp, id).
+		Updates(deleteMap)
+	if result.Error != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Delete, result.Error.Error(), nil)
+		return TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		logging.FromContext(ctx).Warn(constants.Postgres, constants.Delete, service_errors.RecordNotFound, nil)
+		return service_errors.New(service_errors.CodeRecordNotFound)
+	}
+	return nil
+}
+
+func (r BaseRepository[TEntity]) GetById(ctx context.Context, id int) (TEntity, error) {
+	model := new(TEntity)
+	database := Preload(Conn(ctx, r.database), r.preloads)
+	err := database.
+		Where(softDeleteExp, id).
+		First(model).
+		Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		}
+		return *model, TranslateError(err)
+	}
+	return *model, nil
+}
+
+func (r BaseRepository[TEntity]) GetByFilter(ctx context.Context, req filter.PaginationInputWithFilter) (int64, *[]TEntity, error) {
+	model := new(TEntity)
+	var items *[]TEntity
+
+	database := Preload(Conn(ctx, r.database), r.preloads)
+	query, args, err := GenerateDynamicQuery[TEntity](&req.DynamicFilter)
+	if err != nil {
+		return 0, &[]TEntity{}, err
+	}
+	sort := GenerateDynamicSort[TEntity](&req.DynamicFilter)
+	var totalRows int64 = 0
+
+	err = database.
+		Model(model).
+		Where(query, args...).
+		Count(&totalRows).
+		Error
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return 0, &[]TEntity{}, TranslateError(err)
+	}
+
+	err = database.
+		Where(query, args...).
+		Offset(req.GetOffset()).
+		Limit(req.GetPageSize()).
+		Order(sort).
+		Find(&items).
+		Error
+
+	if err != nil {
+		logging.FromContext(ctx).Error(constants.Postgres, constants.Select, err.Error(), nil)
+		return 0, &[]TEntity{}, TranslateError(err)
+	}
+	return totalRows, items, err
+
+}
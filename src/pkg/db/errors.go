@@ -0,0 +1,56 @@
+package db
+
+import (
+	"errors"
+
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Postgres error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// TranslateError converts the gorm and postgres errors the clients can act on to service errors,
+// the original error is kept as the cause and any other error is returned as it is
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return service_errors.Wrap(service_errors.CodeRecordNotFound, err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return service_errors.Wrap(service_errors.CodeRecordExists, err)
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return service_errors.Wrap(service_errors.CodeInvalidReference, err)
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return service_errors.Wrap(service_errors.CodeConstraintViolation, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	var serviceError *service_errors.ServiceError
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		serviceError = service_errors.Wrap(service_errors.CodeRecordExists, err)
+	case pgForeignKeyViolation:
+		serviceError = service_errors.Wrap(service_errors.CodeInvalidReference, err)
+	case pgCheckViolation:
+		serviceError = service_errors.Wrap(service_errors.CodeConstraintViolation, err)
+	case pgSerializationFailure, pgDeadlockDetected:
+		serviceError = service_errors.Wrap(service_errors.CodeConcurrentUpdate, err)
+	default:
+		return err
+	}
+	serviceError.TechnicalMessage = pgErr.ConstraintName
+	return serviceError
+}
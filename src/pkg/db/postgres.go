@@ -2,11 +2,11 @@ package db
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/alielmi98/go-hexa-workout/constants"
 	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -34,7 +34,7 @@ func InitDb(cfg *config.Config) error {
 	sqlDb.SetMaxOpenConns(cfg.Postgres.MaxOpenConns)
 	sqlDb.SetConnMaxLifetime(cfg.Postgres.ConnMaxLifetime * time.Minute)
 
-	log.Printf("caller:%s  Level:%s Msg:Db connection established", constants.Postgres, constants.Startup)
+	logging.GetLogger().Info(constants.Postgres, constants.Startup, "Db connection established", nil)
 	return nil
 }
 
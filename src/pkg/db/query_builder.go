@@ -3,10 +3,14 @@ package db
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/alielmi98/go-hexa-workout/common"
-	"github.com/alielmi98/go-hexa-workout/internal/workout/port/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/filter"
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 	"gorm.io/gorm"
 )
 
@@ -14,56 +18,200 @@ type PreloadEntity struct {
 	Entity string
 }
 
-// GenerateDynamicQuery
-func GenerateDynamicQuery[T any](filter *filter.DynamicFilter) string {
+// Supported values of filter.Filter.FilterType
+const (
+	textFilterType   = "text"
+	numberFilterType = "number"
+	dateFilterType   = "date"
+	boolFilterType   = "bool"
+)
+
+// OwnerScoped is implemented by entities that can be restricted to the rows owned by a user.
+// OwnerScope returns a condition with a single placeholder for the user id.
+type OwnerScoped interface {
+	OwnerScope() string
+}
+
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// GenerateDynamicQuery builds a where clause with placeholders and the arguments bound to them.
+// Fields tagged with filter:"-" can not be filtered or sorted on.
+func GenerateDynamicQuery[T any](filter *filter.DynamicFilter) (string, []interface{}, error) {
 	t := new(T)
 	typeT := reflect.TypeOf(*t)
 	query := make([]string, 0)
+	args := make([]interface{}, 0)
 	query = append(query, "deleted_by is null")
+	if filter.OwnerId != 0 {
+		scoped, ok := any(t).(OwnerScoped)
+		if !ok {
+			err := service_errors.New(service_errors.CodePermissionDenied)
+			err.TechnicalMessage = fmt.Sprintf("%s can not be scoped to an owner", typeT.Name())
+			return "", nil, err
+		}
+		query = append(query, scoped.OwnerScope())
+		args = append(args, filter.OwnerId)
+	}
 	if filter.Filter != nil {
-		for name, filter := range filter.Filter {
-			if fld, ok := typeT.FieldByName(name); ok {
-				query = append(query, GenerateDynamicFilter(fld, filter))
+		// Sort the names so the generated clause is stable between calls
+		names := make([]string, 0, len(filter.Filter))
+		for name := range filter.Filter {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if fld, ok := typeT.FieldByName(name); ok && fld.Tag.Get("filter") != "-" {
+				condition, conditionArgs, err := GenerateDynamicFilter(fld, filter.Filter[name])
+				if err != nil {
+					return "", nil, err
+				}
+				query = append(query, condition)
+				args = append(args, conditionArgs...)
 			}
 		}
 	}
-	return strings.Join(query, " AND ")
+	return strings.Join(query, " AND "), args, nil
 }
 
-func GenerateDynamicFilter(fld reflect.StructField, filter filter.Filter) string {
-	conditionQuery := ""
-	fld.Name = common.ToSnakeCase(fld.Name)
+// GenerateDynamicFilter builds a single condition with placeholders for the given field
+func GenerateDynamicFilter(fld reflect.StructField, filter filter.Filter) (string, []interface{}, error) {
+	column := common.ToSnakeCase(fld.Name)
+	filterType, err := resolveFilterType(fld, filter.FilterType)
+	if err != nil {
+		return "", nil, err
+	}
+
 	switch filter.Type {
-	case "contains":
-		conditionQuery = fmt.Sprintf("%s ILike '%%%s%%'", fld.Name, filter.From)
-	case "notContains":
-		conditionQuery = fmt.Sprintf("%s not ILike '%%%s%%'", fld.Name, filter.From)
-	case "startsWith":
-		conditionQuery = fmt.Sprintf("%s ILike '%s%%'", fld.Name, filter.From)
-	case "endsWith":
-		conditionQuery = fmt.Sprintf("%s ILike '%%%s'", fld.Name, filter.From)
-	case "equals":
-		conditionQuery = fmt.Sprintf("%s = '%s'", fld.Name, filter.From)
-	case "notEqual":
-		conditionQuery = fmt.Sprintf("%s != '%s'", fld.Name, filter.From)
-	case "lessThan":
-		conditionQuery = fmt.Sprintf("%s < %s", fld.Name, filter.From)
-	case "lessThanOrEqual":
-		conditionQuery = fmt.Sprintf("%s <= %s", fld.Name, filter.From)
-	case "greaterThan":
-		conditionQuery = fmt.Sprintf("%s > %s", fld.Name, filter.From)
-	case "greaterThanOrEqual":
-		conditionQuery = fmt.Sprintf("%s >= %s", fld.Name, filter.From)
+	case "contains", "notContains", "startsWith", "endsWith":
+		if filterType != textFilterType {
+			return "", nil, newFilterError(fld.Name, fmt.Sprintf("operator %s is only supported for text filters", filter.Type), nil)
+		}
+		pattern := likeEscaper.Replace(filter.From)
+		switch filter.Type {
+		case "contains":
+			return fmt.Sprintf("%s ILIKE ?", column), []interface{}{"%" + pattern + "%"}, nil
+		case "notContains":
+			return fmt.Sprintf("%s NOT ILIKE ?", column), []interface{}{"%" + pattern + "%"}, nil
+		case "startsWith":
+			return fmt.Sprintf("%s ILIKE ?", column), []interface{}{pattern + "%"}, nil
+		default:
+			return fmt.Sprintf("%s ILIKE ?", column), []interface{}{"%" + pattern}, nil
+		}
+	case "equals", "notEqual", "lessThan", "lessThanOrEqual", "greaterThan", "greaterThanOrEqual":
+		value, err := coerceFilterValue(fld, filterType, filter.From)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s %s ?", column, comparisonOperators[filter.Type]), []interface{}{value}, nil
 	case "inRange":
-		if fld.Type.Kind() == reflect.String {
-			conditionQuery = fmt.Sprintf("%s >= '%s%%' AND ", fld.Name, filter.From)
-			conditionQuery += fmt.Sprintf("%s <= '%%%s'", fld.Name, filter.To)
-		} else {
-			conditionQuery = fmt.Sprintf("%s >= %s AND ", fld.Name, filter.From)
-			conditionQuery += fmt.Sprintf("%s <= %s", fld.Name, filter.To)
+		from, err := coerceFilterValue(fld, filterType, filter.From)
+		if err != nil {
+			return "", nil, err
+		}
+		to, err := coerceFilterValue(fld, filterType, filter.To)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("%s >= ? AND %s <= ?", column, column), []interface{}{from, to}, nil
+	}
+	return "", nil, newFilterError(fld.Name, fmt.Sprintf("unsupported filter type %q", filter.Type), nil)
+}
+
+var comparisonOperators = map[string]string{
+	"equals":             "=",
+	"notEqual":           "!=",
+	"lessThan":           "<",
+	"lessThanOrEqual":    "<=",
+	"greaterThan":        ">",
+	"greaterThanOrEqual": ">=",
+}
+
+// resolveFilterType validates the requested filter type against the field kind,
+// falling back to the kind of the field when no filter type is given
+func resolveFilterType(fld reflect.StructField, filterType string) (string, error) {
+	fieldType := fieldFilterType(fld.Type)
+	switch filterType {
+	case "":
+		if fieldType == "" {
+			return "", newFilterError(fld.Name, "field is not filterable", nil)
+		}
+		return fieldType, nil
+	case "boolean":
+		filterType = boolFilterType
+	case textFilterType, numberFilterType, dateFilterType, boolFilterType:
+	default:
+		return "", newFilterError(fld.Name, fmt.Sprintf("unsupported filterType %q", filterType), nil)
+	}
+	if filterType != fieldType {
+		return "", newFilterError(fld.Name, fmt.Sprintf("filterType %s does not match the field type", filterType), nil)
+	}
+	return filterType, nil
+}
+
+func fieldFilterType(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return dateFilterType
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return textFilterType
+	case reflect.Bool:
+		return boolFilterType
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return numberFilterType
+	}
+	return ""
+}
+
+// coerceFilterValue converts the raw filter value to the go type of the field
+func coerceFilterValue(fld reflect.StructField, filterType string, value string) (interface{}, error) {
+	switch filterType {
+	case numberFilterType:
+		kind := fld.Type.Kind()
+		if kind == reflect.Pointer {
+			kind = fld.Type.Elem().Kind()
+		}
+		if kind == reflect.Float32 || kind == reflect.Float64 {
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, newFilterError(fld.Name, fmt.Sprintf("%q is not a valid number", value), err)
+			}
+			return v, nil
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, newFilterError(fld.Name, fmt.Sprintf("%q is not a valid integer", value), err)
 		}
+		return v, nil
+	case dateFilterType:
+		for _, layout := range dateLayouts {
+			if v, err := time.Parse(layout, value); err == nil {
+				return v, nil
+			}
+		}
+		return nil, newFilterError(fld.Name, fmt.Sprintf("%q is not a valid date", value), nil)
+	case boolFilterType:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, newFilterError(fld.Name, fmt.Sprintf("%q is not a valid boolean", value), err)
+		}
+		return v, nil
 	}
-	return conditionQuery
+	return value, nil
+}
+
+func newFilterError(field string, msg string, err error) error {
+	filterErr := service_errors.Wrap(service_errors.CodeInvalidFilter, err)
+	filterErr.TechnicalMessage = fmt.Sprintf("%s: %s", field, msg)
+	return filterErr
 }
 
 // generateDynamicSort
@@ -74,7 +222,7 @@ func GenerateDynamicSort[T any](filter *filter.DynamicFilter) string {
 	if filter.Sort != nil {
 		for _, tp := range *filter.Sort {
 			fld, ok := typeT.FieldByName(tp.ColId)
-			if ok && (tp.Sort == "asc" || tp.Sort == "desc") {
+			if ok && fld.Tag.Get("filter") != "-" && (tp.Sort == "asc" || tp.Sort == "desc") {
 				fld.Name = common.ToSnakeCase(fld.Name)
 				sort = append(sort, fmt.Sprintf("%s %s", fld.Name, tp.Sort))
 			}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+	"github.com/redis/go-redis/v9"
+)
+
+var redisClient *redis.Client
+
+func InitRedis(cfg *config.Config) error {
+	redisClient = redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Redis.Host, cfg.Redis.Port),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.Db,
+		PoolSize: cfg.Redis.PoolSize,
+	})
+
+	err := redisClient.Ping(context.Background()).Err()
+	if err != nil {
+		return err
+	}
+
+	logging.GetLogger().Info(constants.Redis, constants.Startup, "Redis connection established", nil)
+	return nil
+}
+
+func GetRedis() *redis.Client {
+	return redisClient
+}
+
+func CloseRedis() {
+	if redisClient != nil {
+		redisClient.Close()
+	}
+}
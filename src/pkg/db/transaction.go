@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// Transactor runs a unit of work in one database transaction
+type Transactor struct {
+	database *gorm.DB
+}
+
+func NewTransactor() *Transactor {
+	return &Transactor{database: GetDb()}
+}
+
+// WithinTransaction runs fn in a transaction, the repositories called with the context fn receives
+// take part in it. The transaction is committed when fn returns nil and rolled back otherwise,
+// a nested call joins the transaction of its context.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.database.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// Conn returns the transaction of the context, or database outside of a transaction
+func Conn(ctx context.Context, database *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return database.WithContext(ctx)
+}
@@ -10,11 +10,13 @@ type Filter struct {
 	Type string `json:"type"`
 	From string `json:"from"`
 	To   string `json:"to"`
-	// text number
+	// text number date bool
 	FilterType string `json:"filterType"`
 }
 
 type DynamicFilter struct {
 	Sort   *[]Sort           `json:"sort"`
 	Filter map[string]Filter `json:"filter"`
+	// OwnerId restricts the result to rows owned by the user, it is set by usecases and never bound from requests
+	OwnerId int `json:"-"`
 }
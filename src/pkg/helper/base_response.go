@@ -1,10 +1,19 @@
 package helper
 
+import (
+	"errors"
+
+	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
+	"github.com/alielmi98/go-hexa-workout/pkg/validation"
+)
+
 type BaseHttpResponse struct {
-	Result     any        `json:"result"`
-	Success    bool       `json:"success"`
-	ResultCode ResultCode `json:"resultCode"`
-	Error      any        `json:"error"`
+	Result           any                           `json:"result"`
+	Success          bool                          `json:"success"`
+	ResultCode       ResultCode                    `json:"resultCode"`
+	ErrorCode        service_errors.ErrorCode      `json:"errorCode,omitempty"`
+	ValidationErrors *[]validation.ValidationError `json:"validationErrors,omitempty"`
+	Error            any                           `json:"error"`
 }
 
 func GenerateBaseResponse(result any, success bool, resultCode ResultCode) *BaseHttpResponse {
@@ -16,9 +25,11 @@ func GenerateBaseResponse(result any, success bool, resultCode ResultCode) *Base
 
 func GenerateBaseResponseWithError(result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
 	return &BaseHttpResponse{Result: result,
-		Success:    success,
-		ResultCode: resultCode,
-		Error:      err.Error(),
+		Success:          success,
+		ResultCode:       resultCode,
+		ErrorCode:        errorCode(err),
+		ValidationErrors: validation.GetValidationErrors(err),
+		Error:            err.Error(),
 	}
 
 }
@@ -31,9 +42,30 @@ func GenerateBaseResponseWithAnyError(result any, success bool, resultCode Resul
 	}
 }
 
+// GenerateBaseResponseWithValidationError lists the failed rules of a binding error, errors that are not
+// about a field, like malformed json, are returned as the error message
 func GenerateBaseResponseWithValidationError(result any, success bool, resultCode ResultCode, err error) *BaseHttpResponse {
+	validationErrors := validation.GetValidationErrors(err)
+	if validationErrors == nil {
+		return GenerateBaseResponseWithError(result, success, resultCode, err)
+	}
 	return &BaseHttpResponse{Result: result,
-		Success:    success,
-		ResultCode: resultCode,
+		Success:          success,
+		ResultCode:       resultCode,
+		ErrorCode:        service_errors.CodeValidationError,
+		ValidationErrors: validationErrors,
+		Error:            service_errors.ValidationError,
+	}
+}
+
+// errorCode is the code that decided the status of err, or the code of its outer ServiceError
+func errorCode(err error) service_errors.ErrorCode {
+	if code, _ := TranslateError(err); code != "" {
+		return code
+	}
+	var serviceError *service_errors.ServiceError
+	if errors.As(err, &serviceError) {
+		return serviceError.Code
 	}
+	return ""
 }
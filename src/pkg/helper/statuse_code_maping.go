@@ -1,26 +1,84 @@
 package helper
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/alielmi98/go-hexa-workout/pkg/service_errors"
 )
 
-var StatusCodeMapping = map[string]int{
-	// User
-	service_errors.EmailExists:               409,
-	service_errors.UsernameExists:            409,
-	service_errors.RecordNotFound:            404,
-	service_errors.PermissionDenied:          403,
-	service_errors.UsernameOrPasswordInvalid: 401,
+// ErrorMapping is the http status and the result code returned for an error code
+type ErrorMapping struct {
+	StatusCode int
+	ResultCode ResultCode
+}
+
+var ErrorCodeMapping = map[service_errors.ErrorCode]ErrorMapping{
 	// Token
-	service_errors.InvalidRefreshToken: 401,
+	service_errors.CodeUnExpectedError:     {http.StatusInternalServerError, InternalError},
+	service_errors.CodeClaimsNotFound:      {http.StatusUnauthorized, AuthError},
+	service_errors.CodeTokenRequired:       {http.StatusUnauthorized, AuthError},
+	service_errors.CodeTokenExpired:        {http.StatusUnauthorized, AuthError},
+	service_errors.CodeTokenInvalid:        {http.StatusUnauthorized, AuthError},
+	service_errors.CodeInvalidRefreshToken: {http.StatusUnauthorized, AuthError},
+	service_errors.CodeRefreshTokenReused:  {http.StatusUnauthorized, AuthError},
+	service_errors.CodeInvalidRolesFormat:  {http.StatusUnauthorized, AuthError},
+	// User
+	service_errors.CodeEmailExists:               {http.StatusConflict, ConflictError},
+	service_errors.CodeUsernameExists:            {http.StatusConflict, ConflictError},
+	service_errors.CodeMobileNumberExists:        {http.StatusConflict, ConflictError},
+	service_errors.CodePermissionDenied:          {http.StatusForbidden, ForbiddenError},
+	service_errors.CodeUsernameOrPasswordInvalid: {http.StatusUnauthorized, AuthError},
+	service_errors.CodeUserDisabled:              {http.StatusForbidden, ForbiddenError},
+	service_errors.CodePasswordPolicyViolation:   {http.StatusBadRequest, ValidationError},
+	service_errors.CodeOldPasswordInvalid:        {http.StatusBadRequest, ValidationError},
+	service_errors.CodeTooManyLoginAttempts:      {http.StatusTooManyRequests, LimiterError},
+	// Validation
+	service_errors.CodeValidationError: {http.StatusBadRequest, ValidationError},
+	service_errors.CodeUserIdNotFound:  {http.StatusUnauthorized, AuthError},
+	service_errors.CodeUserNotOwner:    {http.StatusForbidden, ForbiddenError},
+	service_errors.CodeInvalidStatus:   {http.StatusBadRequest, ValidationError},
+	service_errors.CodeInvalidFilter:   {http.StatusBadRequest, ValidationError},
+	// Session
+	service_errors.CodeInvalidSessionState:       {http.StatusConflict, ConflictError},
+	service_errors.CodeScheduledWorkoutNotActive: {http.StatusConflict, ConflictError},
+	service_errors.CodeSessionAlreadyActive:      {http.StatusConflict, ConflictError},
+	// Schedule
+	service_errors.CodeInvalidStatusTransition: {http.StatusConflict, ConflictError},
+	// Notification
+	service_errors.CodeInvalidWebhookUrl: {http.StatusBadRequest, ValidationError},
+	// Limiter
+	service_errors.CodeTooManyRequests: {http.StatusTooManyRequests, LimiterError},
+	// DB
+	service_errors.CodeRecordNotFound:      {http.StatusNotFound, NotFoundError},
+	service_errors.CodeRecordExists:        {http.StatusConflict, ConflictError},
+	service_errors.CodeInvalidReference:    {http.StatusUnprocessableEntity, UnprocessableError},
+	service_errors.CodeConstraintViolation: {http.StatusUnprocessableEntity, UnprocessableError},
+	service_errors.CodeConcurrentUpdate:    {http.StatusConflict, ConflictError},
+	service_errors.CodeUnknownError:        {http.StatusInternalServerError, UnknownError},
 }
 
-func TranslateErrorToStatusCode(err error) int {
-	value, ok := StatusCodeMapping[err.Error()]
-	if !ok {
-		return http.StatusInternalServerError
+var internalErrorMapping = ErrorMapping{http.StatusInternalServerError, InternalError}
+
+// TranslateError returns the code and the mapping of the first ServiceError in the chain of err whose
+// code is mapped, codes without a mapping like FailedToFetchWorkout give way to the error they wrap
+func TranslateError(err error) (service_errors.ErrorCode, ErrorMapping) {
+	var serviceError *service_errors.ServiceError
+	for errors.As(err, &serviceError) {
+		if mapping, ok := ErrorCodeMapping[serviceError.Code]; ok {
+			return serviceError.Code, mapping
+		}
+		err = serviceError.Err
 	}
-	return value
+	return "", internalErrorMapping
+}
+
+func TranslateErrorToStatusCode(err error) int {
+	_, mapping := TranslateError(err)
+	return mapping.StatusCode
+}
+
+func TranslateErrorToResultCode(err error) ResultCode {
+	_, mapping := TranslateError(err)
+	return mapping.ResultCode
 }
@@ -0,0 +1,176 @@
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Status values of an Event
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	dateTimeLayout    = "20060102T150405"
+	utcDateTimeLayout = "20060102T150405Z"
+	// maxLineOctets is the length lines are folded at
+	maxLineOctets = 75
+)
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+// Calendar is an RFC 5545 iCalendar object with its events
+type Calendar struct {
+	ProductId string
+	Name      string
+	Events    []Event
+}
+
+// Event is a VEVENT. Start is written in its location, with a VTIMEZONE for the location,
+// or in UTC when the location is UTC. RecurrenceRule is the value of an RRULE and RecurrenceId
+// makes the event an override of an occurrence of the recurring event with the same Uid.
+type Event struct {
+	Uid            string
+	Stamp          time.Time
+	Start          time.Time
+	Duration       time.Duration
+	Summary        string
+	Description    string
+	Status         string
+	RecurrenceRule string
+	ExceptionDates []time.Time
+	RecurrenceId   *time.Time
+}
+
+// Encode renders the calendar with CRLF line endings and folded lines
+func (c Calendar) Encode() []byte {
+	w := &writer{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + c.ProductId)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		w.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+	for _, tz := range timezones(c.Events) {
+		tz.write(w)
+	}
+	for _, event := range c.Events {
+		event.write(w)
+	}
+	w.line("END:VCALENDAR")
+	return w.buf.Bytes()
+}
+
+func (e Event) write(w *writer) {
+	w.line("BEGIN:VEVENT")
+	w.line("UID:" + e.Uid)
+	w.line("DTSTAMP:" + e.Stamp.UTC().Format(utcDateTimeLayout))
+	if e.RecurrenceId != nil {
+		w.line("RECURRENCE-ID" + dateTime(e.RecurrenceId.In(e.Start.Location())))
+	}
+	w.line("DTSTART" + dateTime(e.Start))
+	if e.Duration > 0 {
+		w.line("DURATION:" + duration(e.Duration))
+	}
+	if e.RecurrenceRule != "" {
+		w.line("RRULE:" + e.RecurrenceRule)
+	}
+	for _, exception := range e.ExceptionDates {
+		w.line("EXDATE" + dateTime(exception.In(e.Start.Location())))
+	}
+	w.line("SUMMARY:" + escape(e.Summary))
+	if e.Description != "" {
+		w.line("DESCRIPTION:" + escape(e.Description))
+	}
+	if e.Status != "" {
+		w.line("STATUS:" + e.Status)
+	}
+	w.line("END:VEVENT")
+}
+
+// dateTime formats the parameters and the value of a date time property
+func dateTime(t time.Time) string {
+	if t.Location() == time.UTC {
+		return ":" + t.Format(utcDateTimeLayout)
+	}
+	return fmt.Sprintf(";TZID=%s:%s", t.Location().String(), t.Format(dateTimeLayout))
+}
+
+func duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	value := "PT"
+	if hours := int(d / time.Hour); hours > 0 {
+		value += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := int(d % time.Hour / time.Minute); minutes > 0 {
+		value += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds := int(d % time.Minute / time.Second); seconds > 0 || value == "PT" {
+		value += fmt.Sprintf("%dS", seconds)
+	}
+	return value
+}
+
+func escape(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// writer writes content lines, folding them at maxLineOctets without splitting UTF-8 characters
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) line(content string) {
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !startsCharacter(content[cut]) {
+			cut--
+		}
+		w.buf.WriteString(content[:cut])
+		w.buf.WriteString("\r\n ")
+		content = content[cut:]
+		// The space starting a continuation line counts
+		limit = maxLineOctets - 1
+	}
+	w.buf.WriteString(content)
+	w.buf.WriteString("\r\n")
+}
+
+func startsCharacter(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// timezones describes the locations the events start in, from the year of their earliest event
+func timezones(events []Event) []timezone {
+	years := map[string]int{}
+	locations := map[string]*time.Location{}
+	for _, event := range events {
+		location := event.Start.Location()
+		if location == time.UTC {
+			continue
+		}
+		year, ok := years[location.String()]
+		if !ok || event.Start.Year() < year {
+			years[location.String()] = event.Start.Year()
+		}
+		locations[location.String()] = location
+	}
+
+	names := []string{}
+	for name := range locations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zones := []timezone{}
+	for _, name := range names {
+		zones = append(zones, timezone{location: locations[name], year: years[name]})
+	}
+	return zones
+}
@@ -0,0 +1,112 @@
+package ical
+
+import (
+	"fmt"
+	"time"
+)
+
+// weekdayCodes are the days of an RRULE BYDAY, indexed by time.Weekday
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// timezone is a VTIMEZONE described by the offset changes of a location during a year
+type timezone struct {
+	location *time.Location
+	year     int
+}
+
+// transition is a change of the offset of a location
+type transition struct {
+	at   time.Time
+	from int
+	to   int
+	name string
+	dst  bool
+}
+
+// write describes the changes of the year, they repeat every year on the same weekday of the month
+// when the location switches between standard and daylight saving time twice a year
+func (z timezone) write(w *writer) {
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + z.location.String())
+	changes := transitions(z.location, z.year)
+	if len(changes) == 0 {
+		name, offset := time.Date(z.year, time.January, 1, 0, 0, 0, 0, z.location).Zone()
+		observance(w, "STANDARD", "19700101T000000", offset, offset, name, "")
+	}
+	for _, change := range changes {
+		kind := "STANDARD"
+		if change.dst {
+			kind = "DAYLIGHT"
+		}
+		onset := change.at.In(time.FixedZone(change.name, change.from))
+		rule := ""
+		if len(changes) == 2 {
+			rule = yearlyRule(onset)
+		}
+		observance(w, kind, onset.Format(dateTimeLayout), change.from, change.to, change.name, rule)
+	}
+	w.line("END:VTIMEZONE")
+}
+
+func observance(w *writer, kind string, start string, from int, to int, name string, rule string) {
+	w.line("BEGIN:" + kind)
+	w.line("DTSTART:" + start)
+	w.line("TZOFFSETFROM:" + offset(from))
+	w.line("TZOFFSETTO:" + offset(to))
+	if rule != "" {
+		w.line("RRULE:" + rule)
+	}
+	if name != "" {
+		w.line("TZNAME:" + escape(name))
+	}
+	w.line("END:" + kind)
+}
+
+// yearlyRule repeats a change on the same weekday of the month, the last one when it is in the last week
+func yearlyRule(onset time.Time) string {
+	ordinal := fmt.Sprint((onset.Day()-1)/7 + 1)
+	daysInMonth := time.Date(onset.Year(), onset.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if onset.Day()+7 > daysInMonth {
+		ordinal = "-1"
+	}
+	return fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%s%s", onset.Month(), ordinal, weekdayCodes[onset.Weekday()])
+}
+
+func offset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	value := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+	if seconds%60 != 0 {
+		value += fmt.Sprintf("%02d", seconds%60)
+	}
+	return value
+}
+
+// transitions finds the offset changes of a location during a year
+func transitions(location *time.Location, year int) []transition {
+	changes := []transition{}
+	day := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(1, 0, 0)
+	for day.Before(end) {
+		next := day.AddDate(0, 0, 1)
+		_, from := day.In(location).Zone()
+		if _, to := next.In(location).Zone(); from != to {
+			// The change is in the day, search the second it happens at
+			before, after := day, next
+			for after.Sub(before) > time.Second {
+				middle := before.Add(after.Sub(before) / 2)
+				if _, o := middle.In(location).Zone(); o == from {
+					before = middle
+				} else {
+					after = middle
+				}
+			}
+			name, to := after.In(location).Zone()
+			changes = append(changes, transition{at: after, from: from, to: to, name: name, dst: after.In(location).IsDST()})
+		}
+		day = next
+	}
+	return changes
+}
@@ -0,0 +1,81 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+)
+
+// PostgresElector elects the leader with a session level advisory lock of postgres. The lock is held on a
+// connection of its own, it is released by postgres when the replica dies or its connection breaks, and
+// another replica takes it the next time it checks. Closing a *sql.Conn only returns the session to the
+// pool, so a connection that may still hold the lock is discarded instead.
+type PostgresElector struct {
+	db   *sql.DB
+	key  int64
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewPostgresElector(db *sql.DB, key int64) *PostgresElector {
+	return &PostgresElector{db: db, key: key}
+}
+
+func (e *PostgresElector) IsLeader(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		// The lock lives as long as the connection
+		if err := e.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		discard(e.conn)
+		e.conn = nil
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var locked bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&locked)
+	if err != nil {
+		// The lock may have been taken before the failure
+		discard(conn)
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+	e.conn = conn
+	return true, nil
+}
+
+func (e *PostgresElector) Resign(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	_, err := e.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", e.key)
+	if err != nil {
+		discard(e.conn)
+	} else {
+		e.conn.Close()
+	}
+	e.conn = nil
+	return err
+}
+
+// discard closes the session of the connection instead of returning it to the pool, which ends the
+// locks it holds. Returning driver.ErrBadConn from Raw makes database/sql close the driver connection.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(driverConn interface{}) error {
+		return driver.ErrBadConn
+	})
+	conn.Close()
+}
@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/logging"
+)
+
+// Elector decides which of the replicas of the service runs the jobs
+type Elector interface {
+	// IsLeader takes the leadership when it is free, or checks it is still held, and reports whether it is held
+	IsLeader(ctx context.Context) (bool, error)
+	// Resign gives the leadership up so another replica can take it
+	Resign(ctx context.Context) error
+}
+
+// Func is the work of a job, the context is cancelled when the scheduler stops
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	schedule Schedule
+	run      Func
+	next     time.Time
+	running  atomic.Bool
+}
+
+// Scheduler runs jobs on their schedule while its replica is the leader. A run that is still going
+// when the job is due again is not overlapped, the job waits for its next time instead.
+type Scheduler struct {
+	elector Elector
+	jobs    []*job
+	running sync.WaitGroup
+	done    chan struct{}
+}
+
+func NewScheduler(elector Elector) *Scheduler {
+	return &Scheduler{elector: elector, done: make(chan struct{})}
+}
+
+// Add registers a job with a schedule read by Parse, it must be called before Start
+func (s *Scheduler) Add(name string, spec string, run Func) error {
+	schedule, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	s.jobs = append(s.jobs, &job{name: name, schedule: schedule, run: run})
+	return nil
+}
+
+// Start runs the jobs in the background until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	go s.loop(ctx)
+}
+
+// Wait blocks until the scheduler has stopped, after its running jobs returned and the leadership was given up
+func (s *Scheduler) Wait() {
+	<-s.done
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+	now := time.Now().UTC()
+	for _, j := range s.jobs {
+		j.next = j.schedule.Next(now)
+	}
+
+	for {
+		next, ok := s.nextRun()
+		if !ok {
+			<-ctx.Done()
+		} else {
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+			case <-timer.C:
+			}
+		}
+		if ctx.Err() != nil {
+			s.stop()
+			return
+		}
+
+		now = time.Now().UTC()
+		leader := s.isLeader(ctx)
+		for _, j := range s.jobs {
+			if j.next.IsZero() || j.next.After(now) {
+				continue
+			}
+			j.next = j.schedule.Next(now)
+			if leader {
+				s.launch(ctx, j)
+			}
+		}
+	}
+}
+
+// nextRun is the earliest time a job is due, a job whose schedule never matches is left out
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	var next time.Time
+	for _, j := range s.jobs {
+		if !j.next.IsZero() && (next.IsZero() || j.next.Before(next)) {
+			next = j.next
+		}
+	}
+	return next, !next.IsZero()
+}
+
+func (s *Scheduler) isLeader(ctx context.Context) bool {
+	leader, err := s.elector.IsLeader(ctx)
+	if err != nil {
+		logging.GetLogger().Error(constants.Internal, constants.Job, "leader election failed: "+err.Error(), nil)
+		return false
+	}
+	return leader
+}
+
+func (s *Scheduler) launch(ctx context.Context, j *job) {
+	logger := logging.GetLogger().With(map[constants.ExtraKey]interface{}{constants.JobName: j.name})
+	if !j.running.CompareAndSwap(false, true) {
+		logger.Warn(constants.Internal, constants.Job, "skipped, the previous run is still going", nil)
+		return
+	}
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		defer j.running.Store(false)
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error(constants.Internal, constants.Job, fmt.Sprintf("panic: %v", r),
+					map[constants.ExtraKey]interface{}{constants.Stack: string(debug.Stack())})
+			}
+		}()
+
+		start := time.Now()
+		err := j.run(logging.NewContext(ctx, logger))
+		extra := map[constants.ExtraKey]interface{}{constants.Latency: time.Since(start).String()}
+		if err != nil {
+			logger.Error(constants.Internal, constants.Job, err.Error(), extra)
+			return
+		}
+		logger.Debug(constants.Internal, constants.Job, "finished", extra)
+	}()
+}
+
+// stop waits for the running jobs, which see the cancelled context, and resigns
+func (s *Scheduler) stop() {
+	s.running.Wait()
+	// The context of the scheduler is cancelled by now
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.elector.Resign(ctx); err != nil {
+		logging.GetLogger().Error(constants.Internal, constants.Job, "resigning the leadership failed: "+err.Error(), nil)
+	}
+	logging.GetLogger().Info(constants.Internal, constants.Job, "Stopped", nil)
+}
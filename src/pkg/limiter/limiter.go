@@ -0,0 +1,39 @@
+package limiter
+
+import (
+	"context"
+	"time"
+)
+
+// Store counts the requests of a key in fixed windows, Take adds one request to the current window
+// of the key and returns the count so far together with the time left until the window resets,
+// Get returns the same without counting a request
+type Store interface {
+	Take(ctx context.Context, key string, window time.Duration) (count int, resetIn time.Duration, err error)
+	Get(ctx context.Context, key string) (count int, resetIn time.Duration, err error)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetIn   time.Duration
+}
+
+// Allow takes one request from the limit of the key
+func Allow(ctx context.Context, store Store, key string, limit int, window time.Duration) (*Result, error) {
+	count, resetIn, err := store.Take(ctx, key, window)
+	if err != nil {
+		return nil, err
+	}
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &Result{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetIn:   resetIn,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package limiter
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the expired windows are removed from the memory store
+const sweepInterval = time.Minute
+
+type memoryWindow struct {
+	count     int
+	expiresAt time.Time
+}
+
+// MemoryStore keeps the windows in the process, the limits are not shared between instances
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string]*memoryWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: map[string]*memoryWindow{}, now: time.Now, lastSweep: time.Now()}
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) > sweepInterval {
+		for k, w := range s.windows {
+			if !now.Before(w.expiresAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.expiresAt) {
+		w = &memoryWindow{expiresAt: now.Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+	return w.count, w.expiresAt.Sub(now), nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.expiresAt) {
+		return 0, 0, nil
+	}
+	return w.count, w.expiresAt.Sub(now), nil
+}
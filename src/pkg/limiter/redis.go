@@ -0,0 +1,53 @@
+package limiter
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript increments the counter and starts its window when the key has no expiry yet, in one round trip
+var takeScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+var getScript = redis.NewScript(`
+local count = redis.call("GET", KEYS[1])
+if not count then
+	return {0, 0}
+end
+return {tonumber(count), redis.call("PTTL", KEYS[1])}
+`)
+
+// RedisStore keeps the windows in redis so that every instance shares the same limits
+type RedisStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisStore(client redis.Scripter) *RedisStore {
+	return &RedisStore{client: client, prefix: "rate-limit:"}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, window time.Duration) (int, time.Duration, error) {
+	values, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(values[0]), time.Duration(values[1]) * time.Millisecond, nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (int, time.Duration, error) {
+	values, err := getScript.Run(ctx, s.client, []string{s.prefix + key}).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(values[0]), time.Duration(values[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,145 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+)
+
+const (
+	debugLevel = slog.LevelDebug
+	infoLevel  = slog.LevelInfo
+	warnLevel  = slog.LevelWarn
+	errorLevel = slog.LevelError
+	fatalLevel = slog.Level(12)
+)
+
+var levels = map[string]slog.Level{
+	"debug": debugLevel,
+	"info":  infoLevel,
+	"warn":  warnLevel,
+	"error": errorLevel,
+	"fatal": fatalLevel,
+}
+
+func parseLevel(level string) (slog.Level, error) {
+	if level == "" {
+		return infoLevel, nil
+	}
+	l, ok := levels[strings.ToLower(level)]
+	if !ok {
+		return infoLevel, fmt.Errorf("unsupported log level %q", level)
+	}
+	return l, nil
+}
+
+// jsonLogger writes one json object per entry, text encoding is available for local development
+type jsonLogger struct {
+	logger *slog.Logger
+}
+
+func newJsonLogger(w io.Writer, level slog.Level, encoding string) *jsonLogger {
+	options := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.LevelKey && a.Value.Any() == fatalLevel {
+				a.Value = slog.StringValue("FATAL")
+			}
+			return a
+		},
+	}
+	var handler slog.Handler
+	if encoding == "text" {
+		handler = slog.NewTextHandler(w, options)
+	} else {
+		handler = slog.NewJSONHandler(w, options)
+	}
+	return &jsonLogger{logger: slog.New(handler)}
+}
+
+func (l *jsonLogger) Debug(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	l.log(debugLevel, cat, sub, msg, extra)
+}
+
+func (l *jsonLogger) Debugf(template string, args ...interface{}) {
+	l.logf(debugLevel, template, args...)
+}
+
+func (l *jsonLogger) Info(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	l.log(infoLevel, cat, sub, msg, extra)
+}
+
+func (l *jsonLogger) Infof(template string, args ...interface{}) {
+	l.logf(infoLevel, template, args...)
+}
+
+func (l *jsonLogger) Warn(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	l.log(warnLevel, cat, sub, msg, extra)
+}
+
+func (l *jsonLogger) Warnf(template string, args ...interface{}) {
+	l.logf(warnLevel, template, args...)
+}
+
+func (l *jsonLogger) Error(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	l.log(errorLevel, cat, sub, msg, extra)
+}
+
+func (l *jsonLogger) Errorf(template string, args ...interface{}) {
+	l.logf(errorLevel, template, args...)
+}
+
+func (l *jsonLogger) Fatal(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	l.log(fatalLevel, cat, sub, msg, extra)
+	os.Exit(1)
+}
+
+func (l *jsonLogger) Fatalf(template string, args ...interface{}) {
+	l.logf(fatalLevel, template, args...)
+	os.Exit(1)
+}
+
+func (l *jsonLogger) With(extra map[constants.ExtraKey]interface{}) Logger {
+	attrs := extraAttrs(extra)
+	args := make([]any, 0, len(attrs))
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	return &jsonLogger{logger: l.logger.With(args...)}
+}
+
+func (l *jsonLogger) log(level slog.Level, cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{}) {
+	attrs := []slog.Attr{
+		slog.String("Category", string(cat)),
+		slog.String("SubCategory", string(sub)),
+	}
+	attrs = append(attrs, extraAttrs(extra)...)
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (l *jsonLogger) logf(level slog.Level, template string, args ...interface{}) {
+	if !l.logger.Enabled(context.Background(), level) {
+		return
+	}
+	l.logger.Log(context.Background(), level, fmt.Sprintf(template, args...))
+}
+
+// extraAttrs converts the extra fields to attributes ordered by key
+func extraAttrs(extra map[constants.ExtraKey]interface{}) []slog.Attr {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, string(k))
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, extra[constants.ExtraKey(k)]))
+	}
+	return attrs
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alielmi98/go-hexa-workout/constants"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+)
+
+// Logger writes leveled entries with a category, a sub category and optional extra fields
+type Logger interface {
+	Debug(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{})
+	Debugf(template string, args ...interface{})
+
+	Info(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{})
+	Infof(template string, args ...interface{})
+
+	Warn(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{})
+	Warnf(template string, args ...interface{})
+
+	Error(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{})
+	Errorf(template string, args ...interface{})
+
+	// Fatal writes the entry and exits the process
+	Fatal(cat constants.Category, sub constants.SubCategory, msg string, extra map[constants.ExtraKey]interface{})
+	Fatalf(template string, args ...interface{})
+
+	// With returns a logger that adds the extra fields to every entry
+	With(extra map[constants.ExtraKey]interface{}) Logger
+}
+
+// ContextKey is the key of the request scoped logger. It is a string so the logger
+// can also be stored on a gin context with Set.
+const ContextKey = "Logger"
+
+// logger is used until Init is called, e.g. while the config is loaded
+var logger Logger = newJsonLogger(os.Stderr, infoLevel, "json")
+
+// Init replaces the global logger with one built from the config
+func Init(cfg *config.LoggerConfig) error {
+	l, err := NewLogger(cfg)
+	if err != nil {
+		return err
+	}
+	logger = l
+	return nil
+}
+
+// GetLogger returns the global logger
+func GetLogger() Logger {
+	return logger
+}
+
+// NewLogger creates a logger that writes entries at or above the configured level to the configured output
+func NewLogger(cfg *config.LoggerConfig) (Logger, error) {
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	encoding := strings.ToLower(cfg.Encoding)
+	if encoding != "" && encoding != "json" && encoding != "text" {
+		return nil, fmt.Errorf("unsupported log encoding %q", cfg.Encoding)
+	}
+	w, err := openOutput(cfg.Output)
+	if err != nil {
+		return nil, err
+	}
+	return newJsonLogger(w, level, encoding), nil
+}
+
+// NewContext returns a copy of ctx that carries the logger
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, ContextKey, l)
+}
+
+// FromContext returns the request scoped logger of ctx, or the global logger when there is none
+func FromContext(ctx context.Context) Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ContextKey).(Logger); ok {
+			return l
+		}
+	}
+	return logger
+}
+
+func openOutput(output string) (io.Writer, error) {
+	switch strings.ToLower(output) {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+	return os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// SecretTag marks a dto field whose value is never logged, the field is declared as
+//
+//	Password string `json:"password" log:"secret"`
+const SecretTag = "secret"
+
+var (
+	secretFields = map[string]bool{}
+	secretsMu    sync.RWMutex
+)
+
+// RegisterSecrets reads the fields tagged with `log:"secret"` from the dtos and their nested structs,
+// the body logger redacts the json fields of these names in every body, compared in lower case.
+// The dto packages register their types in an init function.
+func RegisterSecrets(dtos ...interface{}) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range dtos {
+		registerSecrets(reflect.TypeOf(value), map[reflect.Type]bool{})
+	}
+}
+
+func registerSecrets(t reflect.Type, seen map[reflect.Type]bool) {
+	for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if field.Tag.Get("log") == SecretTag {
+			secretFields[strings.ToLower(name)] = true
+			continue
+		}
+		registerSecrets(field.Type, seen)
+	}
+}
+
+// IsSecretField tells whether a json field of this name was registered as a secret
+func IsSecretField(name string) bool {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	return secretFields[strings.ToLower(name)]
+}
@@ -8,20 +8,139 @@ const (
 	TokenExpired        = "token expired"
 	TokenInvalid        = "token invalid"
 	InvalidRefreshToken = "invalid refresh token"
+	RefreshTokenReused  = "refresh token reused"
 	InvalidRolesFormat  = "invalid roles format"
 	// User
 	EmailExists               = "Email exists"
 	UsernameExists            = "Username exists"
+	MobileNumberExists        = "Mobile number exists"
 	PermissionDenied          = "Permission denied"
 	UsernameOrPasswordInvalid = "username or password invalid"
+	PasswordPolicyViolation   = "password does not meet the password policy"
+	OldPasswordInvalid        = "old password is invalid"
+	UserDisabled              = "user is disabled"
+	TooManyLoginAttempts      = "too many failed login attempts, try again later"
 	// Validation
 	ValidationError      = "validation error"
 	UserIdNotFound       = "failed to get user ID from context"
 	FailedToFetchWorkout = "failed to fetch workout with ID"
 	UserNotOwner         = "user is not the owner of this workout"
-	InvalidStatus        = "invalid status. Status must be 'active' or 'completed' or 'canceled'"
+	InvalidStatus        = "invalid status. Status must be 'planned', 'in_progress', 'completed', 'skipped' or 'cancelled'"
+	InvalidFilter        = "invalid filter"
+	// Session
+	InvalidSessionState       = "the action is not allowed in the current state of the session"
+	ScheduledWorkoutNotActive = "the scheduled workout can not be started in its current status"
+	SessionAlreadyActive      = "the scheduled workout already has a session in progress"
+	// Schedule
+	InvalidStatusTransition = "the scheduled workout can not change from its current status to the requested one"
+	// Notification
+	InvalidWebhookUrl = "the webhook url must be an absolute http or https url of a public host"
+
+	// Limiter
+	TooManyRequests = "too many requests"
+
+	// DB
+	RecordNotFound      = "record not found"
+	RecordExists        = "record already exists"
+	InvalidReference    = "referenced record does not exist"
+	ConstraintViolation = "value violates a constraint"
+	ConcurrentUpdate    = "the record was changed at the same time, try again"
+	UnknownError        = "unknown error"
+)
+
+// ErrorCode identifies the kind of a ServiceError, it is returned to the clients next to the message
+type ErrorCode string
+
+const (
+	// Token
+	CodeUnExpectedError     ErrorCode = "UNEXPECTED_ERROR"
+	CodeClaimsNotFound      ErrorCode = "CLAIMS_NOT_FOUND"
+	CodeTokenRequired       ErrorCode = "TOKEN_REQUIRED"
+	CodeTokenExpired        ErrorCode = "TOKEN_EXPIRED"
+	CodeTokenInvalid        ErrorCode = "TOKEN_INVALID"
+	CodeInvalidRefreshToken ErrorCode = "INVALID_REFRESH_TOKEN"
+	CodeRefreshTokenReused  ErrorCode = "REFRESH_TOKEN_REUSED"
+	CodeInvalidRolesFormat  ErrorCode = "INVALID_ROLES_FORMAT"
+	// User
+	CodeEmailExists               ErrorCode = "EMAIL_EXISTS"
+	CodeUsernameExists            ErrorCode = "USERNAME_EXISTS"
+	CodeMobileNumberExists        ErrorCode = "MOBILE_NUMBER_EXISTS"
+	CodePermissionDenied          ErrorCode = "PERMISSION_DENIED"
+	CodeUsernameOrPasswordInvalid ErrorCode = "USERNAME_OR_PASSWORD_INVALID"
+	CodePasswordPolicyViolation   ErrorCode = "PASSWORD_POLICY_VIOLATION"
+	CodeOldPasswordInvalid        ErrorCode = "OLD_PASSWORD_INVALID"
+	CodeUserDisabled              ErrorCode = "USER_DISABLED"
+	CodeTooManyLoginAttempts      ErrorCode = "TOO_MANY_LOGIN_ATTEMPTS"
+	// Validation
+	CodeValidationError      ErrorCode = "VALIDATION_ERROR"
+	CodeUserIdNotFound       ErrorCode = "USER_ID_NOT_FOUND"
+	CodeFailedToFetchWorkout ErrorCode = "FAILED_TO_FETCH_WORKOUT"
+	CodeUserNotOwner         ErrorCode = "USER_NOT_OWNER"
+	CodeInvalidStatus        ErrorCode = "INVALID_STATUS"
+	CodeInvalidFilter        ErrorCode = "INVALID_FILTER"
+	// Session
+	CodeInvalidSessionState       ErrorCode = "INVALID_SESSION_STATE"
+	CodeScheduledWorkoutNotActive ErrorCode = "SCHEDULED_WORKOUT_NOT_ACTIVE"
+	CodeSessionAlreadyActive      ErrorCode = "SESSION_ALREADY_ACTIVE"
+	// Schedule
+	CodeInvalidStatusTransition ErrorCode = "INVALID_STATUS_TRANSITION"
+	// Notification
+	CodeInvalidWebhookUrl ErrorCode = "INVALID_WEBHOOK_URL"
+
+	// Limiter
+	CodeTooManyRequests ErrorCode = "TOO_MANY_REQUESTS"
 
 	// DB
-	RecordNotFound = "record not found"
-	UnknownError   = "unknown error"
+	CodeRecordNotFound      ErrorCode = "RECORD_NOT_FOUND"
+	CodeRecordExists        ErrorCode = "RECORD_EXISTS"
+	CodeInvalidReference    ErrorCode = "INVALID_REFERENCE"
+	CodeConstraintViolation ErrorCode = "CONSTRAINT_VIOLATION"
+	CodeConcurrentUpdate    ErrorCode = "CONCURRENT_UPDATE"
+	CodeUnknownError        ErrorCode = "UNKNOWN_ERROR"
 )
+
+// messages are the end user messages of the codes
+var messages = map[ErrorCode]string{
+	CodeUnExpectedError:     UnExpectedError,
+	CodeClaimsNotFound:      ClaimsNotFound,
+	CodeTokenRequired:       TokenRequired,
+	CodeTokenExpired:        TokenExpired,
+	CodeTokenInvalid:        TokenInvalid,
+	CodeInvalidRefreshToken: InvalidRefreshToken,
+	CodeRefreshTokenReused:  RefreshTokenReused,
+	CodeInvalidRolesFormat:  InvalidRolesFormat,
+
+	CodeEmailExists:               EmailExists,
+	CodeUsernameExists:            UsernameExists,
+	CodeMobileNumberExists:        MobileNumberExists,
+	CodePermissionDenied:          PermissionDenied,
+	CodeUsernameOrPasswordInvalid: UsernameOrPasswordInvalid,
+	CodePasswordPolicyViolation:   PasswordPolicyViolation,
+	CodeOldPasswordInvalid:        OldPasswordInvalid,
+	CodeUserDisabled:              UserDisabled,
+	CodeTooManyLoginAttempts:      TooManyLoginAttempts,
+
+	CodeValidationError:      ValidationError,
+	CodeUserIdNotFound:       UserIdNotFound,
+	CodeFailedToFetchWorkout: FailedToFetchWorkout,
+	CodeUserNotOwner:         UserNotOwner,
+	CodeInvalidStatus:        InvalidStatus,
+	CodeInvalidFilter:        InvalidFilter,
+	// Session
+	CodeInvalidSessionState:       InvalidSessionState,
+	CodeScheduledWorkoutNotActive: ScheduledWorkoutNotActive,
+	CodeSessionAlreadyActive:      SessionAlreadyActive,
+	// Schedule
+	CodeInvalidStatusTransition: InvalidStatusTransition,
+	// Notification
+	CodeInvalidWebhookUrl: InvalidWebhookUrl,
+
+	CodeTooManyRequests: TooManyRequests,
+
+	CodeRecordNotFound:      RecordNotFound,
+	CodeRecordExists:        RecordExists,
+	CodeInvalidReference:    InvalidReference,
+	CodeConstraintViolation: ConstraintViolation,
+	CodeConcurrentUpdate:    ConcurrentUpdate,
+	CodeUnknownError:        UnknownError,
+}
@@ -1,11 +1,40 @@
 package service_errors
 
+import "errors"
+
 type ServiceError struct {
-	EndUserMessage   string `json:"endUserMessage"`
-	TechnicalMessage string `json:"technicalMessage"`
+	Code             ErrorCode `json:"code"`
+	EndUserMessage   string    `json:"endUserMessage"`
+	TechnicalMessage string    `json:"technicalMessage"`
 	Err              error
 }
 
+// New creates the error of the code with its end user message
+func New(code ErrorCode) *ServiceError {
+	return &ServiceError{Code: code, EndUserMessage: messages[code]}
+}
+
+// Wrap creates the error of the code with err as the cause
+func Wrap(code ErrorCode, err error) *ServiceError {
+	return &ServiceError{Code: code, EndUserMessage: messages[code], Err: err}
+}
+
 func (s *ServiceError) Error() string {
 	return s.EndUserMessage
 }
+
+func (s *ServiceError) Unwrap() error {
+	return s.Err
+}
+
+// HasCode reports whether err or one of the errors it wraps is a ServiceError with the code
+func HasCode(err error, code ErrorCode) bool {
+	var serviceError *ServiceError
+	for errors.As(err, &serviceError) {
+		if serviceError.Code == code {
+			return true
+		}
+		err = serviceError.Err
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/alielmi98/go-hexa-workout/common"
+	"github.com/alielmi98/go-hexa-workout/pkg/config"
+)
+
+// Tags of the password policy rules
+const (
+	PasswordMinLength = "minLength"
+	PasswordMaxLength = "maxLength"
+	PasswordLetter    = "letter"
+	PasswordDigit     = "digit"
+	PasswordUppercase = "uppercase"
+	PasswordLowercase = "lowercase"
+)
+
+// CheckPassword returns one validation error for every rule of the password policy the password breaks.
+// The password itself is never part of the errors.
+func CheckPassword(cfg *config.PasswordConfig, property string, password string) ValidationErrors {
+	violations := ValidationErrors{}
+	length := utf8.RuneCountInString(password)
+	if cfg.MinLength > 0 && length < cfg.MinLength {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordMinLength, Param: strconv.Itoa(cfg.MinLength),
+			Message: fmt.Sprintf("must be at least %d characters long", cfg.MinLength)})
+	}
+	if cfg.MaxLength > 0 && length > cfg.MaxLength {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordMaxLength, Param: strconv.Itoa(cfg.MaxLength),
+			Message: fmt.Sprintf("must be at most %d characters long", cfg.MaxLength)})
+	}
+	if cfg.IncludeChars && !common.HasLetter(password) {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordLetter,
+			Message: "must contain a letter"})
+	}
+	if cfg.IncludeDigits && !common.HasDigit(password) {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordDigit,
+			Message: "must contain a digit"})
+	}
+	if cfg.IncludeUppercase && !common.HasUpper(password) {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordUppercase,
+			Message: "must contain an uppercase letter"})
+	}
+	if cfg.IncludeLowercase && !common.HasLower(password) {
+		violations = append(violations, ValidationError{Field: property, Tag: PasswordLowercase,
+			Message: "must contain a lowercase letter"})
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
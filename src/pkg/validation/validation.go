@@ -0,0 +1,106 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationError describes a single failed validation rule of a field
+type ValidationError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param"`
+	Message string `json:"message,omitempty"`
+}
+
+// ValidationErrors is returned by the usecases when a value breaks one or more rules
+type ValidationErrors []ValidationError
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, e := range v {
+		messages = append(messages, e.Field+" "+e.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// The binding errors name the fields like the json of the request
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// GetValidationErrors converts binding and usecase validation errors to a list of failed rules,
+// it returns nil for any other error
+func GetValidationErrors(err error) *[]ValidationError {
+	var bindingErrors validator.ValidationErrors
+	if errors.As(err, &bindingErrors) {
+		validationErrors := make([]ValidationError, 0, len(bindingErrors))
+		for _, e := range bindingErrors {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   e.Field(),
+				Tag:     e.Tag(),
+				Param:   e.Param(),
+				Message: bindingMessage(e),
+			})
+		}
+		return &validationErrors
+	}
+	var validationErrors ValidationErrors
+	if errors.As(err, &validationErrors) {
+		result := []ValidationError(validationErrors)
+		return &result
+	}
+	return nil
+}
+
+func bindingMessage(e validator.FieldError) string {
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "numeric":
+		return "must be a number"
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters long", e.Param())
+	case "min":
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters long", e.Param())
+		}
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "max":
+		if e.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters long", e.Param())
+		}
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", e.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", e.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", e.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", e.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", e.Param())
+	}
+	return fmt.Sprintf("failed on the %s rule", e.Tag())
+}